@@ -0,0 +1,263 @@
+// Package asm assembles a small, practical subset of AArch64 assembly
+// text into machine code, using a two-pass approach: pass one tokenizes
+// each line, assigns it an address, and records any label it defines;
+// pass two encodes every instruction into its 32-bit word, now free to
+// resolve label operands to PC-relative offsets since the full symbol
+// table is known. It covers the mnemonics this repository's emu package
+// already executes — the LDR/STR family (including the byte/halfword
+// and sign-extending variants), MOV/MOVZ/MOVK, ADD/SUB (immediate),
+// B/BL/B.cond, SVC, and RET — not the full AArch64 encoding space, so
+// that future load/store tests can be written as assembly strings like
+// "ldr x0, [x1, #16]" and run through a real decode path (see the
+// disasm package) instead of calling LoadStoreUnit methods directly.
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SymbolTable maps label names to their byte offset from the start of
+// the assembled code.
+type SymbolTable map[string]uint64
+
+// statement is one assembled line: an optional label definition and/or
+// an instruction. A line may be label-only ("loop:"), instruction-only,
+// or both ("loop: b loop").
+type statement struct {
+	lineNo   int
+	label    string
+	mnemonic string
+	operands []string
+}
+
+// Assemble assembles src into machine code and the table of labels it
+// defines. Label operands on B/BL/B.cond are resolved against that same
+// table, so forward references work.
+func Assemble(src string) ([]byte, *SymbolTable, error) {
+	statements, err := tokenize(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	symbols, instructions := firstPass(statements)
+
+	code, err := secondPass(instructions, symbols)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return code, &symbols, nil
+}
+
+// tokenize splits src into statements, stripping comments (from "//" to
+// end of line) and blank lines, and separating a leading "label:" from
+// the instruction that may follow it on the same line.
+func tokenize(src string) ([]statement, error) {
+	var statements []statement
+
+	for i, raw := range strings.Split(src, "\n") {
+		lineNo := i + 1
+
+		line := raw
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var label string
+		if idx := strings.Index(line, ":"); idx >= 0 {
+			label = strings.TrimSpace(line[:idx])
+			if label == "" {
+				return nil, fmt.Errorf("asm: line %d: empty label", lineNo)
+			}
+			line = strings.TrimSpace(line[idx+1:])
+		}
+
+		stmt := statement{lineNo: lineNo, label: label}
+		if line != "" {
+			mnemonic, operands, err := splitInstruction(line)
+			if err != nil {
+				return nil, fmt.Errorf("asm: line %d: %w", lineNo, err)
+			}
+			stmt.mnemonic = mnemonic
+			stmt.operands = operands
+		}
+		statements = append(statements, stmt)
+	}
+
+	return statements, nil
+}
+
+// splitInstruction splits "mnemonic op1, op2, [op3, #imm]" into the
+// mnemonic and its operands, treating a bracketed memory operand (which
+// may itself contain a comma) as a single operand.
+func splitInstruction(line string) (string, []string, error) {
+	fields := strings.SplitN(line, " ", 2)
+	mnemonic := strings.ToLower(strings.TrimSpace(fields[0]))
+	if len(fields) == 1 {
+		return mnemonic, nil, nil
+	}
+
+	var operands []string
+	var cur strings.Builder
+	depth := 0
+	for _, r := range fields[1] {
+		switch r {
+		case '[':
+			depth++
+			cur.WriteRune(r)
+		case ']':
+			depth--
+			if depth < 0 {
+				return "", nil, fmt.Errorf("unbalanced ']'")
+			}
+			cur.WriteRune(r)
+		case ',':
+			if depth > 0 {
+				cur.WriteRune(r)
+				continue
+			}
+			operands = append(operands, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("unbalanced '['")
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		operands = append(operands, s)
+	}
+
+	return mnemonic, operands, nil
+}
+
+// firstPass assigns each instruction its address (4 bytes apart) and
+// records every label's address, without yet resolving any operand.
+func firstPass(statements []statement) (SymbolTable, []statement) {
+	symbols := make(SymbolTable)
+	var instructions []statement
+
+	addr := uint64(0)
+	for _, stmt := range statements {
+		if stmt.label != "" {
+			symbols[stmt.label] = addr
+		}
+		if stmt.mnemonic != "" {
+			instructions = append(instructions, stmt)
+			addr += 4
+		}
+	}
+
+	return symbols, instructions
+}
+
+// secondPass encodes each instruction now that the full symbol table is
+// known, emitting little-endian words to match emu.Memory's layout.
+func secondPass(instructions []statement, symbols SymbolTable) ([]byte, error) {
+	code := make([]byte, 0, len(instructions)*4)
+
+	addr := uint64(0)
+	for _, stmt := range instructions {
+		word, err := encode(stmt, addr, symbols)
+		if err != nil {
+			return nil, fmt.Errorf("asm: line %d: %w", stmt.lineNo, err)
+		}
+		code = append(code,
+			byte(word), byte(word>>8), byte(word>>16), byte(word>>24))
+		addr += 4
+	}
+
+	return code, nil
+}
+
+// parseImm parses an immediate operand of the form "#123", "#0x7b" or
+// "#-5".
+func parseImm(operand string) (int64, error) {
+	s := strings.TrimSpace(operand)
+	if !strings.HasPrefix(s, "#") {
+		return 0, fmt.Errorf("expected immediate, got %q", operand)
+	}
+	v, err := strconv.ParseInt(s[1:], 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid immediate %q: %w", operand, err)
+	}
+	return v, nil
+}
+
+// parseShift parses a "lsl #N" shift operand, as used by MOVZ/MOVK/ADD/SUB.
+func parseShift(operand string) (int64, error) {
+	fields := strings.Fields(operand)
+	if len(fields) != 2 || strings.ToLower(fields[0]) != "lsl" {
+		return 0, fmt.Errorf("expected \"lsl #N\" shift, got %q", operand)
+	}
+	return parseImm(fields[1])
+}
+
+// register identifies one parsed register operand.
+type register struct {
+	num  uint8
+	is64 bool
+}
+
+// parseReg parses a register operand: x0-x30, w0-w30, xzr, wzr, or sp.
+func parseReg(operand string) (register, error) {
+	s := strings.ToLower(strings.TrimSpace(operand))
+	switch s {
+	case "xzr":
+		return register{num: 31, is64: true}, nil
+	case "wzr":
+		return register{num: 31, is64: false}, nil
+	case "sp":
+		return register{num: 31, is64: true}, nil
+	}
+
+	if len(s) < 2 || (s[0] != 'x' && s[0] != 'w') {
+		return register{}, fmt.Errorf("expected register, got %q", operand)
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil || n < 0 || n > 30 {
+		return register{}, fmt.Errorf("expected register, got %q", operand)
+	}
+
+	return register{num: uint8(n), is64: s[0] == 'x'}, nil
+}
+
+// memOperand is a parsed "[Xn, #imm]" load/store addressing operand.
+// This package only supports the unsigned-immediate form; [Xn] with no
+// offset is accepted as a shorthand for offset 0.
+type memOperand struct {
+	base register
+	imm  int64
+}
+
+func parseMemOperand(operand string) (memOperand, error) {
+	s := strings.TrimSpace(operand)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return memOperand{}, fmt.Errorf("expected memory operand, got %q", operand)
+	}
+	inner := s[1 : len(s)-1]
+
+	parts := strings.SplitN(inner, ",", 2)
+	base, err := parseReg(parts[0])
+	if err != nil {
+		return memOperand{}, err
+	}
+
+	mem := memOperand{base: base}
+	if len(parts) == 2 {
+		imm, err := parseImm(parts[1])
+		if err != nil {
+			return memOperand{}, err
+		}
+		mem.imm = imm
+	}
+
+	return mem, nil
+}
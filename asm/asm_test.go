@@ -0,0 +1,124 @@
+package asm_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/asm"
+	"github.com/sarchlab/m2sim/disasm"
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Assemble", func() {
+	It("should encode LDR (64-bit, unsigned immediate)", func() {
+		code, _, err := asm.Assemble("ldr x0, [x1, #8]")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal([]byte{0x20, 0x04, 0x40, 0xF9}))
+	})
+
+	It("should encode STR (32-bit, unsigned immediate)", func() {
+		code, _, err := asm.Assemble("str w2, [x1, #12]")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal([]byte{0x22, 0x0C, 0x00, 0xB9}))
+	})
+
+	It("should round-trip through disasm.Decode", func() {
+		code, _, err := asm.Assemble("ldr x0, [sp, #8]")
+		Expect(err).NotTo(HaveOccurred())
+
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("ldr x0, [sp, #8]"))
+	})
+
+	It("should resolve a branch to its own label to a zero PC-relative offset", func() {
+		code, symbols, err := asm.Assemble("loop:\n  b loop\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect((*symbols)["loop"]).To(Equal(uint64(0)))
+
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+		Expect(inst.Imm).To(Equal(int64(0)))
+	})
+
+	It("should resolve a forward branch label to a positive PC-relative offset", func() {
+		src := "b target\nmov x0, #1\ntarget:\n  ret\n"
+		code, symbols, err := asm.Assemble(src)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect((*symbols)["target"]).To(Equal(uint64(8)))
+
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+		Expect(inst.Op).To(Equal("b"))
+		Expect(inst.Imm).To(Equal(int64(8)))
+	})
+
+	It("should encode a conditional branch", func() {
+		code, _, err := asm.Assemble("b.eq label\nlabel:\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+		Expect(inst.Op).To(Equal("b.eq"))
+	})
+
+	It("should encode SVC #0", func() {
+		code, _, err := asm.Assemble("svc #0")
+
+		Expect(err).NotTo(HaveOccurred())
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+		Expect(inst.Op).To(Equal("svc"))
+	})
+
+	It("should encode RET with the default X30", func() {
+		code, _, err := asm.Assemble("ret")
+
+		Expect(err).NotTo(HaveOccurred())
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+		Expect(inst.Op).To(Equal("ret"))
+		Expect(inst.Rn).To(Equal(uint8(30)))
+	})
+
+	It("should reject an unknown mnemonic", func() {
+		_, _, err := asm.Assemble("frobnicate x0, x1")
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	// This mirrors the motivating use case: writing a load/store test as
+	// an assembly string, decoding its fields with the same decode path
+	// disasm uses, then driving LoadStoreUnit with those fields instead
+	// of hand-picking register numbers and immediates.
+	It("should drive a real LoadStoreUnit through an assembled and decoded instruction", func() {
+		code, _, err := asm.Assemble("ldr x0, [x1, #16]")
+		Expect(err).NotTo(HaveOccurred())
+
+		word := uint32(code[0]) | uint32(code[1])<<8 | uint32(code[2])<<16 | uint32(code[3])<<24
+		inst, ok := disasm.Decode(word)
+		Expect(ok).To(BeTrue())
+
+		regFile := &emu.RegFile{}
+		memory := emu.NewMemory()
+		memory.MapAnonymous(0x1000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE)
+		memory.Write64(0x1000+16, 0xFEEDFACE)
+		regFile.WriteReg(inst.Rn, 0x1000)
+
+		lsu := emu.NewLoadStoreUnit(regFile, memory)
+		lsu.LDR64(inst.Rd, inst.Rn, uint64(inst.Imm))
+
+		Expect(regFile.ReadReg(inst.Rd)).To(Equal(uint64(0xFEEDFACE)))
+	})
+})
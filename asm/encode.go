@@ -0,0 +1,432 @@
+package asm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Load/store register (unsigned immediate) encoding: size(31:30) 111001
+// opc(23:22) imm12(21:10) Rn(9:5) Rt(4:0). Values are spelled out from
+// the ARM Architecture Reference Manual's encoding diagrams, the same
+// convention disasm/arm64.go uses.
+const (
+	ldstBase = 0x39000000
+
+	ldstSize8  = 0x00000000
+	ldstSize16 = 0x40000000
+	ldstSize32 = 0x80000000
+	ldstSize64 = 0xC0000000
+
+	ldstOpcStore   = 0x00000000
+	ldstOpcLoadU   = 0x00400000 // unsigned load (LDR/LDRB/LDRH)
+	ldstOpcLoadS64 = 0x00800000 // signed load into a 64-bit Xt
+	ldstOpcLoadS32 = 0x00C00000 // signed load into a 32-bit Wt
+)
+
+// Move wide immediate (MOVN/MOVZ/MOVK): sf(31) opc(30:29) 100101 hw(22:21)
+// imm16(20:5) Rd(4:0).
+const (
+	movzBase64 = 0xD2800000
+	movzBase32 = 0x52800000
+	movkBase64 = 0xF2800000
+	movkBase32 = 0x72800000
+)
+
+// ADD/SUB (immediate): sf(31) op(30) S(29)=0 100010 sh(22) imm12(21:10)
+// Rn(9:5) Rd(4:0).
+const (
+	addBase64 = 0x91000000
+	addBase32 = 0x11000000
+	subBase64 = 0xD1000000
+	subBase32 = 0x51000000
+)
+
+// Unconditional/conditional branch and RET encodings, as disasm/arm64.go
+// documents for its decode side.
+const (
+	bBase     = 0x14000000
+	blBase    = 0x94000000
+	bcondBase = 0x54000000
+	retBase   = 0xD65F0000
+	svcBase   = 0xD4000001
+)
+
+// condNames are the 16 AArch64 condition suffixes in architectural
+// encoding order, matching disasm.condNames and emu.ConditionCode.
+var condNames = [16]string{
+	"eq", "ne", "cs", "cc", "mi", "pl", "vs", "vc",
+	"hi", "ls", "ge", "lt", "gt", "le", "al", "nv",
+}
+
+// encode dispatches stmt to the encoder for its mnemonic.
+func encode(stmt statement, pc uint64, symbols SymbolTable) (uint32, error) {
+	mnemonic := stmt.mnemonic
+	ops := stmt.operands
+
+	if strings.HasPrefix(mnemonic, "b.") {
+		return encodeBCond(mnemonic[2:], ops, pc, symbols)
+	}
+
+	switch mnemonic {
+	case "ldr":
+		return encodeLdr(ops)
+	case "str":
+		return encodeStr(ops)
+	case "ldrb":
+		return encodeLdrSub(ops, ldstSize8, false)
+	case "strb":
+		return encodeStrSub(ops, ldstSize8)
+	case "ldrh":
+		return encodeLdrSub(ops, ldstSize16, false)
+	case "strh":
+		return encodeStrSub(ops, ldstSize16)
+	case "ldrsb":
+		return encodeLdrSub(ops, ldstSize8, true)
+	case "ldrsh":
+		return encodeLdrSub(ops, ldstSize16, true)
+	case "ldrsw":
+		return encodeLdrsw(ops)
+	case "mov":
+		return encodeMov(ops)
+	case "movz":
+		return encodeMoveWide(ops, movzBase64, movzBase32)
+	case "movk":
+		return encodeMoveWide(ops, movkBase64, movkBase32)
+	case "add":
+		return encodeAddSub(ops, addBase64, addBase32)
+	case "sub":
+		return encodeAddSub(ops, subBase64, subBase32)
+	case "b":
+		return encodeBranch(ops, pc, symbols, bBase, 26)
+	case "bl":
+		return encodeBranch(ops, pc, symbols, blBase, 26)
+	case "svc":
+		return encodeSvc(ops)
+	case "ret":
+		return encodeRet(ops)
+	default:
+		return 0, fmt.Errorf("unsupported mnemonic %q", stmt.mnemonic)
+	}
+}
+
+func wantOperands(ops []string, n int) error {
+	if len(ops) != n {
+		return fmt.Errorf("expected %d operands, got %d", n, len(ops))
+	}
+	return nil
+}
+
+// encodeLdr encodes "ldr Xt/Wt, [Xn {, #imm}]", scaling the unsigned
+// immediate by the access size (8 bytes for Xt, 4 for Wt).
+func encodeLdr(ops []string) (uint32, error) {
+	return encodeUnsignedLdst(ops, ldstOpcLoadU)
+}
+
+func encodeStr(ops []string) (uint32, error) {
+	return encodeUnsignedLdst(ops, ldstOpcStore)
+}
+
+// encodeUnsignedLdst handles the LDR/STR Xt/Wt forms, whose size (and
+// immediate scale) is taken from the transfer register's width.
+func encodeUnsignedLdst(ops []string, opc uint32) (uint32, error) {
+	if err := wantOperands(ops, 2); err != nil {
+		return 0, err
+	}
+	rt, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	mem, err := parseMemOperand(ops[1])
+	if err != nil {
+		return 0, err
+	}
+
+	size := uint32(ldstSize32)
+	scale := int64(4)
+	if rt.is64 {
+		size = ldstSize64
+		scale = 8
+	}
+
+	return ldstWord(size, opc, mem, rt.num, scale)
+}
+
+// encodeLdrSub encodes LDRB/STRB/LDRH/STRH-shaped byte/halfword loads.
+// signed selects LDRSB/LDRSH (whose dest width picks the opc between the
+// 64-bit and 32-bit signed-load variants) over the plain unsigned load.
+func encodeLdrSub(ops []string, size uint32, signed bool) (uint32, error) {
+	if err := wantOperands(ops, 2); err != nil {
+		return 0, err
+	}
+	rt, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	mem, err := parseMemOperand(ops[1])
+	if err != nil {
+		return 0, err
+	}
+
+	opc := uint32(ldstOpcLoadU)
+	if signed {
+		opc = ldstOpcLoadS32
+		if rt.is64 {
+			opc = ldstOpcLoadS64
+		}
+	}
+
+	return ldstWord(size, opc, mem, rt.num, 1)
+}
+
+func encodeStrSub(ops []string, size uint32) (uint32, error) {
+	if err := wantOperands(ops, 2); err != nil {
+		return 0, err
+	}
+	rt, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	mem, err := parseMemOperand(ops[1])
+	if err != nil {
+		return 0, err
+	}
+
+	return ldstWord(size, ldstOpcStore, mem, rt.num, 1)
+}
+
+// encodeLdrsw encodes "ldrsw Xt, [Xn {, #imm}]" — LDRSW only ever
+// sign-extends a 32-bit load into a 64-bit destination.
+func encodeLdrsw(ops []string) (uint32, error) {
+	if err := wantOperands(ops, 2); err != nil {
+		return 0, err
+	}
+	rt, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	if !rt.is64 {
+		return 0, fmt.Errorf("ldrsw requires a 64-bit destination register")
+	}
+	mem, err := parseMemOperand(ops[1])
+	if err != nil {
+		return 0, err
+	}
+
+	return ldstWord(ldstSize32, ldstOpcLoadS64, mem, rt.num, 4)
+}
+
+// ldstWord assembles the common load/store-unsigned-immediate shape:
+// size | opc | scaled imm12 | Rn | Rt. The byte offset in mem.imm must be
+// non-negative and a multiple of scale.
+func ldstWord(size, opc uint32, mem memOperand, rt uint8, scale int64) (uint32, error) {
+	if mem.imm < 0 || mem.imm%scale != 0 {
+		return 0, fmt.Errorf("offset #%d is not a non-negative multiple of %d", mem.imm, scale)
+	}
+	imm12 := mem.imm / scale
+	if imm12 > 0xFFF {
+		return 0, fmt.Errorf("offset #%d out of range", mem.imm)
+	}
+
+	word := uint32(ldstBase) | size | opc |
+		(uint32(imm12)&0xFFF)<<10 | uint32(mem.base.num)<<5 | uint32(rt)
+	return word, nil
+}
+
+// encodeMov encodes "mov Xd, Xm" (register move, the ORR Xd, XZR, Xm
+// alias) or "mov Xd, #imm" (the MOVZ Xd, #imm, LSL #0 alias).
+func encodeMov(ops []string) (uint32, error) {
+	if err := wantOperands(ops, 2); err != nil {
+		return 0, err
+	}
+	rd, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+
+	if rm, err := parseReg(ops[1]); err == nil {
+		base := uint32(0xAA0003E0)
+		if !rd.is64 {
+			base = 0x2A0003E0
+		}
+		return base | uint32(rm.num)<<16 | uint32(rd.num), nil
+	}
+
+	return encodeMoveWide(ops, movzBase64, movzBase32)
+}
+
+// encodeMoveWide encodes MOVZ/MOVK's "Rd, #imm16 {, lsl #shift}" shape,
+// where shift must be a multiple of 16 (0/16 for Wd, 0/16/32/48 for Xd).
+func encodeMoveWide(ops []string, base64, base32 uint32) (uint32, error) {
+	if len(ops) != 2 && len(ops) != 3 {
+		return 0, fmt.Errorf("expected 2 or 3 operands, got %d", len(ops))
+	}
+	rd, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	imm, err := parseImm(ops[1])
+	if err != nil {
+		return 0, err
+	}
+	if imm < 0 || imm > 0xFFFF {
+		return 0, fmt.Errorf("immediate #%d out of range for a 16-bit move", imm)
+	}
+
+	var shift int64
+	if len(ops) == 3 {
+		shift, err = parseShift(ops[2])
+		if err != nil {
+			return 0, err
+		}
+	}
+	if shift%16 != 0 || shift < 0 || shift > 48 || (!rd.is64 && shift > 16) {
+		return 0, fmt.Errorf("invalid shift #%d for a %s register", shift, widthName(rd.is64))
+	}
+	hw := uint32(shift / 16)
+
+	base := uint32(base32)
+	if rd.is64 {
+		base = base64
+	}
+
+	return base | hw<<21 | (uint32(imm)&0xFFFF)<<5 | uint32(rd.num), nil
+}
+
+// encodeAddSub encodes ADD/SUB's "Rd, Rn, #imm {, lsl #12}" immediate
+// form.
+func encodeAddSub(ops []string, base64, base32 uint32) (uint32, error) {
+	if len(ops) != 3 && len(ops) != 4 {
+		return 0, fmt.Errorf("expected 3 or 4 operands, got %d", len(ops))
+	}
+	rd, err := parseReg(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	rn, err := parseReg(ops[1])
+	if err != nil {
+		return 0, err
+	}
+	imm, err := parseImm(ops[2])
+	if err != nil {
+		return 0, err
+	}
+	if imm < 0 || imm > 0xFFF {
+		return 0, fmt.Errorf("immediate #%d out of range for a 12-bit add/sub", imm)
+	}
+
+	var shiftBit uint32
+	if len(ops) == 4 {
+		shift, err := parseShift(ops[3])
+		if err != nil {
+			return 0, err
+		}
+		if shift != 12 {
+			return 0, fmt.Errorf("add/sub immediate only supports \"lsl #12\", got #%d", shift)
+		}
+		shiftBit = 1
+	}
+
+	base := uint32(base32)
+	if rd.is64 {
+		base = base64
+	}
+
+	return base | shiftBit<<22 | (uint32(imm)&0xFFF)<<10 | uint32(rn.num)<<5 | uint32(rd.num), nil
+}
+
+// encodeBranch encodes B/BL's "label" operand as a PC-relative imm26.
+func encodeBranch(ops []string, pc uint64, symbols SymbolTable, base uint32, bits uint) (uint32, error) {
+	if err := wantOperands(ops, 1); err != nil {
+		return 0, err
+	}
+	imm, err := branchOffset(ops[0], pc, symbols, bits)
+	if err != nil {
+		return 0, err
+	}
+	return base | imm, nil
+}
+
+// encodeBCond encodes "b.<cond> label" as a PC-relative imm19.
+func encodeBCond(cond string, ops []string, pc uint64, symbols SymbolTable) (uint32, error) {
+	if err := wantOperands(ops, 1); err != nil {
+		return 0, err
+	}
+	idx := condIndex(cond)
+	if idx < 0 {
+		return 0, fmt.Errorf("unknown condition %q", cond)
+	}
+	imm, err := branchOffset(ops[0], pc, symbols, 19)
+	if err != nil {
+		return 0, err
+	}
+	return bcondBase | (imm&0x7FFFF)<<5 | uint32(idx), nil
+}
+
+// branchOffset resolves label against symbols and returns the
+// PC-relative word offset, masked to bits wide.
+func branchOffset(label string, pc uint64, symbols SymbolTable, bits uint) (uint32, error) {
+	target, ok := symbols[label]
+	if !ok {
+		return 0, fmt.Errorf("undefined label %q", label)
+	}
+
+	offset := int64(target) - int64(pc)
+	if offset%4 != 0 {
+		return 0, fmt.Errorf("branch target %q is not instruction-aligned", label)
+	}
+	words := offset / 4
+
+	limit := int64(1) << (bits - 1)
+	if words < -limit || words >= limit {
+		return 0, fmt.Errorf("branch target %q is out of range", label)
+	}
+
+	mask := uint32(1)<<bits - 1
+	return uint32(words) & mask, nil
+}
+
+func condIndex(cond string) int {
+	for i, name := range condNames {
+		if name == cond {
+			return i
+		}
+	}
+	return -1
+}
+
+func encodeSvc(ops []string) (uint32, error) {
+	if err := wantOperands(ops, 1); err != nil {
+		return 0, err
+	}
+	imm, err := parseImm(ops[0])
+	if err != nil {
+		return 0, err
+	}
+	if imm < 0 || imm > 0xFFFF {
+		return 0, fmt.Errorf("svc immediate #%d out of range", imm)
+	}
+	return svcBase | (uint32(imm)&0xFFFF)<<5, nil
+}
+
+// encodeRet encodes "ret" (defaults to X30, the link register) or
+// "ret Xn".
+func encodeRet(ops []string) (uint32, error) {
+	rn := register{num: 30, is64: true}
+	if len(ops) == 1 {
+		var err error
+		rn, err = parseReg(ops[0])
+		if err != nil {
+			return 0, err
+		}
+	} else if len(ops) != 0 {
+		return 0, fmt.Errorf("expected 0 or 1 operands, got %d", len(ops))
+	}
+	return retBase | uint32(rn.num)<<5, nil
+}
+
+func widthName(is64 bool) string {
+	if is64 {
+		return "64-bit"
+	}
+	return "32-bit"
+}
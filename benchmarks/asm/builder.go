@@ -0,0 +1,145 @@
+// Package asm is a small assembler-builder for the hand-encoded AArch64
+// instruction streams benchmarks build in []uint32 form. Before this
+// package, a loop or function call meant counting instruction bytes by
+// hand to get EncodeB/EncodeBCond/EncodeBL's offsets right; Builder
+// lets a benchmark instead write Label/Branch/Loop/Call/Function and
+// have forward and backward offsets resolved automatically when the
+// program is built.
+//
+// Builder only owns control flow (branches, calls, and the counter
+// arithmetic a counted Loop needs); everything else — ALU ops, loads,
+// stores, the exit syscall — is encoded by the caller (e.g. with
+// benchmarks.EncodeADDImm) and handed to Emit as an already-encoded
+// instruction word, the same as a BuildProgram call today.
+package asm
+
+import "fmt"
+
+// Condition codes a Branch or Loop back-edge can test, matching the
+// AArch64 condition field values already used throughout
+// benchmarks/microbenchmarks.go (e.g. CondNE = 1, CondLT = 11).
+const (
+	CondEQ = 0
+	CondNE = 1
+	CondGE = 10
+	CondLT = 11
+
+	// CondAL tells Branch to emit an unconditional B instead of a
+	// conditional B.cond.
+	CondAL = -1
+)
+
+type branchKind int
+
+const (
+	branchUnconditional branchKind = iota
+	branchConditional
+	branchLink
+)
+
+type fixup struct {
+	index int
+	label string
+	kind  branchKind
+	cond  int
+}
+
+// Builder assembles a []uint32 instruction stream incrementally,
+// resolving Branch/Call/Loop targets against Label positions once the
+// whole program has been emitted.
+type Builder struct {
+	instrs  []uint32
+	labels  map[string]int
+	fixups  []fixup
+	loopSeq int
+}
+
+// NewBuilder returns an empty Builder ready for Emit/Label/Branch/Loop/
+// Call/Function calls.
+func NewBuilder() *Builder {
+	return &Builder{labels: make(map[string]int)}
+}
+
+// Emit appends an already-encoded instruction word, e.g. the result of
+// benchmarks.EncodeADDImm or benchmarks.EncodeLDR64.
+func (b *Builder) Emit(instr uint32) {
+	b.instrs = append(b.instrs, instr)
+}
+
+// Label marks the current instruction position under name, for a later
+// Branch or Call to resolve its offset against.
+func (b *Builder) Label(name string) {
+	b.labels[name] = len(b.instrs)
+}
+
+// Branch emits a branch to label: an unconditional B if cond is CondAL,
+// otherwise a conditional B.cond. label may be defined earlier
+// (backward branch) or later (forward branch) in the same Builder; its
+// offset is resolved when Build is called.
+func (b *Builder) Branch(label string, cond int) {
+	kind := branchConditional
+	if cond == CondAL {
+		kind = branchUnconditional
+	}
+	b.fixups = append(b.fixups, fixup{index: len(b.instrs), label: label, kind: kind, cond: cond})
+	b.instrs = append(b.instrs, 0) // patched by Build once labels are resolved
+}
+
+// Call emits a BL to funcLabel, resolved the same way as Branch.
+func (b *Builder) Call(funcLabel string) {
+	b.fixups = append(b.fixups, fixup{index: len(b.instrs), label: funcLabel, kind: branchLink})
+	b.instrs = append(b.instrs, 0)
+}
+
+// Function marks label at the current position and emits body, for
+// Call sites to branch into. It does not emit a trailing return; body
+// is expected to end with its own RET (e.g.
+// b.Emit(benchmarks.EncodeRET())).
+func (b *Builder) Function(label string, body func(*Builder)) {
+	b.Label(label)
+	body(b)
+}
+
+// Loop emits a counted loop: body is emitted once, followed by a
+// footer that increments counterReg, compares it against iters, and
+// branches back to the top of body while counterReg < iters. Callers
+// are expected to have initialized counterReg to 0 beforehand (e.g. in
+// a Benchmark's Setup), the same way loopSimulation's unrolled form
+// initialized X1 to 0.
+func (b *Builder) Loop(counterReg uint8, iters int64, body func(*Builder)) {
+	b.loopSeq++
+	label := fmt.Sprintf("__loop%d", b.loopSeq)
+
+	b.Label(label)
+	body(b)
+	b.Emit(encodeADDImm(counterReg, counterReg, 1))
+	b.Emit(encodeCMPImm(counterReg, iters))
+	b.Branch(label, CondLT)
+}
+
+// Build resolves every Branch/Call fixup against its label's position
+// and returns the finished instruction stream. It panics if a fixup
+// references a label that was never defined with Label — a programmer
+// error in the calling benchmark, not a runtime condition.
+func (b *Builder) Build() []uint32 {
+	out := make([]uint32, len(b.instrs))
+	copy(out, b.instrs)
+
+	for _, f := range b.fixups {
+		target, ok := b.labels[f.label]
+		if !ok {
+			panic(fmt.Sprintf("asm: undefined label %q", f.label))
+		}
+		offset := int32(target-f.index) * 4
+
+		switch f.kind {
+		case branchLink:
+			out[f.index] = encodeBL(offset)
+		case branchConditional:
+			out[f.index] = encodeBCond(offset, uint8(f.cond))
+		default:
+			out[f.index] = encodeB(offset)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package asm
+
+import "testing"
+
+func TestBranchResolvesForwardOffset(t *testing.T) {
+	b := NewBuilder()
+	b.Emit(0xAAAA0000)
+	b.Branch("skip", CondAL)
+	b.Emit(0xBBBB0000) // skipped
+	b.Label("skip")
+	b.Emit(0xCCCC0000)
+
+	prog := b.Build()
+	if len(prog) != 4 {
+		t.Fatalf("len(prog) = %d, want 4", len(prog))
+	}
+
+	want := encodeB(8) // skip over one instruction = 2*4 bytes
+	if prog[1] != want {
+		t.Errorf("prog[1] = %#x, want %#x", prog[1], want)
+	}
+}
+
+func TestBranchResolvesBackwardOffset(t *testing.T) {
+	b := NewBuilder()
+	b.Label("top")
+	b.Emit(0xAAAA0000)
+	b.Branch("top", CondNE)
+
+	prog := b.Build()
+	want := encodeBCond(-4, CondNE)
+	if prog[1] != want {
+		t.Errorf("prog[1] = %#x, want %#x", prog[1], want)
+	}
+}
+
+func TestCallResolvesToFunctionLabel(t *testing.T) {
+	b := NewBuilder()
+	b.Emit(0xAAAA0000)
+	b.Call("fn")
+	b.Function("fn", func(b *Builder) {
+		b.Emit(0xCCCC0000)
+	})
+
+	prog := b.Build()
+	want := encodeBL(4)
+	if prog[1] != want {
+		t.Errorf("prog[1] = %#x, want %#x", prog[1], want)
+	}
+}
+
+func TestLoopEmitsBodyOnceWithIncrementAndBackBranch(t *testing.T) {
+	b := NewBuilder()
+	var bodyCalls int
+	b.Loop(1, 10, func(b *Builder) {
+		bodyCalls++
+		b.Emit(0xDDDD0000)
+	})
+
+	if bodyCalls != 1 {
+		t.Fatalf("body called %d times, want 1 (Loop only assembles the body once)", bodyCalls)
+	}
+
+	prog := b.Build()
+	if len(prog) != 4 {
+		t.Fatalf("len(prog) = %d, want 4 (body + increment + compare + branch)", len(prog))
+	}
+	if prog[0] != 0xDDDD0000 {
+		t.Errorf("prog[0] = %#x, want body instruction", prog[0])
+	}
+
+	wantBranch := encodeBCond(-12, CondLT)
+	if prog[3] != wantBranch {
+		t.Errorf("prog[3] = %#x, want %#x", prog[3], wantBranch)
+	}
+}
+
+func TestBuildPanicsOnUndefinedLabel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Build did not panic on an undefined label")
+		}
+	}()
+
+	b := NewBuilder()
+	b.Branch("nowhere", CondAL)
+	b.Build()
+}
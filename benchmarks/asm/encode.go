@@ -0,0 +1,51 @@
+package asm
+
+// encodeB and encodeBCond/encodeBL mirror the bit layout of
+// benchmarks.EncodeB: they exist here, rather than being imported from
+// benchmarks, only so this package doesn't import its own importer
+// (benchmarks uses asm.Builder to assemble loopSimulation and
+// matrixMultiply2x2). encodeADDImm/encodeCMPImm exist for the same
+// reason, to let Loop generate its counter increment and comparison
+// without depending on benchmarks.
+
+// encodeB encodes an unconditional branch: B offset.
+func encodeB(offset int32) uint32 {
+	var inst uint32 = 0b000101 << 26
+	inst |= uint32(offset/4) & 0x3FFFFFF
+	return inst
+}
+
+// encodeBCond encodes a conditional branch: B.cond offset.
+func encodeBCond(offset int32, cond uint8) uint32 {
+	var inst uint32 = 0b01010100 << 24
+	inst |= (uint32(offset/4) & 0x7FFFF) << 5
+	inst |= uint32(cond) & 0xF
+	return inst
+}
+
+// encodeBL encodes a branch-with-link: BL offset.
+func encodeBL(offset int32) uint32 {
+	var inst uint32 = 0b100101 << 26
+	inst |= uint32(offset/4) & 0x3FFFFFF
+	return inst
+}
+
+// encodeADDImm encodes ADD Xd, Xn, #imm.
+func encodeADDImm(rd, rn uint8, imm uint16) uint32 {
+	var inst uint32 = 0b1001000100 << 22
+	inst |= (uint32(imm) & 0xFFF) << 10
+	inst |= uint32(rn&0x1F) << 5
+	inst |= uint32(rd & 0x1F)
+	return inst
+}
+
+// encodeCMPImm encodes CMP Xn, #imm — an alias for SUBS XZR, Xn, #imm
+// (sets flags, discards the result), matching how EncodeCMPReg aliases
+// EncodeSUBReg elsewhere in this repo.
+func encodeCMPImm(rn uint8, imm int64) uint32 {
+	var inst uint32 = 0b1111000100 << 22
+	inst |= (uint32(imm) & 0xFFF) << 10
+	inst |= uint32(rn&0x1F) << 5
+	inst |= 0b11111 // Xd = XZR, discard result
+	return inst
+}
@@ -0,0 +1,228 @@
+package benchmarks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// ComparisonStatus classifies how a benchmark's baseline and current-run
+// results line up, mirroring the categorization roachprod-microbench uses
+// when two reports don't cover the same benchmark set: a benchmark can
+// appear in both, only in the baseline (the current run dropped it, timed
+// out, or errored), or only in the current run (it's new since the
+// baseline was captured).
+type ComparisonStatus string
+
+// The three ComparisonStatus values a BenchmarkComparison can hold.
+const (
+	StatusMatched ComparisonStatus = "matched"
+	StatusNew     ComparisonStatus = "new"
+	StatusDropped ComparisonStatus = "dropped/timeout/error"
+)
+
+// BenchmarkComparison is one benchmark's baseline-vs-current delta. For a
+// StatusNew or StatusDropped entry, only the side that's actually present
+// is populated; the deltas are left at their zero value since there's
+// nothing to diff against.
+type BenchmarkComparison struct {
+	Name   string
+	Status ComparisonStatus
+
+	Baseline Result
+	Current  Result
+
+	DeltaCycles           int64
+	DeltaCPI              float64
+	DeltaRAWHazard        int64
+	DeltaStructuralHazard int64
+	DeltaExec             int64
+	DeltaMem              int64
+	DeltaBranchMispred    int64
+	DeltaFlushes          int64
+	DeltaFetch            int64
+}
+
+// LoadBaseline reads a JSON results file previously produced by
+// Harness.PrintJSON, for use as the -baseline comparison point.
+func LoadBaseline(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarks: reading baseline %q: %w", path, err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("benchmarks: parsing baseline %q: %w", path, err)
+	}
+	return results, nil
+}
+
+// CompareResults pairs up baseline and current results by name and
+// computes their per-benchmark deltas. Benchmarks present in only one of
+// the two runs are reported as StatusNew or StatusDropped rather than
+// silently skipped, so a regression hidden by a benchmark falling out of
+// the suite doesn't go unnoticed. The returned slice is sorted by name so
+// text/csv/json output is stable across runs.
+func CompareResults(baseline, current []Result) []BenchmarkComparison {
+	baselineByName := make(map[string]Result, len(baseline))
+	for _, b := range baseline {
+		baselineByName[b.Name] = b
+	}
+	currentByName := make(map[string]Result, len(current))
+	for _, c := range current {
+		currentByName[c.Name] = c
+	}
+
+	names := make(map[string]struct{}, len(baseline)+len(current))
+	for _, b := range baseline {
+		names[b.Name] = struct{}{}
+	}
+	for _, c := range current {
+		names[c.Name] = struct{}{}
+	}
+
+	comparisons := make([]BenchmarkComparison, 0, len(names))
+	for name := range names {
+		b, hasBaseline := baselineByName[name]
+		c, hasCurrent := currentByName[name]
+
+		switch {
+		case hasBaseline && hasCurrent:
+			comparisons = append(comparisons, BenchmarkComparison{
+				Name:                  name,
+				Status:                StatusMatched,
+				Baseline:              b,
+				Current:               c,
+				DeltaCycles:           int64(c.SimulatedCycles) - int64(b.SimulatedCycles),
+				DeltaCPI:              c.CPI - b.CPI,
+				DeltaRAWHazard:        int64(c.RAWHazardStalls) - int64(b.RAWHazardStalls),
+				DeltaStructuralHazard: int64(c.StructuralHazardStalls) - int64(b.StructuralHazardStalls),
+				DeltaExec:             int64(c.ExecStalls) - int64(b.ExecStalls),
+				DeltaMem:              int64(c.MemStalls) - int64(b.MemStalls),
+				DeltaBranchMispred:    int64(c.BranchMispredictionStalls) - int64(b.BranchMispredictionStalls),
+				DeltaFlushes:          int64(c.PipelineFlushes) - int64(b.PipelineFlushes),
+				DeltaFetch:            int64(c.StallCycles) - int64(b.StallCycles),
+			})
+		case hasCurrent:
+			comparisons = append(comparisons, BenchmarkComparison{Name: name, Status: StatusNew, Current: c})
+		default:
+			comparisons = append(comparisons, BenchmarkComparison{Name: name, Status: StatusDropped, Baseline: b})
+		}
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Name < comparisons[j].Name })
+	return comparisons
+}
+
+// GeomeanSpeedup returns the geometric mean of baseline-CPI/current-CPI
+// across matched benchmarks: greater than 1 means the current run is
+// faster on average. Benchmarks that aren't StatusMatched, or whose CPI
+// is zero on either side, are excluded since they don't contribute a
+// meaningful ratio.
+func GeomeanSpeedup(comparisons []BenchmarkComparison) float64 {
+	logSum := 0.0
+	n := 0
+	for _, c := range comparisons {
+		if c.Status != StatusMatched || c.Baseline.CPI <= 0 || c.Current.CPI <= 0 {
+			continue
+		}
+		logSum += math.Log(c.Baseline.CPI / c.Current.CPI)
+		n++
+	}
+	if n == 0 {
+		return 1.0
+	}
+	return math.Exp(logSum / float64(n))
+}
+
+// AnyRegression reports whether any matched benchmark's CPI regressed
+// (increased) by more than thresholdPct percent relative to its baseline,
+// along with the names of the offending benchmarks, for -fail-on-regress
+// to act on.
+func AnyRegression(comparisons []BenchmarkComparison, thresholdPct float64) (bool, []string) {
+	var regressed []string
+	for _, c := range comparisons {
+		if c.Status != StatusMatched || c.Baseline.CPI <= 0 {
+			continue
+		}
+		pctChange := (c.Current.CPI - c.Baseline.CPI) / c.Baseline.CPI * 100
+		if pctChange > thresholdPct {
+			regressed = append(regressed, c.Name)
+		}
+	}
+	return len(regressed) > 0, regressed
+}
+
+// WriteComparisonText writes a human-readable diff of comparisons to w,
+// followed by the geomean CPI speedup across matched benchmarks.
+func WriteComparisonText(w io.Writer, comparisons []BenchmarkComparison) error {
+	for _, c := range comparisons {
+		switch c.Status {
+		case StatusMatched:
+			if _, err := fmt.Fprintf(w, "%-30s %-8s  dCycles=%+d  dCPI=%+.4f  (RAW=%+d Struct=%+d Exec=%+d Mem=%+d BrMispred=%+d Flush=%+d Fetch=%+d)\n",
+				c.Name, c.Status, c.DeltaCycles, c.DeltaCPI,
+				c.DeltaRAWHazard, c.DeltaStructuralHazard, c.DeltaExec, c.DeltaMem, c.DeltaBranchMispred, c.DeltaFlushes, c.DeltaFetch); err != nil {
+				return err
+			}
+		default:
+			if _, err := fmt.Fprintf(w, "%-30s %-8s\n", c.Name, c.Status); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nGeomean CPI speedup: %.4fx\n", GeomeanSpeedup(comparisons))
+	return err
+}
+
+// WriteComparisonCSV writes comparisons to w as CSV, one row per
+// benchmark, with a header row naming every column.
+func WriteComparisonCSV(w io.Writer, comparisons []BenchmarkComparison) error {
+	cw := csv.NewWriter(w)
+	header := []string{"name", "status", "delta_cycles", "delta_cpi", "delta_raw_hazard", "delta_structural_hazard", "delta_exec", "delta_mem", "delta_branch_mispred", "delta_flushes", "delta_fetch"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, c := range comparisons {
+		row := []string{
+			c.Name,
+			string(c.Status),
+			fmt.Sprintf("%d", c.DeltaCycles),
+			fmt.Sprintf("%.6f", c.DeltaCPI),
+			fmt.Sprintf("%d", c.DeltaRAWHazard),
+			fmt.Sprintf("%d", c.DeltaStructuralHazard),
+			fmt.Sprintf("%d", c.DeltaExec),
+			fmt.Sprintf("%d", c.DeltaMem),
+			fmt.Sprintf("%d", c.DeltaBranchMispred),
+			fmt.Sprintf("%d", c.DeltaFlushes),
+			fmt.Sprintf("%d", c.DeltaFetch),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// comparisonReport is the JSON envelope WriteComparisonJSON emits: the
+// per-benchmark comparisons plus the summary geomean speedup, so a CI
+// consumer doesn't have to recompute it from the raw deltas.
+type comparisonReport struct {
+	Comparisons    []BenchmarkComparison `json:"comparisons"`
+	GeomeanSpeedup float64               `json:"geomean_speedup"`
+}
+
+// WriteComparisonJSON writes comparisons to w as a single JSON object
+// containing the per-benchmark comparisons and the overall geomean
+// speedup.
+func WriteComparisonJSON(w io.Writer, comparisons []BenchmarkComparison) error {
+	report := comparisonReport{Comparisons: comparisons, GeomeanSpeedup: GeomeanSpeedup(comparisons)}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,134 @@
+package benchmarks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareResultsClassifiesAndDiffs(t *testing.T) {
+	baseline := []Result{
+		{Name: "loop", ExitCode: 0, SimulatedCycles: 1000, CPI: 1.5, RAWHazardStalls: 10},
+		{Name: "matrix", ExitCode: 0, SimulatedCycles: 2000, CPI: 2.0},
+	}
+	current := []Result{
+		{Name: "loop", ExitCode: 0, SimulatedCycles: 900, CPI: 1.2, RAWHazardStalls: 8},
+		{Name: "branch", ExitCode: 0, SimulatedCycles: 500, CPI: 1.0},
+	}
+
+	comparisons := CompareResults(baseline, current)
+	if len(comparisons) != 3 {
+		t.Fatalf("len(comparisons) = %d, want 3", len(comparisons))
+	}
+
+	byName := make(map[string]BenchmarkComparison, len(comparisons))
+	for _, c := range comparisons {
+		byName[c.Name] = c
+	}
+
+	loop := byName["loop"]
+	if loop.Status != StatusMatched {
+		t.Fatalf("loop.Status = %q, want %q", loop.Status, StatusMatched)
+	}
+	if loop.DeltaCycles != -100 {
+		t.Errorf("loop.DeltaCycles = %d, want -100", loop.DeltaCycles)
+	}
+	if loop.DeltaRAWHazard != -2 {
+		t.Errorf("loop.DeltaRAWHazard = %d, want -2", loop.DeltaRAWHazard)
+	}
+
+	if byName["matrix"].Status != StatusDropped {
+		t.Errorf("matrix.Status = %q, want %q", byName["matrix"].Status, StatusDropped)
+	}
+	if byName["branch"].Status != StatusNew {
+		t.Errorf("branch.Status = %q, want %q", byName["branch"].Status, StatusNew)
+	}
+}
+
+func TestGeomeanSpeedupOnlyCountsMatchedBenchmarks(t *testing.T) {
+	comparisons := []BenchmarkComparison{
+		{Status: StatusMatched, Baseline: Result{CPI: 2.0}, Current: Result{CPI: 1.0}},
+		{Status: StatusMatched, Baseline: Result{CPI: 2.0}, Current: Result{CPI: 1.0}},
+		{Status: StatusNew, Current: Result{CPI: 100}},
+	}
+
+	got := GeomeanSpeedup(comparisons)
+	if got < 1.99 || got > 2.01 {
+		t.Fatalf("GeomeanSpeedup = %v, want ~2.0", got)
+	}
+}
+
+func TestAnyRegressionFlagsOnlyBenchmarksPastThreshold(t *testing.T) {
+	comparisons := []BenchmarkComparison{
+		{Name: "loop", Status: StatusMatched, Baseline: Result{CPI: 1.0}, Current: Result{CPI: 1.01}},
+		{Name: "matrix", Status: StatusMatched, Baseline: Result{CPI: 1.0}, Current: Result{CPI: 1.10}},
+	}
+
+	regressed, names := AnyRegression(comparisons, 5)
+	if !regressed {
+		t.Fatal("AnyRegression = false, want true")
+	}
+	if len(names) != 1 || names[0] != "matrix" {
+		t.Fatalf("names = %v, want [matrix]", names)
+	}
+
+	regressed, _ = AnyRegression(comparisons, 50)
+	if regressed {
+		t.Fatal("AnyRegression = true with a generous threshold, want false")
+	}
+}
+
+func TestLoadBaselineRoundTripsJSON(t *testing.T) {
+	want := []Result{{Name: "loop", ExitCode: 0, SimulatedCycles: 42, CPI: 1.0}}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	got, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "loop" || got[0].SimulatedCycles != 42 {
+		t.Fatalf("LoadBaseline = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteComparisonFormats(t *testing.T) {
+	comparisons := CompareResults(
+		[]Result{{Name: "loop", CPI: 2.0, SimulatedCycles: 1000}},
+		[]Result{{Name: "loop", CPI: 1.0, SimulatedCycles: 500}},
+	)
+
+	var text bytes.Buffer
+	if err := WriteComparisonText(&text, comparisons); err != nil {
+		t.Fatalf("WriteComparisonText: %v", err)
+	}
+	if !strings.Contains(text.String(), "loop") || !strings.Contains(text.String(), "Geomean") {
+		t.Errorf("text output missing expected content: %q", text.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteComparisonCSV(&csvOut, comparisons); err != nil {
+		t.Fatalf("WriteComparisonCSV: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "loop") {
+		t.Errorf("csv output missing benchmark name: %q", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteComparisonJSON(&jsonOut, comparisons); err != nil {
+		t.Fatalf("WriteComparisonJSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), "geomean_speedup") {
+		t.Errorf("json output missing geomean_speedup: %q", jsonOut.String())
+	}
+}
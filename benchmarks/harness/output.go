@@ -0,0 +1,53 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteStatsText writes a human-readable table of results to w, one
+// line per benchmark.
+func WriteStatsText(w io.Writer, results []StatResult) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w,
+			"%-30s n=%-3d  cycles: mean=%.1f median=%.1f stddev=%.1f min=%d max=%d ci95=[%.1f, %.1f]  ipc=%.4f\n",
+			r.Name, r.Samples, r.MeanCycles, r.MedianCycles, r.StdDevCycles,
+			r.MinCycles, r.MaxCycles, r.CI95Low, r.CI95High, r.IPC); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteStatsJSON writes results to w as a JSON array.
+func WriteStatsJSON(w io.Writer, results []StatResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// WriteSizeSweepText writes a human-readable report of results to w:
+// one header line per benchmark giving the fitted slope/intercept,
+// followed by one indented line per measured size.
+func WriteSizeSweepText(w io.Writer, results []SizeSweepResult) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "%s: %.4f cycles/instruction, %.1f cycles fixed overhead\n",
+			r.Name, r.SlopeCyclesPerInstruction, r.InterceptCycles); err != nil {
+			return err
+		}
+		for _, p := range r.Points {
+			if _, err := fmt.Fprintln(w, sizeSweepPointString(p)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteSizeSweepJSON writes results to w as a JSON array.
+func WriteSizeSweepJSON(w io.Writer, results []SizeSweepResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
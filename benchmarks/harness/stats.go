@@ -0,0 +1,181 @@
+// Package harness runs a benchmarks.Benchmark with the repetition
+// discipline real hardware measurement needs: a warmup period to let
+// the pipeline reach steady state, enough repeats to report a
+// confidence interval instead of a single number, and outlier
+// trimming. It also supports size sweeps — running a benchmark at
+// several problem sizes and fitting the steady-state
+// cycles-per-added-instruction slope by linear regression — the way
+// real M2 CPI is measured, instead of reading a fixed-size benchmark's
+// absolute cycle count and hoping pipeline fill/drain washed out.
+package harness
+
+import (
+	"math"
+	"sort"
+
+	"github.com/sarchlab/m2sim/benchmarks"
+)
+
+// Config controls how Run and RunSizeSweep repeat and analyze a
+// Benchmark.
+type Config struct {
+	// Warmup is the number of runs executed and discarded before
+	// measurement begins, letting cache/predictor state reach
+	// steady-state before a sample is kept.
+	Warmup int
+	// Repeats is the number of measured runs kept after Warmup.
+	Repeats int
+	// TrimFraction is the fraction of Repeats trimmed from each tail
+	// of the sorted cycle-count samples before computing statistics,
+	// discarding the most extreme outliers. 0.1 trims the top and
+	// bottom 10%.
+	TrimFraction float64
+	// Base is the benchmarks.Config each repetition runs under (cache
+	// enablement, PolyBench size, and so on).
+	Base benchmarks.Config
+}
+
+// DefaultConfig returns a Config with 2 warmup runs, 10 measured
+// repeats, 10% outlier trimming per tail, and benchmarks.DefaultConfig
+// as the base harness configuration.
+func DefaultConfig() Config {
+	return Config{
+		Warmup:       2,
+		Repeats:      10,
+		TrimFraction: 0.1,
+		Base:         benchmarks.DefaultConfig(),
+	}
+}
+
+// StatResult is one benchmark's cycle and IPC statistics across
+// Config.Repeats measured runs, after discarding warmup runs and
+// trimming outliers.
+type StatResult struct {
+	Name    string `json:"name"`
+	Samples int    `json:"samples"`
+
+	MeanCycles   float64 `json:"mean_cycles"`
+	MedianCycles float64 `json:"median_cycles"`
+	StdDevCycles float64 `json:"stddev_cycles"`
+	MinCycles    uint64  `json:"min_cycles"`
+	MaxCycles    uint64  `json:"max_cycles"`
+	IPC          float64 `json:"ipc"`
+
+	// CI95Low/CI95High bound a 95% confidence interval for the mean
+	// cycle count, using the normal approximation mean ± 1.96 *
+	// stddev/sqrt(n) rather than a t-distribution, since Repeats is a
+	// user-chosen constant, not something worth a statistics
+	// dependency over.
+	CI95Low  float64 `json:"ci95_low"`
+	CI95High float64 `json:"ci95_high"`
+}
+
+// Run executes b Warmup+Repeats times under cfg, discards the warmup
+// runs, trims TrimFraction outliers from each tail of the remaining
+// cycle-count samples, and returns the resulting StatResult.
+func Run(b benchmarks.Benchmark, cfg Config) StatResult {
+	samples := make([]uint64, 0, cfg.Repeats)
+	var instructions uint64
+
+	for i := 0; i < cfg.Warmup+cfg.Repeats; i++ {
+		h := benchmarks.NewHarness(cfg.Base)
+		h.AddBenchmark(b)
+		r := h.RunAll()[0]
+
+		if i < cfg.Warmup {
+			continue
+		}
+		samples = append(samples, r.SimulatedCycles)
+		instructions = r.InstructionsRetired
+	}
+
+	return summarize(b.Name, samples, instructions, cfg.TrimFraction)
+}
+
+// RunAll runs Run over every benchmark in benchmarkList, in order.
+func RunAll(benchmarkList []benchmarks.Benchmark, cfg Config) []StatResult {
+	results := make([]StatResult, len(benchmarkList))
+	for i, b := range benchmarkList {
+		results[i] = Run(b, cfg)
+	}
+	return results
+}
+
+func summarize(name string, samples []uint64, instructions uint64, trimFraction float64) StatResult {
+	if len(samples) == 0 {
+		return StatResult{Name: name}
+	}
+
+	sorted := make([]uint64, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	trimmed := trim(sorted, trimFraction)
+
+	floats := make([]float64, len(trimmed))
+	for i, s := range trimmed {
+		floats[i] = float64(s)
+	}
+
+	mean := meanOf(floats)
+	stddev := stdDevOf(floats, mean)
+	n := float64(len(floats))
+	margin := 1.96 * stddev / math.Sqrt(n)
+
+	var ipc float64
+	if mean > 0 {
+		ipc = float64(instructions) / mean
+	}
+
+	return StatResult{
+		Name:         name,
+		Samples:      len(trimmed),
+		MeanCycles:   mean,
+		MedianCycles: medianOf(floats),
+		StdDevCycles: stddev,
+		MinCycles:    trimmed[0],
+		MaxCycles:    trimmed[len(trimmed)-1],
+		IPC:          ipc,
+		CI95Low:      mean - margin,
+		CI95High:     mean + margin,
+	}
+}
+
+// trim discards the most extreme trimFraction of sorted (ascending)
+// samples from each tail, keeping at least one sample.
+func trim(sorted []uint64, trimFraction float64) []uint64 {
+	n := int(float64(len(sorted)) * trimFraction)
+	if len(sorted)-2*n < 1 {
+		return sorted
+	}
+	return sorted[n : len(sorted)-n]
+}
+
+func meanOf(samples []float64) float64 {
+	sum := 0.0
+	for _, s := range samples {
+		sum += s
+	}
+	return sum / float64(len(samples))
+}
+
+func medianOf(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func stdDevOf(samples []float64, mean float64) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	variance := 0.0
+	for _, s := range samples {
+		d := s - mean
+		variance += d * d
+	}
+	variance /= float64(len(samples))
+	return math.Sqrt(variance)
+}
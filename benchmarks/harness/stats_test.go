@@ -0,0 +1,101 @@
+package harness
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTrimDiscardsOutliersFromEachTail(t *testing.T) {
+	sorted := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	trimmed := trim(sorted, 0.1)
+	if len(trimmed) != 8 {
+		t.Fatalf("len(trimmed) = %d, want 8", len(trimmed))
+	}
+	if trimmed[0] != 2 || trimmed[len(trimmed)-1] != 9 {
+		t.Errorf("trimmed = %v, want [2..9]", trimmed)
+	}
+}
+
+func TestTrimKeepsAtLeastOneSample(t *testing.T) {
+	sorted := []uint64{1, 2}
+	trimmed := trim(sorted, 0.5)
+	if len(trimmed) != 2 {
+		t.Errorf("len(trimmed) = %d, want 2 (trimming would leave nothing)", len(trimmed))
+	}
+}
+
+func TestSummarizeComputesMeanMedianStdDevAndCI(t *testing.T) {
+	samples := []uint64{100, 100, 100, 100, 100}
+	r := summarize("bench", samples, 400, 0)
+
+	if r.MeanCycles != 100 {
+		t.Errorf("MeanCycles = %v, want 100", r.MeanCycles)
+	}
+	if r.MedianCycles != 100 {
+		t.Errorf("MedianCycles = %v, want 100", r.MedianCycles)
+	}
+	if r.StdDevCycles != 0 {
+		t.Errorf("StdDevCycles = %v, want 0 (identical samples)", r.StdDevCycles)
+	}
+	if r.IPC != 4 {
+		t.Errorf("IPC = %v, want 4 (400 instructions / 100 cycles)", r.IPC)
+	}
+	if r.CI95Low != 100 || r.CI95High != 100 {
+		t.Errorf("CI95 = [%v, %v], want [100, 100] (zero variance)", r.CI95Low, r.CI95High)
+	}
+}
+
+func TestSummarizeEmptySamples(t *testing.T) {
+	r := summarize("bench", nil, 0, 0.1)
+	if r.Name != "bench" || r.Samples != 0 {
+		t.Errorf("summarize(nil) = %+v, want zero-value stats with Name set", r)
+	}
+}
+
+func TestLinearRegressionFitsExactLine(t *testing.T) {
+	// cycles = 2*instructions + 10, exactly.
+	xs := []float64{10, 20, 30, 40}
+	ys := []float64{30, 50, 70, 90}
+
+	slope, intercept := linearRegression(xs, ys)
+	if math.Abs(slope-2) > 1e-9 {
+		t.Errorf("slope = %v, want 2", slope)
+	}
+	if math.Abs(intercept-10) > 1e-9 {
+		t.Errorf("intercept = %v, want 10", intercept)
+	}
+}
+
+func TestLinearRegressionSinglePointReturnsItsY(t *testing.T) {
+	slope, intercept := linearRegression([]float64{5}, []float64{42})
+	if slope != 0 {
+		t.Errorf("slope = %v, want 0 for a single point", slope)
+	}
+	if intercept != 42 {
+		t.Errorf("intercept = %v, want 42", intercept)
+	}
+}
+
+func TestWriteStatsFormats(t *testing.T) {
+	results := []StatResult{
+		{Name: "loop", Samples: 8, MeanCycles: 100, MedianCycles: 100, IPC: 2},
+	}
+
+	var text bytes.Buffer
+	if err := WriteStatsText(&text, results); err != nil {
+		t.Fatalf("WriteStatsText: %v", err)
+	}
+	if !strings.Contains(text.String(), "loop") {
+		t.Errorf("text output missing benchmark name: %q", text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteStatsJSON(&jsonOut, results); err != nil {
+		t.Fatalf("WriteStatsJSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), "\"mean_cycles\"") {
+		t.Errorf("json output missing mean_cycles field: %q", jsonOut.String())
+	}
+}
@@ -0,0 +1,121 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sarchlab/m2sim/benchmarks"
+)
+
+// SizeSweepPoint is one measured (size, cycles) pair from a size sweep,
+// itself averaged over Config.Repeats runs at that size.
+type SizeSweepPoint struct {
+	Size         int     `json:"size"`
+	Cycles       float64 `json:"cycles"`
+	Instructions uint64  `json:"instructions"`
+}
+
+// SizeSweepResult is a benchmark's measured cycle count at each of its
+// Sizes, plus the steady-state slope and intercept a linear regression
+// over those points fits: SlopeCyclesPerInstruction is the marginal
+// cost of one added instruction once pipeline fill/drain is amortized
+// away, and InterceptCycles is that fixed fill/drain overhead itself.
+type SizeSweepResult struct {
+	Name                      string           `json:"name"`
+	Points                    []SizeSweepPoint `json:"points"`
+	SlopeCyclesPerInstruction float64          `json:"slope_cycles_per_instruction"`
+	InterceptCycles           float64          `json:"intercept_cycles"`
+}
+
+// ErrNoSizeSweep is returned by RunSizeSweep when b doesn't define a
+// size sweep (b.Sizes is empty or b.Build is nil).
+var ErrNoSizeSweep = errors.New("harness: benchmark has no Sizes/Build")
+
+// RunSizeSweep runs b once per entry in b.Sizes, substituting
+// b.Build(size) for b.Program each time, averaging Config.Repeats
+// measured runs per size the same way Run does, and fits a linear
+// regression of cycles against instructions retired across the
+// resulting points.
+func RunSizeSweep(b benchmarks.Benchmark, cfg Config) (SizeSweepResult, error) {
+	if len(b.Sizes) == 0 || b.Build == nil {
+		return SizeSweepResult{}, ErrNoSizeSweep
+	}
+
+	points := make([]SizeSweepPoint, len(b.Sizes))
+	for i, size := range b.Sizes {
+		sized := b
+		sized.Program = b.Build(size)
+
+		stat := Run(sized, cfg)
+		points[i] = SizeSweepPoint{
+			Size:         size,
+			Cycles:       stat.MeanCycles,
+			Instructions: uint64(stat.IPC * stat.MeanCycles),
+		}
+	}
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = float64(p.Instructions)
+		ys[i] = p.Cycles
+	}
+	slope, intercept := linearRegression(xs, ys)
+
+	return SizeSweepResult{
+		Name:                      b.Name,
+		Points:                    points,
+		SlopeCyclesPerInstruction: slope,
+		InterceptCycles:           intercept,
+	}, nil
+}
+
+// RunSizeSweeps runs RunSizeSweep over every benchmark in
+// benchmarkList that defines one, silently skipping those that don't
+// (most benchmarks are fixed-size and were never meant to be swept).
+func RunSizeSweeps(benchmarkList []benchmarks.Benchmark, cfg Config) []SizeSweepResult {
+	var results []SizeSweepResult
+	for _, b := range benchmarkList {
+		r, err := RunSizeSweep(b, cfg)
+		if err != nil {
+			continue
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// linearRegression fits y = slope*x + intercept by ordinary least
+// squares. It returns (0, ys[0]) for fewer than two points, since a
+// slope isn't meaningful over a single sample.
+func linearRegression(xs, ys []float64) (slope, intercept float64) {
+	n := float64(len(xs))
+	if n < 2 {
+		if len(ys) == 1 {
+			return 0, ys[0]
+		}
+		return 0, 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, sumY / n
+	}
+
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
+
+// sizeSweepPointString formats one SizeSweepPoint for WriteSizeSweepText.
+func sizeSweepPointString(p SizeSweepPoint) string {
+	return fmt.Sprintf("    size=%-6d instructions=%-8d cycles=%.1f", p.Size, p.Instructions, p.Cycles)
+}
@@ -0,0 +1,37 @@
+package harness
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSizeSweepFormats(t *testing.T) {
+	results := []SizeSweepResult{
+		{
+			Name:                      "dependency_chain",
+			SlopeCyclesPerInstruction: 1.02,
+			InterceptCycles:           12,
+			Points: []SizeSweepPoint{
+				{Size: 50, Cycles: 63, Instructions: 51},
+				{Size: 200, Cycles: 216, Instructions: 201},
+			},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := WriteSizeSweepText(&text, results); err != nil {
+		t.Fatalf("WriteSizeSweepText: %v", err)
+	}
+	if !strings.Contains(text.String(), "dependency_chain") || !strings.Contains(text.String(), "size=50") {
+		t.Errorf("text output missing expected content: %q", text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteSizeSweepJSON(&jsonOut, results); err != nil {
+		t.Fatalf("WriteSizeSweepJSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), "\"slope_cycles_per_instruction\"") {
+		t.Errorf("json output missing slope field: %q", jsonOut.String())
+	}
+}
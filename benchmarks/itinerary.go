@@ -0,0 +1,11 @@
+package benchmarks
+
+import "github.com/sarchlab/m2sim/emu/sched"
+
+// LoadItinerary loads a scheduling itinerary YAML file (see
+// emu/sched.LoadItinerary and configs/m2_pcore.yaml) for use alongside a
+// Benchmark run, mirroring LoadBaseline's role of loading an external
+// JSON file the harness doesn't produce itself.
+func LoadItinerary(path string) (*sched.Itinerary, error) {
+	return sched.LoadItinerary(path)
+}
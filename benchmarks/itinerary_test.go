@@ -0,0 +1,27 @@
+package benchmarks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadItineraryDelegatesToSched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "itinerary.yaml")
+	yaml := "issue_width: 4\nresources:\n  - class: ALU0\n    count: 1\n    latency: 1\ninstructions:\n  ADD:\n    num_micro_ops: 1\n    issue_stages: [ALU0]\n    operand_latency: [1]\n    forwarding_class: bypass\n"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	it, err := LoadItinerary(path)
+	if err != nil {
+		t.Fatalf("LoadItinerary: %v", err)
+	}
+	if it.IssueWidth != 4 {
+		t.Errorf("IssueWidth = %d, want 4", it.IssueWidth)
+	}
+
+	if _, ok := it.Lookup("ADD"); !ok {
+		t.Error("Lookup(\"ADD\") missing, want present")
+	}
+}
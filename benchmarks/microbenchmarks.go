@@ -1,7 +1,12 @@
 // Package benchmarks provides timing benchmark infrastructure for M2Sim calibration.
 package benchmarks
 
-import "github.com/sarchlab/m2sim/emu"
+import (
+	"fmt"
+
+	"github.com/sarchlab/m2sim/benchmarks/asm"
+	"github.com/sarchlab/m2sim/emu"
+)
 
 // GetMicrobenchmarks returns the standard set of microbenchmarks for M2 calibration.
 // Each benchmark targets a specific CPU characteristic.
@@ -10,7 +15,7 @@ import "github.com/sarchlab/m2sim/emu"
 // branchTakenConditional benchmark uses CMP + B.GE to match native benchmarks.
 // Other benchmarks still use unrolled code for simplicity.
 func GetMicrobenchmarks() []Benchmark {
-	return []Benchmark{
+	all := []Benchmark{
 		arithmeticSequential(),
 		arithmetic6Wide(),
 		arithmetic8Wide(),
@@ -37,6 +42,12 @@ func GetMicrobenchmarks() []Benchmark {
 		reductionTree(),
 		strideIndirect(),
 	}
+	all = append(all, branchAlignmentSweepBenchmarks()...)
+	all = append(all, memcmpExpandedBenchmarks()...)
+	all = append(all, literalPoolHeavy())
+	all = append(all, spillHeavy())
+	all = append(all, structEqualityChainBenchmarks()...)
+	return all
 }
 
 // GetCoreBenchmarks returns a minimal set of 3 core benchmarks for quick validation.
@@ -190,6 +201,12 @@ func dependencyChain() Benchmark {
 		},
 		Program:      buildDependencyChain(200),
 		ExpectedExit: 200, // X0 = 0 + 200*1 = 200
+		// Sizes/Build let a harness.RunSizeSweep fit this benchmark's
+		// steady-state cycles-per-instruction slope instead of reading
+		// its absolute cycle count at n=200, amortizing pipeline
+		// fill/drain the way real M2 CPI is measured.
+		Sizes: []int{50, 100, 150, 200},
+		Build: buildDependencyChain,
 	}
 }
 
@@ -361,6 +378,57 @@ func branchHotLoop() Benchmark {
 	}
 }
 
+// branchAlignmentSweepBytes are the fetch-group alignments (in bytes) swept
+// by branchAlignmentSweepBenchmarks, chosen to match the 16/32/64-byte
+// fetch-group sizes BOLT-style layout tools typically align branch targets
+// to.
+var branchAlignmentSweepBytes = []int{16, 32, 64}
+
+// 5d. Branch Alignment Sweep - Tests fetch-group alignment sensitivity
+// Emits the same branchHotLoop body, preceded by a run of NOP padding so the
+// loop head lands at a different offset modulo the swept alignment. Compare
+// the reported cycle counts across the sweep (via -align-branch-targets-to
+// and -fetch-group-bytes) to calibrate the front end's fetch-group model
+// against native measurements.
+func branchAlignmentSweepBenchmarks() []Benchmark {
+	benchmarks := make([]Benchmark, len(branchAlignmentSweepBytes))
+	for i, align := range branchAlignmentSweepBytes {
+		benchmarks[i] = branchAlignmentSweep(align)
+	}
+	return benchmarks
+}
+
+func branchAlignmentSweep(align int) Benchmark {
+	nopWords := align / 4
+	return Benchmark{
+		Name:        fmt.Sprintf("branch_alignment_sweep_%d", align),
+		Description: fmt.Sprintf("branch_hot_loop padded with %d bytes of leading NOPs - measures fetch-group alignment sensitivity", align),
+		Setup: func(regFile *emu.RegFile, memory *emu.Memory) {
+			regFile.WriteReg(8, 93) // X8 = 93 (exit syscall)
+			regFile.WriteReg(0, 4)  // X0 = 4 (loop counter, matches branchHotLoop)
+		},
+		Program:      buildBranchAlignmentSweep(nopWords),
+		ExpectedExit: 0,
+	}
+}
+
+func buildBranchAlignmentSweep(nopWords int) []byte {
+	instrs := make([]uint32, 0, nopWords+4)
+	for i := 0; i < nopWords; i++ {
+		instrs = append(instrs, EncodeNOP())
+	}
+	instrs = append(instrs,
+		// loop:
+		EncodeSUBImm(0, 0, 1, false), // X0 = X0 - 1
+		EncodeCMPImm(0, 0),           // CMP X0, #0
+		EncodeBCond(-8, 1),           // B.NE loop (-8 bytes = -2 instructions), CondNE = 1
+
+		// exit: After loop, X0 = 0
+		EncodeSVC(0), // exit with X0 = 0
+	)
+	return BuildProgram(instrs...)
+}
+
 // 6. Mixed Operations - Combination of ALU, memory, and branches
 func mixedOperations() Benchmark {
 	return Benchmark{
@@ -418,7 +486,47 @@ func EncodeB(offset int32) uint32 {
 // 7. Matrix Operations - Tests computation with memory access pattern
 // Loads values from memory, performs computations, stores results
 // Note: Uses ADD instead of MUL since scalar MUL isn't implemented yet
+//
+// Built on asm.Builder rather than a raw BuildProgram call: there's no
+// branch here to hand-count an offset for, but going through the
+// builder keeps this benchmark in the same style as loopSimulation
+// below, so a later parameterized version (a proper triple loop over
+// an NxN array) is a matter of wrapping these Emit calls in b.Loop
+// rather than rewriting the benchmark from scratch.
 func matrixMultiply2x2() Benchmark {
+	b := asm.NewBuilder()
+
+	// Load A array into X10-X13
+	b.Emit(EncodeLDR64(10, 1, 0)) // X10 = A[0] = 10
+	b.Emit(EncodeLDR64(11, 1, 1)) // X11 = A[1] = 20
+	b.Emit(EncodeLDR64(12, 1, 2)) // X12 = A[2] = 30
+	b.Emit(EncodeLDR64(13, 1, 3)) // X13 = A[3] = 40
+
+	// Load B array into X14-X17
+	b.Emit(EncodeLDR64(14, 2, 0)) // X14 = B[0] = 1
+	b.Emit(EncodeLDR64(15, 2, 1)) // X15 = B[1] = 2
+	b.Emit(EncodeLDR64(16, 2, 2)) // X16 = B[2] = 3
+	b.Emit(EncodeLDR64(17, 2, 3)) // X17 = B[3] = 4
+
+	// Compute C[i] = A[i] + B[i]
+	b.Emit(EncodeADDReg(20, 10, 14, false)) // X20 = 10 + 1 = 11
+	b.Emit(EncodeADDReg(21, 11, 15, false)) // X21 = 20 + 2 = 22
+	b.Emit(EncodeADDReg(22, 12, 16, false)) // X22 = 30 + 3 = 33
+	b.Emit(EncodeADDReg(23, 13, 17, false)) // X23 = 40 + 4 = 44
+
+	// Store C array
+	b.Emit(EncodeSTR64(20, 3, 0)) // C[0] = 11
+	b.Emit(EncodeSTR64(21, 3, 1)) // C[1] = 22
+	b.Emit(EncodeSTR64(22, 3, 2)) // C[2] = 33
+	b.Emit(EncodeSTR64(23, 3, 3)) // C[3] = 44
+
+	// Sum all C elements for exit code: 11 + 22 + 33 + 44 = 110
+	b.Emit(EncodeADDReg(0, 20, 21, false)) // X0 = 11 + 22 = 33
+	b.Emit(EncodeADDReg(0, 0, 22, false))  // X0 = 33 + 33 = 66
+	b.Emit(EncodeADDReg(0, 0, 23, false))  // X0 = 66 + 44 = 110
+
+	b.Emit(EncodeSVC(0))
+
 	return Benchmark{
 		Name:        "matrix_operations",
 		Description: "Matrix-style load/compute/store pattern - tests memory access",
@@ -444,98 +552,32 @@ func matrixMultiply2x2() Benchmark {
 		// Compute C[i] = A[i] + B[i] for i = 0..3
 		// C = [11, 22, 33, 44]
 		// Return sum of C = 11 + 22 + 33 + 44 = 110
-		Program: BuildProgram(
-			// Load A array into X10-X13
-			EncodeLDR64(10, 1, 0), // X10 = A[0] = 10
-			EncodeLDR64(11, 1, 1), // X11 = A[1] = 20
-			EncodeLDR64(12, 1, 2), // X12 = A[2] = 30
-			EncodeLDR64(13, 1, 3), // X13 = A[3] = 40
-
-			// Load B array into X14-X17
-			EncodeLDR64(14, 2, 0), // X14 = B[0] = 1
-			EncodeLDR64(15, 2, 1), // X15 = B[1] = 2
-			EncodeLDR64(16, 2, 2), // X16 = B[2] = 3
-			EncodeLDR64(17, 2, 3), // X17 = B[3] = 4
-
-			// Compute C[i] = A[i] + B[i]
-			EncodeADDReg(20, 10, 14, false), // X20 = 10 + 1 = 11
-			EncodeADDReg(21, 11, 15, false), // X21 = 20 + 2 = 22
-			EncodeADDReg(22, 12, 16, false), // X22 = 30 + 3 = 33
-			EncodeADDReg(23, 13, 17, false), // X23 = 40 + 4 = 44
-
-			// Store C array
-			EncodeSTR64(20, 3, 0), // C[0] = 11
-			EncodeSTR64(21, 3, 1), // C[1] = 22
-			EncodeSTR64(22, 3, 2), // C[2] = 33
-			EncodeSTR64(23, 3, 3), // C[3] = 44
-
-			// Sum all C elements for exit code: 11 + 22 + 33 + 44 = 110
-			EncodeADDReg(0, 20, 21, false), // X0 = 11 + 22 = 33
-			EncodeADDReg(0, 0, 22, false),  // X0 = 33 + 33 = 66
-			EncodeADDReg(0, 0, 23, false),  // X0 = 66 + 44 = 110
-
-			EncodeSVC(0),
-		),
+		Program:      BuildProgram(b.Build()...),
 		ExpectedExit: 110,
 	}
 }
 
-// 8. Loop Simulation - Simulates a counted loop (unrolled)
-// This is what a "for i := 0; i < 10; i++" loop would look like
+// 8. Loop Simulation - A real counted loop with a back-edge branch,
+// built with asm.Builder instead of hand-unrolling the body 10 times
+// and counting instruction bytes for the backward branch offset.
 func loopSimulation() Benchmark {
+	b := asm.NewBuilder()
+	b.Loop(1, 10, func(b *asm.Builder) {
+		b.Emit(EncodeADDReg(0, 0, 1, false)) // sum += i
+	})
+	b.Emit(EncodeSVC(0))
+
 	return Benchmark{
 		Name:        "loop_simulation",
-		Description: "Simulated 10-iteration loop (unrolled) - tests loop-like patterns",
+		Description: "Counted 10-iteration loop (real back-edge branch) - tests loop-like patterns",
 		Setup: func(regFile *emu.RegFile, memory *emu.Memory) {
 			regFile.WriteReg(8, 93) // X8 = 93 (exit syscall)
 			regFile.WriteReg(0, 0)  // X0 = sum = 0
 			regFile.WriteReg(1, 0)  // X1 = i = 0
 		},
-		// Simulate: for i := 0; i < 10; i++ { sum += i }
-		// Result: 0 + 1 + 2 + 3 + 4 + 5 + 6 + 7 + 8 + 9 = 45
-		Program: BuildProgram(
-			// Iteration 0: sum += 0, i++
-			EncodeADDReg(0, 0, 1, false), // sum += i
-			EncodeADDImm(1, 1, 1, false), // i++
-
-			// Iteration 1: sum += 1, i++
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 2
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 3
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 4
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 5
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 6
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 7
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 8
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			// Iteration 9
-			EncodeADDReg(0, 0, 1, false),
-			EncodeADDImm(1, 1, 1, false),
-
-			EncodeSVC(0),
-		),
+		// for i := 0; i < 10; i++ { sum += i }
+		// Result: 0 + 1 + 2 + ... + 9 = 45
+		Program:      BuildProgram(b.Build()...),
 		ExpectedExit: 45,
 	}
 }
@@ -781,6 +823,11 @@ func loadHeavyScaled() Benchmark {
 		},
 		Program:      buildLoadHeavyScaled(numLoads),
 		ExpectedExit: int64(numLoads),
+		// See dependencyChain's Sizes/Build comment: this lets
+		// harness.RunSizeSweep isolate load throughput's steady-state
+		// slope from cold-miss and pipeline fill/drain overhead.
+		Sizes: []int{50, 100, 150, 200},
+		Build: buildLoadHeavyScaled,
 	}
 }
 
@@ -1114,3 +1161,346 @@ func EncodeCMPReg(rn, rm uint8) uint32 {
 }
 
 // Note: encodeMUL removed - scalar MUL/MADD not yet implemented in simulator
+
+// 17. memcmp Expansion - Tests ILP recovery in LLVM's ExpandMemCmp code shape
+//
+// Instead of a byte-by-byte compare loop, ExpandMemCmp widens a fixed-size
+// memcmp into N/16 pairs of 128-bit LDP loads from each buffer, XORs the
+// corresponding halves, OR-reduces every XOR result into one register, and
+// finishes with a single CMP/CSET instead of branching out early. With no
+// early exit, every LDP pair is independent of every other, so this is a
+// stress test for how many in-flight loads the simulated load unit and
+// reduction scheduling can actually overlap.
+var memcmpExpandedSizes = []int{16, 64, 256}
+
+func memcmpExpandedBenchmarks() []Benchmark {
+	var benchmarks []Benchmark
+	for _, n := range memcmpExpandedSizes {
+		benchmarks = append(benchmarks,
+			memcmpExpanded(n, "equal"),
+			memcmpExpanded(n, "first_diff"),
+			memcmpExpanded(n, "last_diff"),
+		)
+	}
+	return benchmarks
+}
+
+// memcmpExpanded builds one ExpandMemCmp-shaped comparison of two n-byte
+// buffers. variant controls which word (if any) differs between the
+// buffers: "equal" makes every word match, "first_diff" breaks the first
+// 8-byte word, and "last_diff" breaks the last one, so the harness can
+// measure whether an early-differing word changes scheduling behavior even
+// though this code shape never branches out early.
+func memcmpExpanded(n int, variant string) Benchmark {
+	const bufA = 0x8000
+	const bufB = 0x9000
+	numWords := n / 8
+
+	return Benchmark{
+		Name:        fmt.Sprintf("memcmp_expanded_%d_%s", n, variant),
+		Description: fmt.Sprintf("%d-byte ExpandMemCmp-style LDP/XOR/ORR compare (%s) - measures load/reduction ILP", n, variant),
+		Setup: func(regFile *emu.RegFile, memory *emu.Memory) {
+			regFile.WriteReg(8, 93)   // X8 = 93 (exit syscall)
+			regFile.WriteReg(1, bufA) // X1 = buffer A base
+			regFile.WriteReg(2, bufB) // X2 = buffer B base
+
+			for i := 0; i < numWords; i++ {
+				word := uint64(i + 1)
+				memory.Write64(bufA+uint64(i)*8, word)
+				memory.Write64(bufB+uint64(i)*8, word)
+			}
+			switch variant {
+			case "first_diff":
+				memory.Write64(bufB, memory.Read64(bufB)+1)
+			case "last_diff":
+				last := bufB + uint64(numWords-1)*8
+				memory.Write64(last, memory.Read64(last)+1)
+			}
+		},
+		Program: buildMemcmpExpanded(numWords),
+		// CSET NE yields 1 when any word differed, 0 when every word matched.
+		ExpectedExit: map[string]int64{"equal": 0, "first_diff": 1, "last_diff": 1}[variant],
+	}
+}
+
+// buildMemcmpExpanded emits numWords/2 LDP pairs from each buffer (X1, X2),
+// XORs each pair's halves together, OR-reduces every XOR result into X5,
+// and finishes with CMP X5, #0 + CSET X0, NE.
+func buildMemcmpExpanded(numWords int) []byte {
+	numPairs := numWords / 2
+	instrs := make([]uint32, 0, numPairs*6+4)
+
+	instrs = append(instrs, EncodeADDImm(5, 31, 0, false)) // X5 = 0 (OR-reduction accumulator)
+	for p := 0; p < numPairs; p++ {
+		instrs = append(instrs,
+			EncodeLDP64(6, 7, 1, p), // X6,X7 = A[2p], A[2p+1]
+			EncodeLDP64(10, 11, 2, p), // X10,X11 = B[2p], B[2p+1] (X8 keeps the exit syscall number)
+			EncodeEORReg(6, 6, 10),    // X6 = A[2p] ^ B[2p]
+			EncodeEORReg(7, 7, 11),    // X7 = A[2p+1] ^ B[2p+1]
+			EncodeORRReg(5, 5, 6),   // X5 |= X6
+			EncodeORRReg(5, 5, 7),   // X5 |= X7
+		)
+	}
+	instrs = append(instrs,
+		EncodeCMPImm(5, 0), // CMP X5, #0
+		EncodeCSET(0, 1),   // X0 = (X5 != 0) ? 1 : 0, CondNE = 1
+		EncodeSVC(0),
+	)
+	return BuildProgram(instrs...)
+}
+
+// 18. Literal Pool Heavy - Tests fetch-side pressure from Constant Island code
+//
+// Some 64-bit immediates don't fit the two MOVZ/MOVK instructions we
+// otherwise use to materialize constants, so ARM back-ends fall back to
+// "LDR Xd, =const" against a literal pool placed inline in the instruction
+// stream (the Constant Island pass). This benchmark loads 32 such
+// constants via PC-relative literal loads and reduces them into X0,
+// exercising the same code shape: an instruction fetcher that has to skip
+// over "data" words sitting between code, and a load unit pulling values
+// out of what's nominally I-cache territory.
+const literalPoolHeavyCount = 32
+
+func literalPoolHeavy() Benchmark {
+	literals := make([]uint64, literalPoolHeavyCount)
+	var expected uint64
+	for i := range literals {
+		// Every 16-bit chunk is nonzero, so none of these fit a single
+		// MOVZ/MOVK pair the way a narrower constant would.
+		literals[i] = 0x1111_2222_3333_4444 ^ (uint64(i+1) * 0x9E3779B97F4A7C15)
+		expected += literals[i]
+	}
+
+	return Benchmark{
+		Name:        "literal_pool_heavy",
+		Description: "32 LDR-literal constant loads reduced into X0 - measures Constant Island fetch/load pressure",
+		Setup: func(regFile *emu.RegFile, memory *emu.Memory) {
+			regFile.WriteReg(8, 93) // X8 = 93 (exit syscall)
+			regFile.WriteReg(0, 0)  // X0 = reduction accumulator
+		},
+		Program:      buildLiteralPoolHeavy(literals),
+		ExpectedExit: int64(expected),
+	}
+}
+
+func buildLiteralPoolHeavy(literals []uint64) []byte {
+	instrs := make([]uint32, 0, len(literals)*2+1)
+	for i := range literals {
+		instrs = append(instrs,
+			EncodeLDRLiteral(1, 0),       // X1 = pool[i]; patched to the real imm19 by BuildProgramWithLiteralPool
+			EncodeADDReg(0, 0, 1, false), // X0 += X1
+		)
+	}
+	instrs = append(instrs, EncodeSVC(0))
+	return BuildProgramWithLiteralPool(instrs, literals)
+}
+
+// 19. Spill Heavy - Tests register-pressure spill/reload traffic
+//
+// Every other benchmark in this file fits comfortably in registers and
+// never touches the stack. Real code spills when a live range exceeds the
+// ~30 usable GPRs (X0-X29; X30/X31 stay reserved as LR/SP), the same
+// emergency-spill pattern wide MSA-style ops can force. spillHeavy keeps
+// spillHeavyLiveValues values live in registers, computes
+// spillHeavySpillCount more values that each depend on two of them, and -
+// since no register remains free to hold those - spills each to
+// [SP, #imm] immediately, reloads them, and reduces everything into X0.
+// This exercises memory disambiguation and store-to-load forwarding, which
+// the pure-register and streaming load/store benchmarks don't.
+const (
+	spillHeavyLiveValues = 30
+	spillHeavySpillCount = 10
+)
+
+func spillHeavy() Benchmark {
+	return Benchmark{
+		Name:        "spill_heavy",
+		Description: "40 partially-dependent values exceeding the ~30 usable GPRs - forces ~10 stack spills, tests memory disambiguation and store-to-load forwarding",
+		Setup: func(regFile *emu.RegFile, memory *emu.Memory) {
+			regFile.WriteReg(8, 93) // X8 = 93 (exit syscall)
+			regFile.SP = 0x20000    // scratch spill region
+		},
+		Program:      buildSpillHeavy(spillHeavyLiveValues, spillHeavySpillCount),
+		ExpectedExit: expectedSpillHeavyExit(spillHeavyLiveValues, spillHeavySpillCount),
+	}
+}
+
+func buildSpillHeavy(numLive, numSpill int) []byte {
+	instrs := make([]uint32, 0, numLive+numSpill*2+numLive)
+
+	// Materialize numLive independent register-resident values: Xi = i+1.
+	for i := 0; i < numLive; i++ {
+		instrs = append(instrs, EncodeADDImm(uint8(i), 31, uint16(i+1), false)) // Xi = XZR + (i+1)
+	}
+
+	// Compute numSpill additional values, each depending on two already-live
+	// registers, and spill each to [SP, #imm] via X30 (the only register not
+	// holding a live value) since nothing else is free to hold it.
+	for j := 0; j < numSpill; j++ {
+		rn := uint8(j % numLive)
+		rm := uint8((j + 1) % numLive)
+		instrs = append(instrs,
+			EncodeADDReg(30, rn, rm, false), // X30 = live[rn] + live[rm]
+			EncodeSTR64SP(30, j),            // spill[j] = X30
+		)
+	}
+
+	// Reload every spilled value and reduce everything into X0.
+	for j := 0; j < numSpill; j++ {
+		instrs = append(instrs,
+			EncodeLDR64SP(30, j),          // X30 = spill[j]
+			EncodeADDReg(0, 0, 30, false), // X0 += spill[j]
+		)
+	}
+	for i := 1; i < numLive; i++ {
+		instrs = append(instrs, EncodeADDReg(0, 0, uint8(i), false)) // X0 += live[i]
+	}
+	// X8 held live[8] above; restore the exit syscall number before SVC.
+	instrs = append(instrs, EncodeADDImm(8, 31, 93, false))
+	instrs = append(instrs, EncodeSVC(0))
+	return BuildProgram(instrs...)
+}
+
+// expectedSpillHeavyExit mirrors buildSpillHeavy's arithmetic in Go so the
+// benchmark can assert its own exit code.
+func expectedSpillHeavyExit(numLive, numSpill int) int64 {
+	live := make([]int64, numLive)
+	for i := range live {
+		live[i] = int64(i + 1)
+	}
+
+	sum := live[0]
+	for j := 0; j < numSpill; j++ {
+		sum += live[j%numLive] + live[(j+1)%numLive]
+	}
+	for i := 1; i < numLive; i++ {
+		sum += live[i]
+	}
+	return sum
+}
+
+// 20. Struct Equality - Tests merged-compare ILP against short-circuit
+// branching
+//
+// LLVM's MergeICmps pass turns "does every field of these two structs
+// match" into ExpandMemCmp's code shape rather than a chain of per-field
+// comparisons: load each pair of 8-byte fields, XOR them together, OR
+// everything into one accumulator, and branch exactly once at the end.
+// structEqualityChain builds that shape by hand for a 4-field struct
+// (four LDR pairs, four EOR results, a depth-2 ORR reduction tree, one
+// terminating CBZ) so it can be measured head-to-head against
+// structEqualityNaive, which does the same comparison as four separate
+// CMP+B.NE short-circuit exits the way an unoptimized front-end would.
+// The pair isolates the front-end/branch-predictor cost of the
+// short-circuit chain from the ILP win of the merged form.
+func structEqualityChainBenchmarks() []Benchmark {
+	return []Benchmark{
+		structEqualityChain("equal"),
+		structEqualityChain("differs"),
+		structEqualityNaive("equal"),
+		structEqualityNaive("differs"),
+	}
+}
+
+// structEqualityFields returns the four field values written to both
+// structs' base buffer; structEqualitySetup perturbs one of them in the
+// second struct for the "differs" variant.
+func structEqualityFields() [4]uint64 {
+	return [4]uint64{0x1111, 0x2222, 0x3333, 0x4444}
+}
+
+func structEqualityChain(variant string) Benchmark {
+	const structA = 0x8000
+	const structB = 0x9000
+
+	return Benchmark{
+		Name:        "struct_equality_chain_" + variant,
+		Description: "MergeICmps-style 4-field struct compare (" + variant + ") via LDR/EOR/ORR reduction + one CBZ - measures merged-compare ILP",
+		Setup:       structEqualitySetup(structA, structB, variant),
+		Program:     buildStructEqualityChain(structA, structB),
+		ExpectedExit: map[string]int64{
+			"equal":   1,
+			"differs": 0,
+		}[variant],
+	}
+}
+
+func structEqualityNaive(variant string) Benchmark {
+	const structA = 0x8000
+	const structB = 0x9000
+
+	return Benchmark{
+		Name:        "struct_equality_naive_" + variant,
+		Description: "Unmerged 4-field struct compare (" + variant + ") via four CMP+B.NE short-circuit exits - measures branch-predictor cost",
+		Setup:       structEqualitySetup(structA, structB, variant),
+		Program:     buildStructEqualityNaive(structA, structB),
+		ExpectedExit: map[string]int64{
+			"equal":   1,
+			"differs": 0,
+		}[variant],
+	}
+}
+
+// structEqualitySetup writes the same four fields to both struct buffers,
+// perturbing the third field of structB when variant is "differs".
+func structEqualitySetup(structA, structB uint64, variant string) func(*emu.RegFile, *emu.Memory) {
+	return func(regFile *emu.RegFile, memory *emu.Memory) {
+		regFile.WriteReg(8, 93)      // X8 = 93 (exit syscall)
+		regFile.WriteReg(1, structA) // X1 = &structA
+		regFile.WriteReg(2, structB) // X2 = &structB
+
+		fields := structEqualityFields()
+		for i, v := range fields {
+			memory.Write64(structA+uint64(i)*8, v)
+			memory.Write64(structB+uint64(i)*8, v)
+		}
+		if variant == "differs" {
+			memory.Write64(structB+2*8, fields[2]+1)
+		}
+	}
+}
+
+// buildStructEqualityChain emits four LDR pairs from [X1] / [X2], XORs
+// each pair, OR-reduces the four XOR results in a depth-2 tree, and
+// branches once: CBZ skips to the "equal" path when the reduction is
+// zero, falling through to the "not equal" path otherwise.
+func buildStructEqualityChain(structA, structB uint64) []byte {
+	instrs := []uint32{
+		EncodeLDR64(3, 1, 0), EncodeLDR64(4, 2, 0), EncodeEORReg(3, 3, 4), // field 0
+		EncodeLDR64(5, 1, 1), EncodeLDR64(6, 2, 1), EncodeEORReg(5, 5, 6), // field 1
+		EncodeLDR64(7, 1, 2), EncodeLDR64(11, 2, 2), EncodeEORReg(7, 7, 11), // field 2 (X8 keeps the exit syscall number)
+		EncodeLDR64(9, 1, 3), EncodeLDR64(10, 2, 3), EncodeEORReg(9, 9, 10), // field 3
+		EncodeORRReg(3, 3, 5),         // tree level 1: xor0 | xor1
+		EncodeORRReg(7, 7, 9),         // tree level 1: xor2 | xor3
+		EncodeORRReg(3, 3, 7),         // tree level 2: everything reduced into X3
+		EncodeCBZ(3, 12),              // X3 == 0 => skip to "equal:" (+12 bytes = 3 instrs)
+		EncodeADDImm(0, 31, 0, false), // not equal: X0 = 0
+		EncodeB(8),                    // skip "equal:" setter (+8 bytes = 2 instrs)
+		EncodeADDImm(0, 31, 1, false), // equal: X0 = 1
+		EncodeSVC(0),
+	}
+	return BuildProgram(instrs...)
+}
+
+// buildStructEqualityNaive emits four independent CMP+B.NE pairs, one per
+// field, each able to short-circuit straight to the "not equal" exit
+// without evaluating the remaining fields.
+func buildStructEqualityNaive(structA, structB uint64) []byte {
+	instrs := make([]uint32, 0, 4*4+4)
+	for i := 0; i < 4; i++ {
+		rtA, rtB := uint8(3), uint8(4)
+		instrs = append(instrs,
+			EncodeLDR64(rtA, 1, uint16(i)),
+			EncodeLDR64(rtB, 2, uint16(i)),
+			EncodeCMPReg(rtA, rtB),
+			EncodeBCond(60-int32(i)*16, 1), // B.NE not_equal (CondNE = 1)
+		)
+	}
+	instrs = append(instrs,
+		EncodeADDImm(0, 31, 1, false), // all fields matched: X0 = 1
+		EncodeB(8),                    // skip "not_equal:" setter
+		EncodeADDImm(0, 31, 0, false), // not_equal: X0 = 0
+		EncodeSVC(0),
+	)
+	return BuildProgram(instrs...)
+}
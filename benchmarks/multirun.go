@@ -0,0 +1,197 @@
+package benchmarks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// StatSummary is a metric's min/median/mean/stddev/p95 across several
+// runs of the same benchmark, letting a single-shot cycle or CPI number
+// be read with a confidence interval instead of taken on faith.
+type StatSummary struct {
+	Min    float64 `json:"min"`
+	Median float64 `json:"median"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	P95    float64 `json:"p95"`
+}
+
+// summarize computes a StatSummary over samples. samples is sorted
+// in place; callers that need the original order should pass a copy.
+func summarize(samples []float64) StatSummary {
+	if len(samples) == 0 {
+		return StatSummary{}
+	}
+	sort.Float64s(samples)
+
+	sum := 0.0
+	for _, s := range samples {
+		sum += s
+	}
+	mean := sum / float64(len(samples))
+
+	variance := 0.0
+	for _, s := range samples {
+		d := s - mean
+		variance += d * d
+	}
+	variance /= float64(len(samples))
+
+	return StatSummary{
+		Min:    samples[0],
+		Median: percentile(samples, 50),
+		Mean:   mean,
+		StdDev: math.Sqrt(variance),
+		P95:    percentile(samples, 95),
+	}
+}
+
+// percentile returns the p-th percentile of sorted (ascending) samples
+// via linear interpolation between the two nearest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
+
+// BenchmarkStats is one benchmark's Cycles, CPI, and per-stall-counter
+// StatSummary across Runs executions with -runs N, keyed by the
+// benchmark's Name so it lines up with the single-run Result it was
+// aggregated from.
+type BenchmarkStats struct {
+	Name string `json:"name"`
+	Runs int    `json:"runs"`
+
+	Cycles                    StatSummary `json:"cycles"`
+	CPI                       StatSummary `json:"cpi"`
+	RAWHazardStalls           StatSummary `json:"raw_hazard_stalls"`
+	StructuralHazardStalls    StatSummary `json:"structural_hazard_stalls"`
+	ExecStalls                StatSummary `json:"exec_stalls"`
+	MemStalls                 StatSummary `json:"mem_stalls"`
+	BranchMispredictionStalls StatSummary `json:"branch_misprediction_stalls"`
+	PipelineFlushes           StatSummary `json:"pipeline_flushes"`
+	FetchStalls               StatSummary `json:"fetch_stalls"`
+}
+
+// AggregateRuns groups runs — one []Result per -runs repetition, each in
+// the same benchmark order — by benchmark name and computes a
+// BenchmarkStats for each. Benchmarks are returned in the order they
+// first appear in runs[0].
+func AggregateRuns(runs [][]Result) []BenchmarkStats {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	var order []string
+	byName := make(map[string][]Result)
+	for _, run := range runs {
+		for _, r := range run {
+			if _, ok := byName[r.Name]; !ok {
+				order = append(order, r.Name)
+			}
+			byName[r.Name] = append(byName[r.Name], r)
+		}
+	}
+
+	stats := make([]BenchmarkStats, 0, len(order))
+	for _, name := range order {
+		results := byName[name]
+
+		collect := func(f func(r Result) float64) StatSummary {
+			samples := make([]float64, len(results))
+			for i, r := range results {
+				samples[i] = f(r)
+			}
+			return summarize(samples)
+		}
+
+		stats = append(stats, BenchmarkStats{
+			Name:                      name,
+			Runs:                      len(results),
+			Cycles:                    collect(func(r Result) float64 { return float64(r.SimulatedCycles) }),
+			CPI:                       collect(func(r Result) float64 { return r.CPI }),
+			RAWHazardStalls:           collect(func(r Result) float64 { return float64(r.RAWHazardStalls) }),
+			StructuralHazardStalls:    collect(func(r Result) float64 { return float64(r.StructuralHazardStalls) }),
+			ExecStalls:                collect(func(r Result) float64 { return float64(r.ExecStalls) }),
+			MemStalls:                 collect(func(r Result) float64 { return float64(r.MemStalls) }),
+			BranchMispredictionStalls: collect(func(r Result) float64 { return float64(r.BranchMispredictionStalls) }),
+			PipelineFlushes:           collect(func(r Result) float64 { return float64(r.PipelineFlushes) }),
+			FetchStalls:               collect(func(r Result) float64 { return float64(r.StallCycles) }),
+		})
+	}
+	return stats
+}
+
+// WriteMultiRunText writes a human-readable summary of stats to w, one
+// line per benchmark covering Cycles and CPI's mean/stddev/p95 (the two
+// metrics most relevant to the "<2% error vs real M2" comparison); the
+// full per-stall-counter breakdown is available via the json/csv formats.
+func WriteMultiRunText(w io.Writer, stats []BenchmarkStats) error {
+	for _, s := range stats {
+		if _, err := fmt.Fprintf(w, "%-30s runs=%-3d  cycles: min=%.0f median=%.0f mean=%.0f stddev=%.1f p95=%.0f  cpi: min=%.4f median=%.4f mean=%.4f stddev=%.4f p95=%.4f\n",
+			s.Name, s.Runs,
+			s.Cycles.Min, s.Cycles.Median, s.Cycles.Mean, s.Cycles.StdDev, s.Cycles.P95,
+			s.CPI.Min, s.CPI.Median, s.CPI.Mean, s.CPI.StdDev, s.CPI.P95); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMultiRunCSV writes stats to w as CSV, one row per benchmark, with
+// every metric's min/median/mean/stddev/p95 as its own column.
+func WriteMultiRunCSV(w io.Writer, stats []BenchmarkStats) error {
+	cw := csv.NewWriter(w)
+
+	metrics := []string{"cycles", "cpi", "raw_hazard_stalls", "structural_hazard_stalls", "exec_stalls", "mem_stalls", "branch_misprediction_stalls", "pipeline_flushes", "fetch_stalls"}
+	header := []string{"name", "runs"}
+	for _, m := range metrics {
+		for _, stat := range []string{"min", "median", "mean", "stddev", "p95"} {
+			header = append(header, m+"_"+stat)
+		}
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		row := []string{s.Name, fmt.Sprintf("%d", s.Runs)}
+		for _, summary := range []StatSummary{
+			s.Cycles, s.CPI, s.RAWHazardStalls, s.StructuralHazardStalls,
+			s.ExecStalls, s.MemStalls, s.BranchMispredictionStalls, s.PipelineFlushes, s.FetchStalls,
+		} {
+			row = append(row,
+				fmt.Sprintf("%.6f", summary.Min),
+				fmt.Sprintf("%.6f", summary.Median),
+				fmt.Sprintf("%.6f", summary.Mean),
+				fmt.Sprintf("%.6f", summary.StdDev),
+				fmt.Sprintf("%.6f", summary.P95),
+			)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMultiRunJSON writes stats to w as a JSON array.
+func WriteMultiRunJSON(w io.Writer, stats []BenchmarkStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
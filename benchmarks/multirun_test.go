@@ -0,0 +1,86 @@
+package benchmarks
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeComputesExpectedStatistics(t *testing.T) {
+	s := summarize([]float64{1, 2, 3, 4, 5})
+
+	if s.Min != 1 {
+		t.Errorf("Min = %v, want 1", s.Min)
+	}
+	if s.Median != 3 {
+		t.Errorf("Median = %v, want 3", s.Median)
+	}
+	if s.Mean != 3 {
+		t.Errorf("Mean = %v, want 3", s.Mean)
+	}
+	if math.Abs(s.StdDev-math.Sqrt(2)) > 1e-9 {
+		t.Errorf("StdDev = %v, want sqrt(2)", s.StdDev)
+	}
+	if s.P95 != 4.8 {
+		t.Errorf("P95 = %v, want 4.8", s.P95)
+	}
+}
+
+func TestAggregateRunsGroupsByNamePreservingOrder(t *testing.T) {
+	runs := [][]Result{
+		{{Name: "loop", SimulatedCycles: 100, CPI: 1.0}, {Name: "matrix", SimulatedCycles: 200, CPI: 2.0}},
+		{{Name: "loop", SimulatedCycles: 110, CPI: 1.1}, {Name: "matrix", SimulatedCycles: 210, CPI: 2.1}},
+		{{Name: "loop", SimulatedCycles: 90, CPI: 0.9}, {Name: "matrix", SimulatedCycles: 190, CPI: 1.9}},
+	}
+
+	stats := AggregateRuns(runs)
+	if len(stats) != 2 {
+		t.Fatalf("len(stats) = %d, want 2", len(stats))
+	}
+	if stats[0].Name != "loop" || stats[1].Name != "matrix" {
+		t.Fatalf("stats order = [%s, %s], want [loop, matrix]", stats[0].Name, stats[1].Name)
+	}
+
+	loop := stats[0]
+	if loop.Runs != 3 {
+		t.Errorf("loop.Runs = %d, want 3", loop.Runs)
+	}
+	if loop.Cycles.Mean != 100 {
+		t.Errorf("loop.Cycles.Mean = %v, want 100", loop.Cycles.Mean)
+	}
+	if loop.CPI.Min != 0.9 {
+		t.Errorf("loop.CPI.Min = %v, want 0.9", loop.CPI.Min)
+	}
+}
+
+func TestWriteMultiRunFormats(t *testing.T) {
+	stats := AggregateRuns([][]Result{
+		{{Name: "loop", SimulatedCycles: 100, CPI: 1.0}},
+		{{Name: "loop", SimulatedCycles: 110, CPI: 1.1}},
+	})
+
+	var text bytes.Buffer
+	if err := WriteMultiRunText(&text, stats); err != nil {
+		t.Fatalf("WriteMultiRunText: %v", err)
+	}
+	if !strings.Contains(text.String(), "loop") {
+		t.Errorf("text output missing benchmark name: %q", text.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteMultiRunCSV(&csvOut, stats); err != nil {
+		t.Fatalf("WriteMultiRunCSV: %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "cycles_mean") {
+		t.Errorf("csv output missing cycles_mean column: %q", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteMultiRunJSON(&jsonOut, stats); err != nil {
+		t.Fatalf("WriteMultiRunJSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), "\"runs\"") {
+		t.Errorf("json output missing runs field: %q", jsonOut.String())
+	}
+}
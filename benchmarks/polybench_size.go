@@ -0,0 +1,30 @@
+package benchmarks
+
+// PolyBenchSize selects which of PolyBench's standard dataset-size presets
+// a PolyBench-derived Benchmark runs with, mirroring the MINI_DATASET/
+// SMALL_DATASET/STANDARD_DATASET/LARGE_DATASET macros PolyBench itself is
+// compiled with. A larger size exercises more loop iterations (and so more
+// stalls for the timing model to classify) at the cost of wall time.
+type PolyBenchSize string
+
+// The four PolyBenchSize presets, ordered smallest to largest. Config's
+// zero value is "", which BenchmarkFromELF treats the same as
+// PolyBenchStandard.
+const (
+	PolyBenchMini     PolyBenchSize = "mini"
+	PolyBenchSmall    PolyBenchSize = "small"
+	PolyBenchStandard PolyBenchSize = "standard"
+	PolyBenchLarge    PolyBenchSize = "large"
+)
+
+// PolyBenchSizeArgv returns the extra argv a PolyBench-derived ELF binary
+// (built by benchmarks/polybench/build.sh) expects to select its dataset
+// size. Those binaries read a single "-size=<name>" argument and scale
+// their loop bounds accordingly; an empty size returns nil, letting the
+// binary fall back to its compiled-in default.
+func PolyBenchSizeArgv(size PolyBenchSize) []string {
+	if size == "" {
+		return nil
+	}
+	return []string{"-size=" + string(size)}
+}
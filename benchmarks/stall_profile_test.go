@@ -26,9 +26,13 @@ type StallProfileResult struct {
 }
 
 // TestStallProfileOctuple runs gemm, bicg, and atax with 8-wide + caches.
+// Under -short it runs the PolyBenchMini dataset size instead of skipping
+// outright, targeting <=10s per kernel so there's still a fast smoke-test
+// path that exercises the full stall-classification pipeline.
 func TestStallProfileOctuple(t *testing.T) {
+	size := PolyBenchStandard
 	if testing.Short() {
-		t.Skip("skipping stall profiling in short mode")
+		size = PolyBenchMini
 	}
 
 	kernels := []struct {
@@ -51,9 +55,10 @@ func TestStallProfileOctuple(t *testing.T) {
 		config := DefaultConfig() // 8-wide + caches
 		config.Output = &bytes.Buffer{}
 		config.MaxCycles = 5_000_000
+		config.PolyBenchSize = size
 
 		harness := NewHarness(config)
-		harness.AddBenchmark(BenchmarkFromELF(k.name, k.name, elfPath))
+		harness.AddBenchmark(BenchmarkFromELF(k.name, k.name, elfPath, PolyBenchSizeArgv(size)...))
 
 		benchResults := harness.RunAll()
 		r := benchResults[0]
@@ -62,7 +67,7 @@ func TestStallProfileOctuple(t *testing.T) {
 		}
 
 		sp := StallProfileResult{
-			Name:                      k.name + " (8-wide+cache)",
+			Name:                      k.name + " (8-wide+cache, " + string(size) + ")",
 			Cycles:                    r.SimulatedCycles,
 			Instructions:              r.InstructionsRetired,
 			CPI:                       r.CPI,
@@ -93,9 +98,16 @@ func TestStallProfileOctuple(t *testing.T) {
 
 // TestStallProfile runs gemm, bicg, and atax with stall profiling enabled.
 // Run with: go test -run TestStallProfile -v ./benchmarks/ -timeout 600s
+//
+// Under -short it runs the PolyBenchMini dataset size instead of skipping
+// outright, targeting <=10s per kernel so there's still a fast smoke-test
+// path that exercises the full stall-classification pipeline.
 func TestStallProfile(t *testing.T) {
+	size := PolyBenchStandard
+	maxCycles := uint64(10_000_000)
 	if testing.Short() {
-		t.Skip("skipping stall profiling in short mode")
+		size = PolyBenchMini
+		maxCycles = 1_000_000
 	}
 
 	kernels := []struct {
@@ -121,10 +133,11 @@ func TestStallProfile(t *testing.T) {
 		config.EnableDCache = false
 		config.EnableOctupleIssue = false
 		config.EnableSextupleIssue = true
-		config.MaxCycles = 10_000_000
+		config.MaxCycles = maxCycles
+		config.PolyBenchSize = size
 
 		harness := NewHarness(config)
-		harness.AddBenchmark(BenchmarkFromELF(k.name, k.name, elfPath))
+		harness.AddBenchmark(BenchmarkFromELF(k.name, k.name, elfPath, PolyBenchSizeArgv(size)...))
 
 		benchResults := harness.RunAll()
 		if len(benchResults) != 1 {
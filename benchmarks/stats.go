@@ -0,0 +1,64 @@
+package benchmarks
+
+import "github.com/sarchlab/m2sim/emu"
+
+// ROIStats implements emu.PseudoOpHandler, tracking the instruction count
+// gem5's m5_work_begin/m5_work_end convention brackets as a benchmark's
+// region of interest. SPECRunner callers attach it via
+// emu.Emulator.SetPseudoOpHandler so a SPEC benchmark compiled against
+// benchmarks/include/m2sim_pseudo.h reports ROI timing the same way it
+// would under gem5, rather than timing the whole process including setup
+// and teardown. Every other pseudo-op is a no-op here; DumpStats/
+// ResetStats/Checkpoint/Panic are for interactive or gem5-compatible
+// drivers, not the SPEC rate-benchmark harness.
+type ROIStats struct {
+	emulator *emu.Emulator
+
+	active       bool
+	roiStart     uint64
+	Instructions uint64 // total instructions retired across all ROI windows
+}
+
+// NewROIStats creates an ROIStats bound to e, reading e.InstructionCount()
+// to measure each region-of-interest window.
+func NewROIStats(e *emu.Emulator) *ROIStats {
+	return &ROIStats{emulator: e}
+}
+
+// Exit is a no-op; SPECRunner observes program exit through the emulator's
+// own StepResult, not this handler.
+func (s *ROIStats) Exit(code int64) {}
+
+// DumpStats is a no-op.
+func (s *ROIStats) DumpStats() {}
+
+// ResetStats is a no-op.
+func (s *ROIStats) ResetStats() {}
+
+// Checkpoint is a no-op.
+func (s *ROIStats) Checkpoint(name string) {}
+
+// Panic is a no-op.
+func (s *ROIStats) Panic(msg string) {}
+
+// WorkBegin starts a region-of-interest window. Nested WorkBegin calls
+// (without an intervening WorkEnd) are ignored, matching gem5's treatment
+// of a single active ROI per handler.
+func (s *ROIStats) WorkBegin(workID, threadID uint64) {
+	if s.active {
+		return
+	}
+	s.active = true
+	s.roiStart = s.emulator.InstructionCount()
+}
+
+// WorkEnd closes the region-of-interest window opened by WorkBegin, adding
+// the instructions retired during it to Instructions. A WorkEnd with no
+// matching WorkBegin is ignored.
+func (s *ROIStats) WorkEnd(workID, threadID uint64) {
+	if !s.active {
+		return
+	}
+	s.active = false
+	s.Instructions += s.emulator.InstructionCount() - s.roiStart
+}
@@ -0,0 +1,51 @@
+package benchmarks
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// encodeHLT encodes a HLT #imm16 instruction: 11010100 010 imm16 00000.
+func encodeHLT(imm16 uint16) uint32 {
+	return 0xD4400000 | (uint32(imm16) << 5)
+}
+
+// encodeMOVZ64 encodes MOVZ Xd, #imm16, hw=0.
+func encodeMOVZ64(rd uint8, imm16 uint16) uint32 {
+	return 0xD2800000 | (uint32(imm16) << 5) | uint32(rd)
+}
+
+func roiProgram(insts ...uint32) []byte {
+	buf := make([]byte, 4*len(insts))
+	for i, inst := range insts {
+		binary.LittleEndian.PutUint32(buf[i*4:], inst)
+	}
+	return buf
+}
+
+func TestROIStatsCountsOnlyInstructionsInsideWorkBeginEnd(t *testing.T) {
+	e := emu.NewEmulator()
+	stats := NewROIStats(e)
+	e.SetPseudoOpHandler(stats)
+	e.LoadProgram(0x1000, roiProgram(
+		encodeMOVZ64(0, 1), // outside ROI
+		encodeHLT(emu.PseudoOpWorkBegin),
+		encodeMOVZ64(1, 2), // inside ROI
+		encodeMOVZ64(2, 3), // inside ROI
+		encodeHLT(emu.PseudoOpWorkEnd),
+		encodeMOVZ64(3, 4), // outside ROI
+	))
+
+	for i := 0; i < 6; i++ {
+		result := e.Step()
+		if result.Err != nil {
+			t.Fatalf("step %d: %v", i, result.Err)
+		}
+	}
+
+	if stats.Instructions != 2 {
+		t.Fatalf("Instructions = %d, want 2", stats.Instructions)
+	}
+}
@@ -0,0 +1,154 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TopDownCategory names one of the categories Intel's Top-Down
+// Microarchitecture Analysis (TMA) method decomposes total pipeline
+// cycles into.
+type TopDownCategory string
+
+// The four top-level TopDownCategory values, plus the two Backend Bound
+// splits into. Memory Bound and Core Bound are reported alongside Backend
+// Bound rather than replacing it, since TMA treats them as a nested
+// breakdown of it, not a fifth sibling category.
+const (
+	TopDownFrontendBound  TopDownCategory = "Frontend Bound"
+	TopDownBadSpeculation TopDownCategory = "Bad Speculation"
+	TopDownBackendBound   TopDownCategory = "Backend Bound"
+	TopDownMemoryBound    TopDownCategory = "Memory Bound"
+	TopDownCoreBound      TopDownCategory = "Core Bound"
+	TopDownRetiring       TopDownCategory = "Retiring"
+)
+
+// TopDownEntry is one category's share of a benchmark's cycles, reported
+// both as an absolute cycle count and as a percentage of the breakdown's
+// total.
+type TopDownEntry struct {
+	Category TopDownCategory `json:"category"`
+	Cycles   int64           `json:"cycles"`
+	Percent  float64         `json:"percent"`
+}
+
+// TopDownBreakdown is one benchmark's stall counters decomposed into TMA
+// categories, so they can be read with the same top-down drill-down flow
+// real hardware performance counters are consumed with: Frontend Bound
+// and Bad Speculation are cycles wasted before or on mis-speculated work,
+// Backend Bound (split into Memory Bound and Core Bound) is cycles
+// wasted waiting on the backend, and Retiring is useful work. Frontend,
+// BadSpeculation, Backend, and Retiring sum to 100%; MemoryBound and
+// CoreBound are Backend's own split and so sum to Backend's percentage,
+// not an additional 100%.
+type TopDownBreakdown struct {
+	Name string `json:"name"`
+
+	Frontend       TopDownEntry `json:"frontend_bound"`
+	BadSpeculation TopDownEntry `json:"bad_speculation"`
+	Backend        TopDownEntry `json:"backend_bound"`
+	MemoryBound    TopDownEntry `json:"memory_bound"`
+	CoreBound      TopDownEntry `json:"core_bound"`
+	Retiring       TopDownEntry `json:"retiring"`
+}
+
+// ComputeTopDown decomposes r's stall counters into a TopDownBreakdown.
+// issueWidth is the pipeline's peak instructions-per-cycle (e.g. 6 or 8,
+// matching Config.EnableSextupleIssue/EnableOctupleIssue), used to turn
+// InstructionsRetired into the ideal cycle count Retiring represents.
+// avgFlushCost is the average number of cycles one pipeline flush costs,
+// used to fold PipelineFlushes into Bad Speculation alongside the
+// branch-misprediction stalls already counted directly. Percentages are
+// normalized against the sum of the four top-level categories' cycles
+// rather than r.SimulatedCycles, since that sum (unlike the simulator's
+// true cycle count) is guaranteed non-zero whenever there's any
+// classified stall or at least issueWidth retired instructions (fewer
+// than that round Retiring down to zero cycles), and is exactly what the
+// reported percentages need to add to 100% against.
+func ComputeTopDown(r Result, issueWidth int, avgFlushCost float64) TopDownBreakdown {
+	frontendCycles := int64(r.StallCycles)
+	badSpecCycles := int64(r.BranchMispredictionStalls) + int64(float64(r.PipelineFlushes)*avgFlushCost)
+	memCycles := int64(r.MemStalls)
+	coreCycles := int64(r.ExecStalls) + int64(r.StructuralHazardStalls) + int64(r.RAWHazardStalls)
+	backendCycles := memCycles + coreCycles
+
+	retiringCycles := int64(r.InstructionsRetired)
+	if issueWidth > 1 {
+		retiringCycles /= int64(issueWidth)
+	}
+
+	total := frontendCycles + badSpecCycles + backendCycles + retiringCycles
+	pct := func(cycles int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(cycles) / float64(total) * 100
+	}
+
+	return TopDownBreakdown{
+		Name:           r.Name,
+		Frontend:       TopDownEntry{TopDownFrontendBound, frontendCycles, pct(frontendCycles)},
+		BadSpeculation: TopDownEntry{TopDownBadSpeculation, badSpecCycles, pct(badSpecCycles)},
+		Backend:        TopDownEntry{TopDownBackendBound, backendCycles, pct(backendCycles)},
+		MemoryBound:    TopDownEntry{TopDownMemoryBound, memCycles, pct(memCycles)},
+		CoreBound:      TopDownEntry{TopDownCoreBound, coreCycles, pct(coreCycles)},
+		Retiring:       TopDownEntry{TopDownRetiring, retiringCycles, pct(retiringCycles)},
+	}
+}
+
+// topDownBarWidth is how many characters wide WriteTopDownText's bar
+// chart renders a 100% category.
+const topDownBarWidth = 40
+
+// WriteTopDownText renders breakdowns as a text bar chart: one row per
+// top-level category (plus the Memory/Core Bound sub-rows), each with a
+// proportional bar of '#' characters.
+func WriteTopDownText(w io.Writer, breakdowns []TopDownBreakdown) error {
+	bar := func(pct float64) string {
+		n := int(pct / 100 * topDownBarWidth)
+		if n < 0 {
+			n = 0
+		}
+		if n > topDownBarWidth {
+			n = topDownBarWidth
+		}
+		return strings.Repeat("#", n) + strings.Repeat(".", topDownBarWidth-n)
+	}
+	row := func(entry TopDownEntry, indent string) error {
+		_, err := fmt.Fprintf(w, "%s%-18s [%s] %5.1f%%  (%d cycles)\n", indent, entry.Category, bar(entry.Percent), entry.Percent, entry.Cycles)
+		return err
+	}
+
+	for _, b := range breakdowns {
+		if _, err := fmt.Fprintf(w, "=== %s ===\n", b.Name); err != nil {
+			return err
+		}
+		for _, entry := range []TopDownEntry{b.Frontend, b.BadSpeculation, b.Backend, b.Retiring} {
+			if err := row(entry, ""); err != nil {
+				return err
+			}
+			if entry.Category == TopDownBackendBound {
+				if err := row(b.MemoryBound, "  "); err != nil {
+					return err
+				}
+				if err := row(b.CoreBound, "  "); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTopDownJSON writes breakdowns to w as a JSON array of nested
+// TopDownBreakdown objects.
+func WriteTopDownJSON(w io.Writer, breakdowns []TopDownBreakdown) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(breakdowns)
+}
@@ -0,0 +1,71 @@
+package benchmarks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestComputeTopDownCategoriesSumToTotal(t *testing.T) {
+	r := Result{
+		Name:                      "loop",
+		InstructionsRetired:       1200,
+		StallCycles:               100,
+		BranchMispredictionStalls: 20,
+		PipelineFlushes:           5,
+		MemStalls:                 300,
+		ExecStalls:                150,
+		StructuralHazardStalls:    50,
+		RAWHazardStalls:           80,
+	}
+
+	b := ComputeTopDown(r, 6, 10)
+
+	total := b.Frontend.Percent + b.BadSpeculation.Percent + b.Backend.Percent + b.Retiring.Percent
+	if total < 99.9 || total > 100.1 {
+		t.Fatalf("top-level percentages sum to %.4f, want ~100", total)
+	}
+
+	if b.Backend.Cycles != b.MemoryBound.Cycles+b.CoreBound.Cycles {
+		t.Errorf("Backend.Cycles = %d, want MemoryBound+CoreBound = %d", b.Backend.Cycles, b.MemoryBound.Cycles+b.CoreBound.Cycles)
+	}
+
+	wantBadSpec := int64(20 + 5*10)
+	if b.BadSpeculation.Cycles != wantBadSpec {
+		t.Errorf("BadSpeculation.Cycles = %d, want %d", b.BadSpeculation.Cycles, wantBadSpec)
+	}
+}
+
+func TestComputeTopDownHandlesZeroActivity(t *testing.T) {
+	b := ComputeTopDown(Result{Name: "idle"}, 6, 10)
+
+	for _, entry := range []TopDownEntry{b.Frontend, b.BadSpeculation, b.Backend, b.Retiring} {
+		if entry.Percent != 0 {
+			t.Errorf("%s.Percent = %v, want 0 for an all-zero result", entry.Category, entry.Percent)
+		}
+	}
+}
+
+func TestWriteTopDownFormats(t *testing.T) {
+	breakdowns := []TopDownBreakdown{ComputeTopDown(Result{
+		Name:                "loop",
+		InstructionsRetired: 600,
+		MemStalls:           100,
+	}, 6, 10)}
+
+	var text bytes.Buffer
+	if err := WriteTopDownText(&text, breakdowns); err != nil {
+		t.Fatalf("WriteTopDownText: %v", err)
+	}
+	if !strings.Contains(text.String(), "loop") || !strings.Contains(text.String(), "Frontend Bound") {
+		t.Errorf("text output missing expected content: %q", text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteTopDownJSON(&jsonOut, breakdowns); err != nil {
+		t.Fatalf("WriteTopDownJSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), "memory_bound") {
+		t.Errorf("json output missing memory_bound: %q", jsonOut.String())
+	}
+}
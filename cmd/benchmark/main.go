@@ -6,12 +6,36 @@
 //
 // Flags:
 //
-//	-format     Output format: text, csv, or json (default: text)
+//	-format     Output format: text, csv, json, topdown, or topdown-json (default: text)
 //	-core       Run only the 3 core benchmarks (loop, matrix, branch)
 //	-no-icache  Disable instruction cache simulation
 //	-no-dcache  Disable data cache simulation
 //	-o          Output file (default: stdout)
 //	-v          Verbose output with validation checks
+//	-baseline   Path to a JSON results file from a previous run; when set,
+//	            prints a comparison against the current run instead of the
+//	            current run alone
+//	-fail-on-regress  Exit non-zero if any benchmark's CPI regresses by
+//	            more than this many percent relative to -baseline
+//	-cpuprofile Write a CPU profile of the simulator itself to this file
+//	-memprofile Write a heap profile of the simulator itself to this file
+//	-trace      Write an execution trace of the simulator itself to this file
+//	-pprof-http Serve live net/http/pprof profiles at this address (e.g. :6060)
+//	-size       PolyBench dataset size for ELF-backed benchmarks: mini,
+//	            small, standard, or large (default: standard)
+//	-topdown-issue-width  Pipeline issue width used by -format=topdown
+//	-topdown-flush-cost   Average flush cost (cycles) used by -format=topdown
+//	-itinerary  Path to a scheduling itinerary YAML file (see
+//	            configs/m2_pcore.yaml); when set, its issue_width
+//	            overrides -topdown-issue-width
+//	-runs       Run each benchmark this many times and report
+//	            min/median/mean/stddev/p95 instead of a single-shot result
+//	-seed       Seed for any nondeterministic pipeline component, for
+//	            reproducible -runs>1 statistics
+//	-fetch-group-bytes  Front-end fetch-group size in bytes used when
+//	            computing fetch-group boundaries (default: 16)
+//	-align-branch-targets-to  Pad branch targets to this byte alignment;
+//	            0 disables alignment-aware fetch modeling (default: 0)
 //
 // Example:
 //
@@ -24,6 +48,25 @@
 //	# Quick validation with 3 core benchmarks
 //	go run ./cmd/benchmark -core -v
 //
+//	# Compare against a saved baseline and fail CI on a >2% CPI regression
+//	go run ./cmd/benchmark -baseline=results.json -fail-on-regress=2
+//
+//	# Profile the simulator itself during a long PolyBench-style run
+//	go run ./cmd/benchmark -cpuprofile=cpu.prof -memprofile=mem.prof
+//
+//	# Top-down cycle breakdown, the way HW performance counters are read
+//	go run ./cmd/benchmark -format=topdown
+//
+//	# Top-down breakdown calibrated against a scheduling itinerary
+//	go run ./cmd/benchmark -format=topdown -itinerary=configs/m2_pcore.yaml
+//
+//	# 10 runs per benchmark with min/median/mean/stddev/p95 statistics
+//	go run ./cmd/benchmark -runs=10 -seed=1
+//
+//	# Calibrate fetch-group alignment against native measurements using
+//	# the branch_alignment_sweep_* benchmarks
+//	go run ./cmd/benchmark -align-branch-targets-to=64 -format=csv
+//
 // The benchmark results can be compared against real M2 hardware measurements
 // to calibrate the simulator's timing model. JSON output is designed for
 // automated comparison with Issue #96 (M2 timing baseline).
@@ -38,12 +81,28 @@ import (
 )
 
 var (
-	format     = flag.String("format", "text", "Output format: text, csv, or json")
+	format     = flag.String("format", "text", "Output format: text, csv, json, topdown, or topdown-json")
 	coreOnly   = flag.Bool("core", false, "Run only the 3 core benchmarks (loop, matrix, branch)")
 	noICache   = flag.Bool("no-icache", false, "Disable instruction cache simulation")
 	noDCache   = flag.Bool("no-dcache", false, "Disable data cache simulation")
 	outputFile = flag.String("o", "", "Output file (default: stdout)")
 	verbose    = flag.Bool("v", false, "Verbose output with validation checks")
+
+	baseline      = flag.String("baseline", "", "Path to a JSON results file to compare this run against")
+	failOnRegress = flag.Float64("fail-on-regress", 0, "Exit non-zero if any benchmark's CPI regresses by more than this many percent vs -baseline")
+
+	polyBenchSize = flag.String("size", string(benchmarks.PolyBenchStandard), "PolyBench dataset size for ELF-backed benchmarks: mini, small, standard, or large")
+
+	topdownIssueWidth = flag.Int("topdown-issue-width", 6, "Pipeline issue width used to compute -format=topdown's Retiring category")
+	topdownFlushCost  = flag.Float64("topdown-flush-cost", 10, "Average cycles one pipeline flush costs, used to compute -format=topdown's Bad Speculation category")
+
+	itineraryPath = flag.String("itinerary", "", "Path to a scheduling itinerary YAML file (see configs/m2_pcore.yaml); when set, its issue_width overrides -topdown-issue-width")
+
+	runs = flag.Int("runs", 1, "Run each benchmark this many times and report min/median/mean/stddev/p95 instead of a single-shot result")
+	seed = flag.Int64("seed", 0, "Seed for any nondeterministic pipeline component (branch predictor init, cache replacement RNG), for reproducible -runs>1 statistics")
+
+	fetchGroupBytes      = flag.Int("fetch-group-bytes", 16, "Front-end fetch-group size in bytes used when computing fetch-group boundaries")
+	alignBranchTargetsTo = flag.Int("align-branch-targets-to", 0, "Pad branch targets to this byte alignment (0 disables alignment-aware fetch modeling)")
 )
 
 func main() {
@@ -61,13 +120,17 @@ func main() {
 	}
 	flag.Parse()
 
+	stopProfiling := startProfiling()
+	defer stopProfiling()
+	handleProfilingSignals(stopProfiling)
+
 	// Set up output
 	output := os.Stdout
 	if *outputFile != "" {
 		f, err := os.Create(*outputFile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
-			os.Exit(1)
+			exitAfterProfiling(stopProfiling, 1)
 		}
 		defer func() {
 			if cerr := f.Close(); cerr != nil {
@@ -83,9 +146,25 @@ func main() {
 	config.EnableDCache = !*noDCache
 	config.Output = output
 	config.Verbose = *verbose
+	config.PolyBenchSize = benchmarks.PolyBenchSize(*polyBenchSize)
+	config.Seed = *seed
+	config.FetchGroupBytes = *fetchGroupBytes
+	config.AlignBranchTargetsTo = *alignBranchTargetsTo
 
 	harness := benchmarks.NewHarness(config)
 
+	// Loading a scheduling model is independent of which benchmarks run;
+	// it only changes how -format=topdown's Retiring category is
+	// computed, by way of its issue_width.
+	if *itineraryPath != "" {
+		itinerary, err := benchmarks.LoadItinerary(*itineraryPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading itinerary: %v\n", err)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+		*topdownIssueWidth = itinerary.IssueWidth
+	}
+
 	// Select benchmarks
 	var benchmarkList []benchmarks.Benchmark
 	if *coreOnly {
@@ -104,12 +183,99 @@ func main() {
 	// Run benchmarks
 	results := harness.RunAll()
 
+	// Repeat the run -runs-1 more times and report aggregate statistics
+	// instead of a single-shot result, giving the "<2% error vs real M2"
+	// claim a confidence interval to be checked against.
+	if *runs > 1 {
+		allRuns := make([][]benchmarks.Result, *runs)
+		allRuns[0] = results
+		for i := 1; i < *runs; i++ {
+			runHarness := benchmarks.NewHarness(config)
+			runHarness.AddBenchmarks(benchmarkList)
+			allRuns[i] = runHarness.RunAll()
+		}
+		stats := benchmarks.AggregateRuns(allRuns)
+
+		var writeErr error
+		switch *format {
+		case "json":
+			writeErr = benchmarks.WriteMultiRunJSON(output, stats)
+		case "csv":
+			writeErr = benchmarks.WriteMultiRunCSV(output, stats)
+		default:
+			writeErr = benchmarks.WriteMultiRunText(output, stats)
+		}
+		if writeErr != nil {
+			fmt.Fprintf(os.Stderr, "Error writing multi-run stats: %v\n", writeErr)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+		return
+	}
+
+	// Compare against a saved baseline instead of printing the run alone.
+	if *baseline != "" {
+		baselineResults, err := benchmarks.LoadBaseline(*baseline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+		comparisons := benchmarks.CompareResults(baselineResults, results)
+
+		var writeErr error
+		switch *format {
+		case "json":
+			writeErr = benchmarks.WriteComparisonJSON(output, comparisons)
+		case "csv":
+			writeErr = benchmarks.WriteComparisonCSV(output, comparisons)
+		case "text":
+			writeErr = benchmarks.WriteComparisonText(output, comparisons)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format: %s (use text, csv, or json)\n", *format)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+		if writeErr != nil {
+			fmt.Fprintf(os.Stderr, "Error writing comparison: %v\n", writeErr)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+
+		if *failOnRegress > 0 {
+			if regressed, names := benchmarks.AnyRegression(comparisons, *failOnRegress); regressed {
+				fmt.Fprintf(os.Stderr, "Regressed beyond %.2f%%: %v\n", *failOnRegress, names)
+				exitAfterProfiling(stopProfiling, 1)
+			}
+		}
+		return
+	}
+
+	// Render a Top-Down Microarchitecture Analysis style breakdown instead
+	// of the raw per-run results: "topdown" is a bar chart for human
+	// reading, "topdown-json" is the same breakdown as nested JSON for
+	// automated consumption.
+	if *format == "topdown" || *format == "topdown-json" {
+		breakdowns := make([]benchmarks.TopDownBreakdown, len(results))
+		for i, r := range results {
+			breakdowns[i] = benchmarks.ComputeTopDown(r, *topdownIssueWidth, *topdownFlushCost)
+		}
+
+		var writeErr error
+		if *format == "topdown-json" {
+			writeErr = benchmarks.WriteTopDownJSON(output, breakdowns)
+		} else {
+			writeErr = benchmarks.WriteTopDownText(output, breakdowns)
+		}
+		if writeErr != nil {
+			fmt.Fprintf(os.Stderr, "Error writing topdown output: %v\n", writeErr)
+			exitAfterProfiling(stopProfiling, 1)
+		}
+		return
+	}
+
 	// Output results
 	switch *format {
 	case "json":
 		if err := harness.PrintJSON(results); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
-			os.Exit(1)
+			exitAfterProfiling(stopProfiling, 1)
 		}
 	case "csv":
 		harness.PrintCSV(results)
@@ -136,7 +302,7 @@ func main() {
 		}
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown format: %s (use text, csv, or json)\n", *format)
-		os.Exit(1)
+		exitAfterProfiling(stopProfiling, 1)
 	}
 
 	// Validate exit codes if verbose
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	_ "net/http/pprof" // registers the /debug/pprof/ handlers on http.DefaultServeMux
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"syscall"
+)
+
+var (
+	cpuProfileFile = flag.String("cpuprofile", "", "Write a CPU profile of the simulator itself to this file")
+	memProfileFile = flag.String("memprofile", "", "Write a heap profile of the simulator itself to this file")
+	traceOutFile   = flag.String("trace", "", "Write an execution trace of the simulator itself to this file")
+	pprofHTTPAddr  = flag.String("pprof-http", "", "Serve live net/http/pprof profiles at this address (e.g. :6060)")
+)
+
+// startProfiling wires up -cpuprofile, -trace, and -pprof-http (all of the
+// simulator's own CPU/memory behavior, not the simulated program's), and
+// returns a stop function that flushes and closes whatever was started.
+// stop is safe to call more than once — once from the normal exit path,
+// once from a SIGINT/SIGTERM handler racing it — and only acts the first
+// time, so a signal arriving mid-run still preserves a usable profile
+// instead of a truncated or doubly-closed one.
+func startProfiling() (stop func()) {
+	var once sync.Once
+	var cpuFile, traceFile *os.File
+
+	if *pprofHTTPAddr != "" {
+		go func() {
+			if err := http.ListenAndServe(*pprofHTTPAddr, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "pprof http server: %v\n", err)
+			}
+		}()
+	}
+
+	if *cpuProfileFile != "" {
+		f, err := os.Create(*cpuProfileFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+		cpuFile = f
+	}
+
+	if *traceOutFile != "" {
+		f, err := os.Create(*traceOutFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating trace file: %v\n", err)
+			os.Exit(1)
+		}
+		if err := trace.Start(f); err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting trace: %v\n", err)
+			os.Exit(1)
+		}
+		traceFile = f
+	}
+
+	return func() {
+		once.Do(func() {
+			if cpuFile != nil {
+				pprof.StopCPUProfile()
+				cpuFile.Close()
+			}
+			if traceFile != nil {
+				trace.Stop()
+				traceFile.Close()
+			}
+			if *memProfileFile != "" {
+				writeMemProfile(*memProfileFile)
+			}
+		})
+	}
+}
+
+// writeMemProfile forces a GC so the heap profile reflects live objects
+// rather than garbage still awaiting collection, then writes it to path.
+func writeMemProfile(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating memory profile: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing memory profile: %v\n", err)
+	}
+}
+
+// exitAfterProfiling flushes whatever startProfiling started via stop,
+// then exits with code. It's the only path main should use to leave the
+// process once profiling may be active, so -cpuprofile/-trace/-memprofile
+// output is never left unflushed by an early os.Exit.
+func exitAfterProfiling(stop func(), code int) {
+	stop()
+	os.Exit(code)
+}
+
+// handleProfilingSignals flushes profiles and exits if the process
+// receives SIGINT or SIGTERM, so a benchmark sweep killed partway through
+// (e.g. a long PolyBench run) still leaves a usable partial profile
+// instead of losing everything captured so far.
+func handleProfilingSignals(stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		exitAfterProfiling(stop, 1)
+	}()
+}
@@ -0,0 +1,41 @@
+// Command emutest-gen writes emutest's generated regression vectors to
+// disk, one JSON file per mnemonic, for use as an emutest.LoadVectors
+// directory.
+//
+// Usage:
+//
+//	go run ./cmd/emutest-gen [-o dir]
+//
+// Flags:
+//
+//	-o  Output directory (default: ./testdata, created if missing)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sarchlab/m2sim/emutest"
+)
+
+func main() {
+	outDir := flag.String("o", "./testdata", "output directory for vector files")
+	flag.Parse()
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "emutest-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	byMnemonic := emutest.GenerateVectors()
+	for mnemonic, vectors := range byMnemonic {
+		path := filepath.Join(*outDir, mnemonic+".json")
+		if err := emutest.WriteVectorFile(path, vectors); err != nil {
+			fmt.Fprintf(os.Stderr, "emutest-gen: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %s (%d vectors)\n", path, len(vectors))
+	}
+}
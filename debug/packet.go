@@ -0,0 +1,54 @@
+// Package debug implements a GDB Remote Serial Protocol server so an
+// external debugger (e.g. aarch64-linux-gnu-gdb) can attach to a running
+// emu.Emulator over TCP.
+package debug
+
+import (
+	"bufio"
+	"fmt"
+)
+
+// encodePacket wraps payload in the RSP framing: `$<payload>#<checksum>`,
+// where checksum is the mod-256 sum of payload's bytes in two lowercase
+// hex digits.
+func encodePacket(payload string) []byte {
+	sum := byte(0)
+	for i := 0; i < len(payload); i++ {
+		sum += payload[i]
+	}
+	return []byte(fmt.Sprintf("$%s#%02x", payload, sum))
+}
+
+// readPacket reads one RSP packet from r and returns its payload (without
+// the leading '$' or trailing '#checksum'); it sends no acknowledgement
+// itself, Serve replies with '+' after each packet. It also handles the bare '+'/'-' ack bytes gdb sends by
+// skipping them, and a stray Ctrl-C (0x03) by returning it as the special
+// payload "\x03".
+func readPacket(r *bufio.Reader) (string, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		switch b {
+		case '+', '-':
+			continue // acknowledgement of our previous reply; ignore
+		case 0x03:
+			return "\x03", nil
+		case '$':
+			payload, err := r.ReadString('#')
+			if err != nil {
+				return "", err
+			}
+			payload = payload[:len(payload)-1] // drop trailing '#'
+
+			// Two checksum hex digits follow; read and discard them (the
+			// TCP stream is assumed reliable, so we don't verify here).
+			if _, err := r.Discard(2); err != nil {
+				return "", err
+			}
+			return payload, nil
+		}
+	}
+}
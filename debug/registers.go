@@ -0,0 +1,165 @@
+package debug
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Register layout gdb expects for an AArch64 target: x0-x30, sp, pc, cpsr.
+const (
+	numGPR     = 31 // x0..x30
+	regSP      = numGPR
+	regPC      = numGPR + 1
+	regCPSR    = numGPR + 2
+	numAllRegs = numGPR + 3
+
+	gprSize  = 8
+	cpsrSize = 4
+)
+
+// encodeRegs returns the 'g' packet payload: every register's bytes,
+// little-endian, concatenated and hex-encoded in gdb's register order.
+func encodeRegs(e *emu.Emulator) string {
+	rf := e.RegFile()
+
+	buf := make([]byte, 0, numGPR*gprSize+gprSize+gprSize+cpsrSize)
+	for i := 0; i < numGPR; i++ {
+		buf = appendLE64(buf, rf.ReadReg(uint8(i)))
+	}
+	buf = appendLE64(buf, rf.SP)
+	buf = appendLE64(buf, rf.PC)
+	buf = appendLE32(buf, packCPSR(e))
+
+	return hex.EncodeToString(buf)
+}
+
+// decodeRegs parses a 'G' packet payload and writes every register back
+// into the emulator's register file.
+func decodeRegs(e *emu.Emulator, payload string) error {
+	raw, err := hex.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("debug: bad register payload: %w", err)
+	}
+
+	rf := e.RegFile()
+	off := 0
+	for i := 0; i < numGPR; i++ {
+		rf.WriteReg(uint8(i), readLE64(raw, off))
+		off += gprSize
+	}
+	rf.SP = readLE64(raw, off)
+	off += gprSize
+	rf.PC = readLE64(raw, off)
+	off += gprSize
+	unpackCPSR(e, readLE32(raw, off))
+
+	return nil
+}
+
+// encodeOneReg returns the hex-encoded value of register n (gdb numbering:
+// 0-30 = x0-x30, 31 = sp, 32 = pc, 33 = cpsr).
+func encodeOneReg(e *emu.Emulator, n int) (string, error) {
+	rf := e.RegFile()
+
+	switch {
+	case n >= 0 && n < numGPR:
+		return hex.EncodeToString(le64(rf.ReadReg(uint8(n)))), nil
+	case n == regSP:
+		return hex.EncodeToString(le64(rf.SP)), nil
+	case n == regPC:
+		return hex.EncodeToString(le64(rf.PC)), nil
+	case n == regCPSR:
+		return hex.EncodeToString(le32(packCPSR(e))), nil
+	default:
+		return "", fmt.Errorf("debug: unknown register %d", n)
+	}
+}
+
+// decodeOneReg writes hexValue into register n.
+func decodeOneReg(e *emu.Emulator, n int, hexValue string) error {
+	raw, err := hex.DecodeString(hexValue)
+	if err != nil {
+		return fmt.Errorf("debug: bad register value: %w", err)
+	}
+
+	rf := e.RegFile()
+	switch {
+	case n >= 0 && n < numGPR:
+		rf.WriteReg(uint8(n), readLE64(raw, 0))
+	case n == regSP:
+		rf.SP = readLE64(raw, 0)
+	case n == regPC:
+		rf.PC = readLE64(raw, 0)
+	case n == regCPSR:
+		unpackCPSR(e, readLE32(raw, 0))
+	default:
+		return fmt.Errorf("debug: unknown register %d", n)
+	}
+	return nil
+}
+
+// packCPSR folds PSTATE.{N,Z,C,V} into the NZCV bits of a CPSR word, the
+// only flags this emulator currently models.
+func packCPSR(e *emu.Emulator) uint32 {
+	p := e.RegFile().PSTATE
+	var v uint32
+	if p.N {
+		v |= 1 << 31
+	}
+	if p.Z {
+		v |= 1 << 30
+	}
+	if p.C {
+		v |= 1 << 29
+	}
+	if p.V {
+		v |= 1 << 28
+	}
+	return v
+}
+
+// unpackCPSR writes the NZCV bits of cpsr back into PSTATE.
+func unpackCPSR(e *emu.Emulator, cpsr uint32) {
+	p := &e.RegFile().PSTATE
+	p.N = cpsr&(1<<31) != 0
+	p.Z = cpsr&(1<<30) != 0
+	p.C = cpsr&(1<<29) != 0
+	p.V = cpsr&(1<<28) != 0
+}
+
+func appendLE64(buf []byte, v uint64) []byte { return append(buf, le64(v)...) }
+func appendLE32(buf []byte, v uint32) []byte { return append(buf, le32(v)...) }
+
+func le64(v uint64) []byte {
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return b
+}
+
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return b
+}
+
+func readLE64(b []byte, off int) uint64 {
+	var v uint64
+	for i := 0; i < 8 && off+i < len(b); i++ {
+		v |= uint64(b[off+i]) << (8 * i)
+	}
+	return v
+}
+
+func readLE32(b []byte, off int) uint32 {
+	var v uint32
+	for i := 0; i < 4 && off+i < len(b); i++ {
+		v |= uint32(b[off+i]) << (8 * i)
+	}
+	return v
+}
@@ -0,0 +1,358 @@
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Server speaks the GDB Remote Serial Protocol against a single
+// emu.Emulator, so `target remote :<port>` from an AArch64 gdb can
+// inspect and control a running emulation.
+type Server struct {
+	emulator *emu.Emulator
+
+	breakpoints map[uint64]bool
+}
+
+// NewServer creates a debug server fronting emulator. It installs a step
+// hook on emulator to implement software breakpoints; only one Server
+// should be attached to a given emulator at a time.
+func NewServer(emulator *emu.Emulator) *Server {
+	s := &Server{
+		emulator:    emulator,
+		breakpoints: make(map[uint64]bool),
+	}
+	emulator.SetStepHook(s.stepHook)
+	return s
+}
+
+// stepHook is installed on the emulator; it requests a stop whenever PC
+// lands on an active software breakpoint.
+func (s *Server) stepHook(pc uint64) emu.StepAction {
+	if s.breakpoints[pc] {
+		return emu.StepTrap
+	}
+	return emu.StepContinue
+}
+
+// ListenAndServe listens on addr (e.g. ":1234") and serves gdb sessions
+// one at a time until the listener is closed or an error occurs.
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("debug: listen: %w", err)
+	}
+	defer ln.Close()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return fmt.Errorf("debug: accept: %w", err)
+		}
+		s.Serve(conn)
+		conn.Close()
+	}
+}
+
+// Serve handles one gdb session to completion on conn (until it closes or
+// gdb sends a 'k' kill / detaches). ListenAndServe calls this per accepted
+// connection; callers embedding Server in their own listener loop can call
+// it directly.
+func (s *Server) Serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	for {
+		payload, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		if payload == "\x03" {
+			continue // Ctrl-C with nothing running; nothing to interrupt
+		}
+
+		// Acknowledge receipt before processing, per the RSP handshake.
+		if _, err := conn.Write([]byte("+")); err != nil {
+			return
+		}
+
+		reply, closeAfter := s.handle(payload)
+		if reply != "" {
+			if _, err := conn.Write(encodePacket(reply)); err != nil {
+				return
+			}
+		}
+		if closeAfter {
+			return
+		}
+	}
+}
+
+// handle dispatches one command payload and returns the reply payload
+// (unframed) and whether the connection should be closed afterward.
+func (s *Server) handle(payload string) (reply string, closeConn bool) {
+	switch {
+	case payload == "?":
+		return "S05", false
+
+	case payload == "g":
+		return encodeRegs(s.emulator), false
+
+	case strings.HasPrefix(payload, "G"):
+		if err := decodeRegs(s.emulator, payload[1:]); err != nil {
+			return "E01", false
+		}
+		return "OK", false
+
+	case strings.HasPrefix(payload, "p"):
+		n, err := strconv.ParseInt(payload[1:], 16, 64)
+		if err != nil {
+			return "E01", false
+		}
+		val, err := encodeOneReg(s.emulator, int(n))
+		if err != nil {
+			return "E01", false
+		}
+		return val, false
+
+	case strings.HasPrefix(payload, "P"):
+		parts := strings.SplitN(payload[1:], "=", 2)
+		if len(parts) != 2 {
+			return "E01", false
+		}
+		n, err := strconv.ParseInt(parts[0], 16, 64)
+		if err != nil {
+			return "E01", false
+		}
+		if err := decodeOneReg(s.emulator, int(n), parts[1]); err != nil {
+			return "E01", false
+		}
+		return "OK", false
+
+	case strings.HasPrefix(payload, "m"):
+		return s.handleReadMem(payload[1:]), false
+
+	case strings.HasPrefix(payload, "M"):
+		return s.handleWriteMem(payload[1:]), false
+
+	case payload == "s":
+		return s.stepAndReport(), false
+
+	case payload == "c":
+		return s.continueAndReport(), false
+
+	case strings.HasPrefix(payload, "Z0,"):
+		return s.handleAddBreakpoint(payload[len("Z0,"):]), false
+
+	case strings.HasPrefix(payload, "z0,"):
+		return s.handleRemoveBreakpoint(payload[len("z0,"):]), false
+
+	case strings.HasPrefix(payload, "vCont"):
+		return s.handleVCont(payload), false
+
+	case strings.HasPrefix(payload, "qSupported"):
+		return "PacketSize=4000;qXfer:features:read+;vContSupported+", false
+
+	case strings.HasPrefix(payload, "qXfer:features:read:target.xml:"):
+		return s.handleXferFeatures(payload[len("qXfer:features:read:target.xml:"):]), false
+
+	case payload == "k":
+		return "", true
+
+	default:
+		return "", false // unsupported command: empty reply per the RSP spec
+	}
+}
+
+// handleReadMem implements 'm addr,length'.
+func (s *Server) handleReadMem(args string) string {
+	addr, length, err := parseAddrLength(args)
+	if err != nil {
+		return "E01"
+	}
+
+	buf := make([]byte, length)
+	mem := s.emulator.Memory()
+	for i := uint64(0); i < length; i++ {
+		buf[i] = mem.Read8(addr + i)
+	}
+	mem.TakeFault() // a read of unmapped debug-inspected memory is not fatal
+
+	return bytesToHex(buf)
+}
+
+// handleWriteMem implements 'M addr,length:XX...'.
+func (s *Server) handleWriteMem(args string) string {
+	head, data, ok := strings.Cut(args, ":")
+	if !ok {
+		return "E01"
+	}
+	addr, length, err := parseAddrLength(head)
+	if err != nil {
+		return "E01"
+	}
+
+	raw, err := hexToBytes(data)
+	if err != nil || uint64(len(raw)) != length {
+		return "E01"
+	}
+
+	mem := s.emulator.Memory()
+	for i, b := range raw {
+		mem.Write8(addr+uint64(i), b)
+	}
+	mem.TakeFault()
+
+	return "OK"
+}
+
+// stepAndReport single-steps once and reports SIGTRAP (the conventional
+// "something happened, go ahead and look" reply) unless the program
+// exited.
+func (s *Server) stepAndReport() string {
+	result := s.emulator.Step()
+	if result.Exited {
+		return fmt.Sprintf("W%02x", byte(result.ExitCode))
+	}
+	return "S05"
+}
+
+// continueAndReport runs the emulator until it exits or a breakpoint
+// (StepTrap) halts it.
+func (s *Server) continueAndReport() string {
+	for {
+		result := s.emulator.Step()
+		if result.Exited {
+			return fmt.Sprintf("W%02x", byte(result.ExitCode))
+		}
+		if result.Stopped {
+			return "S05"
+		}
+		if result.Err != nil {
+			return "S05"
+		}
+	}
+}
+
+// handleAddBreakpoint implements 'Z0,addr,kind'.
+func (s *Server) handleAddBreakpoint(args string) string {
+	addr, err := parseBreakpointAddr(args)
+	if err != nil {
+		return "E01"
+	}
+	s.breakpoints[addr] = true
+	return "OK"
+}
+
+// handleRemoveBreakpoint implements 'z0,addr,kind'.
+func (s *Server) handleRemoveBreakpoint(args string) string {
+	addr, err := parseBreakpointAddr(args)
+	if err != nil {
+		return "E01"
+	}
+	delete(s.breakpoints, addr)
+	return "OK"
+}
+
+// handleVCont implements enough of 'vCont' for gdb's default behavior:
+// advertise continue/step support, and execute whichever was requested.
+func (s *Server) handleVCont(payload string) string {
+	if payload == "vCont?" {
+		return "vCont;c;s"
+	}
+
+	// "vCont;c" or "vCont;s", optionally with a ":tid" suffix we ignore
+	// (this server only ever drives a single thread of execution).
+	action := strings.TrimPrefix(payload, "vCont;")
+	action, _, _ = strings.Cut(action, ":")
+
+	switch action {
+	case "s":
+		return s.stepAndReport()
+	case "c":
+		return s.continueAndReport()
+	default:
+		return "E01"
+	}
+}
+
+// handleXferFeatures implements 'qXfer:features:read:target.xml:offset,length'.
+func (s *Server) handleXferFeatures(args string) string {
+	offset, length, err := parseAddrLength(args)
+	if err != nil {
+		return "E01"
+	}
+
+	if offset >= uint64(len(targetXML)) {
+		return "l" // 'l' = last chunk, nothing more to send
+	}
+
+	end := offset + length
+	if end > uint64(len(targetXML)) {
+		end = uint64(len(targetXML))
+	}
+
+	chunk := targetXML[offset:end]
+	marker := "m" // more data follows
+	if end == uint64(len(targetXML)) {
+		marker = "l"
+	}
+	return marker + chunk
+}
+
+// parseAddrLength parses the common "addr,length" argument shape used by
+// m/M/qXfer, both given as hex.
+func parseAddrLength(s string) (addr, length uint64, err error) {
+	parts := strings.SplitN(s, ",", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("debug: malformed addr,length %q", s)
+	}
+	addr, err = strconv.ParseUint(parts[0], 16, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err = strconv.ParseUint(parts[1], 16, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return addr, length, nil
+}
+
+// parseBreakpointAddr parses "addr,kind" from a Z0/z0 packet, ignoring
+// kind (this server only implements 4-byte software breakpoints).
+func parseBreakpointAddr(s string) (uint64, error) {
+	parts := strings.SplitN(s, ",", 2)
+	if len(parts) == 0 {
+		return 0, fmt.Errorf("debug: malformed breakpoint spec %q", s)
+	}
+	return strconv.ParseUint(parts[0], 16, 64)
+}
+
+func bytesToHex(b []byte) string {
+	const digits = "0123456789abcdef"
+	out := make([]byte, len(b)*2)
+	for i, c := range b {
+		out[i*2] = digits[c>>4]
+		out[i*2+1] = digits[c&0xF]
+	}
+	return string(out)
+}
+
+func hexToBytes(s string) ([]byte, error) {
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("debug: odd-length hex string")
+	}
+	out := make([]byte, len(s)/2)
+	for i := range out {
+		v, err := strconv.ParseUint(s[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = byte(v)
+	}
+	return out, nil
+}
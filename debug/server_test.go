@@ -0,0 +1,118 @@
+package debug_test
+
+import (
+	"bufio"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/debug"
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// dialServer starts a debug.Server for emulator on an ephemeral port and
+// returns a connected client conn and a reader over it.
+func dialServer(emulator *emu.Emulator) (net.Conn, *bufio.Reader) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	server := debug.NewServer(emulator)
+	go func() {
+		defer GinkgoRecover()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.Serve(conn)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	Expect(err).ToNot(HaveOccurred())
+
+	return conn, bufio.NewReader(conn)
+}
+
+func sendRaw(conn net.Conn, raw string) {
+	_, err := conn.Write([]byte(raw))
+	Expect(err).ToNot(HaveOccurred())
+}
+
+// readReply reads and returns the payload of the next RSP packet,
+// discarding the leading '+' ack byte the server sends for every command.
+func readReply(r *bufio.Reader) string {
+	b, err := r.ReadByte()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(b).To(Equal(byte('+')))
+
+	Expect(r.ReadByte()).To(Equal(byte('$')))
+	payload, err := r.ReadString('#')
+	Expect(err).ToNot(HaveOccurred())
+	_, err = r.Discard(2)
+	Expect(err).ToNot(HaveOccurred())
+
+	return payload[:len(payload)-1]
+}
+
+var _ = Describe("Server", func() {
+	var emulator *emu.Emulator
+
+	BeforeEach(func() {
+		emulator = emu.NewEmulator()
+	})
+
+	It("should report a stopped status for '?'", func() {
+		conn, r := dialServer(emulator)
+		defer conn.Close()
+
+		sendRaw(conn, "$?#3f")
+
+		Expect(readReply(r)).To(Equal("S05"))
+	})
+
+	It("should round-trip a register write/read through P and p", func() {
+		conn, r := dialServer(emulator)
+		defer conn.Close()
+
+		sendRaw(conn, "$P0=2a00000000000000#00")
+		Expect(readReply(r)).To(Equal("OK"))
+
+		sendRaw(conn, "$p0#00")
+		Expect(readReply(r)).To(Equal("2a00000000000000"))
+	})
+
+	It("should round-trip memory through M and m", func() {
+		emulator.Memory().Mmap(0x1000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+
+		conn, r := dialServer(emulator)
+		defer conn.Close()
+
+		sendRaw(conn, "$M1000,4:deadbeef#00")
+		Expect(readReply(r)).To(Equal("OK"))
+
+		sendRaw(conn, "$m1000,4#00")
+		Expect(readReply(r)).To(Equal("deadbeef"))
+	})
+
+	It("should stop a continue at a software breakpoint", func() {
+		mem := emulator.Memory()
+		mem.Mmap(0x2000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE|emu.PROT_EXEC, true)
+		// "ADD X0, X0, #0" repeated: a harmless instruction that just
+		// advances PC, standing in for a NOP.
+		for i := uint64(0); i < 4; i++ {
+			mem.Write32(0x2000+i*4, 0x91000000)
+		}
+		emulator.RegFile().PC = 0x2000
+
+		conn, r := dialServer(emulator)
+		defer conn.Close()
+
+		sendRaw(conn, "$Z0,2008,4#00")
+		Expect(readReply(r)).To(Equal("OK"))
+
+		sendRaw(conn, "$c#63")
+		Expect(readReply(r)).To(Equal("S05"))
+		Expect(emulator.RegFile().PC).To(Equal(uint64(0x2008)))
+	})
+})
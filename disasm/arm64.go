@@ -0,0 +1,49 @@
+package disasm
+
+// Mask/match pairs for the ARM64 encoding classes this package covers,
+// in architectural bit order (bit 31 down to bit 0). Values are spelled
+// out from the ARM Architecture Reference Manual's encoding diagrams
+// rather than pulled from a generated table, since this package doesn't
+// (yet) cover enough of the instruction set to justify one.
+
+// Load/store register (unsigned immediate): size(31:30) 111 V(26)=0 01
+// opc(23:22) imm12(21:10) Rn(9:5) Rt(4:0).
+const (
+	ldstMask = 0x3F000000
+	ldstBase = 0x39000000
+
+	sizeMask = 0xC0000000
+	size32   = 0x80000000
+	size64   = 0xC0000000
+
+	opcMask  = 0x00C00000
+	opcStore = 0x00000000
+	opcLoad  = 0x00400000
+)
+
+// Unconditional branch (immediate): op(31) 00101 imm26(25:0).
+const (
+	branchMask = 0xFC000000
+	bBase      = 0x14000000
+	blBase     = 0x94000000
+)
+
+// Conditional branch (immediate): 0101010 o1(24)=0 imm19(23:5) o0(4)=0 cond(3:0).
+const (
+	bcondMask = 0xFF000010
+	bcondBase = 0x54000000
+)
+
+// Unconditional branch (register): 1101011 opc(24:21) op2(20:16)=11111 op3(15:10)=000000 Rn(9:5) op4(4:0)=00000.
+const (
+	branchRegMask = 0xFFFFFC1F
+	brBase        = 0xD61F0000
+	blrBase       = 0xD63F0000
+	retBase       = 0xD65F0000
+)
+
+// SVC: 11010100 000 imm16(20:5) 000 01.
+const (
+	svcMask = 0xFFE0001F
+	svcBase = 0xD4000001
+)
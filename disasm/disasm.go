@@ -0,0 +1,242 @@
+// Package disasm decodes raw 32-bit ARM64 instruction words into a
+// structured Inst and renders it as GNU or Go assembler syntax, so a
+// trace of what the emulator executed can be cross-checked against
+// objdump -d output line by line.
+//
+// Decoding itself is built on emu/decode's Entry/FieldSpec/Table, the
+// mask-and-extracted-fields approach this repository already settled on
+// for the conditional-compare/select block, rather than a second
+// hand-rolled decoder; this package only adds the per-entry rendering
+// step decode.DecodedInst doesn't have an opinion about. It currently
+// covers the load/store (unsigned immediate), branch, conditional
+// branch, branch-register, and SVC classes the emu package executes —
+// the set named in the request this package was added for — not the
+// full ARM64 encoding space.
+package disasm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sarchlab/m2sim/emu/decode"
+)
+
+// Inst is a decoded instruction, general enough to render as either GNU
+// or Go assembler syntax.
+type Inst struct {
+	Op   string // canonical GNU mnemonic, e.g. "ldr", "str", "b.eq", "svc"
+	Is64 bool   // operand width, where the instruction has one
+
+	HasRd bool
+	Rd    uint8 // transfer register: Rt for load/store, Rd elsewhere
+
+	HasRn  bool
+	Rn     uint8 // base or branch-target register
+	RnIsSP bool  // Rn==31 means SP (load/store base), not XZR/WZR
+
+	HasImm bool
+	Imm    int64 // already sign-extended and scaled (bytes for mem offsets, bytes for branch targets)
+
+	IsMem bool // true for load/store: renders as "[Rn, #imm]" / "(Rn)"
+}
+
+// GNUSyntax renders inst the way objdump -d / the GNU assembler would,
+// e.g. "ldr x0, [x1, #8]" or "b.eq #0x28".
+func (inst Inst) GNUSyntax() string {
+	var args []string
+	if inst.HasRd {
+		args = append(args, gnuReg(inst.Rd, inst.Is64))
+	}
+	if inst.IsMem {
+		base := gnuRegOrSP(inst.Rn, true)
+		if inst.Imm != 0 {
+			args = append(args, fmt.Sprintf("[%s, #%d]", base, inst.Imm))
+		} else {
+			args = append(args, fmt.Sprintf("[%s]", base))
+		}
+	} else {
+		if inst.HasRn {
+			args = append(args, gnuReg(inst.Rn, true))
+		}
+		if inst.HasImm {
+			if inst.Imm < 0 {
+				args = append(args, fmt.Sprintf("#-0x%x", -inst.Imm))
+			} else {
+				args = append(args, fmt.Sprintf("#0x%x", inst.Imm))
+			}
+		}
+	}
+
+	if len(args) == 0 {
+		return inst.Op
+	}
+	return inst.Op + " " + strings.Join(args, ", ")
+}
+
+// GoSyntax renders inst the way golang.org/x/arch/arm64/arm64asm's
+// GoSyntax renders a Go assembler instruction: uppercase mnemonic,
+// "R0".."R30"/"ZR"/"RSP" registers, "$imm" immediates, source operands
+// before the destination. It is a best-effort rendering of the classes
+// this package covers, not a full Go assembler encoder.
+func (inst Inst) GoSyntax() string {
+	op := strings.ToUpper(strings.ReplaceAll(inst.Op, ".", ""))
+
+	var args []string
+	if inst.IsMem {
+		base := goRegOrSP(inst.Rn, inst.RnIsSP)
+		args = append(args, fmt.Sprintf("%d(%s)", inst.Imm, base))
+		if inst.HasRd {
+			args = append(args, goReg(inst.Rd))
+		}
+	} else {
+		if inst.HasImm {
+			args = append(args, fmt.Sprintf("$%d", inst.Imm))
+		}
+		if inst.HasRn {
+			args = append(args, goReg(inst.Rn))
+		}
+		if inst.HasRd {
+			args = append(args, goReg(inst.Rd))
+		}
+	}
+
+	if len(args) == 0 {
+		return op
+	}
+	return op + " " + strings.Join(args, ", ")
+}
+
+func gnuReg(n uint8, is64 bool) string {
+	if n == 31 {
+		if is64 {
+			return "xzr"
+		}
+		return "wzr"
+	}
+	if is64 {
+		return fmt.Sprintf("x%d", n)
+	}
+	return fmt.Sprintf("w%d", n)
+}
+
+// gnuRegOrSP is gnuReg, except register 31 means SP rather than the zero
+// register — the Rn==31 special case LDR64SP/STR64SP already hard-code,
+// here generalized for any load/store base register.
+func gnuRegOrSP(n uint8, is64 bool) string {
+	if n == 31 {
+		return "sp"
+	}
+	return gnuReg(n, is64)
+}
+
+func goReg(n uint8) string {
+	if n == 31 {
+		return "ZR"
+	}
+	return fmt.Sprintf("R%d", n)
+}
+
+func goRegOrSP(n uint8, isSP bool) string {
+	if n == 31 && isSP {
+		return "RSP"
+	}
+	return goReg(n)
+}
+
+// condNames are the 16 AArch64 condition suffixes in architectural
+// encoding order, matching emu.ConditionCode's ordering (CondEQ..CondNV).
+var condNames = [16]string{
+	"eq", "ne", "cs", "cc", "mi", "pl", "vs", "vc",
+	"hi", "ls", "ge", "lt", "gt", "le", "al", "nv",
+}
+
+// signExtend sign-extends the low bits-wide field of value to int64.
+func signExtend(value uint32, bits uint8) int64 {
+	shift := 32 - bits
+	return int64(int32(value<<shift)) >> shift
+}
+
+var table = decode.NewTable([]decode.Entry{
+	{Mask: ldstMask | sizeMask | opcMask, Match: ldstBase | size64 | opcLoad, Name: "LDR64", Fields: ldstFields},
+	{Mask: ldstMask | sizeMask | opcMask, Match: ldstBase | size32 | opcLoad, Name: "LDR32", Fields: ldstFields},
+	{Mask: ldstMask | sizeMask | opcMask, Match: ldstBase | size64 | opcStore, Name: "STR64", Fields: ldstFields},
+	{Mask: ldstMask | sizeMask | opcMask, Match: ldstBase | size32 | opcStore, Name: "STR32", Fields: ldstFields},
+
+	{Mask: branchMask, Match: bBase, Name: "B", Fields: branchFields},
+	{Mask: branchMask, Match: blBase, Name: "BL", Fields: branchFields},
+	{Mask: bcondMask, Match: bcondBase, Name: "BCOND", Fields: bcondFields},
+
+	{Mask: branchRegMask, Match: brBase, Name: "BR", Fields: branchRegFields},
+	{Mask: branchRegMask, Match: blrBase, Name: "BLR", Fields: branchRegFields},
+	{Mask: branchRegMask, Match: retBase, Name: "RET", Fields: branchRegFields},
+
+	{Mask: svcMask, Match: svcBase, Name: "SVC", Fields: svcFields},
+})
+
+// ldstFields, branchFields, etc. name the bit-field extractors each
+// encoding class needs; see arm64.go for the masks/matches themselves.
+var (
+	ldstFields      = []decode.FieldSpec{{Name: "Rt", Hi: 4, Lo: 0}, {Name: "Rn", Hi: 9, Lo: 5}, {Name: "imm12", Hi: 21, Lo: 10}}
+	branchFields    = []decode.FieldSpec{{Name: "imm26", Hi: 25, Lo: 0}}
+	bcondFields     = []decode.FieldSpec{{Name: "cond", Hi: 3, Lo: 0}, {Name: "imm19", Hi: 23, Lo: 5}}
+	branchRegFields = []decode.FieldSpec{{Name: "Rn", Hi: 9, Lo: 5}}
+	svcFields       = []decode.FieldSpec{{Name: "imm16", Hi: 20, Lo: 5}}
+)
+
+// renderers map each Table entry's Name to the function that turns its
+// decoded fields into an Inst. Kept separate from decode.Entry (which
+// has no opinion about rendering) rather than extending that type for
+// this one package's sake.
+var renderers = map[string]func(fields map[string]uint32) Inst{
+	"LDR64": func(f map[string]uint32) Inst { return ldst("ldr", true, f) },
+	"LDR32": func(f map[string]uint32) Inst { return ldst("ldr", false, f) },
+	"STR64": func(f map[string]uint32) Inst { return ldst("str", true, f) },
+	"STR32": func(f map[string]uint32) Inst { return ldst("str", false, f) },
+
+	"B": func(f map[string]uint32) Inst {
+		return Inst{Op: "b", HasImm: true, Imm: signExtend(f["imm26"], 26) * 4}
+	},
+	"BL": func(f map[string]uint32) Inst {
+		return Inst{Op: "bl", HasImm: true, Imm: signExtend(f["imm26"], 26) * 4}
+	},
+	"BCOND": func(f map[string]uint32) Inst {
+		return Inst{Op: "b." + condNames[f["cond"]&0xF], HasImm: true, Imm: signExtend(f["imm19"], 19) * 4}
+	},
+
+	"BR":  func(f map[string]uint32) Inst { return Inst{Op: "br", HasRn: true, Rn: uint8(f["Rn"])} },
+	"BLR": func(f map[string]uint32) Inst { return Inst{Op: "blr", HasRn: true, Rn: uint8(f["Rn"])} },
+	"RET": func(f map[string]uint32) Inst { return Inst{Op: "ret", HasRn: true, Rn: uint8(f["Rn"])} },
+
+	"SVC": func(f map[string]uint32) Inst { return Inst{Op: "svc", HasImm: true, Imm: int64(f["imm16"])} },
+}
+
+// ldst builds the Inst common to LDR/STR's unsigned-immediate form: the
+// 12-bit immediate is unsigned and scaled by the access size (4 for
+// 32-bit, 8 for 64-bit), per the ARM64 LDR/STR (immediate) encoding.
+func ldst(op string, is64 bool, f map[string]uint32) Inst {
+	scale := int64(4)
+	if is64 {
+		scale = 8
+	}
+	return Inst{
+		Op: op, Is64: is64,
+		HasRd: true, Rd: uint8(f["Rt"]),
+		HasRn: true, Rn: uint8(f["Rn"]), RnIsSP: true,
+		IsMem: true, HasImm: true, Imm: int64(f["imm12"]) * scale,
+	}
+}
+
+// Decode decodes word into an Inst, reporting ok=false if it doesn't
+// match any instruction class this package covers.
+func Decode(word uint32) (Inst, bool) {
+	decoded, ok := table.Decode(word)
+	if !ok {
+		return Inst{}, false
+	}
+
+	render, ok := renderers[decoded.Name]
+	if !ok {
+		return Inst{}, false
+	}
+	return render(decoded.Fields), true
+}
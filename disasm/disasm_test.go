@@ -0,0 +1,86 @@
+package disasm_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/disasm"
+)
+
+var _ = Describe("Decode", func() {
+	It("should decode LDR (64-bit, unsigned immediate)", func() {
+		inst, ok := disasm.Decode(0xF9400420) // ldr x0, [x1, #8]
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("ldr x0, [x1, #8]"))
+	})
+
+	It("should decode STR (32-bit, unsigned immediate)", func() {
+		inst, ok := disasm.Decode(0xB9000C22) // str w2, [x1, #12]
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("str w2, [x1, #12]"))
+	})
+
+	It("should render LDR/STR with SP as the base register", func() {
+		inst, ok := disasm.Decode(0xF94007E0) // ldr x0, [sp, #8]
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("ldr x0, [sp, #8]"))
+	})
+
+	It("should decode an unconditional branch with a forward displacement", func() {
+		inst, ok := disasm.Decode(0x14000002) // b #8
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("b #0x8"))
+	})
+
+	It("should decode BL", func() {
+		inst, ok := disasm.Decode(0x94000002) // bl #8
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("bl #0x8"))
+	})
+
+	It("should decode a conditional branch and render its condition suffix", func() {
+		inst, ok := disasm.Decode(0x54000040) // b.eq #8
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("b.eq #0x8"))
+	})
+
+	It("should decode a negative conditional branch displacement", func() {
+		inst, ok := disasm.Decode(0x54FFFFE1) // b.ne #-4
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("b.ne #-0x4"))
+	})
+
+	It("should decode RET", func() {
+		inst, ok := disasm.Decode(0xD65F03C0) // ret x30
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("ret x30"))
+	})
+
+	It("should decode SVC", func() {
+		inst, ok := disasm.Decode(0xD4000001) // svc #0
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GNUSyntax()).To(Equal("svc #0x0"))
+	})
+
+	It("should render Go assembler syntax for a load", func() {
+		inst, ok := disasm.Decode(0xF9400420) // ldr x0, [x1, #8]
+
+		Expect(ok).To(BeTrue())
+		Expect(inst.GoSyntax()).To(Equal("LDR 8(R1), R0"))
+	})
+
+	It("should report no match for an unrecognized word", func() {
+		_, ok := disasm.Decode(0x00000000)
+
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,215 @@
+// Package driver provides OS service emulation for ARM64 programs.
+package driver
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Linux ARM64 auxv types the loader populates. Only the ones real dynamic
+// loaders and libc startup code actually read are supported.
+const (
+	AT_NULL     = 0
+	AT_PHDR     = 3
+	AT_PHENT    = 4
+	AT_PHNUM    = 5
+	AT_PAGESZ   = 6
+	AT_ENTRY    = 9
+	AT_UID      = 11
+	AT_EUID     = 12
+	AT_GID      = 13
+	AT_EGID     = 14
+	AT_HWCAP    = 16
+	AT_SECURE   = 23
+	AT_RANDOM   = 25
+	AT_EXECFN   = 31
+	defaultSP   = 0x7FFF_0000_0000
+	stackSize   = 8 * 1024 * 1024
+	stackBottom = defaultSP - stackSize
+)
+
+// LoadedELF describes the outcome of loading an ELF64 ARM64 binary.
+type LoadedELF struct {
+	// Entry is the process entry point (AT_ENTRY / e_entry).
+	Entry uint64
+
+	// StackPointer is the initial SP, pointing at argc with argv/envp/auxv
+	// laid out above it per the Linux ARM64 calling convention.
+	StackPointer uint64
+}
+
+// LoadELF parses a statically-linked ELF64 ARM64 executable from raw,
+// maps its PT_LOAD segments into memory with the segment's permissions,
+// and builds the initial stack image (argv, envp, auxv) a libc/Go runtime
+// startup routine expects to find at SP.
+func LoadELF(memory *emu.Memory, raw []byte, argv, envp []string) (*LoadedELF, error) {
+	f, err := elf.NewFile(readerAt(raw))
+	if err != nil {
+		return nil, fmt.Errorf("driver: parse ELF: %w", err)
+	}
+	defer f.Close()
+
+	if f.Class != elf.ELFCLASS64 || f.Machine != elf.EM_AARCH64 {
+		return nil, fmt.Errorf("driver: unsupported ELF (class=%v machine=%v), want ELF64 AArch64", f.Class, f.Machine)
+	}
+
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		prot := progFlagsToProt(prog.Flags)
+		memory.MapAnonymous(prog.Vaddr, prog.Memsz, emu.PROT_READ|emu.PROT_WRITE)
+
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return nil, fmt.Errorf("driver: read PT_LOAD segment: %w", err)
+		}
+		for i, b := range data {
+			memory.Write8(prog.Vaddr+uint64(i), b)
+		}
+		// BSS tail (Memsz > Filesz) is left zeroed by the anonymous mapping.
+
+		memory.Mprotect(prog.Vaddr, prog.Memsz, prot)
+	}
+
+	phdrAddr := loadBias(f) + elf64PhOff(raw)
+	sp := buildInitialStack(memory, f, phdrAddr, argv, envp)
+
+	return &LoadedELF{
+		Entry:        f.Entry,
+		StackPointer: sp,
+	}, nil
+}
+
+// elf64PhOff reads e_phoff directly from the raw ELF64 header (offset
+// 0x20, 8 bytes, little-endian on AArch64) since debug/elf does not
+// expose it.
+func elf64PhOff(raw []byte) uint64 {
+	if len(raw) < 0x28 {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(raw[0x20:0x28])
+}
+
+// loadBias returns the virtual-address-minus-file-offset bias of the
+// PT_LOAD segment that covers file offset 0 (i.e. includes the ELF and
+// program headers), used to translate e_phoff into a virtual address for
+// AT_PHDR.
+func loadBias(f *elf.File) uint64 {
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_LOAD && prog.Off == 0 {
+			return prog.Vaddr
+		}
+	}
+	return 0
+}
+
+// progFlagsToProt converts ELF program header flags to emu.PROT_* bits.
+func progFlagsToProt(flags elf.ProgFlag) int {
+	prot := 0
+	if flags&elf.PF_R != 0 {
+		prot |= emu.PROT_READ
+	}
+	if flags&elf.PF_W != 0 {
+		prot |= emu.PROT_WRITE
+	}
+	if flags&elf.PF_X != 0 {
+		prot |= emu.PROT_EXEC
+	}
+	return prot
+}
+
+// buildInitialStack maps the process stack and writes argc, argv, envp,
+// and auxv at the top of it, matching the layout the ARM64 Linux kernel
+// hands to _start: [argc][argv...][NULL][envp...][NULL][auxv...][AT_NULL].
+func buildInitialStack(memory *emu.Memory, f *elf.File, phdrAddr uint64, argv, envp []string) uint64 {
+	memory.MapAnonymous(stackBottom, stackSize, emu.PROT_READ|emu.PROT_WRITE)
+
+	// Strings are placed just below the top of the stack, highest address
+	// first, so pointers to them can be recorded as we go.
+	sp := uint64(defaultSP)
+	writeStr := func(s string) uint64 {
+		b := append([]byte(s), 0)
+		sp -= uint64(len(b))
+		sp &= ^uint64(7) // keep 8-byte alignment for subsequent pointers
+		for i, c := range b {
+			memory.Write8(sp+uint64(i), c)
+		}
+		return sp
+	}
+
+	execfnPtr := uint64(0)
+	if len(argv) > 0 {
+		execfnPtr = writeStr(argv[0])
+	}
+	randomPtr := sp - 16
+	for i := uint64(0); i < 16; i++ {
+		memory.Write8(randomPtr+i, byte(i*7+1)) // deterministic, not CSPRNG
+	}
+	sp = randomPtr
+
+	argvPtrs := make([]uint64, len(argv))
+	for i, s := range argv {
+		argvPtrs[i] = writeStr(s)
+	}
+	envpPtrs := make([]uint64, len(envp))
+	for i, s := range envp {
+		envpPtrs[i] = writeStr(s)
+	}
+
+	sp &= ^uint64(15) // 16-byte align before the pointer tables
+
+	auxv := []uint64{
+		AT_PAGESZ, emu.PageSize,
+		AT_PHDR, phdrAddr,
+		AT_PHENT, elf64PhentSize,
+		AT_PHNUM, uint64(len(f.Progs)),
+		AT_ENTRY, f.Entry,
+		AT_UID, 0,
+		AT_EUID, 0,
+		AT_GID, 0,
+		AT_EGID, 0,
+		AT_SECURE, 0,
+		AT_RANDOM, randomPtr,
+		AT_EXECFN, execfnPtr,
+		AT_NULL, 0,
+	}
+
+	words := make([]uint64, 0, 1+len(argvPtrs)+1+len(envpPtrs)+1+len(auxv))
+	words = append(words, uint64(len(argvPtrs)))
+	words = append(words, argvPtrs...)
+	words = append(words, 0)
+	words = append(words, envpPtrs...)
+	words = append(words, 0)
+	words = append(words, auxv...)
+
+	sp -= uint64(len(words)) * 8
+	sp &= ^uint64(15)
+	for i, w := range words {
+		memory.Write64(sp+uint64(i)*8, w)
+	}
+
+	return sp
+}
+
+// elf64PhentSize is sizeof(Elf64_Phdr), reported to the process via
+// AT_PHENT.
+const elf64PhentSize = 56
+
+// readerAt adapts a byte slice to io.ReaderAt for debug/elf.NewFile.
+type readerAt []byte
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off >= int64(len(r)) {
+		return 0, fmt.Errorf("driver: ELF read out of range at offset %d", off)
+	}
+	n := copy(p, r[off:])
+	if n < len(p) {
+		return n, fmt.Errorf("driver: short ELF read at offset %d", off)
+	}
+	return n, nil
+}
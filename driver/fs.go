@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"io"
+	"os"
+)
+
+// File is the subset of *os.File behavior a guest file descriptor opened
+// via openat needs: positioned reads, seeking, stat, and close.
+// *os.File satisfies this directly, so osFS just returns the file
+// os.OpenFile gives it.
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Closer
+	Seek(offset int64, whence int) (int64, error)
+	Stat() (os.FileInfo, error)
+}
+
+// FS abstracts the filesystem openat/newfstatat resolve guest pathnames
+// against, so tests can substitute an in-memory filesystem instead of
+// touching the host disk. WithFS installs one; the default is osFS.
+type FS interface {
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Stat(name string) (os.FileInfo, error)
+}
+
+// osFS is the default FS, backed directly by the host filesystem.
+type osFS struct{}
+
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// WithFS sets the filesystem openat, newfstatat and friends resolve guest
+// pathnames against. The default is the host filesystem, via os.OpenFile
+// and os.Stat.
+func WithFS(fs FS) Option {
+	return func(h *SyscallHandler) {
+		h.fs = fs
+	}
+}
@@ -10,15 +10,22 @@ import (
 
 // ARM64 Linux syscall numbers.
 const (
-	SyscallWrite uint64 = 64 // write(fd, buf, count)
-	SyscallExit  uint64 = 93 // exit(status)
+	SyscallWrite    uint64 = 64  // write(fd, buf, count)
+	SyscallBrk      uint64 = 214 // brk(addr)
+	SyscallMunmap   uint64 = 215 // munmap(addr, length)
+	SyscallMprotect uint64 = 226 // mprotect(addr, length, prot)
+	SyscallMmap     uint64 = 222 // mmap(addr, length, prot, flags, fd, offset)
+	SyscallExit     uint64 = 93  // exit(status)
 )
 
 // Linux error codes.
 const (
+	ENOENT = 2  // No such file or directory
+	EIO    = 5  // I/O error
 	EBADF  = 9  // Bad file descriptor
+	EACCES = 13 // Permission denied
+	EINVAL = 22 // Invalid argument
 	ENOSYS = 38 // Function not implemented
-	EIO    = 5  // I/O error
 )
 
 // SyscallResult represents the result of a syscall execution.
@@ -30,12 +37,68 @@ type SyscallResult struct {
 	ExitCode int64
 }
 
-// SyscallHandler handles ARM64 Linux syscalls.
+// SyscallFunc implements one syscall. It reads its arguments from h and
+// sets X0 (via h.regFile or h.setError) before returning.
+type SyscallFunc func(h *SyscallHandler) SyscallResult
+
+// syscallEntry pairs a registered SyscallFunc with the name it was
+// registered under, so diagnostics can name a syscall without a reverse
+// lookup table.
+type syscallEntry struct {
+	name string
+	fn   SyscallFunc
+}
+
+// ABI abstracts the calling convention a SyscallHandler dispatches
+// through, so the same dispatch table could in principle be driven by a
+// different register mapping (e.g. a non-Linux or non-ARM64 target).
+type ABI interface {
+	// SyscallNumber returns the syscall number for the current register
+	// state.
+	SyscallNumber(regFile *emu.RegFile) uint64
+
+	// Arg returns the n-th syscall argument (0-indexed).
+	Arg(regFile *emu.RegFile, n int) uint64
+
+	// SetReturn writes the syscall's return value back to the register
+	// file.
+	SetReturn(regFile *emu.RegFile, v uint64)
+}
+
+// LinuxARM64ABI implements ABI for the standard ARM64 Linux syscall
+// convention: number in X8, arguments in X0-X5, return value in X0.
+type LinuxARM64ABI struct{}
+
+// SyscallNumber reads the syscall number from X8.
+func (LinuxARM64ABI) SyscallNumber(regFile *emu.RegFile) uint64 {
+	return regFile.ReadReg(8)
+}
+
+// Arg reads argument n from Xn (n must be 0-5).
+func (LinuxARM64ABI) Arg(regFile *emu.RegFile, n int) uint64 {
+	return regFile.ReadReg(uint8(n))
+}
+
+// SetReturn writes the return value to X0.
+func (LinuxARM64ABI) SetReturn(regFile *emu.RegFile, v uint64) {
+	regFile.WriteReg(0, v)
+}
+
+// SyscallHandler handles ARM64 Linux syscalls via a pluggable dispatch
+// table, so new syscalls can be added with RegisterSyscall instead of
+// growing a single switch statement.
 type SyscallHandler struct {
 	regFile *emu.RegFile
 	memory  *emu.Memory
 	stdout  io.Writer
 	stderr  io.Writer
+
+	abi   ABI
+	table map[uint64]syscallEntry
+
+	fs     FS
+	fds    map[uint64]File
+	nextFD uint64
 }
 
 // Option is a functional option for configuring SyscallHandler.
@@ -55,7 +118,9 @@ func WithStderr(w io.Writer) Option {
 	}
 }
 
-// NewSyscallHandler creates a new syscall handler.
+// NewSyscallHandler creates a new syscall handler, pre-registered with the
+// syscalls this package implements. Use RegisterSyscall to add more or
+// override one (e.g. a test stubbing out a syscall's behavior).
 func NewSyscallHandler(
 	regFile *emu.RegFile,
 	memory *emu.Memory,
@@ -66,31 +131,133 @@ func NewSyscallHandler(
 		memory:  memory,
 		stdout:  os.Stdout,
 		stderr:  os.Stderr,
+		abi:     LinuxARM64ABI{},
+		table:   make(map[uint64]syscallEntry),
+		fs:      osFS{},
+		fds:     make(map[uint64]File),
+		nextFD:  3, // fd 0-2 are stdin/stdout/stderr, modeled separately
 	}
 
 	for _, opt := range opts {
 		opt(h)
 	}
 
+	h.registerCoreSyscalls()
+	h.registerLinuxSurfaceSyscalls()
+
 	return h
 }
 
-// Handle executes the syscall indicated by the register file state.
-// ARM64 Linux syscall convention:
-//   - Syscall number in X8
-//   - Arguments in X0-X5
-//   - Return value in X0
+// registerCoreSyscalls registers the syscalls that existed before the
+// dispatch table was introduced: exit, write, and the memory-management
+// family (mmap/munmap/mprotect/brk).
+func (h *SyscallHandler) registerCoreSyscalls() {
+	h.RegisterSyscall(SyscallExit, "exit", (*SyscallHandler).handleExit)
+	h.RegisterSyscall(SyscallWrite, "write", (*SyscallHandler).handleWrite)
+	h.RegisterSyscall(SyscallMmap, "mmap", (*SyscallHandler).handleMmap)
+	h.RegisterSyscall(SyscallMunmap, "munmap", (*SyscallHandler).handleMunmap)
+	h.RegisterSyscall(SyscallMprotect, "mprotect", (*SyscallHandler).handleMprotect)
+	h.RegisterSyscall(SyscallBrk, "brk", (*SyscallHandler).handleBrk)
+}
+
+// registerLinuxSurfaceSyscalls registers the syscalls a statically linked
+// Go or musl "hello world" issues during process startup and teardown,
+// beyond the core set: I/O probing, time, process identity, and signal
+// setup. See syscall_io.go, syscall_time.go, syscall_process.go and
+// syscall_signal.go.
+func (h *SyscallHandler) registerLinuxSurfaceSyscalls() {
+	h.registerIOSyscalls()
+	h.registerFSSyscalls()
+	h.registerTimeSyscalls()
+	h.registerProcessSyscalls()
+	h.registerSignalSyscalls()
+}
+
+// RegisterSyscall installs fn as the handler for syscall number num,
+// replacing any handler previously registered for it. name is used only
+// for diagnostics (e.g. a future strace-style trace).
+func (h *SyscallHandler) RegisterSyscall(num uint64, name string, fn SyscallFunc) {
+	h.table[num] = syscallEntry{name: name, fn: fn}
+}
+
+// Handle executes the syscall indicated by the register file state, using
+// h.abi to locate the syscall number and dispatching through the
+// registered table.
 func (h *SyscallHandler) Handle() SyscallResult {
-	syscallNum := h.regFile.ReadReg(8)
+	num := h.abi.SyscallNumber(h.regFile)
 
-	switch syscallNum {
-	case SyscallExit:
-		return h.handleExit()
-	case SyscallWrite:
-		return h.handleWrite()
-	default:
+	entry, ok := h.table[num]
+	if !ok {
 		return h.handleUnknown()
 	}
+	return entry.fn(h)
+}
+
+// handleMmap handles the mmap syscall (222).
+// void *mmap(void *addr, size_t length, int prot, int flags, int fd, off_t offset)
+//   - X0: addr hint (0 lets the emulator choose)
+//   - X1: length
+//   - X2: prot (PROT_* bits)
+//   - X3: flags (only MAP_FIXED is consulted; mappings are always anonymous)
+//   - Returns: mapped address, or -errno
+func (h *SyscallHandler) handleMmap() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	length := h.regFile.ReadReg(1)
+	prot := int(h.regFile.ReadReg(2))
+	flags := int(h.regFile.ReadReg(3))
+
+	if length == 0 {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	fixed := flags&emu.MAP_FIXED != 0
+	mapped := h.memory.Mmap(addr, length, prot, fixed)
+	h.regFile.WriteReg(0, mapped)
+	return SyscallResult{}
+}
+
+// handleMunmap handles the munmap syscall (215).
+// int munmap(void *addr, size_t length)
+func (h *SyscallHandler) handleMunmap() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	length := h.regFile.ReadReg(1)
+
+	if length == 0 {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	h.memory.Munmap(addr, length)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleMprotect handles the mprotect syscall (226).
+// int mprotect(void *addr, size_t length, int prot)
+func (h *SyscallHandler) handleMprotect() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	length := h.regFile.ReadReg(1)
+	prot := int(h.regFile.ReadReg(2))
+
+	if length == 0 {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	h.memory.Mprotect(addr, length, prot)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleBrk handles the brk syscall (214).
+// void *brk(void *addr)
+//   - X0 == 0: query the current program break
+//   - otherwise: grow or shrink the break to addr
+func (h *SyscallHandler) handleBrk() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	h.regFile.WriteReg(0, h.memory.Brk(addr))
+	return SyscallResult{}
 }
 
 // handleExit handles the exit syscall (93).
@@ -115,15 +282,8 @@ func (h *SyscallHandler) handleWrite() SyscallResult {
 	bufPtr := h.regFile.ReadReg(1)
 	count := h.regFile.ReadReg(2)
 
-	// Select output based on file descriptor
-	var writer io.Writer
-	switch fd {
-	case 1:
-		writer = h.stdout
-	case 2:
-		writer = h.stderr
-	default:
-		// Invalid file descriptor
+	writer := h.writerForFd(fd)
+	if writer == nil {
 		h.setError(EBADF)
 		return SyscallResult{}
 	}
@@ -146,6 +306,19 @@ func (h *SyscallHandler) handleWrite() SyscallResult {
 	return SyscallResult{}
 }
 
+// writerForFd returns the io.Writer backing fd, or nil if fd is not one of
+// the writable descriptors this emulator models (stdout, stderr).
+func (h *SyscallHandler) writerForFd(fd uint64) io.Writer {
+	switch fd {
+	case 1:
+		return h.stdout
+	case 2:
+		return h.stderr
+	default:
+		return nil
+	}
+}
+
 // handleUnknown handles unrecognized syscalls.
 func (h *SyscallHandler) handleUnknown() SyscallResult {
 	h.setError(ENOSYS)
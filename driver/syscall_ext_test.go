@@ -0,0 +1,261 @@
+package driver_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/driver"
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Syscall extensions", func() {
+	var (
+		regFile   *emu.RegFile
+		memory    *emu.Memory
+		handler   *driver.SyscallHandler
+		stdoutBuf *bytes.Buffer
+		stderrBuf *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		stdoutBuf = &bytes.Buffer{}
+		stderrBuf = &bytes.Buffer{}
+		handler = driver.NewSyscallHandler(regFile, memory,
+			driver.WithStdout(stdoutBuf),
+			driver.WithStderr(stderrBuf),
+		)
+	})
+
+	Describe("RegisterSyscall", func() {
+		It("should let a caller override a registered syscall", func() {
+			regFile.WriteReg(8, driver.SyscallGetpid)
+
+			handler.RegisterSyscall(driver.SyscallGetpid, "getpid", func(h *driver.SyscallHandler) driver.SyscallResult {
+				return driver.SyscallResult{}
+			})
+
+			handler.Handle()
+
+			// The overridden handler never touches X0, so it should retain
+			// whatever the register file already held (0, here).
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("writev syscall (66)", func() {
+		It("should write a two-iovec scatter/gather buffer to stdout", func() {
+			iovAddr := uint64(0x3000)
+			msg1 := []byte("Hello, ")
+			msg2 := []byte("World!")
+			buf1Addr := uint64(0x4000)
+			buf2Addr := uint64(0x5000)
+
+			for i, b := range msg1 {
+				memory.Write8(buf1Addr+uint64(i), b)
+			}
+			for i, b := range msg2 {
+				memory.Write8(buf2Addr+uint64(i), b)
+			}
+
+			writeIovec(memory, iovAddr, buf1Addr, uint64(len(msg1)))
+			writeIovec(memory, iovAddr+16, buf2Addr, uint64(len(msg2)))
+
+			regFile.WriteReg(8, driver.SyscallWritev)
+			regFile.WriteReg(0, 1) // fd = stdout
+			regFile.WriteReg(1, iovAddr)
+			regFile.WriteReg(2, 2) // iovcnt
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(len(msg1) + len(msg2))))
+			Expect(stdoutBuf.String()).To(Equal("Hello, World!"))
+		})
+
+		It("should return EBADF for an invalid fd", func() {
+			regFile.WriteReg(8, driver.SyscallWritev)
+			regFile.WriteReg(0, 42)
+			regFile.WriteReg(1, 0x3000)
+			regFile.WriteReg(2, 0)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EBADF)))
+		})
+	})
+
+	Describe("readv syscall (65)", func() {
+		It("should return EBADF (readv is not implemented for any fd)", func() {
+			regFile.WriteReg(8, driver.SyscallReadv)
+			regFile.WriteReg(0, 0)
+			regFile.WriteReg(1, 0x3000)
+			regFile.WriteReg(2, 1)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EBADF)))
+		})
+	})
+
+	Describe("ioctl syscall (29)", func() {
+		It("should return ENOSYS for TCGETS (fd is never a terminal)", func() {
+			regFile.WriteReg(8, driver.SyscallIoctl)
+			regFile.WriteReg(0, 1)
+			regFile.WriteReg(1, driver.TCGETS)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.ENOSYS)))
+		})
+	})
+
+	Describe("clock_gettime syscall (113)", func() {
+		It("should fill a timespec with zero and return 0", func() {
+			tp := uint64(0x6000)
+			regFile.WriteReg(8, driver.SyscallClockGettime)
+			regFile.WriteReg(0, 0)
+			regFile.WriteReg(1, tp)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read64(tp)).To(Equal(uint64(0)))
+			Expect(memory.Read64(tp + 8)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("gettimeofday syscall (169)", func() {
+		It("should fill a timeval with zero and return 0", func() {
+			tv := uint64(0x6100)
+			regFile.WriteReg(8, driver.SyscallGettimeofday)
+			regFile.WriteReg(0, tv)
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read64(tv)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("uname syscall (160)", func() {
+		It("should report a Linux/aarch64 identity", func() {
+			buf := uint64(0x7000)
+			regFile.WriteReg(8, driver.SyscallUname)
+			regFile.WriteReg(0, buf)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(readCString(memory, buf)).To(Equal("Linux"))
+			Expect(readCString(memory, buf+65*4)).To(Equal("aarch64"))
+		})
+	})
+
+	Describe("readlinkat syscall (78)", func() {
+		It("should report /proc/self/exe", func() {
+			buf := uint64(0x7100)
+			regFile.WriteReg(8, driver.SyscallReadlinkat)
+			regFile.WriteReg(2, buf)
+			regFile.WriteReg(3, 64)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			n := regFile.ReadReg(0)
+			Expect(string(readBytes(memory, buf, n))).To(Equal("/proc/self/exe"))
+		})
+	})
+
+	Describe("set_tid_address syscall (96)", func() {
+		It("should return the emulated pid", func() {
+			regFile.WriteReg(8, driver.SyscallSetTidAddress)
+			regFile.WriteReg(0, 0x7200)
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("identity syscalls", func() {
+		It("should report pid 1", func() {
+			regFile.WriteReg(8, driver.SyscallGetpid)
+			handler.Handle()
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(1)))
+		})
+
+		It("should report uid 0", func() {
+			regFile.WriteReg(8, driver.SyscallGetuid)
+			handler.Handle()
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+
+		It("should report gid 0", func() {
+			regFile.WriteReg(8, driver.SyscallGetgid)
+			handler.Handle()
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("signal syscalls", func() {
+		It("should no-op rt_sigaction", func() {
+			regFile.WriteReg(8, driver.SyscallRtSigaction)
+			result := handler.Handle()
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+
+		It("should no-op rt_sigprocmask", func() {
+			regFile.WriteReg(8, driver.SyscallRtSigprocmask)
+			result := handler.Handle()
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("exit_group syscall (94)", func() {
+		It("should terminate like exit", func() {
+			regFile.WriteReg(8, driver.SyscallExitGroup)
+			regFile.WriteReg(0, 7)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeTrue())
+			Expect(result.ExitCode).To(Equal(int64(7)))
+		})
+	})
+})
+
+// writeIovec writes a struct iovec {base, len} at addr.
+func writeIovec(memory *emu.Memory, addr, base, length uint64) {
+	memory.Write64(addr, base)
+	memory.Write64(addr+8, length)
+}
+
+// readCString reads a NUL-terminated string starting at addr.
+func readCString(memory *emu.Memory, addr uint64) string {
+	var buf []byte
+	for i := uint64(0); ; i++ {
+		b := memory.Read8(addr + i)
+		if b == 0 {
+			break
+		}
+		buf = append(buf, b)
+	}
+	return string(buf)
+}
+
+// readBytes reads n raw bytes starting at addr.
+func readBytes(memory *emu.Memory, addr, n uint64) []byte {
+	buf := make([]byte, n)
+	for i := uint64(0); i < n; i++ {
+		buf[i] = memory.Read8(addr + i)
+	}
+	return buf
+}
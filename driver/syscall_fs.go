@@ -0,0 +1,307 @@
+package driver
+
+import (
+	"io"
+	"os"
+)
+
+// ARM64 Linux syscall numbers for the virtual file-descriptor family.
+const (
+	SyscallOpenat     uint64 = 56 // openat(dirfd, pathname, flags, mode)
+	SyscallClose      uint64 = 57 // close(fd)
+	SyscallLseek      uint64 = 62 // lseek(fd, offset, whence)
+	SyscallRead       uint64 = 63 // read(fd, buf, count)
+	SyscallNewfstatat uint64 = 79 // newfstatat(dirfd, pathname, statbuf, flags)
+	SyscallFstat      uint64 = 80 // fstat(fd, statbuf)
+)
+
+// AtFDCWD is the dirfd value meaning "relative to the current working
+// directory" — the only dirfd this emulator understands, since it has no
+// notion of open directory descriptors.
+const AtFDCWD int64 = -100
+
+// Linux open(2) flags, the bits openat's guest callers set in X2.
+const (
+	O_RDONLY = 0x0
+	O_WRONLY = 0x1
+	O_RDWR   = 0x2
+	O_CREAT  = 0x40
+	O_TRUNC  = 0x200
+	O_APPEND = 0x400
+)
+
+// linuxStatSize is sizeof(struct stat) for the generic 64-bit Linux ABI
+// (include/uapi/asm-generic/stat.h), which aarch64 uses.
+const linuxStatSize = 128
+
+// Linux struct stat st_mode file-type bits (the ones this emulator's
+// host-backed files can actually report).
+const (
+	linuxSIFREG = 0o100000
+	linuxSIFDIR = 0o040000
+)
+
+func (h *SyscallHandler) registerFSSyscalls() {
+	h.RegisterSyscall(SyscallOpenat, "openat", (*SyscallHandler).handleOpenat)
+	h.RegisterSyscall(SyscallClose, "close", (*SyscallHandler).handleClose)
+	h.RegisterSyscall(SyscallRead, "read", (*SyscallHandler).handleRead)
+	h.RegisterSyscall(SyscallLseek, "lseek", (*SyscallHandler).handleLseek)
+	h.RegisterSyscall(SyscallFstat, "fstat", (*SyscallHandler).handleFstat)
+	h.RegisterSyscall(SyscallNewfstatat, "newfstatat", (*SyscallHandler).handleNewfstatat)
+}
+
+// handleOpenat handles the openat syscall (56).
+// int openat(int dirfd, const char *pathname, int flags, mode_t mode)
+//   - X0: dirfd (only AtFDCWD is supported)
+//   - X1: pathname pointer (NUL-terminated)
+//   - X2: flags (O_* bits)
+//   - X3: mode
+//   - Returns: new fd, or -errno
+func (h *SyscallHandler) handleOpenat() SyscallResult {
+	dirfd := int64(h.regFile.ReadReg(0))
+	if dirfd != AtFDCWD {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	pathname := h.readCString(h.regFile.ReadReg(1))
+	flags := linuxToGoOpenFlags(int(h.regFile.ReadReg(2)))
+	mode := os.FileMode(h.regFile.ReadReg(3))
+
+	file, err := h.fs.OpenFile(pathname, flags, mode)
+	if err != nil {
+		h.setError(errnoForFSError(err))
+		return SyscallResult{}
+	}
+
+	fd := h.nextFD
+	h.nextFD++
+	h.fds[fd] = file
+	h.regFile.WriteReg(0, fd)
+	return SyscallResult{}
+}
+
+// handleClose handles the close syscall (57).
+// int close(int fd)
+//
+// Closing fd 0-2 (the standard descriptors, modeled as plain io.Reader/
+// io.Writer rather than table entries) is accepted as a no-op success,
+// the same as real Linux.
+func (h *SyscallHandler) handleClose() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+
+	file, ok := h.fds[fd]
+	if !ok {
+		if fd <= 2 {
+			h.regFile.WriteReg(0, 0)
+			return SyscallResult{}
+		}
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	delete(h.fds, fd)
+	if err := file.Close(); err != nil {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleRead handles the read syscall (63).
+// ssize_t read(int fd, void *buf, size_t count)
+//   - X0: fd (must have been returned by openat)
+//   - X1: buffer pointer
+//   - X2: byte count
+//   - Returns: bytes read (0 at EOF), or -errno
+func (h *SyscallHandler) handleRead() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	bufPtr := h.regFile.ReadReg(1)
+	count := h.regFile.ReadReg(2)
+
+	file, ok := h.fds[fd]
+	if !ok {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	buf := make([]byte, count)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	for i := 0; i < n; i++ {
+		h.memory.Write8(bufPtr+uint64(i), buf[i])
+	}
+
+	h.regFile.WriteReg(0, uint64(n))
+	return SyscallResult{}
+}
+
+// handleLseek handles the lseek syscall (62).
+// off_t lseek(int fd, off_t offset, int whence)
+//   - X0: fd
+//   - X1: offset
+//   - X2: whence (SEEK_SET=0, SEEK_CUR=1, SEEK_END=2 — passed straight
+//     through to File.Seek, whose io.Seeker constants match)
+//   - Returns: new offset, or -errno
+func (h *SyscallHandler) handleLseek() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	offset := int64(h.regFile.ReadReg(1))
+	whence := int(h.regFile.ReadReg(2))
+
+	file, ok := h.fds[fd]
+	if !ok {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	newOffset, err := file.Seek(offset, whence)
+	if err != nil {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	h.regFile.WriteReg(0, uint64(newOffset))
+	return SyscallResult{}
+}
+
+// handleFstat handles the fstat syscall (80).
+// int fstat(int fd, struct stat *statbuf)
+func (h *SyscallHandler) handleFstat() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	statbuf := h.regFile.ReadReg(1)
+
+	file, ok := h.fds[fd]
+	if !ok {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	h.writeStat(statbuf, info)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleNewfstatat handles the newfstatat syscall (79).
+// int newfstatat(int dirfd, const char *pathname, struct stat *statbuf, int flags)
+//
+// Only the AtFDCWD, path-based form is supported (the common
+// fstatat(AT_FDCWD, path, &st, 0) a libc stat() issues); the AT_EMPTY_PATH
+// form that stats dirfd itself is not implemented.
+func (h *SyscallHandler) handleNewfstatat() SyscallResult {
+	dirfd := int64(h.regFile.ReadReg(0))
+	if dirfd != AtFDCWD {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	pathname := h.readCString(h.regFile.ReadReg(1))
+	statbuf := h.regFile.ReadReg(2)
+
+	info, err := h.fs.Stat(pathname)
+	if err != nil {
+		h.setError(errnoForFSError(err))
+		return SyscallResult{}
+	}
+
+	h.writeStat(statbuf, info)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// writeStat marshals info into the Linux generic 64-bit struct stat
+// layout at addr. Fields this emulator has no real analogue for
+// (device/inode numbers, link count, ownership, timestamps) are filled
+// with fixed placeholder values rather than host values, since a guest's
+// behavior should not depend on the host filesystem's identity.
+func (h *SyscallHandler) writeStat(addr uint64, info os.FileInfo) {
+	mode := uint32(info.Mode().Perm())
+	if info.IsDir() {
+		mode |= linuxSIFDIR
+	} else {
+		mode |= linuxSIFREG
+	}
+
+	h.zeroStat(addr)
+	h.writeUint64(addr+0, 1)                              // st_dev
+	h.writeUint64(addr+8, 1)                              // st_ino
+	h.memory.Write32(addr+16, mode)                       // st_mode
+	h.memory.Write32(addr+20, 1)                          // st_nlink
+	h.writeUint64(addr+48, uint64(info.Size()))           // st_size
+	h.memory.Write32(addr+56, 4096)                       // st_blksize
+	h.writeUint64(addr+64, (uint64(info.Size())+511)/512) // st_blocks
+}
+
+// zeroStat clears the struct stat region before writeStat fills in the
+// fields it knows, so padding and unmodeled fields (uid, gid, rdev,
+// timestamps) read as zero rather than stale memory.
+func (h *SyscallHandler) zeroStat(addr uint64) {
+	for i := uint64(0); i < linuxStatSize; i++ {
+		h.memory.Write8(addr+i, 0)
+	}
+}
+
+// readCString reads a NUL-terminated string out of emulated memory.
+func (h *SyscallHandler) readCString(addr uint64) string {
+	var buf []byte
+	for {
+		b := h.memory.Read8(addr)
+		if b == 0 {
+			break
+		}
+		buf = append(buf, b)
+		addr++
+	}
+	return string(buf)
+}
+
+// linuxToGoOpenFlags converts Linux open(2) flag bits to the os.O_*
+// flags os.OpenFile expects.
+func linuxToGoOpenFlags(flags int) int {
+	var goFlags int
+
+	switch flags & 0x3 {
+	case O_WRONLY:
+		goFlags = os.O_WRONLY
+	case O_RDWR:
+		goFlags = os.O_RDWR
+	default:
+		goFlags = os.O_RDONLY
+	}
+
+	if flags&O_CREAT != 0 {
+		goFlags |= os.O_CREATE
+	}
+	if flags&O_TRUNC != 0 {
+		goFlags |= os.O_TRUNC
+	}
+	if flags&O_APPEND != 0 {
+		goFlags |= os.O_APPEND
+	}
+
+	return goFlags
+}
+
+// errnoForFSError maps an FS error to the Linux errno this package
+// reports back to the guest.
+func errnoForFSError(err error) int {
+	switch {
+	case os.IsNotExist(err):
+		return ENOENT
+	case os.IsPermission(err):
+		return EACCES
+	default:
+		return EIO
+	}
+}
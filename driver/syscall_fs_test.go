@@ -0,0 +1,299 @@
+package driver_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/driver"
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// memFS is a tiny in-memory driver.FS, keyed by path, used so these tests
+// don't touch the host filesystem.
+type memFS map[string][]byte
+
+func (fs memFS) OpenFile(name string, _ int, _ os.FileMode) (driver.File, error) {
+	data, ok := fs[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &memFile{name: name, data: data}, nil
+}
+
+func (fs memFS) Stat(name string) (os.FileInfo, error) {
+	data, ok := fs[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memFileInfo{name: name, size: int64(len(data))}, nil
+}
+
+// memFile implements driver.File over an in-memory byte slice.
+type memFile struct {
+	name string
+	data []byte
+	pos  int64
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.pos >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.pos:])
+	f.pos += int64(n)
+	return n, nil
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Close() error { return nil }
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	var newPos int64
+	switch whence {
+	case io.SeekStart:
+		newPos = offset
+	case io.SeekCurrent:
+		newPos = f.pos + offset
+	case io.SeekEnd:
+		newPos = int64(len(f.data)) + offset
+	}
+	f.pos = newPos
+	return newPos, nil
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	return memFileInfo{name: f.name, size: int64(len(f.data))}, nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() os.FileMode  { return 0o644 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
+
+var _ = Describe("Virtual file descriptors", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		handler *driver.SyscallHandler
+		fs      memFS
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		fs = memFS{"/greeting.txt": []byte("hello, fd table")}
+		handler = driver.NewSyscallHandler(regFile, memory,
+			driver.WithStdout(&bytes.Buffer{}),
+			driver.WithStderr(&bytes.Buffer{}),
+			driver.WithFS(fs),
+		)
+	})
+
+	// openFile issues openat(AT_FDCWD, path, O_RDONLY, 0) and returns the fd.
+	openFile := func(path string) uint64 {
+		pathAddr := uint64(0x8000)
+		writeCString(memory, pathAddr, path)
+
+		regFile.WriteReg(8, driver.SyscallOpenat)
+		regFile.WriteReg(0, uint64(driver.AtFDCWD))
+		regFile.WriteReg(1, pathAddr)
+		regFile.WriteReg(2, 0)
+		regFile.WriteReg(3, 0)
+		handler.Handle()
+		return regFile.ReadReg(0)
+	}
+
+	Describe("openat syscall (56)", func() {
+		It("should return a new fd for an existing file", func() {
+			fd := openFile("/greeting.txt")
+			Expect(int64(fd)).To(BeNumerically(">=", 3))
+		})
+
+		It("should return -ENOENT for a missing file", func() {
+			fd := openFile("/nope.txt")
+			Expect(int64(fd)).To(Equal(int64(-driver.ENOENT)))
+		})
+
+		It("should return -EBADF for a non-AT_FDCWD dirfd", func() {
+			pathAddr := uint64(0x8000)
+			writeCString(memory, pathAddr, "/greeting.txt")
+
+			regFile.WriteReg(8, driver.SyscallOpenat)
+			regFile.WriteReg(0, 3) // not AT_FDCWD
+			regFile.WriteReg(1, pathAddr)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EBADF)))
+		})
+	})
+
+	Describe("read syscall (63)", func() {
+		It("should read an opened file's contents", func() {
+			fd := openFile("/greeting.txt")
+			bufAddr := uint64(0x9000)
+
+			regFile.WriteReg(8, driver.SyscallRead)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, bufAddr)
+			regFile.WriteReg(2, 5)
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(5)))
+			Expect(string(readBytes(memory, bufAddr, 5))).To(Equal("hello"))
+		})
+
+		It("should return 0 at EOF", func() {
+			fd := openFile("/greeting.txt")
+			bufAddr := uint64(0x9000)
+
+			regFile.WriteReg(8, driver.SyscallRead)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, bufAddr)
+			regFile.WriteReg(2, 1024)
+			handler.Handle()
+
+			// Second read starts past EOF.
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+
+		It("should return -EBADF for an fd never opened", func() {
+			regFile.WriteReg(8, driver.SyscallRead)
+			regFile.WriteReg(0, 42)
+			regFile.WriteReg(1, 0x9000)
+			regFile.WriteReg(2, 1)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EBADF)))
+		})
+	})
+
+	Describe("lseek syscall (62)", func() {
+		It("should seek and affect the next read", func() {
+			fd := openFile("/greeting.txt")
+			bufAddr := uint64(0x9000)
+
+			regFile.WriteReg(8, driver.SyscallLseek)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, 7)
+			regFile.WriteReg(2, 0) // SEEK_SET
+			handler.Handle()
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(7)))
+
+			regFile.WriteReg(8, driver.SyscallRead)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, bufAddr)
+			regFile.WriteReg(2, 2)
+			handler.Handle()
+
+			Expect(string(readBytes(memory, bufAddr, 2))).To(Equal("fd"))
+		})
+	})
+
+	Describe("close syscall (57)", func() {
+		It("should invalidate the fd for further reads", func() {
+			fd := openFile("/greeting.txt")
+
+			regFile.WriteReg(8, driver.SyscallClose)
+			regFile.WriteReg(0, fd)
+			handler.Handle()
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+
+			regFile.WriteReg(8, driver.SyscallRead)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, 0x9000)
+			regFile.WriteReg(2, 1)
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EBADF)))
+		})
+
+		It("should accept closing stdout as a no-op", func() {
+			regFile.WriteReg(8, driver.SyscallClose)
+			regFile.WriteReg(0, 1)
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("fstat syscall (80)", func() {
+		It("should report the open file's size", func() {
+			fd := openFile("/greeting.txt")
+			statbuf := uint64(0xA000)
+
+			regFile.WriteReg(8, driver.SyscallFstat)
+			regFile.WriteReg(0, fd)
+			regFile.WriteReg(1, statbuf)
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read64(statbuf + 48)).To(Equal(uint64(len("hello, fd table")))) // st_size
+		})
+	})
+
+	Describe("newfstatat syscall (79)", func() {
+		It("should stat a path without opening it", func() {
+			pathAddr := uint64(0x8000)
+			writeCString(memory, pathAddr, "/greeting.txt")
+			statbuf := uint64(0xA000)
+
+			regFile.WriteReg(8, driver.SyscallNewfstatat)
+			regFile.WriteReg(0, uint64(driver.AtFDCWD))
+			regFile.WriteReg(1, pathAddr)
+			regFile.WriteReg(2, statbuf)
+			regFile.WriteReg(3, 0)
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read64(statbuf + 48)).To(Equal(uint64(len("hello, fd table"))))
+		})
+
+		It("should return -ENOENT for a missing path", func() {
+			pathAddr := uint64(0x8000)
+			writeCString(memory, pathAddr, "/nope.txt")
+
+			regFile.WriteReg(8, driver.SyscallNewfstatat)
+			regFile.WriteReg(0, uint64(driver.AtFDCWD))
+			regFile.WriteReg(1, pathAddr)
+			regFile.WriteReg(2, 0xA000)
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.ENOENT)))
+		})
+	})
+})
+
+// writeCString writes s NUL-terminated into memory at addr.
+func writeCString(memory *emu.Memory, addr uint64, s string) {
+	for i := 0; i < len(s); i++ {
+		memory.Write8(addr+uint64(i), s[i])
+	}
+	memory.Write8(addr+uint64(len(s)), 0)
+}
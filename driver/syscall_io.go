@@ -0,0 +1,111 @@
+package driver
+
+// ARM64 Linux syscall numbers for the I/O family.
+const (
+	SyscallIoctl  uint64 = 29 // ioctl(fd, request, argp)
+	SyscallReadv  uint64 = 65 // readv(fd, iov, iovcnt)
+	SyscallWritev uint64 = 66 // writev(fd, iov, iovcnt)
+)
+
+// TCGETS is the ioctl request the Go and musl runtimes use to probe
+// whether a file descriptor is a terminal before deciding whether to
+// enable line buffering. handleIoctl does not inspect the request, so
+// TCGETS gets the same ENOSYS answer as any other request.
+const TCGETS = 0x5401
+
+// iovec mirrors struct iovec's layout: a pointer followed by a length,
+// both native-width.
+type iovec struct {
+	base uint64
+	len  uint64
+}
+
+func (h *SyscallHandler) registerIOSyscalls() {
+	h.RegisterSyscall(SyscallIoctl, "ioctl", (*SyscallHandler).handleIoctl)
+	h.RegisterSyscall(SyscallReadv, "readv", (*SyscallHandler).handleReadv)
+	h.RegisterSyscall(SyscallWritev, "writev", (*SyscallHandler).handleWritev)
+}
+
+// handleIoctl handles the ioctl syscall (29).
+// int ioctl(int fd, unsigned long request, ...)
+//   - X0: fd
+//   - X1: request
+//   - Returns: -ENOSYS
+//
+// This emulator models no terminal, so every request, including TCGETS
+// (which runtimes use to probe for a terminal before enabling line
+// buffering), is answered with ENOSYS rather than success:
+// callers fall back to their non-interactive path, which is the correct
+// behavior for an fd that is never actually a tty.
+func (h *SyscallHandler) handleIoctl() SyscallResult {
+	h.setError(ENOSYS)
+	return SyscallResult{}
+}
+
+// handleReadv handles the readv syscall (65).
+// ssize_t readv(int fd, const struct iovec *iov, int iovcnt)
+//
+// readv is not implemented for any file descriptor: it always reports
+// EBADF, including for files opened with openat that read (63) can read.
+// It exists so statically linked runtimes that probe for
+// readv support at startup see a syscall number they recognize rather
+// than ENOSYS.
+func (h *SyscallHandler) handleReadv() SyscallResult {
+	h.setError(EBADF)
+	return SyscallResult{}
+}
+
+// handleWritev handles the writev syscall (66).
+// ssize_t writev(int fd, const struct iovec *iov, int iovcnt)
+//   - X0: fd
+//   - X1: iovec array pointer
+//   - X2: iovec count
+//   - Returns: total bytes written (or negative error code)
+func (h *SyscallHandler) handleWritev() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	iovPtr := h.regFile.ReadReg(1)
+	iovcnt := h.regFile.ReadReg(2)
+
+	writer := h.writerForFd(fd)
+	if writer == nil {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	var total uint64
+	for i := uint64(0); i < iovcnt; i++ {
+		iov := h.readIovec(iovPtr + i*16)
+
+		buf := make([]byte, iov.len)
+		for j := uint64(0); j < iov.len; j++ {
+			buf[j] = h.memory.Read8(iov.base + j)
+		}
+
+		n, err := writer.Write(buf)
+		if err != nil {
+			h.setError(EIO)
+			return SyscallResult{}
+		}
+		total += uint64(n)
+	}
+
+	h.regFile.WriteReg(0, total)
+	return SyscallResult{}
+}
+
+// readIovec reads one struct iovec from memory at addr.
+func (h *SyscallHandler) readIovec(addr uint64) iovec {
+	return iovec{
+		base: h.readUint64(addr),
+		len:  h.readUint64(addr + 8),
+	}
+}
+
+// readUint64 reads a little-endian uint64 out of emulated memory.
+func (h *SyscallHandler) readUint64(addr uint64) uint64 {
+	var v uint64
+	for i := uint64(0); i < 8; i++ {
+		v |= uint64(h.memory.Read8(addr+i)) << (8 * i)
+	}
+	return v
+}
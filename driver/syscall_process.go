@@ -0,0 +1,113 @@
+package driver
+
+// ARM64 Linux syscall numbers for process identity and introspection.
+const (
+	SyscallUname         uint64 = 160 // uname(buf)
+	SyscallReadlinkat    uint64 = 78  // readlinkat(dirfd, pathname, buf, bufsiz)
+	SyscallSetTidAddress uint64 = 96  // set_tid_address(tidptr)
+	SyscallGetpid        uint64 = 172 // getpid()
+	SyscallGetuid        uint64 = 174 // getuid()
+	SyscallGetgid        uint64 = 176 // getgid()
+)
+
+// emulatedPid is the PID this emulator reports for the process it is
+// running; there is exactly one, so it is a constant rather than
+// anything derived from the host OS.
+const emulatedPid = 1
+
+// utsnameFieldSize is the size of each of struct utsname's six fields,
+// per Linux's `new_utsname`.
+const utsnameFieldSize = 65
+
+func (h *SyscallHandler) registerProcessSyscalls() {
+	h.RegisterSyscall(SyscallUname, "uname", (*SyscallHandler).handleUname)
+	h.RegisterSyscall(SyscallReadlinkat, "readlinkat", (*SyscallHandler).handleReadlinkat)
+	h.RegisterSyscall(SyscallSetTidAddress, "set_tid_address", (*SyscallHandler).handleSetTidAddress)
+	h.RegisterSyscall(SyscallGetpid, "getpid", (*SyscallHandler).handleGetpid)
+	h.RegisterSyscall(SyscallGetuid, "getuid", (*SyscallHandler).handleGetuid)
+	h.RegisterSyscall(SyscallGetgid, "getgid", (*SyscallHandler).handleGetgid)
+}
+
+// handleUname handles the uname syscall (160).
+// int uname(struct utsname *buf)
+//   - X0: struct utsname * to fill with six NUL-padded 65-byte fields:
+//     sysname, nodename, release, version, machine, domainname
+//   - Returns: 0
+func (h *SyscallHandler) handleUname() SyscallResult {
+	buf := h.regFile.ReadReg(0)
+
+	fields := []string{"Linux", "m2sim", "0.0.0", "#1", "aarch64", "(none)"}
+	for i, field := range fields {
+		h.writeCString(buf+uint64(i*utsnameFieldSize), field, utsnameFieldSize)
+	}
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleReadlinkat handles the readlinkat syscall (78).
+// ssize_t readlinkat(int dirfd, const char *pathname, char *buf, size_t bufsiz)
+//
+// Only the "/proc/self/exe" query that Go and musl runtimes issue to
+// locate their own binary is answered: pathname is never read, and every
+// call reports that synthetic path, whatever it asked for.
+func (h *SyscallHandler) handleReadlinkat() SyscallResult {
+	buf := h.regFile.ReadReg(2)
+	bufsiz := h.regFile.ReadReg(3)
+
+	const target = "/proc/self/exe"
+	n := uint64(len(target))
+	if n > bufsiz {
+		n = bufsiz
+	}
+	for i := uint64(0); i < n; i++ {
+		h.memory.Write8(buf+i, target[i])
+	}
+
+	h.regFile.WriteReg(0, n)
+	return SyscallResult{}
+}
+
+// handleSetTidAddress handles the set_tid_address syscall (96).
+// int set_tid_address(int *tidptr)
+//
+// This emulator never clears tidptr on thread exit (it models a single
+// thread that never needs clear_child_tid), so the pointer is accepted
+// and ignored; the call just needs to succeed and return a pid.
+func (h *SyscallHandler) handleSetTidAddress() SyscallResult {
+	h.regFile.WriteReg(0, emulatedPid)
+	return SyscallResult{}
+}
+
+// handleGetpid handles the getpid syscall (172).
+func (h *SyscallHandler) handleGetpid() SyscallResult {
+	h.regFile.WriteReg(0, emulatedPid)
+	return SyscallResult{}
+}
+
+// handleGetuid handles the getuid syscall (174).
+//
+// The emulated process always runs as uid 0: there is no host identity
+// to reflect and no privilege boundary being emulated.
+func (h *SyscallHandler) handleGetuid() SyscallResult {
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleGetgid handles the getgid syscall (176).
+func (h *SyscallHandler) handleGetgid() SyscallResult {
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// writeCString writes s into memory at addr, NUL-padded to size bytes,
+// truncating s if it does not fit.
+func (h *SyscallHandler) writeCString(addr uint64, s string, size int) {
+	for i := 0; i < size; i++ {
+		var b byte
+		if i < len(s) {
+			b = s[i]
+		}
+		h.memory.Write8(addr+uint64(i), b)
+	}
+}
@@ -0,0 +1,30 @@
+package driver
+
+// ARM64 Linux syscall numbers for signal setup and process-wide exit.
+const (
+	SyscallRtSigaction   uint64 = 134 // rt_sigaction(signum, act, oldact, sigsetsize)
+	SyscallRtSigprocmask uint64 = 135 // rt_sigprocmask(how, set, oldset, sigsetsize)
+	SyscallExitGroup     uint64 = 94  // exit_group(status)
+)
+
+func (h *SyscallHandler) registerSignalSyscalls() {
+	h.RegisterSyscall(SyscallRtSigaction, "rt_sigaction", (*SyscallHandler).handleRtSigaction)
+	h.RegisterSyscall(SyscallRtSigprocmask, "rt_sigprocmask", (*SyscallHandler).handleRtSigprocmask)
+	h.RegisterSyscall(SyscallExitGroup, "exit_group", (*SyscallHandler).handleExit)
+}
+
+// handleRtSigaction handles the rt_sigaction syscall (134) as a no-op:
+// this emulator never delivers signals, so there is nothing to install a
+// handler against. Runtimes that install handlers defensively during
+// startup just need the call to succeed.
+func (h *SyscallHandler) handleRtSigaction() SyscallResult {
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleRtSigprocmask handles the rt_sigprocmask syscall (135) as a
+// no-op, for the same reason as handleRtSigaction.
+func (h *SyscallHandler) handleRtSigprocmask() SyscallResult {
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
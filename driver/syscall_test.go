@@ -189,6 +189,110 @@ var _ = Describe("Syscall", func() {
 		})
 	})
 
+	Describe("mmap/munmap/mprotect/brk syscalls", func() {
+		It("should map an anonymous region and return its address", func() {
+			regFile.WriteReg(8, driver.SyscallMmap)
+			regFile.WriteReg(0, 0) // no hint
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			regFile.WriteReg(2, uint64(emu.PROT_READ|emu.PROT_WRITE))
+			regFile.WriteReg(3, uint64(emu.MAP_PRIVATE|emu.MAP_ANONYMOUS))
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			addr := regFile.ReadReg(0)
+			Expect(addr).ToNot(Equal(uint64(0)))
+
+			memory.Write64(addr, 0xCAFE)
+			Expect(memory.Read64(addr)).To(Equal(uint64(0xCAFE)))
+		})
+
+		It("should honor MAP_FIXED at the requested address", func() {
+			regFile.WriteReg(8, driver.SyscallMmap)
+			regFile.WriteReg(0, 0x9000)
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			regFile.WriteReg(2, uint64(emu.PROT_READ|emu.PROT_WRITE))
+			regFile.WriteReg(3, uint64(emu.MAP_FIXED))
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x9000)))
+		})
+
+		It("should return EINVAL for zero-length mmap", func() {
+			regFile.WriteReg(8, driver.SyscallMmap)
+			regFile.WriteReg(0, 0)
+			regFile.WriteReg(1, 0)
+			regFile.WriteReg(2, uint64(emu.PROT_READ))
+			regFile.WriteReg(3, 0)
+
+			handler.Handle()
+
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-driver.EINVAL)))
+		})
+
+		It("should fault accesses after munmap", func() {
+			// Addresses below emu.DefaultBrkBase are an auto-mapped RWX
+			// region for backward compatibility (see emu.Memory.pageFor),
+			// so this uses DefaultBrkBase itself, which stays genuinely
+			// unmapped after Munmap.
+			const addr = uint64(emu.DefaultBrkBase)
+
+			regFile.WriteReg(8, driver.SyscallMmap)
+			regFile.WriteReg(0, addr)
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			regFile.WriteReg(2, uint64(emu.PROT_READ|emu.PROT_WRITE))
+			regFile.WriteReg(3, uint64(emu.MAP_FIXED))
+			handler.Handle()
+
+			regFile.WriteReg(8, driver.SyscallMunmap)
+			regFile.WriteReg(0, addr)
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+
+			memory.Read8(addr)
+			fault := memory.TakeFault()
+			Expect(fault).ToNot(BeNil())
+			Expect(fault.Kind).To(Equal(emu.FaultUnmapped))
+		})
+
+		It("should restrict access after mprotect drops the write bit", func() {
+			regFile.WriteReg(8, driver.SyscallMmap)
+			regFile.WriteReg(0, 0xB000)
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			regFile.WriteReg(2, uint64(emu.PROT_READ|emu.PROT_WRITE))
+			regFile.WriteReg(3, uint64(emu.MAP_FIXED))
+			handler.Handle()
+
+			regFile.WriteReg(8, driver.SyscallMprotect)
+			regFile.WriteReg(0, 0xB000)
+			regFile.WriteReg(1, uint64(emu.PageSize))
+			regFile.WriteReg(2, uint64(emu.PROT_READ))
+			handler.Handle()
+
+			memory.Write8(0xB000, 1)
+			fault := memory.TakeFault()
+			Expect(fault).ToNot(BeNil())
+			Expect(fault.Kind).To(Equal(emu.FaultProt))
+		})
+
+		It("should query and then grow the program break", func() {
+			regFile.WriteReg(8, driver.SyscallBrk)
+			regFile.WriteReg(0, 0)
+			handler.Handle()
+			initial := regFile.ReadReg(0)
+
+			regFile.WriteReg(8, driver.SyscallBrk)
+			regFile.WriteReg(0, initial+uint64(emu.PageSize))
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(initial + uint64(emu.PageSize)))
+		})
+	})
+
 	Describe("unknown syscall", func() {
 		It("should return ENOSYS for unknown syscall", func() {
 			regFile.WriteReg(8, 9999) // unknown syscall
@@ -0,0 +1,51 @@
+package driver
+
+// ARM64 Linux syscall numbers for the time family.
+const (
+	SyscallClockGettime uint64 = 113 // clock_gettime(clockid, tp)
+	SyscallGettimeofday uint64 = 169 // gettimeofday(tv, tz)
+)
+
+func (h *SyscallHandler) registerTimeSyscalls() {
+	h.RegisterSyscall(SyscallClockGettime, "clock_gettime", (*SyscallHandler).handleClockGettime)
+	h.RegisterSyscall(SyscallGettimeofday, "gettimeofday", (*SyscallHandler).handleGettimeofday)
+}
+
+// handleClockGettime handles the clock_gettime syscall (113).
+// int clock_gettime(clockid_t clockid, struct timespec *tp)
+//   - X0: clockid (ignored; the emulator has no wall clock)
+//   - X1: struct timespec * to fill with {tv_sec, tv_nsec}
+//   - Returns: 0
+//
+// The emulator always reports time zero, since it has no notion of wall
+// clock time; callers only need a struct shaped correctly, not an
+// accurate reading.
+func (h *SyscallHandler) handleClockGettime() SyscallResult {
+	tp := h.regFile.ReadReg(1)
+	h.writeUint64(tp, 0)   // tv_sec
+	h.writeUint64(tp+8, 0) // tv_nsec
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleGettimeofday handles the gettimeofday syscall (169).
+// int gettimeofday(struct timeval *tv, struct timezone *tz)
+//   - X0: struct timeval * to fill with {tv_sec, tv_usec} (may be 0)
+//   - X1: struct timezone * (ignored; always obsolete per POSIX)
+//   - Returns: 0
+func (h *SyscallHandler) handleGettimeofday() SyscallResult {
+	tv := h.regFile.ReadReg(0)
+	if tv != 0 {
+		h.writeUint64(tv, 0)   // tv_sec
+		h.writeUint64(tv+8, 0) // tv_usec
+	}
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// writeUint64 writes a little-endian uint64 into emulated memory at addr.
+func (h *SyscallHandler) writeUint64(addr uint64, v uint64) {
+	for i := uint64(0); i < 8; i++ {
+		h.memory.Write8(addr+i, byte(v>>(8*i)))
+	}
+}
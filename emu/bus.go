@@ -0,0 +1,200 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import "fmt"
+
+// MemAccessor is the facade LoadStoreUnit and FPU use to reach memory. It
+// is satisfied by both a bare *Memory and a *Bus, so attaching MMIO
+// devices to an Emulator is transparent to the execution units: they keep
+// calling Read8/Write8/Read16/Write16/Read32/Write32/Read64/Write64
+// exactly as before.
+type MemAccessor interface {
+	Read8(addr uint64) byte
+	Write8(addr uint64, value byte)
+	Read16(addr uint64) uint16
+	Write16(addr uint64, value uint16)
+	Read32(addr uint64) uint32
+	Write32(addr uint64, value uint32)
+	Read64(addr uint64) uint64
+	Write64(addr uint64, value uint64)
+}
+
+// Device is a memory-mapped peripheral attached to a Bus. addr is the
+// offset from the start of the Device's mapped range, not an absolute
+// address, so a Device never needs to know where Bus.Map placed it. size
+// is the access width in bytes (1, 2, 4, or 8 — whatever LoadStoreUnit/FPU
+// issued).
+type Device interface {
+	Read(addr uint64, size int) (uint64, error)
+	Write(addr uint64, size int, val uint64) error
+}
+
+// busMapping records one Device's address range on a Bus.
+type busMapping struct {
+	start uint64
+	size  uint64
+	dev   Device
+}
+
+func (m busMapping) contains(addr uint64, size int) bool {
+	return addr >= m.start && addr+uint64(size) <= m.start+m.size
+}
+
+// Bus is a memory-mapped I/O address space: accesses that land entirely
+// inside a mapped Device's range are routed to it; everything else falls
+// through to RAM, the default backing store for every unmapped region.
+// LoadStoreUnit and FPU talk to a Bus through the same MemAccessor facade
+// they'd use for a bare *Memory, so peripherals are transparent to the
+// execution units that issue loads and stores.
+type Bus struct {
+	ram      *Memory
+	mappings []busMapping
+
+	lastFault *Fault
+}
+
+// NewBus creates a Bus backed by ram for every address not claimed by a
+// mapped Device.
+func NewBus(ram *Memory) *Bus {
+	return &Bus{ram: ram}
+}
+
+// RAM returns the Memory backing addresses no Device claims.
+func (b *Bus) RAM() *Memory {
+	return b.ram
+}
+
+// Map binds dev to the address range [start, start+size). It returns an
+// error if the range overlaps a previously mapped Device; RAM has no
+// registration of its own, so it can never conflict with a Map call.
+func (b *Bus) Map(start, size uint64, dev Device) error {
+	for _, m := range b.mappings {
+		if start < m.start+m.size && m.start < start+size {
+			return fmt.Errorf("emu: device at 0x%X..0x%X overlaps device already mapped at 0x%X..0x%X",
+				start, start+size, m.start, m.start+m.size)
+		}
+	}
+	b.mappings = append(b.mappings, busMapping{start: start, size: size, dev: dev})
+	return nil
+}
+
+// deviceFor returns the mapping whose range fully contains [addr, addr+size),
+// or nil if no Device claims it (so the access should fall through to RAM).
+func (b *Bus) deviceFor(addr uint64, size int) *busMapping {
+	for i := range b.mappings {
+		if b.mappings[i].contains(addr, size) {
+			return &b.mappings[i]
+		}
+	}
+	return nil
+}
+
+// TakeFault returns and clears the most recently recorded Device error, or
+// nil if no Device access has failed since the last call. A successful
+// access does not clear an earlier fault, and accesses that fall through
+// to RAM record their own faults there (see Memory.TakeFault).
+func (b *Bus) TakeFault() *Fault {
+	f := b.lastFault
+	b.lastFault = nil
+	return f
+}
+
+func (b *Bus) read(addr uint64, size int) uint64 {
+	m := b.deviceFor(addr, size)
+	if m == nil {
+		switch size {
+		case 1:
+			return uint64(b.ram.Read8(addr))
+		case 2:
+			return uint64(b.ram.Read16(addr))
+		case 4:
+			return uint64(b.ram.Read32(addr))
+		case 8:
+			return b.ram.Read64(addr)
+		default:
+			return 0
+		}
+	}
+
+	val, err := m.dev.Read(addr-m.start, size)
+	if err != nil {
+		b.lastFault = &Fault{Kind: FaultProt, Addr: addr, Op: "read"}
+		return 0
+	}
+	return val
+}
+
+func (b *Bus) write(addr uint64, size int, val uint64) {
+	m := b.deviceFor(addr, size)
+	if m == nil {
+		switch size {
+		case 1:
+			b.ram.Write8(addr, byte(val))
+		case 2:
+			b.ram.Write16(addr, uint16(val))
+		case 4:
+			b.ram.Write32(addr, uint32(val))
+		case 8:
+			b.ram.Write64(addr, val)
+		}
+		return
+	}
+
+	if err := m.dev.Write(addr-m.start, size, val); err != nil {
+		b.lastFault = &Fault{Kind: FaultProt, Addr: addr, Op: "write"}
+	}
+}
+
+// Read8 reads a single byte, routing through a mapped Device if one
+// claims addr.
+func (b *Bus) Read8(addr uint64) byte {
+	return byte(b.read(addr, 1))
+}
+
+// Write8 writes a single byte, routing through a mapped Device if one
+// claims addr.
+func (b *Bus) Write8(addr uint64, value byte) {
+	b.write(addr, 1, uint64(value))
+}
+
+// Read16 reads a 16-bit little-endian value, routing through a mapped
+// Device if one claims the full 2-byte range.
+func (b *Bus) Read16(addr uint64) uint16 {
+	return uint16(b.read(addr, 2))
+}
+
+// Write16 writes a 16-bit little-endian value, routing through a mapped
+// Device if one claims the full 2-byte range.
+func (b *Bus) Write16(addr uint64, value uint16) {
+	b.write(addr, 2, uint64(value))
+}
+
+// Read32 reads a 32-bit little-endian value, routing through a mapped
+// Device if one claims the full 4-byte range.
+func (b *Bus) Read32(addr uint64) uint32 {
+	return uint32(b.read(addr, 4))
+}
+
+// Write32 writes a 32-bit little-endian value, routing through a mapped
+// Device if one claims the full 4-byte range.
+func (b *Bus) Write32(addr uint64, value uint32) {
+	b.write(addr, 4, uint64(value))
+}
+
+// Read64 reads a 64-bit little-endian value, routing through a mapped
+// Device if one claims the full 8-byte range.
+func (b *Bus) Read64(addr uint64) uint64 {
+	return b.read(addr, 8)
+}
+
+// Write64 writes a 64-bit little-endian value, routing through a mapped
+// Device if one claims the full 8-byte range.
+func (b *Bus) Write64(addr uint64, value uint64) {
+	b.write(addr, 8, value)
+}
+
+// FetchInst fetches an instruction word for execution. Devices never hold
+// executable code, so this always reads through to RAM.
+func (b *Bus) FetchInst(addr uint64) (uint32, *Fault) {
+	return b.ram.FetchInst(addr)
+}
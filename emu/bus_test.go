@@ -0,0 +1,64 @@
+package emu_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Bus", func() {
+	var (
+		ram *emu.Memory
+		bus *emu.Bus
+	)
+
+	BeforeEach(func() {
+		ram = emu.NewMemory()
+		bus = emu.NewBus(ram)
+		ram.MapAnonymous(0x1000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE)
+	})
+
+	It("should fall through to RAM for unmapped addresses", func() {
+		bus.Write64(0x1000, 0xDEADBEEF)
+
+		Expect(bus.Read64(0x1000)).To(Equal(uint64(0xDEADBEEF)))
+		Expect(ram.Read64(0x1000)).To(Equal(uint64(0xDEADBEEF)))
+	})
+
+	It("should route accesses inside a mapped Device's range to it", func() {
+		var out bytes.Buffer
+		Expect(bus.Map(0x9000_0000, 0x8, emu.NewUART(&out))).To(Succeed())
+
+		bus.Write32(0x9000_0000, 'h')
+		bus.Write32(0x9000_0000, 'i')
+
+		Expect(out.String()).To(Equal("hi"))
+	})
+
+	It("should reject a Device whose range overlaps an existing mapping", func() {
+		Expect(bus.Map(0x9000_0000, 0x100, emu.NewUART(&bytes.Buffer{}))).To(Succeed())
+
+		err := bus.Map(0x9000_0080, 0x100, emu.NewUART(&bytes.Buffer{}))
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should allow adjacent, non-overlapping mappings", func() {
+		Expect(bus.Map(0x9000_0000, 0x100, emu.NewUART(&bytes.Buffer{}))).To(Succeed())
+
+		err := bus.Map(0x9000_0100, 0x100, emu.NewUART(&bytes.Buffer{}))
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read the instruction count from a mapped Timer", func() {
+		count := uint64(0)
+		Expect(bus.Map(0xA000_0000, 0x8, emu.NewTimer(func() uint64 { return count }))).To(Succeed())
+
+		count = 42
+		Expect(bus.Read64(0xA000_0000)).To(Equal(uint64(42)))
+	})
+})
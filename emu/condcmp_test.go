@@ -15,7 +15,7 @@ import (
 // Format: sf | op | 1 | 11010010 | Rm/imm5 | cond | 1/0 | o2 | Rn | o3 | nzcv
 // op[30]: 1 for CCMP (subtract)
 // imm[11]: 0=register form, 1=immediate form
-func encodeCCMP(rn, rm uint8, cond uint8, nzcv uint8, is64Bit bool, isImm bool) uint32 {
+func encodeCCMP(rn, rm uint8, cond emu.ConditionCode, nzcv uint8, is64Bit bool, isImm bool) uint32 {
 	var sf uint32
 	if is64Bit {
 		sf = 1
@@ -32,7 +32,7 @@ func encodeCCMP(rn, rm uint8, cond uint8, nzcv uint8, is64Bit bool, isImm bool)
 
 // encodeCCMN encodes a CCMN (conditional compare negative) instruction.
 // op[30]: 0 for CCMN (add)
-func encodeCCMN(rn, rm uint8, cond uint8, nzcv uint8, is64Bit bool, isImm bool) uint32 {
+func encodeCCMN(rn, rm uint8, cond emu.ConditionCode, nzcv uint8, is64Bit bool, isImm bool) uint32 {
 	var sf uint32
 	if is64Bit {
 		sf = 1
@@ -47,15 +47,6 @@ func encodeCCMN(rn, rm uint8, cond uint8, nzcv uint8, is64Bit bool, isImm bool)
 		(uint32(rn) << 5) | uint32(nzcv)
 }
 
-// Condition codes
-const (
-	CondEQ_CC = 0b0000 // Equal (Z==1)
-	CondNE_CC = 0b0001 // Not equal (Z==0)
-	CondGE_CC = 0b1010 // Signed greater or equal (N==V)
-	CondLT_CC = 0b1011 // Signed less than (N!=V)
-	CondAL_CC = 0b1110 // Always
-)
-
 func condCmpProgram(inst uint32) []byte {
 	buf := make([]byte, 4)
 	binary.LittleEndian.PutUint32(buf, inst)
@@ -80,7 +71,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				// CCMP X1, X2, #0, EQ
 				// If EQ is true: compare X1 - X2 (100-50=50)
 				// Result: N=0, Z=0, C=1 (no borrow), V=0
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0b0000, true, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0b0000, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -101,7 +92,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 
 				// CCMP X1, X2, #0b1010, EQ
 				// If EQ is false: set flags to nzcv=1010 (N=1, Z=0, C=1, V=0)
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0b1010, true, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0b1010, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -120,7 +111,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true // EQ condition true
 
 				// CCMP X1, X2, #0, EQ => 42-42=0, Z=1
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0, true, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -137,7 +128,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true // EQ condition true
 
 				// CCMP X1, X2, #0, EQ => 50-100=-50, N=1
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0, true, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -155,7 +146,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true // EQ condition true
 
 				// CCMP X1, #10, #0, EQ => 100-10=90
-				inst := encodeCCMP(1, 10, CondEQ_CC, 0, true, true)
+				inst := encodeCCMP(1, 10, emu.CondEQ, 0, true, true)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -175,7 +166,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true
 
 				// CCMP W1, W2, #0, EQ (32-bit)
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0, false, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0, false, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -194,7 +185,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 
 				// CCMP W1, W2, #0, EQ
 				// 0x80000000 - 1 = 0x7FFFFFFF (positive in 32-bit)
-				inst := encodeCCMP(1, 2, CondEQ_CC, 0, false, false)
+				inst := encodeCCMP(1, 2, emu.CondEQ, 0, false, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -215,7 +206,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true // EQ condition true
 
 				// CCMN X1, X2, #0, EQ => 100+50=150, flags like ADDS
-				inst := encodeCCMN(1, 2, CondEQ_CC, 0, true, false)
+				inst := encodeCCMN(1, 2, emu.CondEQ, 0, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -234,7 +225,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 
 				// CCMN X1, X2, #0b1111, EQ
 				// If EQ is false: set flags to nzcv=1111
-				inst := encodeCCMN(1, 2, CondEQ_CC, 0b1111, true, false)
+				inst := encodeCCMN(1, 2, emu.CondEQ, 0b1111, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -253,7 +244,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true
 
 				// CCMN X1, X2, #0, EQ => 0xFFFF...FF + 1 = 0, C=1 (overflow)
-				inst := encodeCCMN(1, 2, CondEQ_CC, 0, true, false)
+				inst := encodeCCMN(1, 2, emu.CondEQ, 0, true, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -271,7 +262,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true
 
 				// CCMN X1, #5, #0, EQ => 100+5=105
-				inst := encodeCCMN(1, 5, CondEQ_CC, 0, true, true)
+				inst := encodeCCMN(1, 5, emu.CondEQ, 0, true, true)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -290,7 +281,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true
 
 				// CCMN W1, W2, #0, EQ (32-bit)
-				inst := encodeCCMN(1, 2, CondEQ_CC, 0, false, false)
+				inst := encodeCCMN(1, 2, emu.CondEQ, 0, false, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -307,7 +298,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 				e.RegFile().PSTATE.Z = true
 
 				// CCMN W1, W2, #0, EQ => 0xFFFFFFFF + 1 = 0, C=1
-				inst := encodeCCMN(1, 2, CondEQ_CC, 0, false, false)
+				inst := encodeCCMN(1, 2, emu.CondEQ, 0, false, false)
 				program := condCmpProgram(inst)
 				e.LoadProgram(0x1000, program)
 
@@ -327,7 +318,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 			e.RegFile().PSTATE.Z = false // NE condition true (Z=0)
 
 			// CCMP X1, X2, #0, NE
-			inst := encodeCCMP(1, 2, CondNE_CC, 0, true, false)
+			inst := encodeCCMP(1, 2, emu.CondNE, 0, true, false)
 			program := condCmpProgram(inst)
 			e.LoadProgram(0x1000, program)
 
@@ -345,7 +336,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 			e.RegFile().PSTATE.V = false // GE is true (N==V)
 
 			// CCMP X1, X2, #0, GE
-			inst := encodeCCMP(1, 2, CondGE_CC, 0, true, false)
+			inst := encodeCCMP(1, 2, emu.CondGE, 0, true, false)
 			program := condCmpProgram(inst)
 			e.LoadProgram(0x1000, program)
 
@@ -362,7 +353,7 @@ var _ = Describe("Conditional Compare Instructions", func() {
 
 			// CCMP X1, X2, #0b1111, AL
 			// AL always evaluates to true, so comparison always happens
-			inst := encodeCCMP(1, 2, CondAL_CC, 0b1111, true, false)
+			inst := encodeCCMP(1, 2, emu.CondAL, 0b1111, true, false)
 			program := condCmpProgram(inst)
 			e.LoadProgram(0x1000, program)
 
@@ -0,0 +1,76 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+// ConditionCode is one of the 16 AArch64 condition codes encoded in a
+// 4-bit cond field: the cond operand of B.cond, CCMP/CCMN, CSEL and its
+// aliases (CSINC/CSINV/CSNEG), and CSET/CSETM. Its values match the
+// architectural encoding directly, so a decoded 4-bit cond field can be
+// converted with a plain cast, e.g. ConditionCode(inst.Cond).
+type ConditionCode uint8
+
+// The 16 AArch64 condition codes, in architectural encoding order.
+const (
+	CondEQ ConditionCode = iota // Equal: Z == 1
+	CondNE                      // Not equal: Z == 0
+	CondCS                      // Carry set (unsigned higher or same): C == 1
+	CondCC                      // Carry clear (unsigned lower): C == 0
+	CondMI                      // Minus (negative): N == 1
+	CondPL                      // Plus (positive or zero): N == 0
+	CondVS                      // Overflow set: V == 1
+	CondVC                      // Overflow clear: V == 0
+	CondHI                      // Unsigned higher: C == 1 && Z == 0
+	CondLS                      // Unsigned lower or same: !(C == 1 && Z == 0)
+	CondGE                      // Signed greater than or equal: N == V
+	CondLT                      // Signed less than: N != V
+	CondGT                      // Signed greater than: Z == 0 && N == V
+	CondLE                      // Signed less than or equal: !(Z == 0 && N == V)
+	CondAL                      // Always
+	CondNV                      // Always (reserved encoding; architecturally behaves as AL)
+)
+
+// CondHS and CondLO are the assembler mnemonics commonly used for CondCS
+// and CondCC respectively; they share the same encoding.
+const (
+	CondHS = CondCS
+	CondLO = CondCC
+)
+
+// Evaluate reports whether c holds against pstate. It is the single
+// canonical condition-code evaluator: every flag-consuming instruction
+// (B.cond, CCMP/CCMN, CSEL and its aliases, CSET/CSETM) routes through
+// this method via Emulator.EvaluateCondition, so they can never disagree
+// about what a condition code means.
+func (c ConditionCode) Evaluate(pstate PSTATE) bool {
+	switch c {
+	case CondEQ:
+		return pstate.Z
+	case CondNE:
+		return !pstate.Z
+	case CondCS:
+		return pstate.C
+	case CondCC:
+		return !pstate.C
+	case CondMI:
+		return pstate.N
+	case CondPL:
+		return !pstate.N
+	case CondVS:
+		return pstate.V
+	case CondVC:
+		return !pstate.V
+	case CondHI:
+		return pstate.C && !pstate.Z
+	case CondLS:
+		return !(pstate.C && !pstate.Z)
+	case CondGE:
+		return pstate.N == pstate.V
+	case CondLT:
+		return pstate.N != pstate.V
+	case CondGT:
+		return !pstate.Z && pstate.N == pstate.V
+	case CondLE:
+		return !(!pstate.Z && pstate.N == pstate.V)
+	default: // CondAL, CondNV
+		return true
+	}
+}
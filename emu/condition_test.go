@@ -0,0 +1,97 @@
+package emu_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("ConditionCode", func() {
+	Describe("Evaluate", func() {
+		It("should evaluate EQ from Z", func() {
+			Expect(emu.CondEQ.Evaluate(emu.PSTATE{Z: true})).To(BeTrue())
+			Expect(emu.CondEQ.Evaluate(emu.PSTATE{Z: false})).To(BeFalse())
+		})
+
+		It("should evaluate NE from Z", func() {
+			Expect(emu.CondNE.Evaluate(emu.PSTATE{Z: false})).To(BeTrue())
+			Expect(emu.CondNE.Evaluate(emu.PSTATE{Z: true})).To(BeFalse())
+		})
+
+		It("should evaluate CS/HS from C", func() {
+			Expect(emu.CondCS.Evaluate(emu.PSTATE{C: true})).To(BeTrue())
+			Expect(emu.CondCS.Evaluate(emu.PSTATE{C: false})).To(BeFalse())
+		})
+
+		It("should evaluate CC/LO from C", func() {
+			Expect(emu.CondCC.Evaluate(emu.PSTATE{C: false})).To(BeTrue())
+			Expect(emu.CondCC.Evaluate(emu.PSTATE{C: true})).To(BeFalse())
+		})
+
+		It("should evaluate MI from N", func() {
+			Expect(emu.CondMI.Evaluate(emu.PSTATE{N: true})).To(BeTrue())
+			Expect(emu.CondMI.Evaluate(emu.PSTATE{N: false})).To(BeFalse())
+		})
+
+		It("should evaluate PL from N", func() {
+			Expect(emu.CondPL.Evaluate(emu.PSTATE{N: false})).To(BeTrue())
+			Expect(emu.CondPL.Evaluate(emu.PSTATE{N: true})).To(BeFalse())
+		})
+
+		It("should evaluate VS from V", func() {
+			Expect(emu.CondVS.Evaluate(emu.PSTATE{V: true})).To(BeTrue())
+			Expect(emu.CondVS.Evaluate(emu.PSTATE{V: false})).To(BeFalse())
+		})
+
+		It("should evaluate VC from V", func() {
+			Expect(emu.CondVC.Evaluate(emu.PSTATE{V: false})).To(BeTrue())
+			Expect(emu.CondVC.Evaluate(emu.PSTATE{V: true})).To(BeFalse())
+		})
+
+		It("should evaluate HI from C && !Z", func() {
+			Expect(emu.CondHI.Evaluate(emu.PSTATE{C: true, Z: false})).To(BeTrue())
+			Expect(emu.CondHI.Evaluate(emu.PSTATE{C: true, Z: true})).To(BeFalse())
+			Expect(emu.CondHI.Evaluate(emu.PSTATE{C: false, Z: false})).To(BeFalse())
+		})
+
+		It("should evaluate LS as the negation of HI", func() {
+			Expect(emu.CondLS.Evaluate(emu.PSTATE{C: true, Z: true})).To(BeTrue())
+			Expect(emu.CondLS.Evaluate(emu.PSTATE{C: false, Z: false})).To(BeTrue())
+			Expect(emu.CondLS.Evaluate(emu.PSTATE{C: true, Z: false})).To(BeFalse())
+		})
+
+		It("should evaluate GE from N == V", func() {
+			Expect(emu.CondGE.Evaluate(emu.PSTATE{N: true, V: true})).To(BeTrue())
+			Expect(emu.CondGE.Evaluate(emu.PSTATE{N: false, V: false})).To(BeTrue())
+			Expect(emu.CondGE.Evaluate(emu.PSTATE{N: true, V: false})).To(BeFalse())
+		})
+
+		It("should evaluate LT from N != V", func() {
+			Expect(emu.CondLT.Evaluate(emu.PSTATE{N: false, V: true})).To(BeTrue())
+			Expect(emu.CondLT.Evaluate(emu.PSTATE{N: false, V: false})).To(BeFalse())
+		})
+
+		It("should evaluate GT from !Z && N == V", func() {
+			Expect(emu.CondGT.Evaluate(emu.PSTATE{Z: false, N: true, V: true})).To(BeTrue())
+			Expect(emu.CondGT.Evaluate(emu.PSTATE{Z: true, N: true, V: true})).To(BeFalse())
+			Expect(emu.CondGT.Evaluate(emu.PSTATE{Z: false, N: true, V: false})).To(BeFalse())
+		})
+
+		It("should evaluate LE as the negation of GT", func() {
+			Expect(emu.CondLE.Evaluate(emu.PSTATE{Z: true, N: true, V: true})).To(BeTrue())
+			Expect(emu.CondLE.Evaluate(emu.PSTATE{Z: false, N: true, V: false})).To(BeTrue())
+			Expect(emu.CondLE.Evaluate(emu.PSTATE{Z: false, N: true, V: true})).To(BeFalse())
+		})
+
+		It("should evaluate AL and NV as always true", func() {
+			Expect(emu.CondAL.Evaluate(emu.PSTATE{})).To(BeTrue())
+			Expect(emu.CondNV.Evaluate(emu.PSTATE{})).To(BeTrue())
+		})
+	})
+
+	It("should alias CondHS to CondCS and CondLO to CondCC", func() {
+		Expect(emu.CondHS).To(Equal(emu.CondCS))
+		Expect(emu.CondLO).To(Equal(emu.CondCC))
+	})
+})
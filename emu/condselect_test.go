@@ -0,0 +1,313 @@
+package emu_test
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Encoder helpers for the conditional select family.
+
+// encodeCSEL encodes a CSEL (conditional select) instruction.
+// Format: sf | op=0 | S=0 | 11010100 | Rm | cond | op2=00 | Rn | Rd
+func encodeCSEL(rd, rn, rm uint8, cond emu.ConditionCode, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (0 << 30) | (0b11010100 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (0b00 << 10) |
+		(uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeCSINC encodes a CSINC (conditional select increment) instruction.
+// op2=01 distinguishes it from CSEL.
+func encodeCSINC(rd, rn, rm uint8, cond emu.ConditionCode, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (0 << 30) | (0b11010100 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (0b01 << 10) |
+		(uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeCSINV encodes a CSINV (conditional select invert) instruction.
+// op=1, op2=00 distinguishes it from CSEL.
+func encodeCSINV(rd, rn, rm uint8, cond emu.ConditionCode, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (1 << 30) | (0b11010100 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (0b00 << 10) |
+		(uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeCSNEG encodes a CSNEG (conditional select negate) instruction.
+// op=1, op2=01 distinguishes it from CSINV.
+func encodeCSNEG(rd, rn, rm uint8, cond emu.ConditionCode, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (1 << 30) | (0b11010100 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (0b01 << 10) |
+		(uint32(rn) << 5) | uint32(rd)
+}
+
+func condSelectProgram(inst uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, inst)
+	return buf
+}
+
+var _ = Describe("Conditional Select Instructions", func() {
+	var e *emu.Emulator
+
+	BeforeEach(func() {
+		e = emu.NewEmulator()
+	})
+
+	Describe("CSEL", func() {
+		Context("64-bit form", func() {
+			It("should select Rn when the condition is true", func() {
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 200)
+				e.RegFile().PSTATE.Z = true // EQ true
+
+				inst := encodeCSEL(0, 1, 2, emu.CondEQ, true)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(100)))
+			})
+
+			It("should select Rm when the condition is false", func() {
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 200)
+				e.RegFile().PSTATE.Z = false // EQ false
+
+				inst := encodeCSEL(0, 1, 2, emu.CondEQ, true)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(200)))
+			})
+		})
+
+		Context("32-bit form", func() {
+			It("should select only the low 32 bits", func() {
+				e.RegFile().WriteReg(1, 0xFFFFFFFF00000001)
+				e.RegFile().WriteReg(2, 2)
+				e.RegFile().PSTATE.Z = true
+
+				inst := encodeCSEL(0, 1, 2, emu.CondEQ, false)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(1)))
+			})
+		})
+
+		It("should tolerate Rd, Rn and Rm all naming the same register", func() {
+			e.RegFile().WriteReg(1, 42)
+			e.RegFile().PSTATE.Z = true
+
+			inst := encodeCSEL(1, 1, 1, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(1)).To(Equal(uint64(42)))
+		})
+
+		It("should read XZR/WZR as zero when used as Rn or Rm", func() {
+			e.RegFile().WriteReg(1, 77)
+			e.RegFile().PSTATE.Z = false // EQ false, so Rm (XZR) is selected
+
+			inst := encodeCSEL(0, 1, 31, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("CSINC", func() {
+		It("should select Rn unmodified when the condition is true", func() {
+			e.RegFile().WriteReg(1, 10)
+			e.RegFile().WriteReg(2, 20)
+			e.RegFile().PSTATE.Z = true
+
+			inst := encodeCSINC(0, 1, 2, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(10)))
+		})
+
+		It("should select Rm+1 when the condition is false", func() {
+			e.RegFile().WriteReg(1, 10)
+			e.RegFile().WriteReg(2, 20)
+			e.RegFile().PSTATE.Z = false
+
+			inst := encodeCSINC(0, 1, 2, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(21)))
+		})
+
+		It("should implement CSET as CSINC with Rn=Rm=XZR and an inverted condition", func() {
+			e.RegFile().PSTATE.Z = true // EQ true, so NE (inverted) is false
+
+			// CSET X0, EQ  ==  CSINC X0, XZR, XZR, NE
+			inst := encodeCSINC(0, 31, 31, emu.CondNE, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("CSINV", func() {
+		It("should select Rn unmodified when the condition is true", func() {
+			e.RegFile().WriteReg(1, 10)
+			e.RegFile().WriteReg(2, 0)
+			e.RegFile().PSTATE.Z = true
+
+			inst := encodeCSINV(0, 1, 2, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(10)))
+		})
+
+		It("should select the bitwise inverse of Rm when the condition is false", func() {
+			e.RegFile().WriteReg(1, 10)
+			e.RegFile().WriteReg(2, 0)
+			e.RegFile().PSTATE.Z = false
+
+			inst := encodeCSINV(0, 1, 2, emu.CondEQ, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
+		})
+
+		It("should implement CSETM as CSINV with Rn=Rm=XZR and an inverted condition", func() {
+			e.RegFile().PSTATE.Z = true // EQ true, so NE (inverted) is false
+
+			// CSETM X0, EQ  ==  CSINV X0, XZR, XZR, NE
+			inst := encodeCSINV(0, 31, 31, emu.CondNE, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
+		})
+	})
+
+	Describe("CSNEG", func() {
+		Context("64-bit form", func() {
+			It("should select Rn unmodified when the condition is true", func() {
+				e.RegFile().WriteReg(1, 10)
+				e.RegFile().WriteReg(2, 5)
+				e.RegFile().PSTATE.Z = true
+
+				inst := encodeCSNEG(0, 1, 2, emu.CondEQ, true)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(10)))
+			})
+
+			It("should select the negation of Rm when the condition is false", func() {
+				e.RegFile().WriteReg(1, 10)
+				e.RegFile().WriteReg(2, 5)
+				e.RegFile().PSTATE.Z = false
+
+				inst := encodeCSNEG(0, 1, 2, emu.CondEQ, true)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFB))) // -5
+			})
+		})
+
+		Context("32-bit form", func() {
+			It("should select the 32-bit negation of Rm when the condition is false", func() {
+				e.RegFile().WriteReg(1, 10)
+				e.RegFile().WriteReg(2, 5)
+				e.RegFile().PSTATE.Z = false
+
+				inst := encodeCSNEG(0, 1, 2, emu.CondEQ, false)
+				e.LoadProgram(0x1000, condSelectProgram(inst))
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFB))) // -5 as uint32
+			})
+		})
+	})
+
+	Describe("Different conditions", func() {
+		It("should handle GT (signed greater than)", func() {
+			e.RegFile().WriteReg(1, 1)
+			e.RegFile().WriteReg(2, 2)
+			e.RegFile().PSTATE.Z = false
+			e.RegFile().PSTATE.N = false
+			e.RegFile().PSTATE.V = false // GT true
+
+			inst := encodeCSEL(0, 1, 2, emu.CondGT, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(1)))
+		})
+
+		It("should handle AL (always)", func() {
+			e.RegFile().WriteReg(1, 1)
+			e.RegFile().WriteReg(2, 2)
+
+			inst := encodeCSEL(0, 1, 2, emu.CondAL, true)
+			e.LoadProgram(0x1000, condSelectProgram(inst))
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(1)))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package emu_test
 
 import (
 	"encoding/binary"
+	"math"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -35,6 +36,33 @@ func encodeSDIV(rd, rn, rm uint8, is64Bit bool) uint32 {
 	return (sf << 31) | (0b011010110 << 21) | (uint32(rm) << 16) | (0b000011 << 10) | (uint32(rn) << 5) | uint32(rd)
 }
 
+// encodeCRC32 encodes a member of the CRC32/CRC32C checksum family, which
+// shares the bits[29:21]=011010110 encoding group with UDIV/SDIV and the
+// variable-shift instructions. sz selects the element size processed from
+// Rm: 0b00=byte, 0b01=halfword, 0b10=word, 0b11=doubleword. isC selects
+// CRC32C (Castagnoli) over plain CRC32 (IEEE 802.3).
+// opcode[15:10] = 0100sz for CRC32, 0101sz for CRC32C.
+func encodeCRC32(rd, rn, rm uint8, sz uint32, isC bool) uint32 {
+	var sf uint32
+	if sz == 0b11 {
+		sf = 1 // CRC32X/CRC32CX take a 64-bit Rm
+	}
+	opcode := 0b010000 | sz
+	if isC {
+		opcode |= 0b000100
+	}
+	return (sf << 31) | (0b011010110 << 21) | (uint32(rm) << 16) | (opcode << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+func encodeCRC32B(rd, rn, rm uint8) uint32  { return encodeCRC32(rd, rn, rm, 0b00, false) }
+func encodeCRC32H(rd, rn, rm uint8) uint32  { return encodeCRC32(rd, rn, rm, 0b01, false) }
+func encodeCRC32W(rd, rn, rm uint8) uint32  { return encodeCRC32(rd, rn, rm, 0b10, false) }
+func encodeCRC32X(rd, rn, rm uint8) uint32  { return encodeCRC32(rd, rn, rm, 0b11, false) }
+func encodeCRC32CB(rd, rn, rm uint8) uint32 { return encodeCRC32(rd, rn, rm, 0b00, true) }
+func encodeCRC32CH(rd, rn, rm uint8) uint32 { return encodeCRC32(rd, rn, rm, 0b01, true) }
+func encodeCRC32CW(rd, rn, rm uint8) uint32 { return encodeCRC32(rd, rn, rm, 0b10, true) }
+func encodeCRC32CX(rd, rn, rm uint8) uint32 { return encodeCRC32(rd, rn, rm, 0b11, true) }
+
 // encodeMADD encodes a MADD instruction.
 // Format: sf | op54 | 11011 | op31 | Rm | o0 | Ra | Rn | Rd
 // MADD Rd, Rn, Rm, Ra => Rd = Ra + (Rn * Rm)
@@ -57,6 +85,49 @@ func encodeMSUB(rd, rn, rm, ra uint8, is64Bit bool) uint32 {
 	return (sf << 31) | (0b11011 << 24) | (uint32(rm) << 16) | (1 << 15) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
 }
 
+// encodeSMADDL encodes a SMADDL instruction (32x32 -> 64 signed
+// multiply-add; Xd = Xa + Wn * Wm, sign-extended).
+// Format: sf | op54 | 11011 | op31 | Rm | o0 | Ra | Rn | Rd
+// bits [28:24] = 0b11011, op31[23:21] = 001, o0[15] = 0 for SMADDL.
+func encodeSMADDL(rd, rn, rm, ra uint8) uint32 {
+	return (1 << 31) | (0b11011 << 24) | (0b001 << 21) | (uint32(rm) << 16) | (0 << 15) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeSMSUBL encodes a SMSUBL instruction (Xd = Xa - Wn * Wm, signed).
+// op31[23:21] = 001, o0[15] = 1 for SMSUBL.
+func encodeSMSUBL(rd, rn, rm, ra uint8) uint32 {
+	return (1 << 31) | (0b11011 << 24) | (0b001 << 21) | (uint32(rm) << 16) | (1 << 15) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeSMULH encodes a SMULH instruction (Xd = high 64 bits of the signed
+// 128-bit product Xn * Xm). op31[23:21] = 010, o0[15] = 0, Ra is ignored
+// and must be encoded as XZR (31).
+func encodeSMULH(rd, rn, rm uint8) uint32 {
+	const ra = 31
+	return (1 << 31) | (0b11011 << 24) | (0b010 << 21) | (uint32(rm) << 16) | (0 << 15) | (ra << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeUMADDL encodes a UMADDL instruction (32x32 -> 64 unsigned
+// multiply-add; Xd = Xa + Wn * Wm, zero-extended).
+// op31[23:21] = 101, o0[15] = 0 for UMADDL.
+func encodeUMADDL(rd, rn, rm, ra uint8) uint32 {
+	return (1 << 31) | (0b11011 << 24) | (0b101 << 21) | (uint32(rm) << 16) | (0 << 15) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeUMSUBL encodes a UMSUBL instruction (Xd = Xa - Wn * Wm, unsigned).
+// op31[23:21] = 101, o0[15] = 1 for UMSUBL.
+func encodeUMSUBL(rd, rn, rm, ra uint8) uint32 {
+	return (1 << 31) | (0b11011 << 24) | (0b101 << 21) | (uint32(rm) << 16) | (1 << 15) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeUMULH encodes a UMULH instruction (Xd = high 64 bits of the
+// unsigned 128-bit product Xn * Xm). op31[23:21] = 110, o0[15] = 0, Ra is
+// ignored and must be encoded as XZR (31).
+func encodeUMULH(rd, rn, rm uint8) uint32 {
+	const ra = 31
+	return (1 << 31) | (0b11011 << 24) | (0b110 << 21) | (uint32(rm) << 16) | (0 << 15) | (ra << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
 // encodeLSLV encodes a LSLV instruction (logical shift left by register).
 // Format: sf | 0 | S | 11010110 | Rm | opcode | Rn | Rd
 // opcode = 001000
@@ -95,6 +166,52 @@ func encodeRORV(rd, rn, rm uint8, is64Bit bool) uint32 {
 	return (sf << 31) | (0b011010110 << 21) | (uint32(rm) << 16) | (0b001011 << 10) | (uint32(rn) << 5) | uint32(rd)
 }
 
+// encodeDP1Source encodes a Data-Processing (1 source) instruction.
+// Format: sf | 1 | S | 11010110 | opcode2 | opcode | Rn | Rd
+// bits [30:21] = 0b1011010110 (S=0, opcode2=00000 for all ops below).
+func encodeDP1Source(opcode uint32, rd, rn uint8, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (1 << 30) | (0b011010110 << 21) | (opcode << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeRBIT encodes a RBIT instruction (opcode = 000000).
+func encodeRBIT(rd, rn uint8, is64Bit bool) uint32 {
+	return encodeDP1Source(0b000000, rd, rn, is64Bit)
+}
+
+// encodeREV16 encodes a REV16 instruction (opcode = 000001).
+func encodeREV16(rd, rn uint8, is64Bit bool) uint32 {
+	return encodeDP1Source(0b000001, rd, rn, is64Bit)
+}
+
+// encodeREV encodes a REV instruction: opcode = 000010 at sf=0 (REV
+// Wd, Wn), opcode = 000011 at sf=1 (REV Xd, Xn).
+func encodeREV(rd, rn uint8, is64Bit bool) uint32 {
+	if is64Bit {
+		return encodeDP1Source(0b000011, rd, rn, true)
+	}
+	return encodeDP1Source(0b000010, rd, rn, false)
+}
+
+// encodeREV32 encodes a REV32 instruction (opcode = 000010, sf=1 only):
+// reverses the bytes within each 32-bit word of Xn independently.
+func encodeREV32(rd, rn uint8) uint32 {
+	return encodeDP1Source(0b000010, rd, rn, true)
+}
+
+// encodeCLZ encodes a CLZ instruction (opcode = 000100).
+func encodeCLZ(rd, rn uint8, is64Bit bool) uint32 {
+	return encodeDP1Source(0b000100, rd, rn, is64Bit)
+}
+
+// encodeCLS encodes a CLS instruction (opcode = 000101).
+func encodeCLS(rd, rn uint8, is64Bit bool) uint32 {
+	return encodeDP1Source(0b000101, rd, rn, is64Bit)
+}
+
 func dataProcProgram(inst uint32) []byte {
 	buf := make([]byte, 4)
 	binary.LittleEndian.PutUint32(buf, inst)
@@ -251,6 +368,126 @@ var _ = Describe("Data Processing Instructions", func() {
 		})
 	})
 
+	Describe("Divide Fault Policy", func() {
+		Context("FaultTrap", func() {
+			It("should trap UDIV by zero, leaving PC and Rd untouched", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultTrap))
+				inst := encodeUDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(0, 0xAAAA)
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 0)
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionDivideByZero))
+				Expect(e.RegFile().PC).To(Equal(uint64(0x1000)))
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xAAAA)))
+			})
+
+			It("should trap SDIV by zero", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultTrap))
+				inst := encodeSDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 0)
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionDivideByZero))
+				Expect(e.RegFile().PC).To(Equal(uint64(0x1000)))
+			})
+
+			It("should trap SDIV INT_MIN/-1 as a distinct overflow vector", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultTrap))
+				inst := encodeSDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(0, 0xAAAA)
+				e.RegFile().WriteReg(1, uint64(int64(math.MinInt64)))
+				e.RegFile().WriteReg(2, uint64(int64(-1)))
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionDivideOverflow))
+				Expect(e.RegFile().PC).To(Equal(uint64(0x1000)))
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xAAAA)))
+			})
+		})
+
+		Context("FaultCallback", func() {
+			It("should trap when the handler returns FaultActionTrap", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultCallback))
+				var seen emu.EmuFault
+				e.SetFaultHandler(func(f emu.EmuFault) emu.FaultAction {
+					seen = f
+					return emu.FaultActionTrap
+				})
+
+				inst := encodeUDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 0)
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionDivideByZero))
+				Expect(e.RegFile().PC).To(Equal(uint64(0x1000)))
+				Expect(seen.Rn).To(Equal(uint64(100)))
+				Expect(seen.Rm).To(Equal(uint64(0)))
+			})
+
+			It("should fall back to the silent result when the handler returns FaultActionSilent", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultCallback))
+				e.SetFaultHandler(func(emu.EmuFault) emu.FaultAction {
+					return emu.FaultActionSilent
+				})
+
+				inst := encodeUDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 0)
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionNone))
+				Expect(e.RegFile().PC).To(Equal(uint64(0x1004)))
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0)))
+			})
+
+			It("should behave like FaultSilent when no handler is attached", func() {
+				e := emu.NewEmulator(emu.WithFaultPolicy(emu.FaultCallback))
+
+				inst := encodeUDIV(0, 1, 2, true)
+				program := dataProcProgram(inst)
+
+				e.RegFile().WriteReg(1, 100)
+				e.RegFile().WriteReg(2, 0)
+				e.LoadProgram(0x1000, program)
+
+				result := e.Step()
+
+				Expect(result.Err).To(BeNil())
+				Expect(result.ExceptionVector).To(Equal(emu.ExceptionNone))
+				Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0)))
+			})
+		})
+	})
+
 	Describe("MADD - Multiply-Add", func() {
 		Context("64-bit", func() {
 			It("should compute Ra + Rn * Rm", func() {
@@ -357,6 +594,438 @@ var _ = Describe("Data Processing Instructions", func() {
 		})
 	})
 
+	Describe("SMADDL - Signed Multiply-Add Long", func() {
+		It("should compute Xa + Wn * Wm sign-extended", func() {
+			// SMADDL X0, W1, W2, X3 => X0 = X3 + (Wn * Wm), signed
+			inst := encodeSMADDL(0, 1, 2, 3)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, uint64(^uint32(4))+1) // W1 = -5
+			e.RegFile().WriteReg(2, 7)
+			e.RegFile().WriteReg(3, 100)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(int64(e.RegFile().ReadReg(0))).To(Equal(int64(100 - 5*7))) // 65
+		})
+
+		It("should work with zero addend (SMULL alias)", func() {
+			// SMULL is SMADDL with Ra = XZR
+			inst := encodeSMADDL(0, 1, 2, 31)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 6)
+			e.RegFile().WriteReg(2, 8)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(48)))
+		})
+	})
+
+	Describe("SMSUBL - Signed Multiply-Subtract Long", func() {
+		It("should compute Xa - Wn * Wm sign-extended", func() {
+			inst := encodeSMSUBL(0, 1, 2, 3)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 5)
+			e.RegFile().WriteReg(2, 7)
+			e.RegFile().WriteReg(3, 100)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(int64(e.RegFile().ReadReg(0))).To(Equal(int64(100 - 5*7))) // 65
+		})
+	})
+
+	Describe("SMULH - Signed Multiply High", func() {
+		It("should compute the upper 64 bits of a signed 128-bit product", func() {
+			inst := encodeSMULH(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			// -1 * -1 = 1, so the upper 64 bits are 0.
+			e.RegFile().WriteReg(1, 0xFFFFFFFFFFFFFFFF) // -1
+			e.RegFile().WriteReg(2, 0xFFFFFFFFFFFFFFFF) // -1
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0)))
+		})
+
+		It("should produce -1 for a negative times a large positive", func() {
+			inst := encodeSMULH(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			// -1 * X = -X, whose upper 64 bits of the 128-bit result are
+			// all ones for any nonzero X.
+			e.RegFile().WriteReg(1, 0xFFFFFFFFFFFFFFFF) // -1
+			e.RegFile().WriteReg(2, 42)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
+		})
+	})
+
+	Describe("UMADDL - Unsigned Multiply-Add Long", func() {
+		It("should compute Xa + Wn * Wm zero-extended", func() {
+			inst := encodeUMADDL(0, 1, 2, 3)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0xFFFFFFFF) // W1, zero-extended to 2^32-1
+			e.RegFile().WriteReg(2, 2)
+			e.RegFile().WriteReg(3, 10)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(10) + 0xFFFFFFFF*2))
+		})
+
+		It("should work with zero addend (UMULL alias)", func() {
+			inst := encodeUMADDL(0, 1, 2, 31)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 6)
+			e.RegFile().WriteReg(2, 8)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(48)))
+		})
+	})
+
+	Describe("UMSUBL - Unsigned Multiply-Subtract Long", func() {
+		It("should compute Xa - Wn * Wm zero-extended", func() {
+			inst := encodeUMSUBL(0, 1, 2, 3)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 5)
+			e.RegFile().WriteReg(2, 7)
+			e.RegFile().WriteReg(3, 100)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(100 - 5*7))) // 65
+		})
+	})
+
+	Describe("UMULH - Unsigned Multiply High", func() {
+		It("should compute the upper 64 bits of an unsigned 128-bit product", func() {
+			inst := encodeUMULH(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0xFFFFFFFFFFFFFFFF)
+			e.RegFile().WriteReg(2, 0xFFFFFFFFFFFFFFFF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			// (2^64-1)^2 = 2^128 - 2^65 + 1, whose upper 64 bits are 2^64-2.
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFE)))
+		})
+
+		It("should return 0 when either operand is zero", func() {
+			inst := encodeUMULH(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0xFFFFFFFFFFFFFFFF)
+			e.RegFile().WriteReg(2, 0)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("CLZ - Count Leading Zeros", func() {
+		It("should count leading zeros (64-bit)", func() {
+			inst := encodeCLZ(0, 1, true)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x0000000F00000000)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(28)))
+		})
+
+		It("should return the register width for a zero input (32-bit)", func() {
+			inst := encodeCLZ(0, 1, false)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(32)))
+		})
+	})
+
+	Describe("CLS - Count Leading Sign Bits", func() {
+		It("should count leading sign bits (64-bit)", func() {
+			inst := encodeCLS(0, 1, true)
+			program := dataProcProgram(inst)
+
+			// Top two bits are both 1 (sign bit repeated once), then a 0.
+			e.RegFile().WriteReg(1, 0xC000000000000000)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(1)))
+		})
+
+		It("should return width-1 for an all-zero input (32-bit)", func() {
+			inst := encodeCLS(0, 1, false)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(31)))
+		})
+	})
+
+	Describe("RBIT - Reverse Bits", func() {
+		It("should reverse the bit order (64-bit)", func() {
+			inst := encodeRBIT(0, 1, true)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x1)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x8000000000000000)))
+		})
+
+		It("should reverse the bit order and zero-extend (32-bit)", func() {
+			inst := encodeRBIT(0, 1, false)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x1)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x80000000)))
+		})
+	})
+
+	Describe("REV - Reverse Bytes", func() {
+		It("should reverse all 8 bytes (64-bit)", func() {
+			inst := encodeREV(0, 1, true)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x0123456789ABCDEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xEFCDAB8967452301)))
+		})
+
+		It("should reverse all 4 bytes and zero-extend (32-bit)", func() {
+			inst := encodeREV(0, 1, false)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x01234567)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x67452301)))
+		})
+	})
+
+	Describe("REV16 - Reverse Bytes in Each Halfword", func() {
+		It("should swap bytes within each 16-bit halfword (64-bit)", func() {
+			inst := encodeREV16(0, 1, true)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x0123456789ABCDEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x23016745AB89EFCD)))
+		})
+
+		It("should swap bytes within each 16-bit halfword and zero-extend (32-bit)", func() {
+			inst := encodeREV16(0, 1, false)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x01234567)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x23016745)))
+		})
+	})
+
+	Describe("REV32 - Reverse Bytes in Each Word", func() {
+		It("should swap bytes within each 32-bit word of a 64-bit register", func() {
+			inst := encodeREV32(0, 1)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x0123456789ABCDEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x67452301EFCDAB89)))
+		})
+	})
+
+	Describe("CRC32/CRC32C Checksum Instructions", func() {
+		It("should compute CRC32B over a byte", func() {
+			inst := encodeCRC32B(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0xAB)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x41047a60)))
+		})
+
+		It("should compute CRC32H over a halfword", func() {
+			inst := encodeCRC32H(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0xBEEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xf53f71a8)))
+		})
+
+		It("should compute CRC32W over a word, folding in a running CRC", func() {
+			inst := encodeCRC32W(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0x12345678)
+			e.RegFile().WriteReg(2, 0xDEADBEEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xb537e7cd)))
+		})
+
+		It("should compute CRC32X over a doubleword", func() {
+			inst := encodeCRC32X(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0x0123456789ABCDEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x21193d2e)))
+		})
+
+		It("should compute CRC32CB using the Castagnoli polynomial", func() {
+			inst := encodeCRC32CB(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0xAB)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x3bc21e9d)))
+		})
+
+		It("should compute CRC32CH using the Castagnoli polynomial", func() {
+			inst := encodeCRC32CH(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0xBEEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x824b18ec)))
+		})
+
+		It("should compute CRC32CW using the Castagnoli polynomial", func() {
+			inst := encodeCRC32CW(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0xDEADBEEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0x09991d14)))
+		})
+
+		It("should compute CRC32CX using the Castagnoli polynomial", func() {
+			inst := encodeCRC32CX(0, 1, 2)
+			program := dataProcProgram(inst)
+
+			e.RegFile().WriteReg(1, 0)
+			e.RegFile().WriteReg(2, 0x0123456789ABCDEF)
+			e.LoadProgram(0x1000, program)
+
+			result := e.Step()
+
+			Expect(result.Err).To(BeNil())
+			Expect(e.RegFile().ReadReg(0)).To(Equal(uint64(0xe9986aa9)))
+		})
+	})
+
 	Describe("Variable Shift Instructions", func() {
 		Describe("LSLV - Logical Shift Left Variable", func() {
 			It("should shift left by register amount (64-bit)", func() {
@@ -0,0 +1,59 @@
+package decode
+
+// condCommonFields are the fields every instruction in the conditional
+// compare and conditional select block shares: the sf (32/64-bit) bit,
+// the second source register, and the 4-bit condition.
+var condCommonFields = []FieldSpec{
+	{Name: "sf", Hi: 31, Lo: 31},
+	{Name: "Rm", Hi: 20, Lo: 16},
+	{Name: "cond", Hi: 15, Lo: 12},
+}
+
+// ccmpFields extends condCommonFields with the conditional-compare-only
+// fields: the immediate-form selector and the literal nzcv operand used
+// when the condition is false.
+var ccmpFields = append(append([]FieldSpec{}, condCommonFields...),
+	FieldSpec{Name: "immForm", Hi: 11, Lo: 11},
+	FieldSpec{Name: "Rn", Hi: 9, Lo: 5},
+	FieldSpec{Name: "nzcv", Hi: 3, Lo: 0},
+)
+
+// cselFields extends condCommonFields with the conditional-select-only
+// fields: the two GPR operands.
+var cselFields = append(append([]FieldSpec{}, condCommonFields...),
+	FieldSpec{Name: "Rn", Hi: 9, Lo: 5},
+	FieldSpec{Name: "Rd", Hi: 4, Lo: 0},
+)
+
+// condCmpMask fixes op(30)=1, S(29)=1, bits 28:21 = 11010010 (the
+// conditional-compare top-level encoding), and o2(10)=0. Bit 30 (op)
+// distinguishes CCMP (1, subtract) from CCMN (0, add).
+const condCmpMask uint32 = 1<<30 | 1<<29 | 0xFF<<21 | 1<<10
+const condCmpBase uint32 = 1<<29 | 0b11010010<<21
+
+// condSelMask fixes S(29)=0, bits 28:21 = 11010100 (the conditional-select
+// top-level encoding), and op2 (11:10). Bit 30 (op) and op2 together
+// distinguish CSEL/CSINC/CSINV/CSNEG.
+const condSelMask uint32 = 1<<30 | 1<<29 | 0xFF<<21 | 0b11<<10
+const condSelBase uint32 = 0b11010100 << 21
+
+// BuildCondBlockTable compiles the conditional-compare (CCMP/CCMN) and
+// conditional-select (CSEL/CSINC/CSINV/CSNEG) instruction entries — the
+// "11010xxx" block this table covers — into a Table for disasm and
+// emu/fuzz to decode against; see the package doc for why it doesn't
+// replace insts.Decoder's dispatch. See decode_test.go for the overlap
+// check that is this table's main payoff as the block grows (CCMP
+// already has ccmp/ccmn, CSEL already has four siblings, and a ladder of
+// hand-written masks is exactly how two of those end up ambiguous by
+// accident).
+func BuildCondBlockTable() *Table {
+	return NewTable([]Entry{
+		{Mask: condCmpMask, Match: condCmpBase | 1<<30, Name: "CCMP", Fields: ccmpFields},
+		{Mask: condCmpMask, Match: condCmpBase, Name: "CCMN", Fields: ccmpFields},
+
+		{Mask: condSelMask, Match: condSelBase, Name: "CSEL", Fields: cselFields},
+		{Mask: condSelMask, Match: condSelBase | 0b01<<10, Name: "CSINC", Fields: cselFields},
+		{Mask: condSelMask, Match: condSelBase | 1<<30, Name: "CSINV", Fields: cselFields},
+		{Mask: condSelMask, Match: condSelBase | 1<<30 | 0b01<<10, Name: "CSNEG", Fields: cselFields},
+	})
+}
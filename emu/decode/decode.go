@@ -0,0 +1,113 @@
+// Package decode provides a declarative alternative to hand-rolled
+// bit-mask matching for decoding fixed-width ARM64 instruction words, for
+// callers that want a word's mnemonic and fields without running the full
+// emulator dispatch — currently disasm and emu/fuzz.
+//
+// Each instruction is described once as an Entry (a mask/match pair plus
+// the FieldSpecs a caller needs), rather than as a growing ladder of
+// "if word&mask == match" checks scattered across the decoder. A Table
+// compiles a set of Entries into a two-level trie keyed on bits 25:21 —
+// the slice of ARM's top-level C4.1 encoding-tree grouping that happens
+// to already distinguish every instruction block this package currently
+// covers — so Decode only has to linear-scan the handful of entries that
+// share a word's top-level group instead of the whole table.
+//
+// This package decodes; it does not dispatch. Entry has no handler of
+// its own, and Table.Decode returns a DecodedInst (name plus fields) for
+// the caller to interpret however it needs — emu/emulator.go's real
+// execute/resolveHandler switches are unrelated and keep decoding through
+// insts.Decoder, since wiring this table into that live path would mean
+// rebuilding every instruction handler around DecodedInst's untyped
+// Fields map instead of insts.Instruction's typed fields, which is a
+// larger change than this package takes on.
+package decode
+
+// FieldSpec names a bit range [Hi:Lo] (inclusive, Hi >= Lo) to extract
+// from a decoded instruction word into DecodedInst.Fields.
+type FieldSpec struct {
+	Name   string
+	Hi, Lo uint8
+}
+
+// extract pulls this field's bits out of word.
+func (f FieldSpec) extract(word uint32) uint32 {
+	width := uint32(f.Hi) - uint32(f.Lo) + 1
+	mask := uint32(1)<<width - 1
+	return (word >> f.Lo) & mask
+}
+
+// Entry describes one instruction's encoding: a word matches it when
+// word&Mask == Match. Fields lists what a handler for this instruction
+// needs extracted; Name identifies the instruction (e.g. "CCMP").
+type Entry struct {
+	Mask, Match uint32
+	Name        string
+	Fields      []FieldSpec
+}
+
+func (e Entry) matches(word uint32) bool {
+	return word&e.Mask == e.Match
+}
+
+// Overlaps reports whether some instruction word could match both a and
+// b — i.e. whether their mask/match pairs are ambiguous. Two entries
+// overlap exactly when, on every bit both masks constrain, their match
+// bits agree.
+func Overlaps(a, b Entry) bool {
+	return (a.Match^b.Match)&a.Mask&b.Mask == 0
+}
+
+// DecodedInst is the result of a successful Decode: the matched
+// instruction's name and its requested fields, keyed by FieldSpec.Name.
+type DecodedInst struct {
+	Name   string
+	Fields map[string]uint32
+}
+
+// top5Mask isolates bits 25:21, the trie key.
+const top5Mask uint32 = 0x3E00000 // ((1<<5)-1) << 21
+
+// Table is a compiled set of Entries, ready for repeated Decode calls.
+type Table struct {
+	entries []Entry
+	trie    [32][]Entry
+}
+
+// NewTable compiles entries into a Table. Entries are tried in the order
+// given within whichever trie bucket(s) they fall into, so if two entries
+// were to overlap (see Overlaps) the earlier one wins; BuildDefaultTable's
+// entries are checked never to overlap by decode_test.go.
+func NewTable(entries []Entry) *Table {
+	t := &Table{entries: entries}
+	for _, e := range entries {
+		relevant := e.Mask & top5Mask
+		for key := uint32(0); key < 32; key++ {
+			if key<<21&relevant == e.Match&relevant {
+				t.trie[key] = append(t.trie[key], e)
+			}
+		}
+	}
+	return t
+}
+
+// Entries returns the entries this Table was built from.
+func (t *Table) Entries() []Entry {
+	return t.entries
+}
+
+// Decode matches word against the table, returning the first Entry whose
+// mask/match pair matches and the fields it asked for, or ok=false if no
+// entry matches.
+func (t *Table) Decode(word uint32) (inst DecodedInst, ok bool) {
+	key := (word & top5Mask) >> 21
+	for _, e := range t.trie[key] {
+		if e.matches(word) {
+			fields := make(map[string]uint32, len(e.Fields))
+			for _, f := range e.Fields {
+				fields[f.Name] = f.extract(word)
+			}
+			return DecodedInst{Name: e.Name, Fields: fields}, true
+		}
+	}
+	return DecodedInst{}, false
+}
@@ -0,0 +1,146 @@
+package decode_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu/decode"
+)
+
+// encodeCCMP mirrors emu's encodeCCMP test helper, kept local since test
+// helpers are unexported.
+func encodeCCMP(rn, rm uint8, cond uint8, nzcv uint8, is64Bit, isImm bool) uint32 {
+	var sf, immBit uint32
+	if is64Bit {
+		sf = 1
+	}
+	if isImm {
+		immBit = 1
+	}
+	return (sf << 31) | (1 << 30) | (1 << 29) | (0b11010010 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (immBit << 11) |
+		(uint32(rn) << 5) | uint32(nzcv)
+}
+
+func encodeCCMN(rn, rm uint8, cond uint8, nzcv uint8, is64Bit, isImm bool) uint32 {
+	var sf, immBit uint32
+	if is64Bit {
+		sf = 1
+	}
+	if isImm {
+		immBit = 1
+	}
+	return (sf << 31) | (0 << 30) | (1 << 29) | (0b11010010 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (immBit << 11) |
+		(uint32(rn) << 5) | uint32(nzcv)
+}
+
+func encodeCSEL(rd, rn, rm uint8, cond uint8, is64Bit bool) uint32 {
+	var sf uint32
+	if is64Bit {
+		sf = 1
+	}
+	return (sf << 31) | (0b11010100 << 21) | (uint32(rm) << 16) |
+		(uint32(cond) << 12) | (uint32(rn) << 5) | uint32(rd)
+}
+
+func encodeCSINC(rd, rn, rm uint8, cond uint8, is64Bit bool) uint32 {
+	return encodeCSEL(rd, rn, rm, cond, is64Bit) | (0b01 << 10)
+}
+
+func encodeCSINV(rd, rn, rm uint8, cond uint8, is64Bit bool) uint32 {
+	return encodeCSEL(rd, rn, rm, cond, is64Bit) | (1 << 30)
+}
+
+func encodeCSNEG(rd, rn, rm uint8, cond uint8, is64Bit bool) uint32 {
+	return encodeCSEL(rd, rn, rm, cond, is64Bit) | (1 << 30) | (0b01 << 10)
+}
+
+var _ = Describe("Conditional-block decode table", func() {
+	table := decode.BuildCondBlockTable()
+
+	Describe("CCMP/CCMN", func() {
+		It("should decode a 64-bit register-form CCMP", func() {
+			word := encodeCCMP(1, 2, 0b0001, 0b1010, true, false)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CCMP"))
+			Expect(got.Fields["sf"]).To(Equal(uint32(1)))
+			Expect(got.Fields["Rn"]).To(Equal(uint32(1)))
+			Expect(got.Fields["Rm"]).To(Equal(uint32(2)))
+			Expect(got.Fields["cond"]).To(Equal(uint32(0b0001)))
+			Expect(got.Fields["immForm"]).To(Equal(uint32(0)))
+			Expect(got.Fields["nzcv"]).To(Equal(uint32(0b1010)))
+		})
+
+		It("should decode a 32-bit immediate-form CCMN", func() {
+			word := encodeCCMN(3, 5, 0b1110, 0b0101, false, true)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CCMN"))
+			Expect(got.Fields["sf"]).To(Equal(uint32(0)))
+			Expect(got.Fields["immForm"]).To(Equal(uint32(1)))
+			Expect(got.Fields["nzcv"]).To(Equal(uint32(0b0101)))
+		})
+	})
+
+	Describe("CSEL/CSINC/CSINV/CSNEG", func() {
+		It("should decode CSEL", func() {
+			word := encodeCSEL(0, 1, 2, 0b0000, true)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CSEL"))
+			Expect(got.Fields["Rd"]).To(Equal(uint32(0)))
+			Expect(got.Fields["Rn"]).To(Equal(uint32(1)))
+			Expect(got.Fields["Rm"]).To(Equal(uint32(2)))
+		})
+
+		It("should decode CSINC", func() {
+			word := encodeCSINC(3, 4, 5, 0b0001, false)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CSINC"))
+		})
+
+		It("should decode CSINV", func() {
+			word := encodeCSINV(3, 4, 5, 0b0001, true)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CSINV"))
+		})
+
+		It("should decode CSNEG", func() {
+			word := encodeCSNEG(3, 4, 5, 0b0001, true)
+
+			got, ok := table.Decode(word)
+
+			Expect(ok).To(BeTrue())
+			Expect(got.Name).To(Equal("CSNEG"))
+		})
+	})
+
+	It("should report no match for a word outside the conditional block", func() {
+		_, ok := table.Decode(0x00000000)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should have no two entries in the block whose mask/match overlap", func() {
+		entries := table.Entries()
+		for i := range entries {
+			for j := i + 1; j < len(entries); j++ {
+				Expect(decode.Overlaps(entries[i], entries[j])).To(BeFalse(),
+					"%s and %s overlap", entries[i].Name, entries[j].Name)
+			}
+		}
+	})
+})
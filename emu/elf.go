@@ -0,0 +1,200 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Default layout constants for the process stack LoadELF builds. These
+// mirror driver.LoadELF's layout; the two loaders are kept independent
+// (emu cannot import driver, which already imports emu) rather than
+// sharing code, since LoadELF exists precisely so a caller that only has
+// the emu package can run a real binary without reaching for driver.
+const (
+	elfDefaultSP   = 0x7FFF_0000_0000
+	elfStackSize   = 8 * 1024 * 1024
+	elfStackBottom = elfDefaultSP - elfStackSize
+	elfPhentSize   = 56 // sizeof(Elf64_Phdr)
+)
+
+// Auxv types LoadELF populates, matching the subset driver.LoadELF writes.
+const (
+	atNull   = 0
+	atPhdr   = 3
+	atPhent  = 4
+	atPhnum  = 5
+	atPagesz = 6
+	atEntry  = 9
+	atUID    = 11
+	atEUID   = 12
+	atGID    = 13
+	atEGID   = 14
+	atSecure = 23
+	atRandom = 25
+	atExecfn = 31
+)
+
+// LoadELF parses a statically-linked ELF64 AArch64 executable from r,
+// mapping its PT_LOAD segments into the emulator's memory with their
+// p_flags permissions, zero-filling each segment's BSS tail, building a
+// minimal argv/envp/auxv stack image per the AArch64 System V ABI, and
+// setting PC to the entry point. It is a companion to LoadProgram for
+// callers that have a real cross-compiled binary (e.g. a Go or Clang test
+// executable) rather than a bare instruction stream.
+//
+// Only ET_EXEC and ET_DYN (loaded at a fixed zero bias) AArch64 binaries
+// with no PT_INTERP and no .dynamic section are supported: LoadELF does
+// not perform any relocation or symbol resolution, so a binary requiring
+// either is rejected with a clear error rather than silently producing
+// wrong code.
+func (e *Emulator) LoadELF(r io.ReaderAt) (entry uint64, err error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return 0, fmt.Errorf("emu: parse ELF: %w", err)
+	}
+	defer f.Close()
+
+	if f.Class != elf.ELFCLASS64 || f.Machine != elf.EM_AARCH64 {
+		return 0, fmt.Errorf("emu: unsupported ELF (class=%v machine=%v), want ELF64 AArch64", f.Class, f.Machine)
+	}
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return 0, fmt.Errorf("emu: unsupported ELF type %v, want ET_EXEC or ET_DYN", f.Type)
+	}
+	if f.Section(".dynamic") != nil {
+		return 0, fmt.Errorf("emu: dynamically-linked ELF not supported (no relocation support)")
+	}
+
+	var phdrOff uint64
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_INTERP {
+			return 0, fmt.Errorf("emu: ELF requires an interpreter, not supported")
+		}
+		if prog.Type == elf.PT_LOAD && prog.Off == 0 {
+			phdrOff = prog.Vaddr
+		}
+	}
+
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		e.memory.MapAnonymous(prog.Vaddr, prog.Memsz, PROT_READ|PROT_WRITE)
+
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return 0, fmt.Errorf("emu: read PT_LOAD segment: %w", err)
+		}
+		for i, b := range data {
+			e.memory.Write8(prog.Vaddr+uint64(i), b)
+		}
+		// BSS tail (Memsz > Filesz) is left zeroed by the anonymous mapping.
+
+		e.memory.Mprotect(prog.Vaddr, prog.Memsz, progFlagsToProt(prog.Flags))
+	}
+
+	phdrAddr := phdrOff + elf64PhOff(r)
+	sp := e.buildELFStack(f, phdrAddr)
+
+	e.icache.InvalidateAll()
+	e.regFile.PC = f.Entry
+	e.regFile.SP = sp
+
+	return f.Entry, nil
+}
+
+// elf64PhOff reads e_phoff directly from the raw ELF64 header (offset
+// 0x20, 8 bytes, little-endian on AArch64) since debug/elf does not
+// expose it.
+func elf64PhOff(r io.ReaderAt) uint64 {
+	var buf [8]byte
+	if _, err := r.ReadAt(buf[:], 0x20); err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// progFlagsToProt converts ELF program header flags to emu.PROT_* bits.
+func progFlagsToProt(flags elf.ProgFlag) int {
+	prot := 0
+	if flags&elf.PF_R != 0 {
+		prot |= PROT_READ
+	}
+	if flags&elf.PF_W != 0 {
+		prot |= PROT_WRITE
+	}
+	if flags&elf.PF_X != 0 {
+		prot |= PROT_EXEC
+	}
+	return prot
+}
+
+// buildELFStack maps the process stack and writes argc, argv, envp, and
+// auxv at the top of it, matching the layout the ARM64 Linux kernel hands
+// to _start. LoadELF has no argv/envp parameters of its own, so it passes
+// a single conventional program name and an empty environment; a caller
+// that needs real arguments should use driver.LoadELF instead.
+func (e *Emulator) buildELFStack(f *elf.File, phdrAddr uint64) uint64 {
+	argv := []string{"a.out"}
+
+	e.memory.MapAnonymous(elfStackBottom, elfStackSize, PROT_READ|PROT_WRITE)
+
+	sp := uint64(elfDefaultSP)
+	writeStr := func(s string) uint64 {
+		b := append([]byte(s), 0)
+		sp -= uint64(len(b))
+		sp &= ^uint64(7) // keep 8-byte alignment for subsequent pointers
+		for i, c := range b {
+			e.memory.Write8(sp+uint64(i), c)
+		}
+		return sp
+	}
+
+	execfnPtr := writeStr(argv[0])
+	randomPtr := sp - 16
+	for i := uint64(0); i < 16; i++ {
+		e.memory.Write8(randomPtr+i, byte(i*7+1)) // deterministic, not CSPRNG
+	}
+	sp = randomPtr
+
+	argvPtrs := make([]uint64, len(argv))
+	for i, s := range argv {
+		argvPtrs[i] = writeStr(s)
+	}
+
+	sp &= ^uint64(15) // 16-byte align before the pointer tables
+
+	auxv := []uint64{
+		atPagesz, PageSize,
+		atPhdr, phdrAddr,
+		atPhent, elfPhentSize,
+		atPhnum, uint64(len(f.Progs)),
+		atEntry, f.Entry,
+		atUID, 0,
+		atEUID, 0,
+		atGID, 0,
+		atEGID, 0,
+		atSecure, 0,
+		atRandom, randomPtr,
+		atExecfn, execfnPtr,
+		atNull, 0,
+	}
+
+	words := make([]uint64, 0, 1+len(argvPtrs)+1+1+len(auxv))
+	words = append(words, uint64(len(argvPtrs)))
+	words = append(words, argvPtrs...)
+	words = append(words, 0) // argv NULL terminator
+	words = append(words, 0) // empty envp, just its NULL terminator
+	words = append(words, auxv...)
+
+	sp -= uint64(len(words)) * 8
+	sp &= ^uint64(15)
+	for i, w := range words {
+		e.memory.Write64(sp+uint64(i)*8, w)
+	}
+
+	return sp
+}
@@ -0,0 +1,106 @@
+package emu_test
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// buildMinimalELF assembles the smallest valid ELF64 AArch64 executable
+// that debug/elf can parse: a file header, one PT_LOAD program header
+// covering the whole image, and a handful of code bytes.
+func buildMinimalELF(code []byte, vaddr uint64) []byte {
+	const ehsize = 64
+	const phentsize = 56
+	phoff := uint64(ehsize)
+	entry := vaddr + phoff + phentsize
+
+	buf := make([]byte, int(phoff)+phentsize+len(code))
+
+	copy(buf[0:4], []byte{0x7F, 'E', 'L', 'F'})
+	buf[4] = 2 // ELFCLASS64
+	buf[5] = 1 // little-endian
+	buf[6] = 1 // EV_CURRENT
+
+	le := binary.LittleEndian
+	le.PutUint16(buf[16:18], 2)         // e_type: ET_EXEC
+	le.PutUint16(buf[18:20], 183)       // e_machine: EM_AARCH64
+	le.PutUint32(buf[20:24], 1)         // e_version
+	le.PutUint64(buf[24:32], entry)     // e_entry
+	le.PutUint64(buf[32:40], phoff)     // e_phoff
+	le.PutUint64(buf[40:48], 0)         // e_shoff
+	le.PutUint32(buf[48:52], 0)         // e_flags
+	le.PutUint16(buf[52:54], ehsize)    // e_ehsize
+	le.PutUint16(buf[54:56], phentsize) // e_phentsize
+	le.PutUint16(buf[56:58], 1)         // e_phnum
+	le.PutUint16(buf[58:60], 0)         // e_shentsize
+	le.PutUint16(buf[60:62], 0)         // e_shnum
+	le.PutUint16(buf[62:64], 0)         // e_shstrndx
+
+	ph := buf[phoff : phoff+phentsize]
+	le.PutUint32(ph[0:4], 1)                      // p_type: PT_LOAD
+	le.PutUint32(ph[4:8], 5)                      // p_flags: R+X
+	le.PutUint64(ph[8:16], 0)                     // p_offset
+	le.PutUint64(ph[16:24], vaddr)                // p_vaddr
+	le.PutUint64(ph[24:32], vaddr)                // p_paddr
+	le.PutUint64(ph[32:40], uint64(len(buf)))     // p_filesz
+	le.PutUint64(ph[40:48], uint64(len(buf)))     // p_memsz
+	le.PutUint64(ph[48:56], uint64(emu.PageSize)) // p_align
+
+	copy(buf[int(phoff)+phentsize:], code)
+
+	return buf
+}
+
+var _ = Describe("Emulator.LoadELF", func() {
+	const vaddr = 0x400000
+
+	var e *emu.Emulator
+
+	BeforeEach(func() {
+		e = emu.NewEmulator()
+	})
+
+	It("should map PT_LOAD segments and set PC to the entry point", func() {
+		raw := buildMinimalELF([]byte{0xDE, 0xAD, 0xBE, 0xEF}, vaddr)
+
+		entry, err := e.LoadELF(bytes.NewReader(raw))
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entry).To(Equal(uint64(vaddr + 64 + 56)))
+		Expect(e.RegFile().PC).To(Equal(entry))
+
+		word, fault := e.Memory().FetchInst(entry)
+		Expect(fault).To(BeNil())
+		Expect(word).To(Equal(uint32(0xEFBEADDE)))
+	})
+
+	It("should build an initial stack with argc/argv/auxv", func() {
+		raw := buildMinimalELF([]byte{0, 0, 0, 0}, vaddr)
+
+		_, err := e.LoadELF(bytes.NewReader(raw))
+		Expect(err).ToNot(HaveOccurred())
+
+		argc := e.Memory().Read64(e.RegFile().SP)
+		Expect(argc).To(Equal(uint64(1))) // LoadELF always passes a single argv[0]
+	})
+
+	It("should reject non-AArch64 input", func() {
+		raw := buildMinimalELF([]byte{0, 0, 0, 0}, vaddr)
+		raw[18] = 0x3E // e_machine: EM_X86_64
+
+		_, err := e.LoadELF(bytes.NewReader(raw))
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject non-ELF input", func() {
+		_, err := e.LoadELF(bytes.NewReader([]byte("not an elf")))
+
+		Expect(err).To(HaveOccurred())
+	})
+})
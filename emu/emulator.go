@@ -2,10 +2,14 @@
 package emu
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"os"
 
+	"golang.org/x/arch/arm64/arm64asm"
+
 	"github.com/sarchlab/m2sim/insts"
 )
 
@@ -19,19 +23,120 @@ type StepResult struct {
 
 	// Err is set if an error occurred during execution.
 	Err error
+
+	// Stopped is true if a StepHook requested the run loop halt before the
+	// instruction at PC was executed (e.g. a debugger breakpoint).
+	Stopped bool
+
+	// Disassembly is the human-readable form of the instruction Step just
+	// executed (e.g. "udiv x0, x1, x2"), populated only when a tracer is
+	// attached via SetTracer.
+	Disassembly string
+
+	// ExceptionVector reports which architecturally-defined edge case
+	// trapped execution, when the emulator's FaultPolicy is FaultTrap (or
+	// FaultCallback and the handler returned FaultActionTrap). PC is left
+	// pointing at the faulting instruction so a host can inspect machine
+	// state before resuming. Zero (ExceptionNone) otherwise.
+	ExceptionVector ExceptionVector
+}
+
+// ExceptionVector identifies the architecturally-defined condition an
+// EmuFault reports. ARMv8 hardware resolves all of these silently; a
+// FaultPolicy other than FaultSilent gives a host tool visibility into
+// them instead.
+type ExceptionVector int
+
+const (
+	// ExceptionNone indicates no fault occurred.
+	ExceptionNone ExceptionVector = iota
+	// ExceptionDivideByZero indicates a UDIV or SDIV with a zero divisor.
+	// ARMv8 silently yields 0.
+	ExceptionDivideByZero
+	// ExceptionDivideOverflow indicates an SDIV computing INT_MIN / -1,
+	// the one signed-division input that overflows. ARMv8 silently yields
+	// INT_MIN.
+	ExceptionDivideOverflow
+	// ExceptionUnalignedAccess is reserved for unaligned loads and stores.
+	// LoadStoreUnit does not yet check alignment, so this is never
+	// reported today.
+	ExceptionUnalignedAccess
+)
+
+// EmuFault describes one occurrence of an architecturally-defined edge
+// case, reported to a FaultCallback handler and, in FaultTrap mode,
+// mirrored into StepResult.ExceptionVector.
+type EmuFault struct {
+	Vector ExceptionVector
+	PC     uint64
+	Rn, Rm uint64 // operand values at the time of the fault
 }
 
+// FaultPolicy selects how the emulator responds to an EmuFault.
+type FaultPolicy int
+
+const (
+	// FaultSilent reproduces ARMv8 hardware behavior: the faulting
+	// instruction still executes and produces its architecturally-defined
+	// silent result (0 for divide-by-zero, INT_MIN for SDIV overflow).
+	// This is the default.
+	FaultSilent FaultPolicy = iota
+	// FaultTrap stops Step immediately on every EmuFault, without
+	// executing the faulting instruction: StepResult.ExceptionVector is
+	// set and PC is left unincremented.
+	FaultTrap
+	// FaultCallback consults the handler installed via SetFaultHandler for
+	// every EmuFault and acts on the FaultAction it returns. With no
+	// handler installed, it behaves like FaultSilent.
+	FaultCallback
+)
+
+// FaultAction tells the emulator how to proceed after a FaultCallback
+// handler has inspected an EmuFault.
+type FaultAction int
+
+const (
+	// FaultActionSilent executes the faulting instruction and produces its
+	// architecturally-defined silent result, the same as FaultSilent.
+	FaultActionSilent FaultAction = iota
+	// FaultActionTrap stops Step immediately, the same as FaultTrap.
+	FaultActionTrap
+)
+
+// StepAction tells the emulator what to do in response to a StepHook
+// callback, ahead of executing the instruction at the reported PC.
+type StepAction int
+
+const (
+	// StepContinue executes the instruction normally.
+	StepContinue StepAction = iota
+	// StepStop halts the run loop without executing the instruction,
+	// leaving PC pointing at it.
+	StepStop
+	// StepTrap behaves like StepStop but marks the halt as a trap, for
+	// hooks that want to distinguish a breakpoint from a plain pause.
+	StepTrap
+)
+
 // Emulator executes ARM64 instructions functionally.
 type Emulator struct {
-	regFile        *RegFile
-	memory         *Memory
-	decoder        *insts.Decoder
-	syscallHandler SyscallHandler
+	regFile *RegFile
+	memory  *Memory
+
+	// bus is what lsu and fpu actually talk to: RAM (memory) by default,
+	// plus whatever Devices have been mapped onto it via Bus.Map. Fetch and
+	// syscalls bypass it and go straight to memory, since devices never
+	// hold executable code and syscalls operate on conventional RAM.
+	bus             *Bus
+	decoder         *insts.Decoder
+	syscallHandler  SyscallHandler
+	pseudoOpHandler PseudoOpHandler
 
 	// Execution units
 	alu        *ALU
 	lsu        *LoadStoreUnit
 	branchUnit *BranchUnit
+	fpu        *FPU
 
 	// I/O
 	stdout io.Writer
@@ -40,6 +145,232 @@ type Emulator struct {
 	// Execution state
 	instructionCount uint64
 	maxInstructions  uint64 // 0 means no limit
+
+	// icache backs RunFast; the step-by-step Step/Run path never touches it.
+	icache *ICache
+
+	// jitCache backs RunJIT, caching the compiled machine code for the
+	// straight-line ADD/SUB runs it folds into native calls. Lazily
+	// initialized on first use, so an Emulator that never calls RunJIT
+	// never allocates it.
+	jitCache *jitCache
+
+	// stepHook, if set, is consulted before each instruction is executed
+	// by Step (and therefore Run). It is the hook point debug.Server uses
+	// to implement software breakpoints and single-stepping.
+	stepHook func(pc uint64) StepAction
+
+	// traceSink, if set, receives every architecturally-visible event as
+	// Step executes. It is the hook point package trace's Recorder attaches
+	// through.
+	traceSink TraceSink
+
+	// tracer, if set, receives a disassembled line for every instruction
+	// Step executes, and causes StepResult.Disassembly to be populated.
+	// Unlike traceSink this is plain human-readable output, not a
+	// structured event stream meant for replay.
+	tracer io.Writer
+
+	// instTrace, if set, receives a full before/after register and PSTATE
+	// snapshot for every instruction Step executes. Unlike traceSink, which
+	// reports individual register/memory writes as they happen for
+	// replay, instTrace reports one self-contained record per instruction,
+	// which is what makes flag-mutating instructions like CCMP/CCMN
+	// debuggable: a miscompare against real hardware shows up as a single
+	// record with the wrong pstate, rather than having to be reconstructed
+	// from a stream of RecordRegWrite calls.
+	instTrace InstructionTraceSink
+
+	// faultPolicy selects how divide-by-zero, SDIV overflow, and (in the
+	// future) unaligned accesses are reported. Defaults to FaultSilent.
+	faultPolicy FaultPolicy
+
+	// faultHandler is consulted for every EmuFault when faultPolicy is
+	// FaultCallback.
+	faultHandler func(EmuFault) FaultAction
+}
+
+// TraceSink receives architecturally-visible events as Step executes, so
+// a trace recorder can log them without this package depending on the
+// trace package. Register writes and retired PCs are reported by Step
+// itself; memory writes arrive via the Memory write observer Step wires up
+// in SetTraceSink.
+type TraceSink interface {
+	// RecordRegWrite reports that GPR reg (0-30) or SP (31) changed from
+	// old to newVal.
+	RecordRegWrite(reg uint8, old, newVal uint64)
+
+	// RecordMemWrite reports that the byte at addr changed from old to
+	// newVal.
+	RecordMemWrite(addr uint64, old, newVal byte)
+
+	// RecordSyscall reports a completed syscall's number, X0-X5 arguments,
+	// and result.
+	RecordSyscall(num uint64, args [6]uint64, exited bool, exitCode int64)
+
+	// RecordRetire reports that the instruction at pc finished executing.
+	RecordRetire(pc uint64)
+}
+
+// SetTraceSink attaches sink to receive every event Step produces (and,
+// transitively, memory writes via the Memory write observer). Pass nil to
+// detach. RunFast does not consult the sink, matching its relationship to
+// stepHook: tracing is a step-by-step concern.
+func (e *Emulator) SetTraceSink(sink TraceSink) {
+	e.traceSink = sink
+	if sink == nil {
+		e.memory.SetWriteObserver(nil)
+		return
+	}
+	e.memory.SetWriteObserver(func(addr uint64, old, newVal byte) {
+		sink.RecordMemWrite(addr, old, newVal)
+	})
+}
+
+// RegSnapshot captures every GPR and SP at a point in time, for diffing
+// across a Step (traceSink) or reporting before/after a single instruction
+// (InstructionTraceSink). PC is reported separately by both mechanisms.
+type RegSnapshot [32]uint64 // [0..30] = x0-x30, [31] = sp
+
+func (e *Emulator) snapshotGPR() RegSnapshot {
+	var s RegSnapshot
+	for i := 0; i < 31; i++ {
+		s[i] = e.regFile.ReadReg(uint8(i))
+	}
+	s[31] = e.regFile.SP
+	return s
+}
+
+// diffAndRecordRegs reports every register that differs between before
+// and the register file's current state.
+func (e *Emulator) diffAndRecordRegs(before RegSnapshot) {
+	after := e.snapshotGPR()
+	for i := range after {
+		if before[i] != after[i] {
+			e.traceSink.RecordRegWrite(uint8(i), before[i], after[i])
+		}
+	}
+}
+
+// InstructionTraceSink receives a full record of every instruction Step
+// executes: its address and raw encoding, a disassembled mnemonic, the
+// register file immediately before and after, and the resulting PSTATE.
+// Attach one with SetInstructionTracer.
+type InstructionTraceSink interface {
+	OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter RegSnapshot, pstate PSTATE)
+}
+
+// SetInstructionTracer attaches sink to receive an OnInstruction record for
+// every instruction Step executes. Pass nil to detach. RunFast does not
+// consult it, matching tracer and traceSink's relationship to the fast
+// interpreter loop.
+func (e *Emulator) SetInstructionTracer(sink InstructionTraceSink) {
+	e.instTrace = sink
+}
+
+// SetTracer attaches w to receive one human-readable disassembled line per
+// instruction Step executes, and causes StepResult.Disassembly to be
+// populated on every subsequent Step. It also attaches w to the load/store
+// unit, so every LDR*/STR* the instruction stream executes additionally
+// logs its effective address and the value read or written (see
+// LoadStoreUnit.SetTracer). Pass nil to detach both. RunFast does not
+// consult it, matching traceSink's relationship to the fast interpreter
+// loop.
+func (e *Emulator) SetTracer(w io.Writer) {
+	e.tracer = w
+	e.lsu.SetTracer(w)
+}
+
+// disassemble decodes the 4-byte instruction word fetched from pc into a
+// human-readable line via golang.org/x/arch/arm64/arm64asm, for SetTracer
+// output and StepResult.Disassembly.
+func disassemble(word uint32, pc uint64) string {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], word)
+
+	inst, err := arm64asm.Decode(buf[:])
+	if err != nil {
+		return fmt.Sprintf("(unknown instruction 0x%08X)", word)
+	}
+
+	return arm64asm.GoSyntax(inst, pc, nil, nil)
+}
+
+// SetStepHook installs fn to be called with the current PC immediately
+// before each instruction executes. Pass nil to remove a previously
+// installed hook. RunFast does not consult the hook; it is intended for
+// the step-by-step Step/Run path used by interactive debugging.
+func (e *Emulator) SetStepHook(fn func(pc uint64) StepAction) {
+	e.stepHook = fn
+}
+
+// SetPseudoOpHandler attaches handler to receive gem5-style M5 pseudo-ops
+// (see PseudoOpHandler in pseudoop.go). Pass nil to detach. Unlike
+// WithSyscallHandler, this can be called after NewEmulator, since callers
+// that implement PseudoOpHandler (e.g. benchmarks.ROIStats) typically need
+// a reference to the already-constructed Emulator themselves.
+func (e *Emulator) SetPseudoOpHandler(handler PseudoOpHandler) {
+	e.pseudoOpHandler = handler
+}
+
+// SetFaultHandler attaches fn to be consulted for every EmuFault when the
+// emulator's FaultPolicy is FaultCallback. Pass nil to detach; with no
+// handler attached, FaultCallback behaves like FaultSilent.
+func (e *Emulator) SetFaultHandler(fn func(EmuFault) FaultAction) {
+	e.faultHandler = fn
+}
+
+// handleFault applies the emulator's FaultPolicy to f, returning true if
+// Step should stop immediately with StepResult.ExceptionVector set to
+// f.Vector and PC left unincremented, instead of producing the
+// architecturally-defined silent result.
+func (e *Emulator) handleFault(f EmuFault) bool {
+	switch e.faultPolicy {
+	case FaultTrap:
+		return true
+	case FaultCallback:
+		if e.faultHandler == nil {
+			return false
+		}
+		return e.faultHandler(f) == FaultActionTrap
+	default:
+		return false
+	}
+}
+
+// checkDivideFault inspects a UDIV/SDIV's operands for divide-by-zero or
+// (SDIV only) the INT_MIN/-1 overflow, and consults handleFault. It
+// returns a non-nil EmuFault only when the active FaultPolicy decided the
+// condition should trap; the faulting instruction must not execute in
+// that case.
+func (e *Emulator) checkDivideFault(inst *insts.Instruction) *EmuFault {
+	rn := e.regFile.ReadReg(inst.Rn)
+	rm := e.regFile.ReadReg(inst.Rm)
+
+	var vector ExceptionVector
+	switch {
+	case rm == 0:
+		vector = ExceptionDivideByZero
+	case inst.Op == insts.OpSDIV && isSignedDivideOverflow(rn, rm, inst.Is64Bit):
+		vector = ExceptionDivideOverflow
+	default:
+		return nil
+	}
+
+	f := EmuFault{Vector: vector, PC: e.regFile.PC, Rn: rn, Rm: rm}
+	if !e.handleFault(f) {
+		return nil
+	}
+	return &f
+}
+
+// isSignedDivideOverflow reports whether rn/rm is the one signed-division
+// input that overflows: the most negative value divided by -1.
+func isSignedDivideOverflow(rn, rm uint64, is64Bit bool) bool {
+	if is64Bit {
+		return int64(rn) == math.MinInt64 && int64(rm) == -1
+	}
+	return int32(uint32(rn)) == math.MinInt32 && int32(uint32(rm)) == -1
 }
 
 // EmulatorOption is a functional option for configuring the Emulator.
@@ -66,6 +397,14 @@ func WithSyscallHandler(handler SyscallHandler) EmulatorOption {
 	}
 }
 
+// WithPseudoOpHandler sets a custom pseudo-op handler. See PseudoOpHandler
+// in pseudoop.go for the gem5-style HLT pseudo-ops this configures.
+func WithPseudoOpHandler(handler PseudoOpHandler) EmulatorOption {
+	return func(e *Emulator) {
+		e.pseudoOpHandler = handler
+	}
+}
+
 // WithStackPointer sets the initial stack pointer value.
 func WithStackPointer(sp uint64) EmulatorOption {
 	return func(e *Emulator) {
@@ -81,6 +420,15 @@ func WithMaxInstructions(max uint64) EmulatorOption {
 	}
 }
 
+// WithFaultPolicy sets how the emulator responds to divide-by-zero, SDIV
+// overflow, and (in the future) unaligned accesses. The default is
+// FaultSilent.
+func WithFaultPolicy(policy FaultPolicy) EmulatorOption {
+	return func(e *Emulator) {
+		e.faultPolicy = policy
+	}
+}
+
 // NewEmulator creates a new ARM64 emulator.
 func NewEmulator(opts ...EmulatorOption) *Emulator {
 	regFile := &RegFile{}
@@ -102,15 +450,20 @@ func NewEmulator(opts ...EmulatorOption) *Emulator {
 	}
 
 	// Create execution units
+	e.bus = NewBus(memory)
 	e.alu = NewALU(regFile)
-	e.lsu = NewLoadStoreUnit(regFile, memory)
+	e.lsu = NewLoadStoreUnit(regFile, e.bus)
 	e.branchUnit = NewBranchUnit(regFile)
+	e.fpu = NewFPU(regFile, e.bus)
 
 	// If no syscall handler was provided, create a default one
 	if e.syscallHandler == nil {
 		e.syscallHandler = NewDefaultSyscallHandler(regFile, memory, e.stdout, e.stderr)
 	}
 
+	e.icache = NewICache()
+	memory.SetExecWriteObserver(e.icache.Invalidate)
+
 	return e
 }
 
@@ -119,46 +472,76 @@ func (e *Emulator) RegFile() *RegFile {
 	return e.regFile
 }
 
-// Memory returns the emulator's memory.
+// Memory returns the emulator's RAM, the backing store for every address
+// no Device has claimed on Bus.
 func (e *Emulator) Memory() *Memory {
 	return e.memory
 }
 
+// Bus returns the emulator's memory-mapped I/O bus, so callers can attach
+// Devices with Bus.Map before running a bare-metal program.
+func (e *Emulator) Bus() *Bus {
+	return e.bus
+}
+
 // InstructionCount returns the number of instructions executed.
 func (e *Emulator) InstructionCount() uint64 {
 	return e.instructionCount
 }
 
-// LoadProgram loads a program into memory and sets the entry point.
-// The program can be either a []byte or a *Memory.
+// LoadProgram loads a program into memory and sets the entry point. The
+// program can be a []byte, a *Memory, or a *Bus — pass a *Bus with Devices
+// already mapped via Bus.Map to run a bare-metal program against MMIO
+// peripherals such as UART or Timer.
 func (e *Emulator) LoadProgram(entry uint64, program interface{}) {
 	switch p := program.(type) {
 	case []byte:
 		e.memory.LoadProgram(entry, p)
 	case *Memory:
-		// Use the provided memory directly
+		// Use the provided memory directly, behind a fresh Bus with no
+		// devices mapped.
 		e.memory = p
-		// Update execution units to use new memory
-		e.lsu = NewLoadStoreUnit(e.regFile, e.memory)
-		// Update syscall handler with new memory
-		e.syscallHandler = NewDefaultSyscallHandler(e.regFile, e.memory, e.stdout, e.stderr)
+		e.bus = NewBus(e.memory)
+		e.resetExecutionUnits()
+	case *Bus:
+		// Use the provided bus (and whatever Devices it already has
+		// mapped) directly.
+		e.bus = p
+		e.memory = p.RAM()
+		e.resetExecutionUnits()
 	}
+	e.icache.InvalidateAll()
 	e.regFile.PC = entry
 }
 
+// resetExecutionUnits rebuilds the units that hold a reference to e.memory
+// or e.bus, after LoadProgram swaps either one out from under them.
+func (e *Emulator) resetExecutionUnits() {
+	e.lsu = NewLoadStoreUnit(e.regFile, e.bus)
+	e.lsu.SetTracer(e.tracer)
+	e.fpu = NewFPU(e.regFile, e.bus)
+	e.syscallHandler = NewDefaultSyscallHandler(e.regFile, e.memory, e.stdout, e.stderr)
+	e.memory.SetExecWriteObserver(e.icache.Invalidate)
+}
+
 // Reset resets the emulator to its initial state.
 func (e *Emulator) Reset() {
 	e.regFile = &RegFile{}
 	e.memory = NewMemory()
+	e.bus = NewBus(e.memory)
 	e.instructionCount = 0
 
 	// Recreate execution units
 	e.alu = NewALU(e.regFile)
-	e.lsu = NewLoadStoreUnit(e.regFile, e.memory)
+	e.lsu = NewLoadStoreUnit(e.regFile, e.bus)
 	e.branchUnit = NewBranchUnit(e.regFile)
+	e.fpu = NewFPU(e.regFile, e.bus)
 
 	// Recreate syscall handler
 	e.syscallHandler = NewDefaultSyscallHandler(e.regFile, e.memory, e.stdout, e.stderr)
+
+	e.icache = NewICache()
+	e.memory.SetExecWriteObserver(e.icache.Invalidate)
 }
 
 // Step executes a single instruction.
@@ -171,8 +554,27 @@ func (e *Emulator) Step() StepResult {
 		}
 	}
 
-	// 1. Fetch: Read 4 bytes at PC
-	word := e.memory.Read32(e.regFile.PC)
+	if e.stepHook != nil {
+		switch e.stepHook(e.regFile.PC) {
+		case StepStop:
+			return StepResult{Stopped: true}
+		case StepTrap:
+			return StepResult{Stopped: true, Err: fmt.Errorf("trap at PC=0x%X", e.regFile.PC)}
+		}
+	}
+
+	pc := e.regFile.PC
+
+	var before RegSnapshot
+	if e.traceSink != nil || e.instTrace != nil {
+		before = e.snapshotGPR()
+	}
+
+	// 1. Fetch: Read 4 bytes at PC, enforcing the executable permission bit.
+	word, fault := e.memory.FetchInst(pc)
+	if fault != nil {
+		return StepResult{Err: fault}
+	}
 
 	// 2. Decode
 	inst := e.decoder.Decode(word)
@@ -183,6 +585,24 @@ func (e *Emulator) Step() StepResult {
 	// Increment instruction count
 	e.instructionCount++
 
+	if e.traceSink != nil {
+		e.diffAndRecordRegs(before)
+		e.traceSink.RecordRetire(pc)
+	}
+
+	if e.tracer != nil {
+		result.Disassembly = disassemble(word, pc)
+		fmt.Fprintf(e.tracer, "0x%08X: %s\n", pc, result.Disassembly)
+	}
+
+	if e.instTrace != nil {
+		mnemonic := result.Disassembly
+		if mnemonic == "" {
+			mnemonic = disassemble(word, pc)
+		}
+		e.instTrace.OnInstruction(pc, word, mnemonic, before, e.snapshotGPR(), e.regFile.PSTATE)
+	}
+
 	return result
 }
 
@@ -194,6 +614,10 @@ func (e *Emulator) Run() int64 {
 		if result.Exited {
 			return result.ExitCode
 		}
+		if result.Stopped {
+			// A StepHook halted the run (e.g. a debugger breakpoint).
+			return -1
+		}
 		if result.Err != nil {
 			// On error, treat as abnormal exit
 			return -1
@@ -201,8 +625,152 @@ func (e *Emulator) Run() int64 {
 	}
 }
 
+// RunFast executes instructions until the program exits or an error
+// occurs, like Run, but drives a threaded-interpreter loop over e.icache
+// instead of re-fetching and re-decoding every PC: each instruction is
+// decoded and resolved to a handler exactly once, then the cached
+// *DecodedInst is reused on every subsequent visit (e.g. loop bodies).
+// Use Step/Run instead when single-instruction granularity is needed, such
+// as under the gdbserver debugger.
+func (e *Emulator) RunFast() int64 {
+	for {
+		if e.maxInstructions > 0 && e.instructionCount >= e.maxInstructions {
+			return -1
+		}
+
+		result := e.stepFast(e.regFile.PC)
+		if result.Exited {
+			return result.ExitCode
+		}
+		if result.Err != nil {
+			return -1
+		}
+	}
+}
+
+// resolveHandler picks the InstHandler for inst once at decode time so
+// RunFast's hot loop never re-switches on format/op. It delegates to the
+// same execute* helpers the step-by-step interpreter uses, so the two
+// paths can never disagree on semantics.
+func (e *Emulator) resolveHandler(inst *insts.Instruction) InstHandler {
+	if inst.Op == insts.OpHLT && isPseudoOpImm(uint16(inst.Imm)) {
+		imm := uint16(inst.Imm)
+		return func(e *Emulator, _ *DecodedInst) StepResult {
+			return e.executePseudoOp(imm)
+		}
+	}
+	if inst.Op == insts.OpUnknown {
+		return func(e *Emulator, _ *DecodedInst) StepResult {
+			return StepResult{Err: fmt.Errorf("unknown instruction at PC=0x%X", e.regFile.PC)}
+		}
+	}
+	if inst.Op == insts.OpSVC {
+		return func(e *Emulator, _ *DecodedInst) StepResult {
+			return e.executeSVC()
+		}
+	}
+
+	switch inst.Format {
+	case insts.FormatDPImm:
+		return advancingHandler((*Emulator).executeDPImm)
+	case insts.FormatDPReg:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			if f := e.executeDPReg(d.Inst); f != nil {
+				return StepResult{ExceptionVector: f.Vector}
+			}
+			e.regFile.PC += 4
+			return StepResult{}
+		}
+	case insts.FormatBranch:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			e.executeBranch(d.Inst)
+			return StepResult{}
+		}
+	case insts.FormatBranchCond:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			e.executeBranchCond(d.Inst)
+			return StepResult{}
+		}
+	case insts.FormatBranchReg:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			e.executeBranchReg(d.Inst)
+			return StepResult{}
+		}
+	case insts.FormatLoadStore:
+		return advancingHandler((*Emulator).executeLoadStore)
+	case insts.FormatLoadStorePair:
+		return advancingHandler((*Emulator).executeLoadStorePair)
+	case insts.FormatLoadStoreExcl:
+		// Covers the whole exclusive/acquire-release family dispatched by
+		// executeLoadStoreExcl, including the 32-bit pair and LDAR/STLR
+		// added after this case was first written - resolveHandler keys
+		// on Format, not Op, so nothing further is needed here as that
+		// family grows.
+		return advancingHandler((*Emulator).executeLoadStoreExcl)
+	case insts.FormatAtomic:
+		// Likewise covers every Op executeAtomic switches on, including
+		// the CAS family added after this case was first written.
+		return advancingHandler((*Emulator).executeAtomic)
+	case insts.FormatCmpBranch:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			e.executeCmpBranch(d.Inst)
+			return StepResult{}
+		}
+	case insts.FormatTestBranch:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			e.executeTestBranch(d.Inst)
+			return StepResult{}
+		}
+	case insts.FormatCondCmp:
+		return advancingHandler((*Emulator).executeCondCmp)
+	case insts.FormatCondSelect:
+		return advancingHandler((*Emulator).executeCondSelect)
+	case insts.FormatDP3Source:
+		return advancingHandler((*Emulator).executeDP3Source)
+	case insts.FormatDP1Source:
+		return advancingHandler((*Emulator).executeDP1Source)
+	case insts.FormatPCRel:
+		return advancingHandler((*Emulator).executePCRel)
+	case insts.FormatLoadStoreLit:
+		return advancingHandler((*Emulator).executeLoadStoreLit)
+	case insts.FormatMoveWide:
+		return advancingHandler((*Emulator).executeMoveWide)
+	case insts.FormatFPDPImm:
+		return advancingHandler((*Emulator).executeFPDPImm)
+	case insts.FormatFPDPReg:
+		return advancingHandler((*Emulator).executeFPDPReg)
+	case insts.FormatFPLoadStore:
+		return advancingHandler((*Emulator).executeFPLoadStore)
+	case insts.FormatFPConvert:
+		return advancingHandler((*Emulator).executeFPConvert)
+	case insts.FormatSIMDVector:
+		return advancingHandler((*Emulator).executeSIMDVector)
+	default:
+		return func(e *Emulator, d *DecodedInst) StepResult {
+			return StepResult{Err: fmt.Errorf("unimplemented format %d at PC=0x%X", d.Inst.Format, e.regFile.PC)}
+		}
+	}
+}
+
+// advancingHandler wraps an execute* method that does not touch PC itself
+// into an InstHandler that advances PC by 4 afterward, matching execute's
+// behavior for non-branch formats.
+func advancingHandler(exec func(*Emulator, *insts.Instruction)) InstHandler {
+	return func(e *Emulator, d *DecodedInst) StepResult {
+		exec(e, d.Inst)
+		e.regFile.PC += 4
+		return StepResult{}
+	}
+}
+
 // execute dispatches and executes a decoded instruction.
 func (e *Emulator) execute(inst *insts.Instruction) StepResult {
+	// Recognize gem5-style M5 pseudo-ops before the unknown-instruction
+	// check, since the decoder may not have a dedicated Op for a plain HLT.
+	if inst.Op == insts.OpHLT && isPseudoOpImm(uint16(inst.Imm)) {
+		return e.executePseudoOp(uint16(inst.Imm))
+	}
+
 	// Check for unknown instruction
 	if inst.Op == insts.OpUnknown {
 		return StepResult{
@@ -220,7 +788,9 @@ func (e *Emulator) execute(inst *insts.Instruction) StepResult {
 	case insts.FormatDPImm:
 		e.executeDPImm(inst)
 	case insts.FormatDPReg:
-		e.executeDPReg(inst)
+		if f := e.executeDPReg(inst); f != nil {
+			return StepResult{ExceptionVector: f.Vector}
+		}
 	case insts.FormatBranch:
 		e.executeBranch(inst)
 		return StepResult{} // PC already updated by branch
@@ -232,12 +802,42 @@ func (e *Emulator) execute(inst *insts.Instruction) StepResult {
 		return StepResult{} // PC already updated
 	case insts.FormatLoadStore:
 		e.executeLoadStore(inst)
+	case insts.FormatLoadStorePair:
+		e.executeLoadStorePair(inst)
+	case insts.FormatLoadStoreExcl:
+		e.executeLoadStoreExcl(inst)
+	case insts.FormatAtomic:
+		e.executeAtomic(inst)
+	case insts.FormatCmpBranch:
+		e.executeCmpBranch(inst)
+		return StepResult{} // PC already updated
+	case insts.FormatTestBranch:
+		e.executeTestBranch(inst)
+		return StepResult{} // PC already updated
+	case insts.FormatCondCmp:
+		e.executeCondCmp(inst)
+	case insts.FormatCondSelect:
+		e.executeCondSelect(inst)
+	case insts.FormatDP3Source:
+		e.executeDP3Source(inst)
+	case insts.FormatDP1Source:
+		e.executeDP1Source(inst)
 	case insts.FormatPCRel:
 		e.executePCRel(inst)
 	case insts.FormatLoadStoreLit:
 		e.executeLoadStoreLit(inst)
 	case insts.FormatMoveWide:
 		e.executeMoveWide(inst)
+	case insts.FormatFPDPImm:
+		e.executeFPDPImm(inst)
+	case insts.FormatFPDPReg:
+		e.executeFPDPReg(inst)
+	case insts.FormatFPLoadStore:
+		e.executeFPLoadStore(inst)
+	case insts.FormatFPConvert:
+		e.executeFPConvert(inst)
+	case insts.FormatSIMDVector:
+		e.executeSIMDVector(inst)
 	default:
 		return StepResult{
 			Err: fmt.Errorf("unimplemented format %d at PC=0x%X", inst.Format, e.regFile.PC),
@@ -255,9 +855,22 @@ func (e *Emulator) executeSVC() StepResult {
 	// Advance PC first (syscall return address is next instruction)
 	e.regFile.PC += 4
 
+	var num uint64
+	var args [6]uint64
+	if e.traceSink != nil {
+		num = e.regFile.ReadReg(8)
+		for i := range args {
+			args[i] = e.regFile.ReadReg(uint8(i))
+		}
+	}
+
 	// Invoke syscall handler
 	syscallResult := e.syscallHandler.Handle()
 
+	if e.traceSink != nil {
+		e.traceSink.RecordSyscall(num, args, syscallResult.Exited, syscallResult.ExitCode)
+	}
+
 	return StepResult{
 		Exited:   syscallResult.Exited,
 		ExitCode: syscallResult.ExitCode,
@@ -284,11 +897,48 @@ func (e *Emulator) executeDPImm(inst *insts.Instruction) {
 		} else {
 			e.alu.SUB32Imm(inst.Rd, inst.Rn, uint32(imm), inst.SetFlags)
 		}
+	case insts.OpAND, insts.OpORR, insts.OpEOR:
+		// The logical-immediate encodings have no LSL#12 shift field; the
+		// decoder is expected to hand inst.Imm already replicated to the
+		// full register width by the ARM bitmask-immediate algorithm, so
+		// these cases bypass the imm/shift computation above.
+		e.executeLogicalImm(inst)
 	}
 }
 
-// executeDPReg executes Data Processing Register instructions.
-func (e *Emulator) executeDPReg(inst *insts.Instruction) {
+// executeLogicalImm executes AND/ORR/EOR (immediate), including the
+// flag-setting ANDS alias (AND with SetFlags set).
+func (e *Emulator) executeLogicalImm(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpAND:
+		if inst.Is64Bit {
+			e.alu.AND64Imm(inst.Rd, inst.Rn, inst.Imm, inst.SetFlags)
+		} else {
+			e.alu.AND32Imm(inst.Rd, inst.Rn, uint32(inst.Imm), inst.SetFlags)
+		}
+	case insts.OpORR:
+		if inst.Is64Bit {
+			e.alu.ORR64Imm(inst.Rd, inst.Rn, inst.Imm)
+		} else {
+			e.alu.ORR32Imm(inst.Rd, inst.Rn, uint32(inst.Imm))
+		}
+	case insts.OpEOR:
+		if inst.Is64Bit {
+			e.alu.EOR64Imm(inst.Rd, inst.Rn, inst.Imm)
+		} else {
+			e.alu.EOR32Imm(inst.Rd, inst.Rn, uint32(inst.Imm))
+		}
+	}
+}
+
+// executeDPReg executes Data Processing Register instructions: the
+// register ALU ops (ADD/SUB/AND/ORR/EOR), UDIV/SDIV, and the CRC32/CRC32C
+// checksum family, all of which share the Data-processing (2 source)
+// encoding group. It returns a non-nil EmuFault when UDIV/SDIV hit
+// divide-by-zero or (SDIV only) INT_MIN/-1 overflow and the emulator's
+// FaultPolicy decided the condition should trap instead of producing the
+// silent ARMv8 result; callers must not advance PC in that case.
+func (e *Emulator) executeDPReg(inst *insts.Instruction) *EmuFault {
 	switch inst.Op {
 	case insts.OpADD:
 		if inst.Is64Bit {
@@ -320,7 +970,43 @@ func (e *Emulator) executeDPReg(inst *insts.Instruction) {
 		} else {
 			e.alu.EOR32(inst.Rd, inst.Rn, inst.Rm)
 		}
+	case insts.OpUDIV:
+		if f := e.checkDivideFault(inst); f != nil {
+			return f
+		}
+		if inst.Is64Bit {
+			e.alu.UDIV64(inst.Rd, inst.Rn, inst.Rm)
+		} else {
+			e.alu.UDIV32(inst.Rd, inst.Rn, inst.Rm)
+		}
+	case insts.OpSDIV:
+		if f := e.checkDivideFault(inst); f != nil {
+			return f
+		}
+		if inst.Is64Bit {
+			e.alu.SDIV64(inst.Rd, inst.Rn, inst.Rm)
+		} else {
+			e.alu.SDIV32(inst.Rd, inst.Rn, inst.Rm)
+		}
+	case insts.OpCRC32B:
+		e.alu.CRC32B(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32H:
+		e.alu.CRC32H(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32W:
+		e.alu.CRC32W(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32X:
+		e.alu.CRC32X(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32CB:
+		e.alu.CRC32CB(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32CH:
+		e.alu.CRC32CH(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32CW:
+		e.alu.CRC32CW(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpCRC32CX:
+		e.alu.CRC32CX(inst.Rd, inst.Rn, inst.Rm)
 	}
+
+	return nil
 }
 
 // executeBranch executes unconditional branch instructions (B, BL).
@@ -333,12 +1019,60 @@ func (e *Emulator) executeBranch(inst *insts.Instruction) {
 	}
 }
 
+// EvaluateCondition reports whether cond holds against the emulator's
+// current PSTATE. It is the single canonical condition-code evaluator
+// that B.cond, CCMP/CCMN, CSEL and its aliases (CSINC/CSINV/CSNEG), and
+// CSET/CSETM all route through — see ConditionCode.Evaluate.
+func (e *Emulator) EvaluateCondition(cond ConditionCode) bool {
+	return cond.Evaluate(e.regFile.PSTATE)
+}
+
+// executeCondCmp executes CCMP and CCMN. When cond holds, it behaves like
+// a flag-only SUBS (CCMP) or ADDS (CCMN) against Rn and the register or
+// immediate operand, discarding the would-be result exactly as CMP/CMN do
+// by aliasing Rd to the zero register. When cond does not hold, PSTATE is
+// instead overwritten with the instruction's literal nzcv field — the one
+// place condition flags come from a literal rather than an ALU result,
+// still routed through PSTATE's per-flag setters.
+func (e *Emulator) executeCondCmp(inst *insts.Instruction) {
+	if e.EvaluateCondition(ConditionCode(inst.Cond)) {
+		switch {
+		case inst.Op == insts.OpCCMP && inst.ImmForm:
+			if inst.Is64Bit {
+				e.alu.SUB64Imm(31, inst.Rn, inst.Imm, true)
+			} else {
+				e.alu.SUB32Imm(31, inst.Rn, uint32(inst.Imm), true)
+			}
+		case inst.Op == insts.OpCCMP:
+			if inst.Is64Bit {
+				e.alu.SUB64(31, inst.Rn, inst.Rm, true)
+			} else {
+				e.alu.SUB32(31, inst.Rn, inst.Rm, true)
+			}
+		case inst.ImmForm: // CCMN, immediate form
+			if inst.Is64Bit {
+				e.alu.ADD64Imm(31, inst.Rn, inst.Imm, true)
+			} else {
+				e.alu.ADD32Imm(31, inst.Rn, uint32(inst.Imm), true)
+			}
+		default: // CCMN, register form
+			if inst.Is64Bit {
+				e.alu.ADD64(31, inst.Rn, inst.Rm, true)
+			} else {
+				e.alu.ADD32(31, inst.Rn, inst.Rm, true)
+			}
+		}
+		return
+	}
+
+	e.regFile.PSTATE.SetNZ(inst.NZCV&0b1000 != 0, inst.NZCV&0b0100 != 0)
+	e.regFile.PSTATE.SetC(inst.NZCV&0b0010 != 0)
+	e.regFile.PSTATE.SetV(inst.NZCV&0b0001 != 0)
+}
+
 // executeBranchCond executes conditional branch instructions.
 func (e *Emulator) executeBranchCond(inst *insts.Instruction) {
-	// Convert insts.Cond to emu.Cond
-	cond := Cond(inst.Cond)
-
-	if e.branchUnit.CheckCondition(cond) {
+	if e.EvaluateCondition(ConditionCode(inst.Cond)) {
 		e.regFile.PC = uint64(int64(e.regFile.PC) + inst.BranchOffset)
 	} else {
 		// Condition not met, advance to next instruction
@@ -392,6 +1126,508 @@ func (e *Emulator) executeLoadStore(inst *insts.Instruction) {
 				e.lsu.STR32(inst.Rd, inst.Rn, inst.Imm)
 			}
 		}
+	case insts.OpLDRB:
+		if useSP {
+			e.lsu.LDRBSP(inst.Rd, inst.Imm)
+		} else {
+			e.lsu.LDRB(inst.Rd, inst.Rn, inst.Imm)
+		}
+	case insts.OpLDRSB:
+		if inst.Is64Bit {
+			if useSP {
+				e.lsu.LDRSB64SP(inst.Rd, inst.Imm)
+			} else {
+				e.lsu.LDRSB64(inst.Rd, inst.Rn, inst.Imm)
+			}
+		} else {
+			if useSP {
+				e.lsu.LDRSB32SP(inst.Rd, inst.Imm)
+			} else {
+				e.lsu.LDRSB32(inst.Rd, inst.Rn, inst.Imm)
+			}
+		}
+	case insts.OpSTRB:
+		if useSP {
+			e.lsu.STRBSP(inst.Rd, inst.Imm)
+		} else {
+			e.lsu.STRB(inst.Rd, inst.Rn, inst.Imm)
+		}
+	case insts.OpLDRH:
+		if useSP {
+			e.lsu.LDRHSP(inst.Rd, inst.Imm)
+		} else {
+			e.lsu.LDRH(inst.Rd, inst.Rn, inst.Imm)
+		}
+	case insts.OpLDRSH:
+		if inst.Is64Bit {
+			if useSP {
+				e.lsu.LDRSH64SP(inst.Rd, inst.Imm)
+			} else {
+				e.lsu.LDRSH64(inst.Rd, inst.Rn, inst.Imm)
+			}
+		} else {
+			if useSP {
+				e.lsu.LDRSH32SP(inst.Rd, inst.Imm)
+			} else {
+				e.lsu.LDRSH32(inst.Rd, inst.Rn, inst.Imm)
+			}
+		}
+	case insts.OpSTRH:
+		if useSP {
+			e.lsu.STRHSP(inst.Rd, inst.Imm)
+		} else {
+			e.lsu.STRH(inst.Rd, inst.Rn, inst.Imm)
+		}
+	case insts.OpLDRSW:
+		if useSP {
+			e.lsu.LDRSWSP(inst.Rd, inst.Imm)
+		} else {
+			e.lsu.LDRSW(inst.Rd, inst.Rn, inst.Imm)
+		}
+	}
+}
+
+// executeLoadStorePair executes LDP/STP across both operand widths (32-
+// and 64-bit) and all three addressing modes (signed offset, pre-index,
+// post-index), routing through the SP-based variants when Rn is SP (31)
+// so stack-frame prologue/epilogue sequences like
+// "stp x29, x30, [sp, #-16]!" / "ldp x29, x30, [sp], #16" need no
+// special-casing here.
+func (e *Emulator) executeLoadStorePair(inst *insts.Instruction) {
+	useSP := inst.Rn == 31
+
+	switch inst.Op {
+	case insts.OpLDP:
+		if inst.Is64Bit {
+			switch inst.IndexMode {
+			case insts.IndexPre:
+				if useSP {
+					e.lsu.LDP64SPPreIndex(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.LDP64PreIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			case insts.IndexPost:
+				if useSP {
+					e.lsu.LDP64SPPostIndex(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.LDP64PostIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			default:
+				if useSP {
+					e.lsu.LDP64SP(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.LDP64(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			}
+		} else {
+			switch inst.IndexMode {
+			case insts.IndexPre:
+				e.lsu.LDP32PreIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			case insts.IndexPost:
+				e.lsu.LDP32PostIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			default:
+				e.lsu.LDP32(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			}
+		}
+	case insts.OpSTP:
+		if inst.Is64Bit {
+			switch inst.IndexMode {
+			case insts.IndexPre:
+				if useSP {
+					e.lsu.STP64SPPreIndex(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.STP64PreIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			case insts.IndexPost:
+				if useSP {
+					e.lsu.STP64SPPostIndex(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.STP64PostIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			default:
+				if useSP {
+					e.lsu.STP64SP(inst.Rd, inst.Rt2, inst.BranchOffset)
+				} else {
+					e.lsu.STP64(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+				}
+			}
+		} else {
+			switch inst.IndexMode {
+			case insts.IndexPre:
+				e.lsu.STP32PreIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			case insts.IndexPost:
+				e.lsu.STP32PostIndex(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			default:
+				e.lsu.STP32(inst.Rd, inst.Rt2, inst.Rn, inst.BranchOffset)
+			}
+		}
+	}
+}
+
+// executeLoadStoreExcl executes the LDXR/STXR exclusive-monitor family and
+// the plain acquire-release LDAR/STLR that isn't paired with an exclusive.
+// As with executeLoadStorePair, the first register listed in the mnemonic
+// is decoded into Rd, the second (where present) into Rt2, and the base
+// address register into Rn: LDXR Xt, [Xn] decodes as Rd=Xt, Rn=Xn, while
+// STXR Ws, Xt, [Xn] decodes as Rd=Ws, Rt2=Xt, Rn=Xn.
+func (e *Emulator) executeLoadStoreExcl(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpLDXR:
+		if inst.Is64Bit {
+			e.lsu.LDXR64(inst.Rd, inst.Rn)
+		} else {
+			e.lsu.LDXR32(inst.Rd, inst.Rn)
+		}
+	case insts.OpLDAXR:
+		if inst.Is64Bit {
+			e.lsu.LDAXR64(inst.Rd, inst.Rn)
+		} else {
+			e.lsu.LDAXR32(inst.Rd, inst.Rn)
+		}
+	case insts.OpSTXR:
+		if inst.Is64Bit {
+			e.lsu.STXR64(inst.Rd, inst.Rt2, inst.Rn)
+		} else {
+			e.lsu.STXR32(inst.Rd, inst.Rt2, inst.Rn)
+		}
+	case insts.OpSTLXR:
+		if inst.Is64Bit {
+			e.lsu.STLXR64(inst.Rd, inst.Rt2, inst.Rn)
+		} else {
+			e.lsu.STLXR32(inst.Rd, inst.Rt2, inst.Rn)
+		}
+	case insts.OpLDAR:
+		if inst.Is64Bit {
+			e.lsu.LDAR64(inst.Rd, inst.Rn)
+		} else {
+			e.lsu.LDAR32(inst.Rd, inst.Rn)
+		}
+	case insts.OpSTLR:
+		if inst.Is64Bit {
+			e.lsu.STLR64(inst.Rd, inst.Rn)
+		} else {
+			e.lsu.STLR32(inst.Rd, inst.Rn)
+		}
+	}
+}
+
+// executeAtomic executes the LSE atomic memory-operation family: LDADD,
+// LDCLR, LDEOR, LDSET, SWP and CAS, each with their A/L/AL ordering
+// variants, decoded the same way as executeLoadStoreExcl's STXR case
+// (Rd=source register, Rt2=destination register, Rn=base address
+// register).
+func (e *Emulator) executeAtomic(inst *insts.Instruction) {
+	rs, rt, rn := inst.Rd, inst.Rt2, inst.Rn
+
+	switch inst.Op {
+	case insts.OpCAS:
+		if inst.Is64Bit {
+			e.lsu.CAS64(rs, rt, rn)
+		} else {
+			e.lsu.CAS32(rs, rt, rn)
+		}
+	case insts.OpCASA:
+		if inst.Is64Bit {
+			e.lsu.CASA64(rs, rt, rn)
+		} else {
+			e.lsu.CASA32(rs, rt, rn)
+		}
+	case insts.OpCASL:
+		if inst.Is64Bit {
+			e.lsu.CASL64(rs, rt, rn)
+		} else {
+			e.lsu.CASL32(rs, rt, rn)
+		}
+	case insts.OpCASAL:
+		if inst.Is64Bit {
+			e.lsu.CASAL64(rs, rt, rn)
+		} else {
+			e.lsu.CASAL32(rs, rt, rn)
+		}
+	case insts.OpLDADD:
+		if inst.Is64Bit {
+			e.lsu.LDADD64(rs, rt, rn)
+		} else {
+			e.lsu.LDADD32(rs, rt, rn)
+		}
+	case insts.OpLDADDA:
+		if inst.Is64Bit {
+			e.lsu.LDADDA64(rs, rt, rn)
+		} else {
+			e.lsu.LDADDA32(rs, rt, rn)
+		}
+	case insts.OpLDADDL:
+		if inst.Is64Bit {
+			e.lsu.LDADDL64(rs, rt, rn)
+		} else {
+			e.lsu.LDADDL32(rs, rt, rn)
+		}
+	case insts.OpLDADDAL:
+		if inst.Is64Bit {
+			e.lsu.LDADDAL64(rs, rt, rn)
+		} else {
+			e.lsu.LDADDAL32(rs, rt, rn)
+		}
+	case insts.OpLDCLR:
+		if inst.Is64Bit {
+			e.lsu.LDCLR64(rs, rt, rn)
+		} else {
+			e.lsu.LDCLR32(rs, rt, rn)
+		}
+	case insts.OpLDCLRA:
+		if inst.Is64Bit {
+			e.lsu.LDCLRA64(rs, rt, rn)
+		} else {
+			e.lsu.LDCLRA32(rs, rt, rn)
+		}
+	case insts.OpLDCLRL:
+		if inst.Is64Bit {
+			e.lsu.LDCLRL64(rs, rt, rn)
+		} else {
+			e.lsu.LDCLRL32(rs, rt, rn)
+		}
+	case insts.OpLDCLRAL:
+		if inst.Is64Bit {
+			e.lsu.LDCLRAL64(rs, rt, rn)
+		} else {
+			e.lsu.LDCLRAL32(rs, rt, rn)
+		}
+	case insts.OpLDEOR:
+		if inst.Is64Bit {
+			e.lsu.LDEOR64(rs, rt, rn)
+		} else {
+			e.lsu.LDEOR32(rs, rt, rn)
+		}
+	case insts.OpLDEORA:
+		if inst.Is64Bit {
+			e.lsu.LDEORA64(rs, rt, rn)
+		} else {
+			e.lsu.LDEORA32(rs, rt, rn)
+		}
+	case insts.OpLDEORL:
+		if inst.Is64Bit {
+			e.lsu.LDEORL64(rs, rt, rn)
+		} else {
+			e.lsu.LDEORL32(rs, rt, rn)
+		}
+	case insts.OpLDEORAL:
+		if inst.Is64Bit {
+			e.lsu.LDEORAL64(rs, rt, rn)
+		} else {
+			e.lsu.LDEORAL32(rs, rt, rn)
+		}
+	case insts.OpLDSET:
+		if inst.Is64Bit {
+			e.lsu.LDSET64(rs, rt, rn)
+		} else {
+			e.lsu.LDSET32(rs, rt, rn)
+		}
+	case insts.OpLDSETA:
+		if inst.Is64Bit {
+			e.lsu.LDSETA64(rs, rt, rn)
+		} else {
+			e.lsu.LDSETA32(rs, rt, rn)
+		}
+	case insts.OpLDSETL:
+		if inst.Is64Bit {
+			e.lsu.LDSETL64(rs, rt, rn)
+		} else {
+			e.lsu.LDSETL32(rs, rt, rn)
+		}
+	case insts.OpLDSETAL:
+		if inst.Is64Bit {
+			e.lsu.LDSETAL64(rs, rt, rn)
+		} else {
+			e.lsu.LDSETAL32(rs, rt, rn)
+		}
+	case insts.OpSWP:
+		if inst.Is64Bit {
+			e.lsu.SWP64(rs, rt, rn)
+		} else {
+			e.lsu.SWP32(rs, rt, rn)
+		}
+	case insts.OpSWPA:
+		if inst.Is64Bit {
+			e.lsu.SWPA64(rs, rt, rn)
+		} else {
+			e.lsu.SWPA32(rs, rt, rn)
+		}
+	case insts.OpSWPL:
+		if inst.Is64Bit {
+			e.lsu.SWPL64(rs, rt, rn)
+		} else {
+			e.lsu.SWPL32(rs, rt, rn)
+		}
+	case insts.OpSWPAL:
+		if inst.Is64Bit {
+			e.lsu.SWPAL64(rs, rt, rn)
+		} else {
+			e.lsu.SWPAL32(rs, rt, rn)
+		}
+	}
+}
+
+// executeCmpBranch executes CBZ/CBNZ: branch if Rn is (not) zero.
+func (e *Emulator) executeCmpBranch(inst *insts.Instruction) {
+	val := e.regFile.ReadReg(inst.Rn)
+	if !inst.Is64Bit {
+		val = uint64(uint32(val))
+	}
+
+	var taken bool
+	switch inst.Op {
+	case insts.OpCBZ:
+		taken = val == 0
+	case insts.OpCBNZ:
+		taken = val != 0
+	}
+
+	if taken {
+		e.regFile.PC = uint64(int64(e.regFile.PC) + inst.BranchOffset)
+	} else {
+		e.regFile.PC += 4
+	}
+}
+
+// executeTestBranch executes TBZ/TBNZ: branch if bit inst.Imm of Rn is
+// (not) set.
+func (e *Emulator) executeTestBranch(inst *insts.Instruction) {
+	bit := (e.regFile.ReadReg(inst.Rn) >> inst.Imm) & 1
+
+	var taken bool
+	switch inst.Op {
+	case insts.OpTBZ:
+		taken = bit == 0
+	case insts.OpTBNZ:
+		taken = bit == 1
+	}
+
+	if taken {
+		e.regFile.PC = uint64(int64(e.regFile.PC) + inst.BranchOffset)
+	} else {
+		e.regFile.PC += 4
+	}
+}
+
+// executeCondSelect executes the conditional select family (CSEL, CSINC,
+// CSINV, CSNEG), evaluating inst.Cond once here and handing the result to
+// ALU so it stays the sole owner of the integer register-result
+// computation, the same division of labor it has with executeDPReg.
+// CSET and CSETM are decoder-level aliases of CSINC/CSINV with
+// Rn=Rm=XZR and an inverted condition, the same alias-folding convention
+// the decoder uses for CMP (SUBS, Rd=XZR) and MUL (MADD, Ra=XZR), so they
+// need no separate case here.
+func (e *Emulator) executeCondSelect(inst *insts.Instruction) {
+	condTrue := e.EvaluateCondition(ConditionCode(inst.Cond))
+
+	switch inst.Op {
+	case insts.OpCSEL:
+		if inst.Is64Bit {
+			e.alu.CSEL64(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		} else {
+			e.alu.CSEL32(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		}
+	case insts.OpCSINC:
+		if inst.Is64Bit {
+			e.alu.CSINC64(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		} else {
+			e.alu.CSINC32(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		}
+	case insts.OpCSINV:
+		if inst.Is64Bit {
+			e.alu.CSINV64(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		} else {
+			e.alu.CSINV32(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		}
+	case insts.OpCSNEG:
+		if inst.Is64Bit {
+			e.alu.CSNEG64(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		} else {
+			e.alu.CSNEG32(inst.Rd, inst.Rn, inst.Rm, condTrue)
+		}
+	}
+}
+
+// executeDP3Source executes the 3-source data processing instructions: the
+// multiply family (MUL, MADD, MSUB, UMULH, SMULH) and the widening multiply
+// family (UMADDL, UMSUBL, SMADDL, SMSUBL, and their UMULL/SMULL Ra=XZR
+// aliases). MUL/UMULL/SMULL are the Ra=XZR/WZR alias of MADD/UMADDL/SMADDL
+// respectively, which the decoder is expected to fold into the
+// accumulate form with inst.Ra set to the zero register, the same way it
+// already folds CMP into OpSUB with Rd=XZR. ALU computes UMULH/SMULH as the
+// upper 64 bits of a 128-bit product via math/bits.Mul64, so no big.Int
+// allocation is needed.
+func (e *Emulator) executeDP3Source(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpMADD:
+		if inst.Is64Bit {
+			e.alu.MADD64(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+		} else {
+			e.alu.MADD32(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+		}
+	case insts.OpMSUB:
+		if inst.Is64Bit {
+			e.alu.MSUB64(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+		} else {
+			e.alu.MSUB32(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+		}
+	case insts.OpUMULH:
+		e.alu.UMULH(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpSMULH:
+		e.alu.SMULH(inst.Rd, inst.Rn, inst.Rm)
+	case insts.OpUMADDL:
+		// UMULL is this instruction's Ra=XZR alias, the same way MUL aliases
+		// MADD above.
+		e.alu.UMADDL(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+	case insts.OpUMSUBL:
+		e.alu.UMSUBL(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+	case insts.OpSMADDL:
+		// SMULL is this instruction's Ra=XZR alias.
+		e.alu.SMADDL(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+	case insts.OpSMSUBL:
+		e.alu.SMSUBL(inst.Rd, inst.Rn, inst.Rm, inst.Ra)
+	}
+}
+
+// executeDP1Source executes the 1-source data processing instructions: the
+// bit-manipulation family CLZ, CLS, RBIT, REV, REV16, and REV32. REV and
+// REV32 are both full register-width byte-reverse operations, but only REV
+// varies with inst.Is64Bit the way CLZ/CLS/RBIT/REV16 do; REV32 always
+// reverses bytes within each 32-bit word of a 64-bit register.
+func (e *Emulator) executeDP1Source(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpCLZ:
+		if inst.Is64Bit {
+			e.alu.CLZ64(inst.Rd, inst.Rn)
+		} else {
+			e.alu.CLZ32(inst.Rd, inst.Rn)
+		}
+	case insts.OpCLS:
+		if inst.Is64Bit {
+			e.alu.CLS64(inst.Rd, inst.Rn)
+		} else {
+			e.alu.CLS32(inst.Rd, inst.Rn)
+		}
+	case insts.OpRBIT:
+		if inst.Is64Bit {
+			e.alu.RBIT64(inst.Rd, inst.Rn)
+		} else {
+			e.alu.RBIT32(inst.Rd, inst.Rn)
+		}
+	case insts.OpREV:
+		if inst.Is64Bit {
+			e.alu.REV64(inst.Rd, inst.Rn)
+		} else {
+			e.alu.REV32(inst.Rd, inst.Rn)
+		}
+	case insts.OpREV16:
+		if inst.Is64Bit {
+			e.alu.REV1664(inst.Rd, inst.Rn)
+		} else {
+			e.alu.REV1632(inst.Rd, inst.Rn)
+		}
+	case insts.OpREV32:
+		e.alu.REV32Words(inst.Rd, inst.Rn)
 	}
 }
 
@@ -459,3 +1695,77 @@ func (e *Emulator) executeMoveWide(inst *insts.Instruction) {
 		e.regFile.WriteReg(inst.Rd, result)
 	}
 }
+
+// executeFPDPImm executes FP data-processing (immediate) instructions,
+// i.e. FMOV of an already-decoded immediate pattern into a V register.
+func (e *Emulator) executeFPDPImm(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpFMOV:
+		e.fpu.FMOVImm(inst.Rd, inst.Imm, inst.IsDouble)
+	}
+}
+
+// executeFPDPReg executes scalar FP data-processing (register)
+// instructions: FMOV, FADD, FSUB, FMUL, FDIV, FCMP.
+func (e *Emulator) executeFPDPReg(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpFMOV:
+		e.fpu.FMOV(inst.Rd, inst.Rn, inst.IsDouble)
+	case insts.OpFADD:
+		e.fpu.FADD(inst.Rd, inst.Rn, inst.Rm, inst.IsDouble)
+	case insts.OpFSUB:
+		e.fpu.FSUB(inst.Rd, inst.Rn, inst.Rm, inst.IsDouble)
+	case insts.OpFMUL:
+		e.fpu.FMUL(inst.Rd, inst.Rn, inst.Rm, inst.IsDouble)
+	case insts.OpFDIV:
+		e.fpu.FDIV(inst.Rd, inst.Rn, inst.Rm, inst.IsDouble)
+	case insts.OpFCMP:
+		e.fpu.FCMP(inst.Rn, inst.Rm, inst.IsDouble)
+	}
+}
+
+// executeFPLoadStore executes scalar LDR/STR on S and D registers.
+func (e *Emulator) executeFPLoadStore(inst *insts.Instruction) {
+	useSP := inst.Rn == 31
+
+	switch inst.Op {
+	case insts.OpLDR:
+		if useSP {
+			e.fpu.LDRSP(inst.Rd, inst.Imm, inst.IsDouble)
+		} else {
+			e.fpu.LDR(inst.Rd, inst.Rn, inst.Imm, inst.IsDouble)
+		}
+	case insts.OpSTR:
+		if useSP {
+			e.fpu.STRSP(inst.Rd, inst.Imm, inst.IsDouble)
+		} else {
+			e.fpu.STR(inst.Rd, inst.Rn, inst.Imm, inst.IsDouble)
+		}
+	}
+}
+
+// executeFPConvert executes FP<->FP and FP<->integer conversions: FCVT,
+// FCVTZS, SCVTF.
+func (e *Emulator) executeFPConvert(inst *insts.Instruction) {
+	switch inst.Op {
+	case insts.OpFCVT:
+		e.fpu.FCVT(inst.Rd, inst.Rn, inst.IsDouble)
+	case insts.OpFCVTZS:
+		e.fpu.FCVTZS(inst.Rd, inst.Rn, inst.IsDouble, inst.Is64Bit)
+	case insts.OpSCVTF:
+		e.fpu.SCVTF(inst.Rd, int64(e.regFile.ReadReg(inst.Rn)), inst.IsDouble, inst.Is64Bit)
+	}
+}
+
+// executeSIMDVector executes vector (ASIMD) instructions across a
+// register's full arrangement, e.g. ADD.4s and FMUL.2d.
+func (e *Emulator) executeSIMDVector(inst *insts.Instruction) {
+	arr := Arrangement(inst.Arrangement)
+
+	switch inst.Op {
+	case insts.OpADD:
+		e.fpu.VectorADD(inst.Rd, inst.Rn, inst.Rm, arr)
+	case insts.OpFMUL:
+		e.fpu.VectorFMUL(inst.Rd, inst.Rn, inst.Rm, arr)
+	}
+}
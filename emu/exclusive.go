@@ -0,0 +1,386 @@
+package emu
+
+// monitorState is a LoadStoreUnit's local exclusive monitor: the address
+// range reserved by the most recent LDXR/LDAXR, if any. Real ARM64 cores
+// track this per-core and also snoop other cores' stores to the same
+// granule; this emulator only models one core, so invalidateIfOverlaps
+// only needs to watch this LoadStoreUnit's own stores.
+type monitorState struct {
+	valid bool
+	addr  uint64
+	size  uint64
+}
+
+// reserve opens an exclusive monitor on [addr, addr+size), as LDXR/LDAXR do.
+func (lsu *LoadStoreUnit) reserve(addr, size uint64) {
+	lsu.monitor = monitorState{valid: true, addr: addr, size: size}
+}
+
+// clearMonitor closes the exclusive monitor, as STXR/STLXR do once they've
+// resolved (whether they succeeded or failed).
+func (lsu *LoadStoreUnit) clearMonitor() {
+	lsu.monitor = monitorState{}
+}
+
+// invalidateIfOverlaps clears the exclusive monitor if [addr, addr+size)
+// overlaps the reserved granule. Every store method — LDP/STP/STR/STRB/
+// STRH included — calls this, so a plain store to a reserved address
+// between an LDXR and its matching STXR makes that STXR fail, the same
+// as real hardware's monitor snooping.
+func (lsu *LoadStoreUnit) invalidateIfOverlaps(addr, size uint64) {
+	if !lsu.monitor.valid {
+		return
+	}
+	if addr < lsu.monitor.addr+lsu.monitor.size && lsu.monitor.addr < addr+size {
+		lsu.clearMonitor()
+	}
+}
+
+// LDXR64 performs an exclusive 64-bit load: Xt = mem[Xn], and opens an
+// exclusive monitor on the accessed doubleword for a following STXR64 to
+// consult.
+func (lsu *LoadStoreUnit) LDXR64(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := lsu.memory.Read64(addr)
+	lsu.reserve(addr, 8)
+	lsu.regFile.WriteReg(rt, value)
+	lsu.trace("LDXR64", addr, value, 8)
+}
+
+// LDAXR64 is LDXR64 with acquire semantics. This emulator executes
+// instructions one at a time with no reordering, so acquire/release add
+// no observable behavior beyond LDXR64/STXR64's own monitor bookkeeping.
+func (lsu *LoadStoreUnit) LDAXR64(rt, rn uint8) {
+	lsu.LDXR64(rt, rn)
+}
+
+// STXR64 performs an exclusive 64-bit store: if the monitor opened by the
+// last LDXR64/LDAXR64 still covers mem[Xn] (no intervening store touched
+// it), mem[Xn] = Xt and Ws receives 0 (success); otherwise the store does
+// not happen and Ws receives 1 (failure). Either way the monitor is
+// cleared, matching the architecture's single-use reservation.
+func (lsu *LoadStoreUnit) STXR64(rs, rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	if lsu.monitor.valid && lsu.monitor.addr == addr && lsu.monitor.size == 8 {
+		value := lsu.regFile.ReadReg(rt)
+		lsu.memory.Write64(addr, value)
+		lsu.trace("STXR64", addr, value, 8)
+		lsu.regFile.WriteReg(rs, 0)
+	} else {
+		lsu.regFile.WriteReg(rs, 1)
+	}
+	lsu.clearMonitor()
+}
+
+// STLXR64 is STXR64 with release semantics; see LDAXR64.
+func (lsu *LoadStoreUnit) STLXR64(rs, rt, rn uint8) {
+	lsu.STXR64(rs, rt, rn)
+}
+
+// LDXR32 performs an exclusive 32-bit load with zero extension: Wt = mem[Xn],
+// and opens an exclusive monitor on the accessed word for a following
+// STXR32 to consult.
+func (lsu *LoadStoreUnit) LDXR32(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := lsu.memory.Read32(addr)
+	lsu.reserve(addr, 4)
+	lsu.regFile.WriteReg(rt, uint64(value))
+	lsu.trace("LDXR32", addr, uint64(value), 4)
+}
+
+// LDAXR32 is LDXR32 with acquire semantics; see LDAXR64.
+func (lsu *LoadStoreUnit) LDAXR32(rt, rn uint8) {
+	lsu.LDXR32(rt, rn)
+}
+
+// STXR32 performs an exclusive 32-bit store: if the monitor opened by the
+// last LDXR32/LDAXR32 still covers mem[Xn], mem[Xn] = Wt and Ws receives 0
+// (success); otherwise the store does not happen and Ws receives 1
+// (failure). Either way the monitor is cleared.
+func (lsu *LoadStoreUnit) STXR32(rs, rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	if lsu.monitor.valid && lsu.monitor.addr == addr && lsu.monitor.size == 4 {
+		value := uint32(lsu.regFile.ReadReg(rt))
+		lsu.memory.Write32(addr, value)
+		lsu.trace("STXR32", addr, uint64(value), 4)
+		lsu.regFile.WriteReg(rs, 0)
+	} else {
+		lsu.regFile.WriteReg(rs, 1)
+	}
+	lsu.clearMonitor()
+}
+
+// STLXR32 is STXR32 with release semantics; see STLXR64.
+func (lsu *LoadStoreUnit) STLXR32(rs, rt, rn uint8) {
+	lsu.STXR32(rs, rt, rn)
+}
+
+// LDAR64 performs a plain 64-bit acquire load: Xt = mem[Xn]. Unlike
+// LDAXR64, it opens no exclusive monitor - it exists purely to give
+// acquire-release semantics a load that isn't paired with a store-
+// exclusive, as real ARM64 code uses for lock-free reads. This emulator
+// runs one instruction at a time with no reordering, so it behaves
+// exactly like LDR64.
+func (lsu *LoadStoreUnit) LDAR64(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := lsu.memory.Read64(addr)
+	lsu.regFile.WriteReg(rt, value)
+	lsu.trace("LDAR64", addr, value, 8)
+}
+
+// LDAR32 is LDAR64's 32-bit (Wt) form, zero-extended into the full
+// 64-bit register.
+func (lsu *LoadStoreUnit) LDAR32(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := lsu.memory.Read32(addr)
+	lsu.regFile.WriteReg(rt, uint64(value))
+	lsu.trace("LDAR32", addr, uint64(value), 4)
+}
+
+// STLR64 performs a plain 64-bit release store: mem[Xn] = Xt. Like
+// LDAR64, it carries no exclusive-monitor bookkeeping of its own, but it
+// does clear any open monitor its address overlaps, the same as every
+// other store in this unit.
+func (lsu *LoadStoreUnit) STLR64(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := lsu.regFile.ReadReg(rt)
+	lsu.memory.Write64(addr, value)
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.trace("STLR64", addr, value, 8)
+}
+
+// STLR32 is STLR64's 32-bit (Wt) form.
+func (lsu *LoadStoreUnit) STLR32(rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	value := uint32(lsu.regFile.ReadReg(rt))
+	lsu.memory.Write32(addr, value)
+	lsu.invalidateIfOverlaps(addr, 4)
+	lsu.trace("STLR32", addr, uint64(value), 4)
+}
+
+// CAS64 performs an LSE compare-and-swap: if mem[Xn] equals Xs (the
+// expected value), mem[Xn] is set to Xt; either way, Xs is overwritten
+// with the value mem[Xn] held before the operation, so the caller can
+// tell success (Xs comes back equal to what it held going in) from
+// failure (Xs comes back holding the actual current value) without a
+// separate status register - the same contract real CAS has.
+func (lsu *LoadStoreUnit) CAS64(rs, rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	expected := lsu.regFile.ReadReg(rs)
+	old := lsu.memory.Read64(addr)
+	if old == expected {
+		value := lsu.regFile.ReadReg(rt)
+		lsu.memory.Write64(addr, value)
+		lsu.invalidateIfOverlaps(addr, 8)
+		lsu.trace("CAS64", addr, value, 8)
+	}
+	lsu.regFile.WriteReg(rs, old)
+}
+
+// CASA64 is CAS64 with acquire semantics.
+func (lsu *LoadStoreUnit) CASA64(rs, rt, rn uint8) { lsu.CAS64(rs, rt, rn) }
+
+// CASL64 is CAS64 with release semantics.
+func (lsu *LoadStoreUnit) CASL64(rs, rt, rn uint8) { lsu.CAS64(rs, rt, rn) }
+
+// CASAL64 is CAS64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) CASAL64(rs, rt, rn uint8) { lsu.CAS64(rs, rt, rn) }
+
+// CAS32 is CAS64's 32-bit (Ws/Wt) form, comparing and swapping the low
+// 32 bits of Xn's target and zero-extending the returned old value.
+func (lsu *LoadStoreUnit) CAS32(rs, rt, rn uint8) {
+	addr := lsu.regFile.ReadReg(rn)
+	expected := uint32(lsu.regFile.ReadReg(rs))
+	old := lsu.memory.Read32(addr)
+	if old == expected {
+		value := uint32(lsu.regFile.ReadReg(rt))
+		lsu.memory.Write32(addr, value)
+		lsu.invalidateIfOverlaps(addr, 4)
+		lsu.trace("CAS32", addr, uint64(value), 4)
+	}
+	lsu.regFile.WriteReg(rs, uint64(old))
+}
+
+// CASA32 is CAS32 with acquire semantics.
+func (lsu *LoadStoreUnit) CASA32(rs, rt, rn uint8) { lsu.CAS32(rs, rt, rn) }
+
+// CASL32 is CAS32 with release semantics.
+func (lsu *LoadStoreUnit) CASL32(rs, rt, rn uint8) { lsu.CAS32(rs, rt, rn) }
+
+// CASAL32 is CAS32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) CASAL32(rs, rt, rn uint8) { lsu.CAS32(rs, rt, rn) }
+
+// atomicOp64 is the read-modify-write core of the LDADD/LDCLR/LDEOR/LDSET/
+// SWP 64-bit family: it reads the doubleword at [Xn], combines it with Xs
+// via combine, writes the result back, and returns the value mem[Xn] held
+// before the update in Xt (the semantics all five share — SWP's combine
+// just discards old and returns the new value being swapped in).
+func (lsu *LoadStoreUnit) atomicOp64(op string, rs, rt, rn uint8, combine func(old, operand uint64) uint64) {
+	addr := lsu.regFile.ReadReg(rn)
+	operand := lsu.regFile.ReadReg(rs)
+	old := lsu.memory.Read64(addr)
+	result := combine(old, operand)
+	lsu.memory.Write64(addr, result)
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.regFile.WriteReg(rt, old)
+	lsu.trace(op, addr, result, 8)
+}
+
+// atomicOp32 is atomicOp64 for the 32-bit (Wn) family, zero-extending the
+// returned old value the same way LDR32 does.
+func (lsu *LoadStoreUnit) atomicOp32(op string, rs, rt, rn uint8, combine func(old, operand uint32) uint32) {
+	addr := lsu.regFile.ReadReg(rn)
+	operand := uint32(lsu.regFile.ReadReg(rs))
+	old := lsu.memory.Read32(addr)
+	result := combine(old, operand)
+	lsu.memory.Write32(addr, result)
+	lsu.invalidateIfOverlaps(addr, 4)
+	lsu.regFile.WriteReg(rt, uint64(old))
+	lsu.trace(op, addr, uint64(result), 4)
+}
+
+// The acquire (A), release (L) and acquire-release (AL) suffixes below
+// are modeled as plain aliases of the relaxed form: this emulator runs one
+// instruction at a time with no store buffering or reordering to order
+// against, so there is nothing for the ordering variants to do that the
+// relaxed form doesn't already do.
+
+// LDADD64 atomically does Xt = mem[Xn]; mem[Xn] += Xs.
+func (lsu *LoadStoreUnit) LDADD64(rs, rt, rn uint8) {
+	lsu.atomicOp64("LDADD64", rs, rt, rn, func(old, operand uint64) uint64 { return old + operand })
+}
+
+// LDADDA64 is LDADD64 with acquire semantics.
+func (lsu *LoadStoreUnit) LDADDA64(rs, rt, rn uint8) { lsu.LDADD64(rs, rt, rn) }
+
+// LDADDL64 is LDADD64 with release semantics.
+func (lsu *LoadStoreUnit) LDADDL64(rs, rt, rn uint8) { lsu.LDADD64(rs, rt, rn) }
+
+// LDADDAL64 is LDADD64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDADDAL64(rs, rt, rn uint8) { lsu.LDADD64(rs, rt, rn) }
+
+// LDCLR64 atomically does Xt = mem[Xn]; mem[Xn] &^= Xs (bit clear).
+func (lsu *LoadStoreUnit) LDCLR64(rs, rt, rn uint8) {
+	lsu.atomicOp64("LDCLR64", rs, rt, rn, func(old, operand uint64) uint64 { return old &^ operand })
+}
+
+// LDCLRA64 is LDCLR64 with acquire semantics.
+func (lsu *LoadStoreUnit) LDCLRA64(rs, rt, rn uint8) { lsu.LDCLR64(rs, rt, rn) }
+
+// LDCLRL64 is LDCLR64 with release semantics.
+func (lsu *LoadStoreUnit) LDCLRL64(rs, rt, rn uint8) { lsu.LDCLR64(rs, rt, rn) }
+
+// LDCLRAL64 is LDCLR64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDCLRAL64(rs, rt, rn uint8) { lsu.LDCLR64(rs, rt, rn) }
+
+// LDEOR64 atomically does Xt = mem[Xn]; mem[Xn] ^= Xs.
+func (lsu *LoadStoreUnit) LDEOR64(rs, rt, rn uint8) {
+	lsu.atomicOp64("LDEOR64", rs, rt, rn, func(old, operand uint64) uint64 { return old ^ operand })
+}
+
+// LDEORA64 is LDEOR64 with acquire semantics.
+func (lsu *LoadStoreUnit) LDEORA64(rs, rt, rn uint8) { lsu.LDEOR64(rs, rt, rn) }
+
+// LDEORL64 is LDEOR64 with release semantics.
+func (lsu *LoadStoreUnit) LDEORL64(rs, rt, rn uint8) { lsu.LDEOR64(rs, rt, rn) }
+
+// LDEORAL64 is LDEOR64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDEORAL64(rs, rt, rn uint8) { lsu.LDEOR64(rs, rt, rn) }
+
+// LDSET64 atomically does Xt = mem[Xn]; mem[Xn] |= Xs (bit set).
+func (lsu *LoadStoreUnit) LDSET64(rs, rt, rn uint8) {
+	lsu.atomicOp64("LDSET64", rs, rt, rn, func(old, operand uint64) uint64 { return old | operand })
+}
+
+// LDSETA64 is LDSET64 with acquire semantics.
+func (lsu *LoadStoreUnit) LDSETA64(rs, rt, rn uint8) { lsu.LDSET64(rs, rt, rn) }
+
+// LDSETL64 is LDSET64 with release semantics.
+func (lsu *LoadStoreUnit) LDSETL64(rs, rt, rn uint8) { lsu.LDSET64(rs, rt, rn) }
+
+// LDSETAL64 is LDSET64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDSETAL64(rs, rt, rn uint8) { lsu.LDSET64(rs, rt, rn) }
+
+// SWP64 atomically does Xt = mem[Xn]; mem[Xn] = Xs.
+func (lsu *LoadStoreUnit) SWP64(rs, rt, rn uint8) {
+	lsu.atomicOp64("SWP64", rs, rt, rn, func(_, operand uint64) uint64 { return operand })
+}
+
+// SWPA64 is SWP64 with acquire semantics.
+func (lsu *LoadStoreUnit) SWPA64(rs, rt, rn uint8) { lsu.SWP64(rs, rt, rn) }
+
+// SWPL64 is SWP64 with release semantics.
+func (lsu *LoadStoreUnit) SWPL64(rs, rt, rn uint8) { lsu.SWP64(rs, rt, rn) }
+
+// SWPAL64 is SWP64 with acquire-release semantics.
+func (lsu *LoadStoreUnit) SWPAL64(rs, rt, rn uint8) { lsu.SWP64(rs, rt, rn) }
+
+// LDADD32 is LDADD64's 32-bit (Ws/Wt) form.
+func (lsu *LoadStoreUnit) LDADD32(rs, rt, rn uint8) {
+	lsu.atomicOp32("LDADD32", rs, rt, rn, func(old, operand uint32) uint32 { return old + operand })
+}
+
+// LDADDA32 is LDADD32 with acquire semantics.
+func (lsu *LoadStoreUnit) LDADDA32(rs, rt, rn uint8) { lsu.LDADD32(rs, rt, rn) }
+
+// LDADDL32 is LDADD32 with release semantics.
+func (lsu *LoadStoreUnit) LDADDL32(rs, rt, rn uint8) { lsu.LDADD32(rs, rt, rn) }
+
+// LDADDAL32 is LDADD32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDADDAL32(rs, rt, rn uint8) { lsu.LDADD32(rs, rt, rn) }
+
+// LDCLR32 is LDCLR64's 32-bit (Ws/Wt) form.
+func (lsu *LoadStoreUnit) LDCLR32(rs, rt, rn uint8) {
+	lsu.atomicOp32("LDCLR32", rs, rt, rn, func(old, operand uint32) uint32 { return old &^ operand })
+}
+
+// LDCLRA32 is LDCLR32 with acquire semantics.
+func (lsu *LoadStoreUnit) LDCLRA32(rs, rt, rn uint8) { lsu.LDCLR32(rs, rt, rn) }
+
+// LDCLRL32 is LDCLR32 with release semantics.
+func (lsu *LoadStoreUnit) LDCLRL32(rs, rt, rn uint8) { lsu.LDCLR32(rs, rt, rn) }
+
+// LDCLRAL32 is LDCLR32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDCLRAL32(rs, rt, rn uint8) { lsu.LDCLR32(rs, rt, rn) }
+
+// LDEOR32 is LDEOR64's 32-bit (Ws/Wt) form.
+func (lsu *LoadStoreUnit) LDEOR32(rs, rt, rn uint8) {
+	lsu.atomicOp32("LDEOR32", rs, rt, rn, func(old, operand uint32) uint32 { return old ^ operand })
+}
+
+// LDEORA32 is LDEOR32 with acquire semantics.
+func (lsu *LoadStoreUnit) LDEORA32(rs, rt, rn uint8) { lsu.LDEOR32(rs, rt, rn) }
+
+// LDEORL32 is LDEOR32 with release semantics.
+func (lsu *LoadStoreUnit) LDEORL32(rs, rt, rn uint8) { lsu.LDEOR32(rs, rt, rn) }
+
+// LDEORAL32 is LDEOR32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDEORAL32(rs, rt, rn uint8) { lsu.LDEOR32(rs, rt, rn) }
+
+// LDSET32 is LDSET64's 32-bit (Ws/Wt) form.
+func (lsu *LoadStoreUnit) LDSET32(rs, rt, rn uint8) {
+	lsu.atomicOp32("LDSET32", rs, rt, rn, func(old, operand uint32) uint32 { return old | operand })
+}
+
+// LDSETA32 is LDSET32 with acquire semantics.
+func (lsu *LoadStoreUnit) LDSETA32(rs, rt, rn uint8) { lsu.LDSET32(rs, rt, rn) }
+
+// LDSETL32 is LDSET32 with release semantics.
+func (lsu *LoadStoreUnit) LDSETL32(rs, rt, rn uint8) { lsu.LDSET32(rs, rt, rn) }
+
+// LDSETAL32 is LDSET32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) LDSETAL32(rs, rt, rn uint8) { lsu.LDSET32(rs, rt, rn) }
+
+// SWP32 is SWP64's 32-bit (Ws/Wt) form.
+func (lsu *LoadStoreUnit) SWP32(rs, rt, rn uint8) {
+	lsu.atomicOp32("SWP32", rs, rt, rn, func(_, operand uint32) uint32 { return operand })
+}
+
+// SWPA32 is SWP32 with acquire semantics.
+func (lsu *LoadStoreUnit) SWPA32(rs, rt, rn uint8) { lsu.SWP32(rs, rt, rn) }
+
+// SWPL32 is SWP32 with release semantics.
+func (lsu *LoadStoreUnit) SWPL32(rs, rt, rn uint8) { lsu.SWP32(rs, rt, rn) }
+
+// SWPAL32 is SWP32 with acquire-release semantics.
+func (lsu *LoadStoreUnit) SWPAL32(rs, rt, rn uint8) { lsu.SWP32(rs, rt, rn) }
@@ -0,0 +1,297 @@
+package emu_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("LoadStoreUnit pair and exclusive/atomic families", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		lsu     *emu.LoadStoreUnit
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		lsu = emu.NewLoadStoreUnit(regFile, memory)
+	})
+
+	Describe("LDP64/STP64 SP-relative variants", func() {
+		It("should load a pair from SP-relative offset", func() {
+			regFile.SP = 0x2000
+			memory.Write64(0x2000, 0x1111111111111111)
+			memory.Write64(0x2008, 0x2222222222222222)
+
+			lsu.LDP64SP(0, 1, 0)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x1111111111111111)))
+			Expect(regFile.ReadReg(1)).To(Equal(uint64(0x2222222222222222)))
+		})
+
+		It("should store a pair pre-indexed, matching the stp x29,x30,[sp,#-16]! prologue", func() {
+			regFile.SP = 0x3000
+			regFile.WriteReg(29, 0xAAAA)
+			regFile.WriteReg(30, 0xBBBB)
+
+			lsu.STP64SPPreIndex(29, 30, -16)
+
+			Expect(regFile.SP).To(Equal(uint64(0x2FF0)))
+			Expect(memory.Read64(0x2FF0)).To(Equal(uint64(0xAAAA)))
+			Expect(memory.Read64(0x2FF8)).To(Equal(uint64(0xBBBB)))
+		})
+
+		It("should load a pair post-indexed, matching the ldp x29,x30,[sp],#16 epilogue", func() {
+			regFile.SP = 0x2FF0
+			memory.Write64(0x2FF0, 0xAAAA)
+			memory.Write64(0x2FF8, 0xBBBB)
+
+			lsu.LDP64SPPostIndex(29, 30, 16)
+
+			Expect(regFile.ReadReg(29)).To(Equal(uint64(0xAAAA)))
+			Expect(regFile.ReadReg(30)).To(Equal(uint64(0xBBBB)))
+			Expect(regFile.SP).To(Equal(uint64(0x3000)))
+		})
+	})
+
+	Describe("LDP32/STP32", func() {
+		It("should load a 32-bit pair with zero extension", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write32(0x1000, 0xDEADBEEF)
+			memory.Write32(0x1004, 0xCAFEBABE)
+
+			lsu.LDP32(0, 2, 1, 0)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xDEADBEEF)))
+			Expect(regFile.ReadReg(2)).To(Equal(uint64(0xCAFEBABE)))
+		})
+
+		It("should store a 32-bit pair post-indexed", func() {
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(0, 0x11111111)
+			regFile.WriteReg(2, 0x22222222)
+
+			lsu.STP32PostIndex(0, 2, 1, 8)
+
+			Expect(memory.Read32(0x1000)).To(Equal(uint32(0x11111111)))
+			Expect(memory.Read32(0x1004)).To(Equal(uint32(0x22222222)))
+			Expect(regFile.ReadReg(1)).To(Equal(uint64(0x1008)))
+		})
+	})
+
+	Describe("LDXR64/STXR64", func() {
+		It("should succeed when nothing touches the reserved granule", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0)
+
+			lsu.LDXR64(0, 1)
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR64(3, 2, 1)
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(0))) // success
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(0x42)))
+		})
+
+		It("should fail when an intervening store overlaps the reserved granule", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0)
+
+			lsu.LDXR64(0, 1)
+			lsu.STR64(0, 1, 0) // a plain store to the reserved address
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR64(3, 2, 1)
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(1))) // failure
+		})
+
+		It("should clear the monitor after STXR64 regardless of outcome", func() {
+			regFile.WriteReg(1, 0x1000)
+
+			lsu.LDXR64(0, 1)
+			lsu.STXR64(2, 0, 1) // first STXR64 succeeds and clears the monitor
+			lsu.STXR64(3, 0, 1) // second has no open reservation
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("atomics", func() {
+		It("should perform LDADD64 and return the pre-update value", func() {
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(2, 5)
+			memory.Write64(0x1000, 10)
+
+			lsu.LDADD64(2, 0, 1)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(10))) // old value
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(15)))
+		})
+
+		It("should perform LDSET32 as a bitwise OR", func() {
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(2, 0x0F)
+			memory.Write32(0x1000, 0xF0)
+
+			lsu.LDSET32(2, 0, 1)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xF0)))
+			Expect(memory.Read32(0x1000)).To(Equal(uint32(0xFF)))
+		})
+
+		It("should perform SWP64 unconditionally, discarding the old value", func() {
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(2, 0xCAFE)
+			memory.Write64(0x1000, 0xBEEF)
+
+			lsu.SWP64(2, 0, 1)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xBEEF)))
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(0xCAFE)))
+		})
+
+		It("should invalidate an open exclusive monitor, same as any other store", func() {
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(2, 1)
+
+			lsu.LDXR64(0, 1)
+			lsu.LDADD64(2, 3, 1) // atomic store to the reserved granule
+			regFile.WriteReg(4, 0x99)
+			lsu.STXR64(5, 4, 1)
+
+			Expect(regFile.ReadReg(5)).To(Equal(uint64(1))) // failure
+		})
+	})
+
+	Describe("LDXR32/STXR32", func() {
+		It("should succeed when nothing touches the reserved granule", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write32(0x1000, 0)
+
+			lsu.LDXR32(0, 1)
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR32(3, 2, 1)
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(0))) // success
+			Expect(memory.Read32(0x1000)).To(Equal(uint32(0x42)))
+		})
+
+		It("should fail when an intervening store overlaps the reserved granule", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write32(0x1000, 0)
+
+			lsu.LDXR32(0, 1)
+			lsu.STR32(0, 1, 0) // a plain store to the reserved address
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR32(3, 2, 1)
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(1))) // failure
+		})
+
+		It("should not be satisfied by a reservation of a different size at the same address", func() {
+			regFile.WriteReg(1, 0x1000)
+
+			lsu.LDXR64(0, 1) // opens an 8-byte reservation
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR32(3, 2, 1) // asks for a 4-byte one
+
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(1))) // failure
+		})
+	})
+
+	Describe("LDAR/STLR", func() {
+		It("should load like a plain LDR64, opening no exclusive monitor", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xDEADBEEF)
+
+			lsu.LDAR64(0, 1)
+			regFile.WriteReg(2, 0x42)
+			lsu.STXR64(3, 2, 1) // no reservation was opened by LDAR64
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xDEADBEEF)))
+			Expect(regFile.ReadReg(3)).To(Equal(uint64(1))) // failure
+		})
+
+		It("should store like a plain STR64, invalidating any open monitor", func() {
+			regFile.WriteReg(1, 0x1000)
+
+			lsu.LDXR64(0, 1)
+			regFile.WriteReg(2, 0xCAFE)
+			lsu.STLR64(2, 1)
+			regFile.WriteReg(3, 0x42)
+			lsu.STXR64(4, 3, 1)
+
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(0xCAFE)))
+			Expect(regFile.ReadReg(4)).To(Equal(uint64(1))) // failure: monitor was cleared
+		})
+
+		It("should zero-extend LDAR32 and only write the low word with STLR32", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xFFFFFFFFFFFFFFFF)
+			memory.Write32(0x1000, 0xDEADBEEF)
+
+			lsu.LDAR32(0, 1)
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xDEADBEEF)))
+
+			regFile.WriteReg(2, 0x11111111)
+			lsu.STLR32(2, 1)
+
+			Expect(memory.Read32(0x1000)).To(Equal(uint32(0x11111111)))
+			Expect(memory.Read32(0x1004)).To(Equal(uint32(0xFFFFFFFF))) // untouched
+		})
+	})
+
+	Describe("CAS", func() {
+		It("should swap in the new value and return the old one when the comparison matches", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xAAAA)
+			regFile.WriteReg(2, 0xAAAA) // expected
+			regFile.WriteReg(3, 0xBBBB) // new value
+
+			lsu.CAS64(2, 3, 1)
+
+			Expect(regFile.ReadReg(2)).To(Equal(uint64(0xAAAA))) // old value == expected: success
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(0xBBBB)))
+		})
+
+		It("should leave memory untouched and return the actual value on mismatch", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xAAAA)
+			regFile.WriteReg(2, 0x9999) // wrong expected value
+			regFile.WriteReg(3, 0xBBBB)
+
+			lsu.CAS64(2, 3, 1)
+
+			Expect(regFile.ReadReg(2)).To(Equal(uint64(0xAAAA))) // actual value: caller sees the mismatch
+			Expect(memory.Read64(0x1000)).To(Equal(uint64(0xAAAA)))
+		})
+
+		It("should invalidate an open exclusive monitor on success, same as any other store", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xAAAA)
+			regFile.WriteReg(2, 0xAAAA)
+			regFile.WriteReg(3, 0xBBBB)
+
+			lsu.LDXR64(0, 1)
+			lsu.CAS64(2, 3, 1)
+			regFile.WriteReg(4, 0x42)
+			lsu.STXR64(5, 4, 1)
+
+			Expect(regFile.ReadReg(5)).To(Equal(uint64(1))) // failure
+		})
+
+		It("should compare and swap CAS32's low word with zero extension", func() {
+			regFile.WriteReg(1, 0x1000)
+			memory.Write32(0x1000, 0x1111)
+			regFile.WriteReg(2, 0x1111)
+			regFile.WriteReg(3, 0x2222)
+
+			lsu.CAS32(2, 3, 1)
+
+			Expect(regFile.ReadReg(2)).To(Equal(uint64(0x1111)))
+			Expect(memory.Read32(0x1000)).To(Equal(uint32(0x2222)))
+		})
+	})
+})
@@ -0,0 +1,199 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+// FPU is the execution unit Emulator.execute dispatches to for the FP/SIMD
+// encoding classes (FormatFPDPImm, FormatFPDPReg, FormatFPLoadStore,
+// FormatFPConvert, FormatSIMDVector). It delegates the actual arithmetic
+// to a VALU operating on the same RegFile's V registers, the same
+// division of labor ALU has with the integer formats; unlike ALU, FPU
+// also owns scalar load/store addressing itself (mirroring
+// LoadStoreUnit's Xn/SP-relative addressing) since the FP/SIMD decoder
+// groups load/store under the same format classes as the arithmetic.
+type FPU struct {
+	valu    *VALU
+	regFile *RegFile
+	memory  MemAccessor
+}
+
+// NewFPU creates an FPU operating on regFile's V registers and memory.
+// memory is typically a *Memory, or a *Bus if the emulator has MMIO
+// devices attached.
+func NewFPU(regFile *RegFile, memory MemAccessor) *FPU {
+	return &FPU{
+		valu:    NewVALU(regFile),
+		regFile: regFile,
+		memory:  memory,
+	}
+}
+
+// FMOVImm writes an already-decoded immediate bit pattern into Vd at the
+// given precision.
+func (f *FPU) FMOVImm(rd uint8, bits uint64, isDouble bool) {
+	if isDouble {
+		f.valu.FMOVImmD(rd, bits)
+	} else {
+		f.valu.FMOVImmS(rd, uint32(bits))
+	}
+}
+
+// FMOV copies Vn to Vd at the given precision (register-to-register form).
+func (f *FPU) FMOV(rd, rn uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FMOVD(rd, rn)
+	} else {
+		f.valu.FMOVS(rd, rn)
+	}
+}
+
+// FADD computes Vd = Vn + Vm at the given precision.
+func (f *FPU) FADD(rd, rn, rm uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FADD64(rd, rn, rm)
+	} else {
+		f.valu.FADD32(rd, rn, rm)
+	}
+}
+
+// FSUB computes Vd = Vn - Vm at the given precision.
+func (f *FPU) FSUB(rd, rn, rm uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FSUB64(rd, rn, rm)
+	} else {
+		f.valu.FSUB32(rd, rn, rm)
+	}
+}
+
+// FMUL computes Vd = Vn * Vm at the given precision.
+func (f *FPU) FMUL(rd, rn, rm uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FMUL64(rd, rn, rm)
+	} else {
+		f.valu.FMUL32(rd, rn, rm)
+	}
+}
+
+// FDIV computes Vd = Vn / Vm at the given precision.
+func (f *FPU) FDIV(rd, rn, rm uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FDIV64(rd, rn, rm)
+	} else {
+		f.valu.FDIV32(rd, rn, rm)
+	}
+}
+
+// FCMP compares Vn against Vm at the given precision and sets PSTATE.
+func (f *FPU) FCMP(rn, rm uint8, isDouble bool) {
+	if isDouble {
+		f.valu.FCMP64(rn, rm)
+	} else {
+		f.valu.FCMP32(rn, rm)
+	}
+}
+
+// FCVT converts Vn from srcIsDouble's precision to the other precision,
+// writing the result to Vd.
+func (f *FPU) FCVT(rd, rn uint8, srcIsDouble bool) {
+	if srcIsDouble {
+		f.valu.FCVTDToS(rd, rn)
+	} else {
+		f.valu.FCVTSToD(rd, rn)
+	}
+}
+
+// FCVTZS converts Vn to a signed integer, rounding toward zero, and
+// writes it to Xd/Wd.
+func (f *FPU) FCVTZS(rd uint8, rn uint8, isDouble, is64Bit bool) {
+	var result int64
+	if isDouble {
+		result = f.valu.FCVTZS64(rn)
+	} else {
+		result = int64(f.valu.FCVTZS32(rn))
+	}
+	if !is64Bit {
+		result = int64(int32(result))
+	}
+	f.regFile.WriteReg(rd, uint64(result))
+}
+
+// SCVTF converts Xn/Wn to floating-point and writes it to Vd.
+func (f *FPU) SCVTF(rd uint8, src int64, isDouble, is64Bit bool) {
+	if !is64Bit {
+		src = int64(int32(src))
+	}
+	if isDouble {
+		f.valu.SCVTF64(rd, src)
+	} else {
+		f.valu.SCVTF32(rd, int32(src))
+	}
+}
+
+// LDR loads a scalar S or D register from memory: Vd = mem[Xn + offset].
+func (f *FPU) LDR(rd, rn uint8, offset uint64, isDouble bool) {
+	addr := f.regFile.ReadReg(rn) + offset
+	f.loadFrom(rd, addr, isDouble)
+}
+
+// LDRSP is LDR using SP as the base register.
+func (f *FPU) LDRSP(rd uint8, offset uint64, isDouble bool) {
+	f.loadFrom(rd, f.regFile.SP+offset, isDouble)
+}
+
+func (f *FPU) loadFrom(rd uint8, addr uint64, isDouble bool) {
+	if isDouble {
+		f.regFile.WriteD(rd, f.memory.Read64(addr))
+	} else {
+		f.regFile.WriteS(rd, f.memory.Read32(addr))
+	}
+}
+
+// STR stores a scalar S or D register to memory: mem[Xn + offset] = Vd.
+func (f *FPU) STR(rd, rn uint8, offset uint64, isDouble bool) {
+	addr := f.regFile.ReadReg(rn) + offset
+	f.storeTo(rd, addr, isDouble)
+}
+
+// STRSP is STR using SP as the base register.
+func (f *FPU) STRSP(rd uint8, offset uint64, isDouble bool) {
+	f.storeTo(rd, f.regFile.SP+offset, isDouble)
+}
+
+func (f *FPU) storeTo(rd uint8, addr uint64, isDouble bool) {
+	if isDouble {
+		f.memory.Write64(addr, f.regFile.ReadD(rd))
+	} else {
+		f.memory.Write32(addr, f.regFile.ReadS(rd))
+	}
+}
+
+// VectorADD computes Vd = Vn + Vm lane-by-lane, per arr (e.g. ADD.4s).
+func (f *FPU) VectorADD(rd, rn, rm uint8, arr Arrangement) {
+	f.valu.VADD(rd, rn, rm, arr)
+}
+
+// VectorFMUL computes Vd = Vn * Vm lane-by-lane as floats, per arr (e.g.
+// FMUL.2d). Unlike VectorADD, this interprets each lane as a float of the
+// matching width rather than a plain integer.
+func (f *FPU) VectorFMUL(rd, rn, rm uint8, arr Arrangement) {
+	switch arr {
+	case Arrangement2D:
+		for _, lane := range [2]uint8{0, 1} {
+			f.vectorFMULLane64(rd, rn, rm, lane)
+		}
+	case Arrangement4S:
+		for _, lane := range [4]uint8{0, 1, 2, 3} {
+			f.vectorFMULLane32(rd, rn, rm, lane)
+		}
+	}
+}
+
+func (f *FPU) vectorFMULLane64(rd, rn, rm, lane uint8) {
+	a := f.valu.laneAsD(rn, lane)
+	b := f.valu.laneAsD(rm, lane)
+	f.valu.writeLaneD(rd, lane, a*b)
+}
+
+func (f *FPU) vectorFMULLane32(rd, rn, rm, lane uint8) {
+	a := f.valu.laneAsS(rn, lane)
+	b := f.valu.laneAsS(rm, lane)
+	f.valu.writeLaneS(rd, lane, a*b)
+}
@@ -0,0 +1,122 @@
+package emu_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("FPU", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		fpu     *emu.FPU
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		fpu = emu.NewFPU(regFile, memory)
+	})
+
+	Describe("scalar arithmetic", func() {
+		It("should add two doubles", func() {
+			regFile.WriteD(1, math.Float64bits(1.5))
+			regFile.WriteD(2, math.Float64bits(2.5))
+
+			fpu.FADD(0, 1, 2, true)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(4.0))
+		})
+
+		It("should multiply two singles", func() {
+			regFile.WriteS(1, math.Float32bits(2))
+			regFile.WriteS(2, math.Float32bits(3))
+
+			fpu.FMUL(0, 1, 2, false)
+
+			Expect(math.Float32frombits(regFile.ReadS(0))).To(Equal(float32(6)))
+		})
+	})
+
+	Describe("FCVT", func() {
+		It("should widen a single to a double", func() {
+			regFile.WriteS(1, math.Float32bits(1.25))
+
+			fpu.FCVT(0, 1, false)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(1.25))
+		})
+
+		It("should narrow a double to a single", func() {
+			regFile.WriteD(1, math.Float64bits(2.5))
+
+			fpu.FCVT(0, 1, true)
+
+			Expect(math.Float32frombits(regFile.ReadS(0))).To(Equal(float32(2.5)))
+		})
+	})
+
+	Describe("FCVTZS/SCVTF", func() {
+		It("should convert a signed integer to a double and back", func() {
+			fpu.SCVTF(0, -12, true, true)
+
+			fpu.FCVTZS(1, 0, true, true)
+
+			Expect(regFile.ReadReg(1)).To(Equal(uint64(0xFFFFFFFFFFFFFFF4))) // -12
+		})
+	})
+
+	Describe("scalar load/store", func() {
+		It("should store and load a double through memory", func() {
+			regFile.WriteReg(1, 0x2000)
+			regFile.WriteD(0, math.Float64bits(42.5))
+
+			fpu.STR(0, 1, 0, true)
+			fpu.LDR(2, 1, 0, true)
+
+			Expect(math.Float64frombits(regFile.ReadD(2))).To(Equal(42.5))
+		})
+
+		It("should store and load a single using SP as base", func() {
+			regFile.SP = 0x3000
+			regFile.WriteS(0, math.Float32bits(-1.5))
+
+			fpu.STRSP(0, 0, false)
+			fpu.LDRSP(3, 0, false)
+
+			Expect(math.Float32frombits(regFile.ReadS(3))).To(Equal(float32(-1.5)))
+		})
+	})
+
+	Describe("vector ops", func() {
+		It("should add four 32-bit lanes", func() {
+			var a, b [16]byte
+			for i := 0; i < 4; i++ {
+				a[i*4] = byte(i)
+				b[i*4] = 1
+			}
+			regFile.WriteV(1, a)
+			regFile.WriteV(2, b)
+
+			fpu.VectorADD(0, 1, 2, emu.Arrangement4S)
+
+			out := regFile.ReadV(0)
+			for i := 0; i < 4; i++ {
+				Expect(out[i*4]).To(Equal(byte(i + 1)))
+			}
+		})
+
+		It("should multiply two 2D lanes as doubles", func() {
+			regFile.WriteD(1, math.Float64bits(2))
+			regFile.WriteD(2, math.Float64bits(3))
+
+			fpu.VectorFMUL(0, 1, 2, emu.Arrangement2D)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(6.0))
+		})
+	})
+})
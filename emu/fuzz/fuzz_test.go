@@ -0,0 +1,88 @@
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/emu/fuzz"
+)
+
+// boundaryOperands are the operand values the request this test was added
+// for calls out by name: the values most likely to expose an off-by-one
+// in carry/overflow logic.
+var boundaryOperands = []uint64{
+	0,
+	1,
+	0xFFFFFFFFFFFFFFFF, // -1
+	0x8000000000000000, // INT64_MIN
+	0xFFFFFFFFFFFFFFFF, // UINT64_MAX (same bit pattern as -1, kept for clarity at call sites)
+}
+
+// FuzzCCMPFlags checks every CCMP/CCMN encoding the fuzz engine discovers
+// (seeded below with every condition x sf x isImm combination over the
+// boundary operands) against PureGoBackend. A failure here means the
+// emulator's NZCV computation disagrees with the ARM ARM pseudocode for
+// some input neither condcmp_test.go nor condselect_test.go happened to
+// enumerate by hand.
+func FuzzCCMPFlags(f *testing.F) {
+	for cond := emu.ConditionCode(0); cond < 16; cond++ {
+		for _, sf := range []bool{false, true} {
+			for _, isImm := range []bool{false, true} {
+				for _, rn := range boundaryOperands {
+					for _, rm := range boundaryOperands {
+						f.Add(uint8(cond), sf, isImm, true, rn, rm, uint8(0), uint8(0b0000))
+						f.Add(uint8(cond), sf, isImm, false, rn, rm, uint8(0), uint8(0b1010))
+					}
+				}
+			}
+		}
+	}
+
+	oracle := fuzz.NewFlagOracle(fuzz.NewPureGoBackend())
+
+	f.Fuzz(func(t *testing.T, condRaw uint8, sf, isImm, isCCMP bool, rn, rm uint64, flagsIn, nzcv uint8) {
+		cond := emu.ConditionCode(condRaw % 16)
+
+		word := encodeCondCmpWord(cond, nzcv, sf, isImm, isCCMP)
+
+		initial := fuzz.State{}
+		initial.Regs[1] = rn
+		initial.Regs[2] = rm
+		initial.PSTATE = emu.PSTATE{
+			N: flagsIn&0b1000 != 0,
+			Z: flagsIn&0b0100 != 0,
+			C: flagsIn&0b0010 != 0,
+			V: flagsIn&0b0001 != 0,
+		}
+
+		mismatch, err := oracle.Check(word, initial)
+		if err != nil {
+			t.Skip(err)
+		}
+		if mismatch != nil {
+			t.Fatalf("flag mismatch for cond=%d sf=%v isImm=%v isCCMP=%v rn=%#x rm=%#x: got %+v want %+v",
+				cond, sf, isImm, isCCMP, rn, rm, mismatch.Got, mismatch.Want)
+		}
+	})
+}
+
+// encodeCondCmpWord builds a CCMP/CCMN register-form-or-immediate-form
+// encoding with Rn=1, Rm=2 — the immediate form reuses Rm's bit field as
+// a 5-bit unsigned immediate, which these boundary-operand seeds don't
+// specifically target, but the fuzzer is free to explore once seeded.
+func encodeCondCmpWord(cond emu.ConditionCode, nzcv uint8, sf, isImm, isCCMP bool) uint32 {
+	var sfBit, immBit, op uint32
+	if sf {
+		sfBit = 1
+	}
+	if isImm {
+		immBit = 1
+	}
+	if isCCMP {
+		op = 1
+	}
+	const rn, rm = 1, 2
+	return (sfBit << 31) | (op << 30) | (1 << 29) | (0b11010010 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (immBit << 11) |
+		(uint32(rn) << 5) | uint32(nzcv&0b1111)
+}
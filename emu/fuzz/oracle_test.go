@@ -0,0 +1,100 @@
+package fuzz_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/emu/fuzz"
+)
+
+func encodeCCMP(rn, rm uint8, cond emu.ConditionCode, nzcv uint8, is64Bit, isImm bool) uint32 {
+	var sf, immBit uint32
+	if is64Bit {
+		sf = 1
+	}
+	if isImm {
+		immBit = 1
+	}
+	return (sf << 31) | (1 << 30) | (1 << 29) | (0b11010010 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (immBit << 11) |
+		(uint32(rn) << 5) | uint32(nzcv)
+}
+
+func encodeCCMN(rn, rm uint8, cond emu.ConditionCode, nzcv uint8, is64Bit, isImm bool) uint32 {
+	var sf, immBit uint32
+	if is64Bit {
+		sf = 1
+	}
+	if isImm {
+		immBit = 1
+	}
+	return (sf << 31) | (0 << 30) | (1 << 29) | (0b11010010 << 21) |
+		(uint32(rm) << 16) | (uint32(cond) << 12) | (immBit << 11) |
+		(uint32(rn) << 5) | uint32(nzcv)
+}
+
+var _ = Describe("FlagOracle with PureGoBackend", func() {
+	var (
+		oracle  *fuzz.FlagOracle
+		initial fuzz.State
+	)
+
+	BeforeEach(func() {
+		oracle = fuzz.NewFlagOracle(fuzz.NewPureGoBackend())
+		initial = fuzz.State{}
+	})
+
+	It("should agree with the emulator on a passing CCMP at the carry boundary", func() {
+		initial.Regs[1] = 0
+		initial.Regs[2] = 1
+		initial.PSTATE = emu.PSTATE{Z: true} // AL-ish: make EQ true
+		word := encodeCCMP(1, 2, emu.CondEQ, 0b0000, true, false)
+
+		mismatch, err := oracle.Check(word, initial)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatch).To(BeNil())
+	})
+
+	It("should agree with the emulator on signed overflow at INT_MIN/INT_MAX boundaries", func() {
+		initial.Regs[1] = 0x7FFFFFFFFFFFFFFF
+		initial.Regs[2] = 0xFFFFFFFFFFFFFFFF // -1
+		initial.PSTATE = emu.PSTATE{Z: true}
+		word := encodeCCMN(1, 2, emu.CondEQ, 0b0000, true, false)
+
+		mismatch, err := oracle.Check(word, initial)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatch).To(BeNil())
+	})
+
+	It("should use the literal nzcv operand when the condition is false", func() {
+		initial.PSTATE = emu.PSTATE{Z: false} // EQ is false
+		word := encodeCCMP(1, 2, emu.CondEQ, 0b1001, true, false)
+
+		mismatch, err := oracle.Check(word, initial)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatch).To(BeNil())
+	})
+
+	It("should report an error for a word outside the conditional-compare block", func() {
+		_, err := oracle.Check(0x00000000, initial)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("NativeBackend", func() {
+	It("should report unavailability rather than executing anything when qemu is absent", func() {
+		backend := fuzz.NewNativeBackend()
+		if backend.Available() {
+			Skip("qemu-aarch64 is present on this host")
+		}
+
+		_, err := backend.Reference(0, fuzz.State{})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
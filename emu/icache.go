@@ -0,0 +1,53 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import "github.com/sarchlab/m2sim/insts"
+
+// DecodedInst is a pre-decoded instruction ready for dispatch: the raw
+// decode from insts.Decoder plus a resolved handler, so RunFast never has
+// to re-parse bitfields or branch on inst.Format/inst.Op at execute time.
+type DecodedInst struct {
+	Inst    *insts.Instruction
+	Handler InstHandler
+}
+
+// InstHandler executes one decoded instruction against the emulator. It
+// returns the StepResult for the instruction, mirroring Emulator.execute's
+// per-case behavior (PC advance is the handler's responsibility, matching
+// how branch instructions already manage their own PC updates).
+type InstHandler func(e *Emulator, d *DecodedInst) StepResult
+
+// ICache caches the decode+dispatch work for each PC seen by RunFast. It
+// is invalidated a cache line at a time when a write lands on executable
+// memory, so self-modifying code is re-decoded on its next fetch.
+type ICache struct {
+	entries map[uint64]*DecodedInst
+}
+
+// NewICache creates an empty instruction cache.
+func NewICache() *ICache {
+	return &ICache{entries: make(map[uint64]*DecodedInst)}
+}
+
+// Lookup returns the cached decode for pc, or nil if it has not been
+// decoded yet (or was invalidated since).
+func (c *ICache) Lookup(pc uint64) *DecodedInst {
+	return c.entries[pc]
+}
+
+// Insert records the decode for pc.
+func (c *ICache) Insert(pc uint64, d *DecodedInst) {
+	c.entries[pc] = d
+}
+
+// Invalidate drops the cached decode, if any, for the 4-byte-aligned
+// instruction slot containing addr. Called when a store targets
+// executable memory.
+func (c *ICache) Invalidate(addr uint64) {
+	delete(c.entries, addr&^3)
+}
+
+// InvalidateAll clears the entire cache, e.g. after Reset or a bulk remap.
+func (c *ICache) InvalidateAll() {
+	c.entries = make(map[uint64]*DecodedInst)
+}
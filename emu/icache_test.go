@@ -0,0 +1,48 @@
+// Package emu provides functional ARM64 emulation.
+package emu_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/insts"
+)
+
+var _ = Describe("ICache", func() {
+	var cache *emu.ICache
+
+	BeforeEach(func() {
+		cache = emu.NewICache()
+	})
+
+	It("should miss on an address that was never inserted", func() {
+		Expect(cache.Lookup(0x1000)).To(BeNil())
+	})
+
+	It("should return what was inserted", func() {
+		d := &emu.DecodedInst{Inst: &insts.Instruction{Op: insts.OpADD}}
+		cache.Insert(0x1000, d)
+
+		Expect(cache.Lookup(0x1000)).To(BeIdenticalTo(d))
+	})
+
+	It("should drop the entry for the 4-byte-aligned slot on Invalidate", func() {
+		d := &emu.DecodedInst{Inst: &insts.Instruction{Op: insts.OpADD}}
+		cache.Insert(0x1000, d)
+
+		cache.Invalidate(0x1002) // a byte write inside the same instruction word
+
+		Expect(cache.Lookup(0x1000)).To(BeNil())
+	})
+
+	It("should clear every entry on InvalidateAll", func() {
+		cache.Insert(0x1000, &emu.DecodedInst{})
+		cache.Insert(0x2000, &emu.DecodedInst{})
+
+		cache.InvalidateAll()
+
+		Expect(cache.Lookup(0x1000)).To(BeNil())
+		Expect(cache.Lookup(0x2000)).To(BeNil())
+	})
+})
@@ -0,0 +1,209 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/sarchlab/m2sim/insts"
+)
+
+// jitMaxBlockInstrs bounds how many straight-line instructions RunJIT
+// will fold into a single compiled block. Benchmark-sized hot loops
+// (a handful of ADD/SUB instructions) are well under this; it exists
+// so a pathological program can't make compileBlock build an
+// unbounded buffer before hitting a block-ending instruction.
+const jitMaxBlockInstrs = 64
+
+// jitBlockKey identifies one cached compiled block by its guest entry
+// PC and a hash of the encoded bytes it covers, so a block that the
+// guest later overwrites (legal, if rare, on ARM64 after an icache
+// invalidation) misses the cache instead of running stale machine
+// code.
+type jitBlockKey struct {
+	pc   uint64
+	hash [32]byte
+}
+
+// jitBlock is one compiled basic block: native machine code in an
+// executable buffer, plus the number of guest instructions it covers
+// so RunJIT can advance PC and InstructionCount by the right amount
+// after one native call.
+type jitBlock struct {
+	buf         *execBuffer
+	guestInstrs int
+}
+
+// jitCache caches compiled blocks for the lifetime of an Emulator. It
+// never evicts: benchmark- and test-sized programs have at most a few
+// hundred distinct blocks, far below what's worth LRU complexity for.
+type jitCache struct {
+	blocks map[jitBlockKey]*jitBlock
+}
+
+func newJITCache() *jitCache {
+	return &jitCache{blocks: make(map[jitBlockKey]*jitBlock)}
+}
+
+func hashBlock(words []uint32) [32]byte {
+	buf := make([]byte, len(words)*4)
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(buf[i*4:], w)
+	}
+	return sha256.Sum256(buf)
+}
+
+// jitEligible reports whether inst can be folded into a JIT-compiled
+// block. Only non-flag-setting 64-bit ADD/SUB (immediate and
+// register forms) are currently supported; everything else — loads,
+// stores, branches, syscalls, 32-bit ops, anything touching XZR, and an
+// immediate form carrying the LSL#12 shift — ends the block and falls
+// back to the normal threaded interpreter for that instruction, the
+// same as a cache miss in RunFast. The shifted-immediate form is
+// rejected rather than folded because compileBlock's emitters encode
+// inst.Imm directly and never apply inst.Shift the way the
+// interpreter's executeDPImm does.
+func jitEligible(inst *insts.Instruction) bool {
+	if !jitSupported {
+		return false
+	}
+	if !inst.Is64Bit || inst.SetFlags {
+		return false
+	}
+	if inst.Op != insts.OpADD && inst.Op != insts.OpSUB {
+		return false
+	}
+	if inst.Rd >= 31 || inst.Rn >= 31 {
+		return false
+	}
+	if inst.Format == insts.FormatDPReg && inst.Rm >= 31 {
+		return false
+	}
+	if inst.Format == insts.FormatDPImm && inst.Shift != 0 {
+		return false
+	}
+	return inst.Format == insts.FormatDPImm || inst.Format == insts.FormatDPReg
+}
+
+// RunJIT executes instructions until the program exits or an error
+// occurs, like RunFast, but additionally folds runs of consecutive
+// ADD/SUB instructions into a single native call: the first time a
+// basic block of eligible instructions is reached, it's assembled into
+// host machine code (see jit_amd64.go/jit_arm64.go), cached by entry
+// PC and a hash of its encoded bytes, and run directly instead of
+// being re-interpreted one instruction at a time on every visit — the
+// way a hot loop body is visited thousands of times by
+// branchHotLoop-style benchmarks.
+//
+// RunJIT only accelerates this functional path; the timing pipeline is
+// untouched and keeps interpreting one instruction at a time the way
+// it always has. On a GOARCH with no emitter table (see
+// jit_fallback.go), jitSupported is false and RunJIT behaves exactly
+// like RunFast.
+func (e *Emulator) RunJIT() int64 {
+	if e.jitCache == nil {
+		e.jitCache = newJITCache()
+	}
+
+	for {
+		if e.maxInstructions > 0 && e.instructionCount >= e.maxInstructions {
+			return -1
+		}
+
+		pc := e.regFile.PC
+		words, insns := e.scanJITBlock(pc)
+		if len(insns) == 0 {
+			// The instruction at pc isn't JIT-eligible (a branch,
+			// syscall, load/store, or an unsupported ALU form); fall
+			// back to the normal threaded interpreter for this one
+			// step, the same instruction handler RunFast uses.
+			result := e.stepFast(pc)
+			if result.Exited {
+				return result.ExitCode
+			}
+			if result.Err != nil {
+				return -1
+			}
+			continue
+		}
+
+		key := jitBlockKey{pc: pc, hash: hashBlock(words)}
+		block := e.jitCache.blocks[key]
+		if block == nil {
+			var err error
+			block, err = compileBlock(insns)
+			if err != nil {
+				// Compilation failed for a reason narrower than
+				// jitEligible already checked for (e.g. the host
+				// code buffer couldn't be allocated); interpret this
+				// block's first instruction and retry from scratch
+				// next time around.
+				result := e.stepFast(pc)
+				if result.Exited {
+					return result.ExitCode
+				}
+				if result.Err != nil {
+					return -1
+				}
+				continue
+			}
+			e.jitCache.blocks[key] = block
+		}
+
+		block.buf.Run(&e.regFile.gpr)
+		e.regFile.PC = pc + uint64(4*block.guestInstrs)
+		e.instructionCount += uint64(block.guestInstrs)
+	}
+}
+
+// scanJITBlock collects the run of consecutive jitEligible instructions
+// starting at pc, stopping at the first ineligible instruction, a
+// decode fault, or jitMaxBlockInstrs. It returns the raw encoded words
+// (for hashBlock) alongside the decoded instructions (for
+// compileBlock); both are empty if the instruction at pc itself isn't
+// eligible.
+func (e *Emulator) scanJITBlock(pc uint64) ([]uint32, []*insts.Instruction) {
+	var words []uint32
+	var insns []*insts.Instruction
+
+	remaining := jitMaxBlockInstrs
+	if e.maxInstructions > 0 {
+		if budget := e.maxInstructions - e.instructionCount; budget < uint64(remaining) {
+			remaining = int(budget)
+		}
+	}
+
+	for i := 0; i < remaining; i++ {
+		word, fault := e.memory.FetchInst(pc + uint64(4*i))
+		if fault != nil {
+			break
+		}
+		inst := e.decoder.Decode(word)
+		if !jitEligible(inst) {
+			break
+		}
+		words = append(words, word)
+		insns = append(insns, inst)
+	}
+	return words, insns
+}
+
+// stepFast executes exactly one instruction at pc through the same
+// icache-backed handler resolution RunFast uses, for the instructions
+// RunJIT can't fold into a compiled block.
+func (e *Emulator) stepFast(pc uint64) StepResult {
+	d := e.icache.Lookup(pc)
+	if d == nil {
+		word, fault := e.memory.FetchInst(pc)
+		if fault != nil {
+			return StepResult{Err: fault}
+		}
+		inst := e.decoder.Decode(word)
+		d = &DecodedInst{Inst: inst, Handler: e.resolveHandler(inst)}
+		e.icache.Insert(pc, d)
+	}
+
+	result := d.Handler(e, d)
+	e.instructionCount++
+	return result
+}
@@ -0,0 +1,119 @@
+//go:build amd64
+
+package emu
+
+import (
+	"fmt"
+
+	"github.com/sarchlab/m2sim/insts"
+)
+
+// jitSupported is true on architectures with an emitter table below
+// (see also jit_arm64.go); jit_fallback.go sets it to false everywhere
+// else.
+const jitSupported = true
+
+// callJITBlock transfers control to the native code at fn, passing
+// gpr as its single argument (the guest general-purpose register
+// file, X0-X30), following the System V AMD64 calling convention:
+// gpr arrives in RDI, the first argument register. Implemented in
+// jit_call_amd64.s.
+//
+//go:noescape
+func callJITBlock(fn uintptr, gpr *[31]uint64)
+
+// compileBlock assembles insns — a run of jitEligible ADD/SUB
+// instructions — into amd64 machine code operating directly on the
+// guest register file. Every emitted instruction routes its operands
+// through RAX, addressing a register Xn as [RDI + 8*n]; this forgoes
+// host register allocation entirely in exchange for a trivially
+// correct emitter, which is the right tradeoff for a first JIT
+// backend whose job is replacing the interpreter's per-instruction
+// decode/dispatch overhead, not competing with a real register
+// allocator.
+func compileBlock(insns []*insts.Instruction) (*jitBlock, error) {
+	var code []byte
+	for _, inst := range insns {
+		switch {
+		case inst.Format == insts.FormatDPImm && inst.Op == insts.OpADD:
+			code = append(code, emitLoadReg(inst.Rn)...)
+			code = append(code, emitAddImm32(uint32(inst.Imm))...)
+			code = append(code, emitStoreReg(inst.Rd)...)
+		case inst.Format == insts.FormatDPImm && inst.Op == insts.OpSUB:
+			code = append(code, emitLoadReg(inst.Rn)...)
+			code = append(code, emitSubImm32(uint32(inst.Imm))...)
+			code = append(code, emitStoreReg(inst.Rd)...)
+		case inst.Format == insts.FormatDPReg && inst.Op == insts.OpADD:
+			code = append(code, emitLoadReg(inst.Rn)...)
+			code = append(code, emitAddReg(inst.Rm)...)
+			code = append(code, emitStoreReg(inst.Rd)...)
+		case inst.Format == insts.FormatDPReg && inst.Op == insts.OpSUB:
+			code = append(code, emitLoadReg(inst.Rn)...)
+			code = append(code, emitSubReg(inst.Rm)...)
+			code = append(code, emitStoreReg(inst.Rd)...)
+		default:
+			return nil, fmt.Errorf("emu: jit: unreachable opcode reached compileBlock")
+		}
+	}
+	code = append(code, 0xC3) // RET
+
+	buf, err := newExecBuffer(code)
+	if err != nil {
+		return nil, err
+	}
+	return &jitBlock{buf: buf, guestInstrs: len(insns)}, nil
+}
+
+// regDisp32 returns Xn's byte displacement from the gpr array's base
+// address, sign-extended to int32 for disp32 addressing (always valid
+// regardless of n, unlike the shorter disp8 form).
+func regDisp32(n uint8) int32 {
+	return int32(n) * 8
+}
+
+func le32(v int32) []byte {
+	u := uint32(v)
+	return []byte{byte(u), byte(u >> 8), byte(u >> 16), byte(u >> 24)}
+}
+
+// emitLoadReg emits MOV RAX, [RDI+disp32] — RAX = Xn.
+func emitLoadReg(n uint8) []byte {
+	out := []byte{0x48, 0x8B, 0x87}
+	return append(out, le32(regDisp32(n))...)
+}
+
+// emitStoreReg emits MOV [RDI+disp32], RAX — Xn = RAX.
+func emitStoreReg(n uint8) []byte {
+	out := []byte{0x48, 0x89, 0x87}
+	return append(out, le32(regDisp32(n))...)
+}
+
+// emitAddReg emits ADD RAX, [RDI+disp32] — RAX += Xm.
+func emitAddReg(m uint8) []byte {
+	out := []byte{0x48, 0x03, 0x87}
+	return append(out, le32(regDisp32(m))...)
+}
+
+// emitSubReg emits SUB RAX, [RDI+disp32] — RAX -= Xm.
+func emitSubReg(m uint8) []byte {
+	out := []byte{0x48, 0x2B, 0x87}
+	return append(out, le32(regDisp32(m))...)
+}
+
+// emitAddImm32 emits ADD RAX, imm32 — RAX += imm.
+func emitAddImm32(imm uint32) []byte {
+	out := []byte{0x48, 0x81, 0xC0}
+	return append(out, le32(int32(imm))...)
+}
+
+// emitSubImm32 emits SUB RAX, imm32 — RAX -= imm.
+func emitSubImm32(imm uint32) []byte {
+	out := []byte{0x48, 0x81, 0xE8}
+	return append(out, le32(int32(imm))...)
+}
+
+// Run transfers control to buf's compiled code with gpr as its
+// argument.
+func (buf *execBuffer) Run(gpr *[31]uint64) {
+	callJITBlock(buf.addr(), gpr)
+}
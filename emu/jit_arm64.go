@@ -0,0 +1,110 @@
+//go:build arm64
+
+package emu
+
+import (
+	"fmt"
+
+	"github.com/sarchlab/m2sim/insts"
+)
+
+// jitSupported is true on architectures with an emitter table below
+// (see also jit_amd64.go); jit_fallback.go sets it to false everywhere
+// else.
+const jitSupported = true
+
+// callJITBlock transfers control to the native code at fn, passing
+// gpr as its single argument, following AAPCS64: gpr arrives in X0,
+// the first argument register. Implemented in jit_call_arm64.s.
+//
+//go:noescape
+func callJITBlock(fn uintptr, gpr *[31]uint64)
+
+// compileBlock assembles insns — a run of jitEligible ADD/SUB
+// instructions — into arm64 host machine code operating directly on
+// the guest register file, addressing a register Xn at
+// [X0, #8*n]. The host happening to also be arm64 here is
+// incidental: this emits fixed load/ALU/store sequences through a
+// scratch register (X9) rather than reusing the guest's own encoded
+// words directly, so it doesn't have to reason about which host
+// registers the Go runtime is using at the call site.
+func compileBlock(insns []*insts.Instruction) (*jitBlock, error) {
+	var code []uint32
+	for _, inst := range insns {
+		switch {
+		case inst.Format == insts.FormatDPImm && inst.Op == insts.OpADD:
+			code = append(code, encodeLoadReg(inst.Rn))
+			code = append(code, encodeAddImm(uint32(inst.Imm)))
+			code = append(code, encodeStoreReg(inst.Rd))
+		case inst.Format == insts.FormatDPImm && inst.Op == insts.OpSUB:
+			code = append(code, encodeLoadReg(inst.Rn))
+			code = append(code, encodeSubImm(uint32(inst.Imm)))
+			code = append(code, encodeStoreReg(inst.Rd))
+		case inst.Format == insts.FormatDPReg && inst.Op == insts.OpADD:
+			code = append(code, encodeLoadReg(inst.Rn))
+			code = append(code, encodeLoadReg2(inst.Rm))
+			code = append(code, encodeAddReg())
+			code = append(code, encodeStoreReg(inst.Rd))
+		case inst.Format == insts.FormatDPReg && inst.Op == insts.OpSUB:
+			code = append(code, encodeLoadReg(inst.Rn))
+			code = append(code, encodeLoadReg2(inst.Rm))
+			code = append(code, encodeSubReg())
+			code = append(code, encodeStoreReg(inst.Rd))
+		default:
+			return nil, fmt.Errorf("emu: jit: unreachable opcode reached compileBlock")
+		}
+	}
+	code = append(code, 0xD65F03C0) // RET
+
+	bytes := make([]byte, len(code)*4)
+	for i, w := range code {
+		bytes[i*4+0] = byte(w)
+		bytes[i*4+1] = byte(w >> 8)
+		bytes[i*4+2] = byte(w >> 16)
+		bytes[i*4+3] = byte(w >> 24)
+	}
+
+	buf, err := newExecBuffer(bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &jitBlock{buf: buf, guestInstrs: len(insns)}, nil
+}
+
+// encodeLoadReg emits "LDR X9, [X0, #8*n]" — X9 = Xn. The unsigned
+// immediate offset is encoded in units of 8 bytes at bits [21:10].
+func encodeLoadReg(n uint8) uint32 {
+	return 0xF9400009 | (uint32(n) << 10)
+}
+
+// encodeLoadReg2 emits "LDR X10, [X0, #8*n]" — X10 = Xn.
+func encodeLoadReg2(n uint8) uint32 {
+	return 0xF940000A | (uint32(n) << 10)
+}
+
+// encodeStoreReg emits "STR X9, [X0, #8*n]" — Xn = X9.
+func encodeStoreReg(n uint8) uint32 {
+	return 0xF9000009 | (uint32(n) << 10)
+}
+
+// encodeAddImm emits "ADD X9, X9, #imm" (imm must fit in 12 bits,
+// guaranteed by the ARM64 immediate-form decode that produced this
+// instruction).
+func encodeAddImm(imm uint32) uint32 {
+	return 0x91000129 | ((imm & 0xFFF) << 10)
+}
+
+// encodeSubImm emits "SUB X9, X9, #imm".
+func encodeSubImm(imm uint32) uint32 {
+	return 0xD1000129 | ((imm & 0xFFF) << 10)
+}
+
+// encodeAddReg emits "ADD X9, X9, X10".
+func encodeAddReg() uint32 {
+	return 0x8B0A0129
+}
+
+// encodeSubReg emits "SUB X9, X9, X10".
+func encodeSubReg() uint32 {
+	return 0xCB0A0129
+}
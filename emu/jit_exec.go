@@ -0,0 +1,39 @@
+//go:build amd64 || arm64
+
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// execBuffer is a page of memory holding compiled native code. It is
+// written as PROT_READ|PROT_WRITE, then mprotect'd to
+// PROT_READ|PROT_EXEC before it's ever run, so no page is ever both
+// writable and executable at once.
+type execBuffer struct {
+	mem []byte
+}
+
+// newExecBuffer maps code into an executable buffer.
+func newExecBuffer(code []byte) (*execBuffer, error) {
+	mem, err := syscall.Mmap(-1, 0, len(code),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("emu: jit: mmap: %w", err)
+	}
+	copy(mem, code)
+	if err := syscall.Mprotect(mem, syscall.PROT_READ|syscall.PROT_EXEC); err != nil {
+		_ = syscall.Munmap(mem)
+		return nil, fmt.Errorf("emu: jit: mprotect: %w", err)
+	}
+	return &execBuffer{mem: mem}, nil
+}
+
+// addr returns the buffer's base address as a callable function
+// pointer.
+func (buf *execBuffer) addr() uintptr {
+	return uintptr(unsafe.Pointer(&buf.mem[0]))
+}
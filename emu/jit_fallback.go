@@ -0,0 +1,22 @@
+//go:build !amd64 && !arm64
+
+package emu
+
+import (
+	"errors"
+
+	"github.com/sarchlab/m2sim/insts"
+)
+
+// jitSupported is false on architectures with no emitter table (see
+// jit_amd64.go/jit_arm64.go); jitEligible always returns false here,
+// so RunJIT degrades to exactly the same behavior as RunFast.
+const jitSupported = false
+
+// compileBlock is never actually called — jitEligible returns false
+// whenever jitSupported is false, so RunJIT always takes the stepFast
+// fallback path on this GOARCH. It's defined anyway so the package
+// still compiles here.
+func compileBlock(insns []*insts.Instruction) (*jitBlock, error) {
+	return nil, errors.New("emu: jit: unsupported on this architecture")
+}
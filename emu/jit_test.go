@@ -0,0 +1,64 @@
+// Package emu provides functional ARM64 emulation.
+package emu_test
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// encodeADDImm encodes "ADD Xd, Xn, #imm" (64-bit, immediate form).
+func encodeADDImm(rd, rn uint8, imm uint16) uint32 {
+	return (1 << 31) | (0b10001 << 23) | (uint32(imm) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeSUBImm encodes "SUB Xd, Xn, #imm" (64-bit, immediate form).
+func encodeSUBImm(rd, rn uint8, imm uint16) uint32 {
+	return (1 << 31) | (1 << 30) | (0b10001 << 23) | (uint32(imm) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeADDReg encodes "ADD Xd, Xn, Xm" (64-bit, shifted-register form,
+// shift amount 0).
+func encodeADDReg(rd, rn, rm uint8) uint32 {
+	return (1 << 31) | (0b01011 << 24) | (uint32(rm) << 16) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeHLT encodes a HLT #imm16 instruction: 11010100 010 imm16 00000.
+func encodeHLT(imm16 uint16) uint32 {
+	return 0xD4400000 | (uint32(imm16) << 5)
+}
+
+// addChainProgram is a straight run of JIT-eligible ADD/SUB
+// instructions (plus a trailing HLT to exit), small enough to fit in
+// one compiled block.
+func addChainProgram() []byte {
+	words := []uint32{
+		encodeADDImm(0, 31, 5), // X0 = XZR + 5
+		encodeADDReg(0, 0, 1),  // X0 += X1 (0)
+		encodeSUBImm(0, 0, 2),  // X0 -= 2  => X0 == 3
+		encodeHLT(emu.PseudoOpExit),
+	}
+	buf := make([]byte, len(words)*4)
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(buf[i*4:], w)
+	}
+	return buf
+}
+
+var _ = Describe("RunJIT", func() {
+	It("produces the same exit code as RunFast for a straight-line ADD/SUB chain", func() {
+		fast := emu.NewEmulator()
+		fast.LoadProgram(0x1000, addChainProgram())
+		fastExit := fast.RunFast()
+
+		jit := emu.NewEmulator()
+		jit.LoadProgram(0x1000, addChainProgram())
+		jitExit := jit.RunJIT()
+
+		Expect(jitExit).To(Equal(fastExit))
+		Expect(jitExit).To(Equal(int64(3)))
+	})
+})
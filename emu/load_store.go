@@ -1,27 +1,54 @@
 // Package emu provides functional ARM64 emulation.
 package emu
 
+import (
+	"fmt"
+	"io"
+)
+
 // LoadStoreUnit implements ARM64 load and store operations.
 type LoadStoreUnit struct {
 	regFile *RegFile
-	memory  *Memory
+	memory  MemAccessor
+	tracer  io.Writer
+	monitor monitorState
 }
 
 // NewLoadStoreUnit creates a new LoadStoreUnit connected to the given
-// register file and memory.
-func NewLoadStoreUnit(regFile *RegFile, memory *Memory) *LoadStoreUnit {
+// register file and memory. memory is typically a *Memory, or a *Bus if
+// the emulator has MMIO devices attached.
+func NewLoadStoreUnit(regFile *RegFile, memory MemAccessor) *LoadStoreUnit {
 	return &LoadStoreUnit{
 		regFile: regFile,
 		memory:  memory,
 	}
 }
 
+// SetTracer attaches w to receive one line per load/store this unit
+// performs, reporting the effective address and the value read or
+// written. Pass nil to detach. Emulator.SetTracer wires this up
+// automatically, so callers normally don't call it directly.
+func (lsu *LoadStoreUnit) SetTracer(w io.Writer) {
+	lsu.tracer = w
+}
+
+// trace writes one memory-access line if a tracer is attached. op is the
+// mnemonic (e.g. "LDR64"), addr the effective address, and value what
+// was read or written, truncated to size bytes by the caller already.
+func (lsu *LoadStoreUnit) trace(op string, addr, value uint64, size int) {
+	if lsu.tracer == nil {
+		return
+	}
+	fmt.Fprintf(lsu.tracer, "  mem: %-8s [0x%016X] = 0x%0*X\n", op, addr, size*2, value)
+}
+
 // LDR64 performs a 64-bit load: Xd = mem[Xn + offset]
 func (lsu *LoadStoreUnit) LDR64(rd, rn uint8, offset uint64) {
 	base := lsu.regFile.ReadReg(rn)
 	addr := base + offset
 	value := lsu.memory.Read64(addr)
 	lsu.regFile.WriteReg(rd, value)
+	lsu.trace("LDR64", addr, value, 8)
 }
 
 // LDR64SP performs a 64-bit load using SP as base: Xd = mem[SP + offset]
@@ -29,6 +56,7 @@ func (lsu *LoadStoreUnit) LDR64SP(rd uint8, offset uint64) {
 	addr := lsu.regFile.SP + offset
 	value := lsu.memory.Read64(addr)
 	lsu.regFile.WriteReg(rd, value)
+	lsu.trace("LDR64", addr, value, 8)
 }
 
 // LDR32 performs a 32-bit load with zero extension: Xd = zero_extend(mem[Xn + offset])
@@ -38,6 +66,7 @@ func (lsu *LoadStoreUnit) LDR32(rd, rn uint8, offset uint64) {
 	value := lsu.memory.Read32(addr)
 	// Zero-extend to 64 bits by storing as uint64
 	lsu.regFile.WriteReg(rd, uint64(value))
+	lsu.trace("LDR32", addr, uint64(value), 4)
 }
 
 // LDR32SP performs a 32-bit load using SP as base: Xd = zero_extend(mem[SP + offset])
@@ -45,6 +74,7 @@ func (lsu *LoadStoreUnit) LDR32SP(rd uint8, offset uint64) {
 	addr := lsu.regFile.SP + offset
 	value := lsu.memory.Read32(addr)
 	lsu.regFile.WriteReg(rd, uint64(value))
+	lsu.trace("LDR32", addr, uint64(value), 4)
 }
 
 // STR64 performs a 64-bit store: mem[Xn + offset] = Xd
@@ -53,6 +83,8 @@ func (lsu *LoadStoreUnit) STR64(rd, rn uint8, offset uint64) {
 	addr := base + offset
 	value := lsu.regFile.ReadReg(rd)
 	lsu.memory.Write64(addr, value)
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.trace("STR64", addr, value, 8)
 }
 
 // STR64SP performs a 64-bit store using SP as base: mem[SP + offset] = Xd
@@ -60,6 +92,8 @@ func (lsu *LoadStoreUnit) STR64SP(rd uint8, offset uint64) {
 	addr := lsu.regFile.SP + offset
 	value := lsu.regFile.ReadReg(rd)
 	lsu.memory.Write64(addr, value)
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.trace("STR64", addr, value, 8)
 }
 
 // STR32 performs a 32-bit store: mem[Xn + offset] = Wd (lower 32 bits)
@@ -68,6 +102,8 @@ func (lsu *LoadStoreUnit) STR32(rd, rn uint8, offset uint64) {
 	addr := base + offset
 	value := uint32(lsu.regFile.ReadReg(rd))
 	lsu.memory.Write32(addr, value)
+	lsu.invalidateIfOverlaps(addr, 4)
+	lsu.trace("STR32", addr, uint64(value), 4)
 }
 
 // STR32SP performs a 32-bit store using SP as base: mem[SP + offset] = Wd
@@ -75,4 +111,302 @@ func (lsu *LoadStoreUnit) STR32SP(rd uint8, offset uint64) {
 	addr := lsu.regFile.SP + offset
 	value := uint32(lsu.regFile.ReadReg(rd))
 	lsu.memory.Write32(addr, value)
+	lsu.invalidateIfOverlaps(addr, 4)
+	lsu.trace("STR32", addr, uint64(value), 4)
+}
+
+// LDRB performs an 8-bit load with zero extension: Xd = zero_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRB(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	lsu.regFile.WriteReg(rd, uint64(lsu.memory.Read8(addr)))
+}
+
+// LDRBSP performs an 8-bit load using SP as base: Xd = zero_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRBSP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	lsu.regFile.WriteReg(rd, uint64(lsu.memory.Read8(addr)))
+}
+
+// LDRSB64 performs an 8-bit load with sign extension to 64 bits:
+// Xd = sign_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRSB64(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	value := int64(int8(lsu.memory.Read8(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSB64SP performs LDRSB64 using SP as base: Xd = sign_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRSB64SP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	value := int64(int8(lsu.memory.Read8(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSB32 performs an 8-bit load with sign extension to 32 bits, zero-
+// extended into the full 64-bit register: Wd = sign_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRSB32(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	value := int64(int32(int8(lsu.memory.Read8(addr))))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSB32SP performs LDRSB32 using SP as base: Wd = sign_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRSB32SP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	value := int64(int32(int8(lsu.memory.Read8(addr))))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// STRB performs an 8-bit store: mem[Xn + offset] = Wd (lowest byte)
+func (lsu *LoadStoreUnit) STRB(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	lsu.memory.Write8(addr, byte(lsu.regFile.ReadReg(rd)))
+	lsu.invalidateIfOverlaps(addr, 1)
+}
+
+// STRBSP performs an 8-bit store using SP as base: mem[SP + offset] = Wd (lowest byte)
+func (lsu *LoadStoreUnit) STRBSP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	lsu.memory.Write8(addr, byte(lsu.regFile.ReadReg(rd)))
+	lsu.invalidateIfOverlaps(addr, 1)
+}
+
+// LDRH performs a 16-bit load with zero extension: Xd = zero_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRH(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	lsu.regFile.WriteReg(rd, uint64(lsu.memory.Read16(addr)))
+}
+
+// LDRHSP performs a 16-bit load using SP as base: Xd = zero_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRHSP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	lsu.regFile.WriteReg(rd, uint64(lsu.memory.Read16(addr)))
+}
+
+// LDRSH64 performs a 16-bit load with sign extension to 64 bits:
+// Xd = sign_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRSH64(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	value := int64(int16(lsu.memory.Read16(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSH64SP performs LDRSH64 using SP as base: Xd = sign_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRSH64SP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	value := int64(int16(lsu.memory.Read16(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSH32 performs a 16-bit load with sign extension to 32 bits, zero-
+// extended into the full 64-bit register: Wd = sign_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRSH32(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	value := int64(int32(int16(lsu.memory.Read16(addr))))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSH32SP performs LDRSH32 using SP as base: Wd = sign_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRSH32SP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	value := int64(int32(int16(lsu.memory.Read16(addr))))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// STRH performs a 16-bit store: mem[Xn + offset] = Wd (lowest halfword)
+func (lsu *LoadStoreUnit) STRH(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	lsu.memory.Write16(addr, uint16(lsu.regFile.ReadReg(rd)))
+	lsu.invalidateIfOverlaps(addr, 2)
+}
+
+// STRHSP performs a 16-bit store using SP as base: mem[SP + offset] = Wd (lowest halfword)
+func (lsu *LoadStoreUnit) STRHSP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	lsu.memory.Write16(addr, uint16(lsu.regFile.ReadReg(rd)))
+	lsu.invalidateIfOverlaps(addr, 2)
+}
+
+// LDRSW performs a 32-bit load with sign extension to 64 bits: Xd = sign_extend(mem[Xn + offset])
+func (lsu *LoadStoreUnit) LDRSW(rd, rn uint8, offset uint64) {
+	addr := lsu.regFile.ReadReg(rn) + offset
+	value := int64(int32(lsu.memory.Read32(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDRSWSP performs LDRSW using SP as base: Xd = sign_extend(mem[SP + offset])
+func (lsu *LoadStoreUnit) LDRSWSP(rd uint8, offset uint64) {
+	addr := lsu.regFile.SP + offset
+	value := int64(int32(lsu.memory.Read32(addr)))
+	lsu.regFile.WriteReg(rd, uint64(value))
+}
+
+// LDP64 performs a 64-bit load pair with a signed offset, no writeback:
+// Xt1, Xt2 = mem[Xn + offset], mem[Xn + offset + 8]
+func (lsu *LoadStoreUnit) LDP64(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+}
+
+// LDP64PreIndex performs LDP64, then writes the computed address back to
+// Xn (pre-indexed addressing: the base is updated before the access).
+func (lsu *LoadStoreUnit) LDP64PreIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+	lsu.regFile.WriteReg(rn, addr)
+}
+
+// LDP64PostIndex loads from the unmodified value of Xn, then writes
+// Xn + offset back to Xn (post-indexed addressing).
+func (lsu *LoadStoreUnit) LDP64PostIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := lsu.regFile.ReadReg(rn)
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+	lsu.regFile.WriteReg(rn, uint64(int64(addr)+offset))
+}
+
+// STP64 performs a 64-bit store pair with a signed offset, no writeback:
+// mem[Xn + offset], mem[Xn + offset + 8] = Xt1, Xt2
+func (lsu *LoadStoreUnit) STP64(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+}
+
+// STP64PreIndex performs STP64, then writes the computed address back to
+// Xn (pre-indexed addressing).
+func (lsu *LoadStoreUnit) STP64PreIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+	lsu.regFile.WriteReg(rn, addr)
+}
+
+// STP64PostIndex stores to the unmodified value of Xn, then writes
+// Xn + offset back to Xn (post-indexed addressing).
+func (lsu *LoadStoreUnit) STP64PostIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := lsu.regFile.ReadReg(rn)
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+	lsu.regFile.WriteReg(rn, uint64(int64(addr)+offset))
+}
+
+// LDP64SP performs LDP64 using SP as base: Xt1, Xt2 = mem[SP + offset], mem[SP + offset + 8]
+func (lsu *LoadStoreUnit) LDP64SP(rt1, rt2 uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.SP) + offset)
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+}
+
+// LDP64SPPreIndex performs LDP64SP, then writes the computed address back
+// to SP (pre-indexed addressing).
+func (lsu *LoadStoreUnit) LDP64SPPreIndex(rt1, rt2 uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.SP) + offset)
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+	lsu.regFile.SP = addr
+}
+
+// LDP64SPPostIndex loads from the unmodified value of SP, then writes
+// SP + offset back to SP (post-indexed addressing).
+func (lsu *LoadStoreUnit) LDP64SPPostIndex(rt1, rt2 uint8, offset int64) {
+	addr := lsu.regFile.SP
+	lsu.regFile.WriteReg(rt1, lsu.memory.Read64(addr))
+	lsu.regFile.WriteReg(rt2, lsu.memory.Read64(addr+8))
+	lsu.regFile.SP = uint64(int64(addr) + offset)
+}
+
+// STP64SP performs STP64 using SP as base: mem[SP + offset], mem[SP + offset + 8] = Xt1, Xt2
+//
+// This is the encoding "stp x29, x30, [sp, #-16]!" uses for its
+// function-prologue form (paired with STP64SPPreIndex, below), and the
+// one every matching epilogue's "ldp x29, x30, [sp], #16" pairs with
+// LDP64SPPostIndex.
+func (lsu *LoadStoreUnit) STP64SP(rt1, rt2 uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.SP) + offset)
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+}
+
+// STP64SPPreIndex performs STP64SP, then writes the computed address back
+// to SP (pre-indexed addressing) — the "stp x29, x30, [sp, #-16]!"
+// prologue pattern.
+func (lsu *LoadStoreUnit) STP64SPPreIndex(rt1, rt2 uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.SP) + offset)
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+	lsu.regFile.SP = addr
+}
+
+// STP64SPPostIndex stores to the unmodified value of SP, then writes
+// SP + offset back to SP (post-indexed addressing).
+func (lsu *LoadStoreUnit) STP64SPPostIndex(rt1, rt2 uint8, offset int64) {
+	addr := lsu.regFile.SP
+	lsu.memory.Write64(addr, lsu.regFile.ReadReg(rt1))
+	lsu.memory.Write64(addr+8, lsu.regFile.ReadReg(rt2))
+	lsu.invalidateIfOverlaps(addr, 16)
+	lsu.regFile.SP = uint64(int64(addr) + offset)
+}
+
+// LDP32 performs a 32-bit load pair with a signed offset, no writeback,
+// zero-extending each word into its 64-bit destination register:
+// Wt1, Wt2 = mem[Xn + offset], mem[Xn + offset + 4]
+func (lsu *LoadStoreUnit) LDP32(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.regFile.WriteReg(rt1, uint64(lsu.memory.Read32(addr)))
+	lsu.regFile.WriteReg(rt2, uint64(lsu.memory.Read32(addr+4)))
+}
+
+// LDP32PreIndex performs LDP32, then writes the computed address back to
+// Xn (pre-indexed addressing).
+func (lsu *LoadStoreUnit) LDP32PreIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.regFile.WriteReg(rt1, uint64(lsu.memory.Read32(addr)))
+	lsu.regFile.WriteReg(rt2, uint64(lsu.memory.Read32(addr+4)))
+	lsu.regFile.WriteReg(rn, addr)
+}
+
+// LDP32PostIndex loads from the unmodified value of Xn, then writes
+// Xn + offset back to Xn (post-indexed addressing).
+func (lsu *LoadStoreUnit) LDP32PostIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := lsu.regFile.ReadReg(rn)
+	lsu.regFile.WriteReg(rt1, uint64(lsu.memory.Read32(addr)))
+	lsu.regFile.WriteReg(rt2, uint64(lsu.memory.Read32(addr+4)))
+	lsu.regFile.WriteReg(rn, uint64(int64(addr)+offset))
+}
+
+// STP32 performs a 32-bit store pair with a signed offset, no writeback:
+// mem[Xn + offset], mem[Xn + offset + 4] = Wt1, Wt2
+func (lsu *LoadStoreUnit) STP32(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.memory.Write32(addr, uint32(lsu.regFile.ReadReg(rt1)))
+	lsu.memory.Write32(addr+4, uint32(lsu.regFile.ReadReg(rt2)))
+	lsu.invalidateIfOverlaps(addr, 8)
+}
+
+// STP32PreIndex performs STP32, then writes the computed address back to
+// Xn (pre-indexed addressing).
+func (lsu *LoadStoreUnit) STP32PreIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := uint64(int64(lsu.regFile.ReadReg(rn)) + offset)
+	lsu.memory.Write32(addr, uint32(lsu.regFile.ReadReg(rt1)))
+	lsu.memory.Write32(addr+4, uint32(lsu.regFile.ReadReg(rt2)))
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.regFile.WriteReg(rn, addr)
+}
+
+// STP32PostIndex stores to the unmodified value of Xn, then writes
+// Xn + offset back to Xn (post-indexed addressing).
+func (lsu *LoadStoreUnit) STP32PostIndex(rt1, rt2, rn uint8, offset int64) {
+	addr := lsu.regFile.ReadReg(rn)
+	lsu.memory.Write32(addr, uint32(lsu.regFile.ReadReg(rt1)))
+	lsu.memory.Write32(addr+4, uint32(lsu.regFile.ReadReg(rt2)))
+	lsu.invalidateIfOverlaps(addr, 8)
+	lsu.regFile.WriteReg(rn, uint64(int64(addr)+offset))
 }
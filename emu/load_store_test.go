@@ -1,6 +1,8 @@
 package emu_test
 
 import (
+	"bytes"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -260,7 +262,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should load byte with zero extension", func() {
 			memory.Write8(0x1000, 0xAB)
 
-			lsu.LDRB(0, 0x1000)
+			lsu.LDRB(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xAB)))
 		})
@@ -269,7 +271,7 @@ var _ = Describe("LoadStoreUnit", func() {
 			regFile.WriteReg(0, 0xFFFFFFFFFFFFFFFF)
 			memory.Write8(0x1000, 0x42)
 
-			lsu.LDRB(0, 0x1000)
+			lsu.LDRB(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x42)))
 		})
@@ -277,7 +279,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should handle max byte value", func() {
 			memory.Write8(0x1000, 0xFF)
 
-			lsu.LDRB(0, 0x1000)
+			lsu.LDRB(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFF)))
 		})
@@ -287,7 +289,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should store lower byte to memory", func() {
 			regFile.WriteReg(0, 0x123456789ABCDEF0)
 
-			lsu.STRB(0, 0x1000)
+			lsu.STRB(0, 1, 0x1000)
 
 			Expect(memory.Read8(0x1000)).To(Equal(uint8(0xF0)))
 		})
@@ -296,7 +298,7 @@ var _ = Describe("LoadStoreUnit", func() {
 			memory.Write64(0x1000, 0xFFFFFFFFFFFFFFFF)
 			regFile.WriteReg(0, 0x00)
 
-			lsu.STRB(0, 0x1000)
+			lsu.STRB(0, 1, 0x1000)
 
 			// Only the first byte should be zero
 			Expect(memory.Read8(0x1000)).To(Equal(uint8(0x00)))
@@ -308,7 +310,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend positive byte", func() {
 			memory.Write8(0x1000, 0x7F) // +127
 
-			lsu.LDRSB64(0, 0x1000)
+			lsu.LDRSB64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x7F)))
 		})
@@ -316,7 +318,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend negative byte", func() {
 			memory.Write8(0x1000, 0x80) // -128
 
-			lsu.LDRSB64(0, 0x1000)
+			lsu.LDRSB64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFF80)))
 		})
@@ -324,7 +326,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend -1", func() {
 			memory.Write8(0x1000, 0xFF) // -1
 
-			lsu.LDRSB64(0, 0x1000)
+			lsu.LDRSB64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
 		})
@@ -334,7 +336,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend positive byte to 32-bit", func() {
 			memory.Write8(0x1000, 0x7F) // +127
 
-			lsu.LDRSB32(0, 0x1000)
+			lsu.LDRSB32(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x7F)))
 		})
@@ -342,7 +344,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend negative byte to 32-bit", func() {
 			memory.Write8(0x1000, 0x80) // -128
 
-			lsu.LDRSB32(0, 0x1000)
+			lsu.LDRSB32(0, 1, 0x1000)
 
 			// Upper 32 bits should be zero (32-bit register)
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFF80)))
@@ -351,7 +353,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend -1 to 32-bit", func() {
 			memory.Write8(0x1000, 0xFF) // -1
 
-			lsu.LDRSB32(0, 0x1000)
+			lsu.LDRSB32(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFF)))
 		})
@@ -361,7 +363,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should load halfword with zero extension", func() {
 			memory.Write16(0x1000, 0xABCD)
 
-			lsu.LDRH(0, 0x1000)
+			lsu.LDRH(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xABCD)))
 		})
@@ -370,7 +372,7 @@ var _ = Describe("LoadStoreUnit", func() {
 			regFile.WriteReg(0, 0xFFFFFFFFFFFFFFFF)
 			memory.Write16(0x1000, 0x1234)
 
-			lsu.LDRH(0, 0x1000)
+			lsu.LDRH(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x1234)))
 		})
@@ -378,7 +380,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should handle max halfword value", func() {
 			memory.Write16(0x1000, 0xFFFF)
 
-			lsu.LDRH(0, 0x1000)
+			lsu.LDRH(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFF)))
 		})
@@ -388,7 +390,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should store lower halfword to memory", func() {
 			regFile.WriteReg(0, 0x123456789ABCDEF0)
 
-			lsu.STRH(0, 0x1000)
+			lsu.STRH(0, 1, 0x1000)
 
 			Expect(memory.Read16(0x1000)).To(Equal(uint16(0xDEF0)))
 		})
@@ -397,7 +399,7 @@ var _ = Describe("LoadStoreUnit", func() {
 			memory.Write64(0x1000, 0xFFFFFFFFFFFFFFFF)
 			regFile.WriteReg(0, 0x0000)
 
-			lsu.STRH(0, 0x1000)
+			lsu.STRH(0, 1, 0x1000)
 
 			Expect(memory.Read16(0x1000)).To(Equal(uint16(0x0000)))
 			Expect(memory.Read16(0x1002)).To(Equal(uint16(0xFFFF)))
@@ -408,7 +410,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend positive halfword", func() {
 			memory.Write16(0x1000, 0x7FFF) // +32767
 
-			lsu.LDRSH64(0, 0x1000)
+			lsu.LDRSH64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x7FFF)))
 		})
@@ -416,7 +418,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend negative halfword", func() {
 			memory.Write16(0x1000, 0x8000) // -32768
 
-			lsu.LDRSH64(0, 0x1000)
+			lsu.LDRSH64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFF8000)))
 		})
@@ -424,7 +426,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend -1", func() {
 			memory.Write16(0x1000, 0xFFFF) // -1
 
-			lsu.LDRSH64(0, 0x1000)
+			lsu.LDRSH64(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
 		})
@@ -434,7 +436,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend positive halfword to 32-bit", func() {
 			memory.Write16(0x1000, 0x7FFF) // +32767
 
-			lsu.LDRSH32(0, 0x1000)
+			lsu.LDRSH32(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x7FFF)))
 		})
@@ -442,7 +444,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend negative halfword to 32-bit", func() {
 			memory.Write16(0x1000, 0x8000) // -32768
 
-			lsu.LDRSH32(0, 0x1000)
+			lsu.LDRSH32(0, 1, 0x1000)
 
 			// Upper 32 bits should be zero (32-bit register)
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFF8000)))
@@ -451,7 +453,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend -1 to 32-bit", func() {
 			memory.Write16(0x1000, 0xFFFF) // -1
 
-			lsu.LDRSH32(0, 0x1000)
+			lsu.LDRSH32(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFF)))
 		})
@@ -461,7 +463,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend positive word", func() {
 			memory.Write32(0x1000, 0x7FFFFFFF) // +2147483647
 
-			lsu.LDRSW(0, 0x1000)
+			lsu.LDRSW(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0x7FFFFFFF)))
 		})
@@ -469,7 +471,7 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend negative word", func() {
 			memory.Write32(0x1000, 0x80000000) // -2147483648
 
-			lsu.LDRSW(0, 0x1000)
+			lsu.LDRSW(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFF80000000)))
 		})
@@ -477,9 +479,130 @@ var _ = Describe("LoadStoreUnit", func() {
 		It("should sign-extend -1", func() {
 			memory.Write32(0x1000, 0xFFFFFFFF) // -1
 
-			lsu.LDRSW(0, 0x1000)
+			lsu.LDRSW(0, 1, 0x1000)
 
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFFFF)))
 		})
 	})
+
+	Describe("Sub-word SP-based addressing", func() {
+		It("should load a byte from SP with zero extension", func() {
+			regFile.SP = 0x2000
+			memory.Write8(0x2008, 0xFF)
+
+			lsu.LDRBSP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFF)))
+		})
+
+		It("should store a byte to SP", func() {
+			regFile.SP = 0x2000
+			regFile.WriteReg(0, 0xAB)
+
+			lsu.STRBSP(0, 8)
+
+			Expect(memory.Read8(0x2008)).To(Equal(uint8(0xAB)))
+		})
+
+		It("should sign-extend a byte from SP to 64 bits when the MSB is set", func() {
+			regFile.SP = 0x2000
+			memory.Write8(0x2008, 0x80) // -128
+
+			lsu.LDRSB64SP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFFFF80)))
+		})
+
+		It("should sign-extend a byte from SP to 32 bits when the MSB is set", func() {
+			regFile.SP = 0x2000
+			memory.Write8(0x2008, 0x80) // -128
+
+			lsu.LDRSB32SP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFF80)))
+		})
+
+		It("should load a halfword from SP with zero extension", func() {
+			regFile.SP = 0x2000
+			memory.Write16(0x2008, 0xABCD)
+
+			lsu.LDRHSP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xABCD)))
+		})
+
+		It("should store a halfword to SP", func() {
+			regFile.SP = 0x2000
+			regFile.WriteReg(0, 0x1234)
+
+			lsu.STRHSP(0, 8)
+
+			Expect(memory.Read16(0x2008)).To(Equal(uint16(0x1234)))
+		})
+
+		It("should sign-extend a halfword from SP to 64 bits when the MSB is set", func() {
+			regFile.SP = 0x2000
+			memory.Write16(0x2008, 0x8000) // -32768
+
+			lsu.LDRSH64SP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFFFFFF8000)))
+		})
+
+		It("should sign-extend a halfword from SP to 32 bits when the MSB is set", func() {
+			regFile.SP = 0x2000
+			memory.Write16(0x2008, 0x8000) // -32768
+
+			lsu.LDRSH32SP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFF8000)))
+		})
+
+		It("should sign-extend a word from SP to 64 bits when the MSB is set", func() {
+			regFile.SP = 0x2000
+			memory.Write32(0x2008, 0x80000000) // -2147483648
+
+			lsu.LDRSWSP(0, 8)
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0xFFFFFFFF80000000)))
+		})
+	})
+
+	Describe("SetTracer", func() {
+		It("should log the effective address and value of a load", func() {
+			var buf bytes.Buffer
+			lsu.SetTracer(&buf)
+			regFile.WriteReg(1, 0x1000)
+			memory.Write64(0x1000, 0xDEADBEEF12345678)
+
+			lsu.LDR64(0, 1, 0)
+
+			Expect(buf.String()).To(ContainSubstring("LDR64"))
+			Expect(buf.String()).To(ContainSubstring("0x0000000000001000"))
+			Expect(buf.String()).To(ContainSubstring("0xDEADBEEF12345678"))
+		})
+
+		It("should log the effective address and value of a store", func() {
+			var buf bytes.Buffer
+			lsu.SetTracer(&buf)
+			regFile.WriteReg(1, 0x1000)
+			regFile.WriteReg(0, 0xCAFE)
+
+			lsu.STR32(0, 1, 4)
+
+			Expect(buf.String()).To(ContainSubstring("STR32"))
+			Expect(buf.String()).To(ContainSubstring("0x0000000000001004"))
+			Expect(buf.String()).To(ContainSubstring("0x0000CAFE"))
+		})
+
+		It("should stop logging once detached with nil", func() {
+			var buf bytes.Buffer
+			lsu.SetTracer(&buf)
+			lsu.SetTracer(nil)
+
+			lsu.LDR64(0, 1, 0)
+
+			Expect(buf.String()).To(BeEmpty())
+		})
+	})
 })
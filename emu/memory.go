@@ -3,33 +3,325 @@ package emu
 
 import "encoding/binary"
 
-// Memory provides a simple byte-addressable memory model for emulation.
+// PageSize is the size of a single page managed by Memory.
+const PageSize = 4096
+
+// pageMask isolates the page-aligned portion of an address.
+const pageMask = ^uint64(PageSize - 1)
+
+// FaultKind identifies the reason a memory access could not complete.
+type FaultKind int
+
+const (
+	// FaultNone indicates no fault occurred.
+	FaultNone FaultKind = iota
+	// FaultUnmapped indicates the access targeted a page with no mapping
+	// (the SIGSEGV-equivalent condition).
+	FaultUnmapped
+	// FaultProt indicates the access targeted a mapped page that lacks the
+	// required permission (e.g. writing to a read-only page, or fetching
+	// from a non-executable page).
+	FaultProt
+)
+
+// Fault describes a failed memory access. It implements error so callers
+// that only care about success/failure can treat it as a normal error,
+// while the CPU can inspect Kind/Addr/Op to emulate the corresponding
+// architectural exception.
+type Fault struct {
+	Kind FaultKind
+	Addr uint64
+	Op   string // "read", "write", or "exec"
+}
+
+// Error implements the error interface.
+func (f *Fault) Error() string {
+	switch f.Kind {
+	case FaultUnmapped:
+		return "page fault: unmapped address 0x" + hex64(f.Addr) + " (" + f.Op + ")"
+	case FaultProt:
+		return "protection fault: 0x" + hex64(f.Addr) + " (" + f.Op + ")"
+	default:
+		return "memory fault"
+	}
+}
+
+// hex64 formats a uint64 as lowercase hex without leading zeros.
+func hex64(v uint64) string {
+	const digits = "0123456789abcdef"
+	if v == 0 {
+		return "0"
+	}
+	var buf [16]byte
+	i := len(buf)
+	for v > 0 {
+		i--
+		buf[i] = digits[v&0xF]
+		v >>= 4
+	}
+	return string(buf[i:])
+}
+
+// page is a single fixed-size page of memory with its own permission bits.
+type page struct {
+	data [PageSize]byte
+	prot int // combination of PROT_READ, PROT_WRITE, PROT_EXEC
+}
+
+// Memory is a page-table-backed address space for ARM64 emulation. Pages
+// are allocated lazily as they are mapped; unmapped addresses fault rather
+// than silently reading/writing zero, matching real kernel behavior.
+//
+// Read8/Write8/Read32/Write32/Read64/Write64 remain a thin facade over the
+// page table so existing callers (ALU, LoadStoreUnit, etc.) are unaffected;
+// they record a *Fault via TakeFault() instead of returning one directly.
 type Memory struct {
-	data map[uint64]byte
+	pages map[uint64]*page
+
+	programBreak uint64 // current brk
+	mmapNext     uint64 // bump allocator for non-fixed anonymous mmaps
+
+	lastFault *Fault
+
+	// onExecWrite, if set, is notified whenever a write lands on a page
+	// with the executable bit set, so an instruction cache built on top of
+	// Memory (see ICache) can invalidate the affected decode.
+	onExecWrite func(addr uint64)
+
+	// onWrite, if set, is notified of every successful write with the
+	// byte's previous and new value, so a trace recorder (see package
+	// trace) can log memory writes without Memory knowing anything about
+	// tracing.
+	onWrite func(addr uint64, old, newVal byte)
+}
+
+// SetExecWriteObserver registers fn to be called with the address of any
+// write that targets executable memory. Only one observer is supported at
+// a time; passing nil disables the notification.
+func (m *Memory) SetExecWriteObserver(fn func(addr uint64)) {
+	m.onExecWrite = fn
+}
+
+// SetWriteObserver registers fn to be called with the address, previous
+// value, and new value of every successful write, regardless of the
+// target page's permissions. Only one observer is supported at a time;
+// passing nil disables the notification.
+func (m *Memory) SetWriteObserver(fn func(addr uint64, old, newVal byte)) {
+	m.onWrite = fn
 }
 
-// NewMemory creates a new memory instance.
+// Default layout constants for the managed address space.
+const (
+	DefaultBrkBase  uint64 = 0x0000_0001_0000_0000
+	DefaultMmapBase uint64 = 0x0000_0040_0000_0000
+)
+
+// NewMemory creates a new, empty page-table-backed memory instance.
 func NewMemory() *Memory {
 	return &Memory{
-		data: make(map[uint64]byte),
+		pages:        make(map[uint64]*page),
+		programBreak: DefaultBrkBase,
+		mmapNext:     DefaultMmapBase,
+	}
+}
+
+// TakeFault returns and clears the most recently recorded fault, or nil if
+// the last access succeeded. The CPU should call this after any memory
+// operation it wants to fault on.
+func (m *Memory) TakeFault() *Fault {
+	f := m.lastFault
+	m.lastFault = nil
+	return f
+}
+
+func (m *Memory) fault(kind FaultKind, addr uint64, op string) {
+	m.lastFault = &Fault{Kind: kind, Addr: addr, Op: op}
+}
+
+// pageFor returns the page backing addr, recording a fault and returning
+// nil if it is unmapped or lacks the requested permission bit.
+//
+// Addresses below DefaultBrkBase are auto-mapped RWX on first touch
+// instead of faulting. That low region models the conventional
+// text/data/bss/stack addresses a test or a direct RegFile/Memory caller
+// pokes without going through Mmap first, so callers written before this
+// page-table rewrite keep working unchanged; brk and mmap-managed memory
+// at or above DefaultBrkBase still fault until explicitly mapped, which
+// is the behavior this rewrite exists to model.
+func (m *Memory) pageFor(addr uint64, need int, op string) *page {
+	base := addr & pageMask
+	p, ok := m.pages[base]
+	if !ok {
+		if base < DefaultBrkBase {
+			p = &page{prot: PROT_READ | PROT_WRITE | PROT_EXEC}
+			m.pages[base] = p
+			return p
+		}
+		m.fault(FaultUnmapped, addr, op)
+		return nil
+	}
+	if p.prot&need == 0 {
+		m.fault(FaultProt, addr, op)
+		return nil
+	}
+	return p
+}
+
+// Mmap maps a region of length bytes (rounded up to a whole number of
+// pages) with the given protection. If addr is non-zero and fixed is true
+// the mapping is placed at exactly addr (MAP_FIXED semantics); otherwise a
+// fresh region is allocated above the mmap base. It returns the start
+// address of the mapping.
+func (m *Memory) Mmap(addr uint64, length uint64, prot int, fixed bool) uint64 {
+	if length == 0 {
+		return 0
+	}
+
+	nPages := (length + PageSize - 1) / PageSize
+
+	var base uint64
+	if fixed && addr != 0 {
+		base = addr & pageMask
+	} else {
+		base = m.mmapNext
+		m.mmapNext += nPages * PageSize
+	}
+
+	for i := uint64(0); i < nPages; i++ {
+		m.pages[base+i*PageSize] = &page{prot: prot}
 	}
+
+	return base
 }
 
-// Read8 reads a single byte from memory.
+// Munmap removes the mapping covering [addr, addr+length).
+func (m *Memory) Munmap(addr uint64, length uint64) {
+	if length == 0 {
+		return
+	}
+
+	base := addr & pageMask
+	nPages := (length + PageSize - 1) / PageSize
+	for i := uint64(0); i < nPages; i++ {
+		delete(m.pages, base+i*PageSize)
+	}
+}
+
+// Mprotect changes the permission bits of the mapping covering
+// [addr, addr+length). Pages outside any existing mapping are ignored.
+func (m *Memory) Mprotect(addr uint64, length uint64, prot int) {
+	if length == 0 {
+		return
+	}
+
+	base := addr & pageMask
+	nPages := (length + PageSize - 1) / PageSize
+	for i := uint64(0); i < nPages; i++ {
+		if p, ok := m.pages[base+i*PageSize]; ok {
+			p.prot = prot
+		}
+	}
+}
+
+// Brk adjusts the program break. addr == 0 queries the current break.
+// Growing the break maps fresh RW pages to cover the new region; shrinking
+// it unmaps pages that fall below the new break. It returns the resulting
+// break address.
+func (m *Memory) Brk(addr uint64) uint64 {
+	if addr == 0 || addr == m.programBreak {
+		return m.programBreak
+	}
+
+	if addr > m.programBreak {
+		growStart := (m.programBreak + PageSize - 1) & pageMask
+		if addr > growStart {
+			m.Mmap(growStart, addr-growStart, PROT_READ|PROT_WRITE, true)
+		}
+		m.programBreak = addr
+		return m.programBreak
+	}
+
+	shrinkFrom := (addr + PageSize - 1) & pageMask
+	oldTop := (m.programBreak + PageSize - 1) & pageMask
+	if oldTop > shrinkFrom {
+		m.Munmap(shrinkFrom, oldTop-shrinkFrom)
+	}
+	m.programBreak = addr
+	return m.programBreak
+}
+
+// MapAnonymous is a convenience wrapper used by loaders to map freshly
+// zeroed pages (e.g. a segment's BSS tail) with the given protection at a
+// fixed address.
+func (m *Memory) MapAnonymous(addr uint64, length uint64, prot int) {
+	m.Mmap(addr, length, prot, true)
+}
+
+// FetchInst fetches a 32-bit instruction word for execution, enforcing
+// that the containing page is mapped and executable.
+func (m *Memory) FetchInst(addr uint64) (uint32, *Fault) {
+	p := m.pageFor(addr, PROT_EXEC, "exec")
+	if p == nil {
+		return 0, m.TakeFault()
+	}
+	off := addr & (PageSize - 1)
+	// An instruction never straddles a page boundary at 4-byte alignment.
+	return binary.LittleEndian.Uint32(p.data[off : off+4]), nil
+}
+
+// Read8 reads a single byte from memory. On an unmapped or unreadable
+// address it returns 0 and records a fault retrievable via TakeFault.
 func (m *Memory) Read8(addr uint64) byte {
-	return m.data[addr]
+	p := m.pageFor(addr, PROT_READ, "read")
+	if p == nil {
+		return 0
+	}
+	return p.data[addr&(PageSize-1)]
 }
 
-// Write8 writes a single byte to memory.
+// Write8 writes a single byte to memory. On an unmapped or unwritable
+// address the write is dropped and a fault is recorded.
 func (m *Memory) Write8(addr uint64, value byte) {
-	m.data[addr] = value
+	p := m.pageFor(addr, PROT_WRITE, "write")
+	if p == nil {
+		return
+	}
+	off := addr & (PageSize - 1)
+	old := p.data[off]
+	p.data[off] = value
+
+	if m.onWrite != nil {
+		m.onWrite(addr, old, value)
+	}
+
+	if p.prot&PROT_EXEC != 0 && m.onExecWrite != nil {
+		m.onExecWrite(addr)
+	}
+}
+
+// Read16 reads a 16-bit little-endian value from memory.
+func (m *Memory) Read16(addr uint64) uint16 {
+	var buf [2]byte
+	for i := uint64(0); i < 2; i++ {
+		buf[i] = m.Read8(addr + i)
+	}
+	return binary.LittleEndian.Uint16(buf[:])
+}
+
+// Write16 writes a 16-bit little-endian value to memory.
+func (m *Memory) Write16(addr uint64, value uint16) {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], value)
+	for i := uint64(0); i < 2; i++ {
+		m.Write8(addr+i, buf[i])
+	}
 }
 
 // Read32 reads a 32-bit little-endian value from memory.
 func (m *Memory) Read32(addr uint64) uint32 {
 	var buf [4]byte
 	for i := uint64(0); i < 4; i++ {
-		buf[i] = m.data[addr+i]
+		buf[i] = m.Read8(addr + i)
 	}
 	return binary.LittleEndian.Uint32(buf[:])
 }
@@ -39,7 +331,7 @@ func (m *Memory) Write32(addr uint64, value uint32) {
 	var buf [4]byte
 	binary.LittleEndian.PutUint32(buf[:], value)
 	for i := uint64(0); i < 4; i++ {
-		m.data[addr+i] = buf[i]
+		m.Write8(addr+i, buf[i])
 	}
 }
 
@@ -47,7 +339,7 @@ func (m *Memory) Write32(addr uint64, value uint32) {
 func (m *Memory) Read64(addr uint64) uint64 {
 	var buf [8]byte
 	for i := uint64(0); i < 8; i++ {
-		buf[i] = m.data[addr+i]
+		buf[i] = m.Read8(addr + i)
 	}
 	return binary.LittleEndian.Uint64(buf[:])
 }
@@ -57,6 +349,67 @@ func (m *Memory) Write64(addr uint64, value uint64) {
 	var buf [8]byte
 	binary.LittleEndian.PutUint64(buf[:], value)
 	for i := uint64(0); i < 8; i++ {
-		m.data[addr+i] = buf[i]
+		m.Write8(addr+i, buf[i])
+	}
+}
+
+// ReadBytes fills buf from memory starting at addr. Unaligned leading and
+// trailing bytes go through Read8 one at a time, but the 8-byte-aligned
+// middle of buf is filled with Read64 calls, so a cache-line-sized refill
+// issues roughly a tenth as many calls as the equivalent Read8 loop.
+func (m *Memory) ReadBytes(addr uint64, buf []byte) {
+	i := 0
+	for i < len(buf) && addr%8 != 0 {
+		buf[i] = m.Read8(addr)
+		i++
+		addr++
+	}
+	for len(buf)-i >= 8 {
+		binary.LittleEndian.PutUint64(buf[i:], m.Read64(addr))
+		i += 8
+		addr += 8
+	}
+	for i < len(buf) {
+		buf[i] = m.Read8(addr)
+		i++
+		addr++
+	}
+}
+
+// WriteBytes writes buf to memory starting at addr. Unaligned leading and
+// trailing bytes go through Write8 one at a time, but the 8-byte-aligned
+// middle of buf is written with Write64 calls, so a cache-line-sized
+// writeback issues roughly a tenth as many calls as the equivalent Write8
+// loop.
+func (m *Memory) WriteBytes(addr uint64, buf []byte) {
+	i := 0
+	for i < len(buf) && addr%8 != 0 {
+		m.Write8(addr, buf[i])
+		i++
+		addr++
+	}
+	for len(buf)-i >= 8 {
+		m.Write64(addr, binary.LittleEndian.Uint64(buf[i:]))
+		i += 8
+		addr += 8
+	}
+	for i < len(buf) {
+		m.Write8(addr, buf[i])
+		i++
+		addr++
+	}
+}
+
+// LoadProgram maps len(program) bytes (rounded up to whole pages) starting
+// at entry with RWX permission and copies program into them. It is a
+// convenience used by tests and simple callers that do not go through the
+// ELF loader.
+func (m *Memory) LoadProgram(entry uint64, program []byte) {
+	if len(program) == 0 {
+		return
+	}
+	m.Mmap(entry, uint64(len(program)), PROT_READ|PROT_WRITE|PROT_EXEC, true)
+	for i, b := range program {
+		m.Write8(entry+uint64(i), b)
 	}
 }
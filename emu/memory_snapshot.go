@@ -0,0 +1,105 @@
+package emu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// memorySnapshotMagic and memorySnapshotVersion identify the binary
+// format Snapshot writes and Restore reads, so a future format change
+// fails loudly on old snapshots instead of silently misreading them.
+const (
+	memorySnapshotMagic   uint32 = 0x4D32534D // "M2SM"
+	memorySnapshotVersion uint32 = 1
+)
+
+// Snapshot writes every currently-mapped page's address, protection bits,
+// and contents to w, along with the program break and mmap bump
+// allocator position, so Restore can reconstruct an equivalent Memory
+// without replaying whatever syscalls or loads built this one.
+func (m *Memory) Snapshot(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, memorySnapshotMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, memorySnapshotVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, m.programBreak); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, m.mmapNext); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(m.pages))); err != nil {
+		return err
+	}
+
+	for addr, p := range m.pages {
+		if err := binary.Write(w, binary.LittleEndian, addr); err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, int32(p.prot)); err != nil {
+			return err
+		}
+		if _, err := w.Write(p.data[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore replaces m's entire address space with the pages, program
+// break, and mmap allocator position r was snapshotted from. It returns
+// an error rather than partially restoring if the stream's magic or
+// version doesn't match, or if it's truncated.
+func (m *Memory) Restore(r io.Reader) error {
+	var magic, version uint32
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
+		return fmt.Errorf("emu: reading memory snapshot magic: %w", err)
+	}
+	if magic != memorySnapshotMagic {
+		return fmt.Errorf("emu: memory snapshot has bad magic 0x%08X, want 0x%08X", magic, memorySnapshotMagic)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return fmt.Errorf("emu: reading memory snapshot version: %w", err)
+	}
+	if version != memorySnapshotVersion {
+		return fmt.Errorf("emu: memory snapshot version %d is not supported (want %d)", version, memorySnapshotVersion)
+	}
+
+	var programBreak, mmapNext, numPages uint64
+	if err := binary.Read(r, binary.LittleEndian, &programBreak); err != nil {
+		return fmt.Errorf("emu: reading memory snapshot program break: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &mmapNext); err != nil {
+		return fmt.Errorf("emu: reading memory snapshot mmap cursor: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &numPages); err != nil {
+		return fmt.Errorf("emu: reading memory snapshot page count: %w", err)
+	}
+
+	pages := make(map[uint64]*page, numPages)
+	for i := uint64(0); i < numPages; i++ {
+		var addr uint64
+		var prot int32
+		if err := binary.Read(r, binary.LittleEndian, &addr); err != nil {
+			return fmt.Errorf("emu: reading memory snapshot page %d address: %w", i, err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &prot); err != nil {
+			return fmt.Errorf("emu: reading memory snapshot page %d protection: %w", i, err)
+		}
+		p := &page{prot: int(prot)}
+		if _, err := io.ReadFull(r, p.data[:]); err != nil {
+			return fmt.Errorf("emu: reading memory snapshot page %d data: %w", i, err)
+		}
+		pages[addr] = p
+	}
+
+	m.pages = pages
+	m.programBreak = programBreak
+	m.mmapNext = mmapNext
+	m.lastFault = nil
+	return nil
+}
@@ -0,0 +1,53 @@
+package emu_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Memory Snapshot/Restore", func() {
+	It("should round-trip mapped pages, their protection, and the mmap/brk cursors", func() {
+		src := emu.NewMemory()
+		src.Mmap(0x10000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+		src.Write64(0x10000, 0x1122334455667788)
+		src.Brk(src.Brk(0) + emu.PageSize)
+
+		var buf bytes.Buffer
+		Expect(src.Snapshot(&buf)).To(Succeed())
+
+		dst := emu.NewMemory()
+		Expect(dst.Restore(&buf)).To(Succeed())
+
+		Expect(dst.Read64(0x10000)).To(Equal(uint64(0x1122334455667788)))
+		Expect(dst.TakeFault()).To(BeNil())
+
+		// A write to the restored page should still respect its protection.
+		dst.Write64(0x10000, 0xCAFE)
+		Expect(dst.TakeFault()).To(BeNil())
+	})
+
+	It("should reject a snapshot with a bad magic number", func() {
+		dst := emu.NewMemory()
+		err := dst.Restore(bytes.NewReader([]byte{0, 0, 0, 0}))
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a snapshot with an unsupported version", func() {
+		src := emu.NewMemory()
+
+		var buf bytes.Buffer
+		Expect(src.Snapshot(&buf)).To(Succeed())
+		raw := buf.Bytes()
+		raw[4] = 0xFF // corrupt the version field just past the magic
+
+		dst := emu.NewMemory()
+		err := dst.Restore(bytes.NewReader(raw))
+
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,174 @@
+// Package emu provides functional ARM64 emulation.
+package emu_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Memory", func() {
+	var memory *emu.Memory
+
+	BeforeEach(func() {
+		memory = emu.NewMemory()
+	})
+
+	Describe("unmapped access", func() {
+		It("should fault when reading an unmapped address", func() {
+			// Addresses below DefaultBrkBase are an auto-mapped RWX region
+			// (see Memory.pageFor) for backward compatibility with callers
+			// that poke conventional text/data/bss/stack addresses without
+			// an explicit Mmap call, so this test targets the managed brk
+			// region instead, which still faults until actually mapped.
+			memory.Read8(emu.DefaultBrkBase)
+
+			fault := memory.TakeFault()
+			Expect(fault).ToNot(BeNil())
+			Expect(fault.Kind).To(Equal(emu.FaultUnmapped))
+			Expect(fault.Addr).To(Equal(uint64(emu.DefaultBrkBase)))
+		})
+
+		It("should not record a fault after a successful access", func() {
+			memory.Mmap(0x2000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+			memory.Write8(0x2000, 0xAB)
+			memory.Read8(0x2000)
+
+			Expect(memory.TakeFault()).To(BeNil())
+		})
+	})
+
+	Describe("Mmap/Munmap", func() {
+		It("should make a mapped region readable and writable", func() {
+			addr := memory.Mmap(0x3000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+			Expect(addr).To(Equal(uint64(0x3000)))
+
+			memory.Write64(addr, 0xDEADBEEF)
+			Expect(memory.Read64(addr)).To(Equal(uint64(0xDEADBEEF)))
+			Expect(memory.TakeFault()).To(BeNil())
+		})
+
+		It("should fault on access after Munmap", func() {
+			// Same reasoning as above: use an address at/above
+			// DefaultBrkBase so the auto-mapped low region doesn't
+			// silently re-map it right back after Munmap.
+			addr := emu.DefaultBrkBase
+			memory.Mmap(addr, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+			memory.Munmap(addr, emu.PageSize)
+
+			memory.Read8(addr)
+			Expect(memory.TakeFault().Kind).To(Equal(emu.FaultUnmapped))
+		})
+
+		It("should allocate non-fixed mappings above the mmap base without overlap", func() {
+			a := memory.Mmap(0, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, false)
+			b := memory.Mmap(0, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, false)
+			Expect(b).To(Equal(a + emu.PageSize))
+		})
+	})
+
+	Describe("Mprotect", func() {
+		It("should fault on write to a read-only page", func() {
+			addr := memory.Mmap(0x5000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+			memory.Mprotect(addr, emu.PageSize, emu.PROT_READ)
+
+			memory.Write8(addr, 1)
+			Expect(memory.TakeFault().Kind).To(Equal(emu.FaultProt))
+		})
+
+		It("should fault on fetch from a non-executable page", func() {
+			addr := memory.Mmap(0x6000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+
+			_, fault := memory.FetchInst(addr)
+			Expect(fault).ToNot(BeNil())
+			Expect(fault.Kind).To(Equal(emu.FaultProt))
+		})
+
+		It("should allow fetch once the exec bit is granted", func() {
+			addr := memory.Mmap(0x7000, emu.PageSize, emu.PROT_READ|emu.PROT_EXEC, true)
+			memory.Write32(addr, 0x12345678)
+
+			word, fault := memory.FetchInst(addr)
+			Expect(fault).To(BeNil())
+			Expect(word).To(Equal(uint32(0x12345678)))
+		})
+	})
+
+	Describe("Brk", func() {
+		It("should report the default break when queried with 0", func() {
+			Expect(memory.Brk(0)).To(Equal(emu.DefaultBrkBase))
+		})
+
+		It("should grow the break and make the new region writable", func() {
+			newBreak := emu.DefaultBrkBase + emu.PageSize
+			Expect(memory.Brk(newBreak)).To(Equal(newBreak))
+
+			memory.Write64(emu.DefaultBrkBase, 42)
+			Expect(memory.Read64(emu.DefaultBrkBase)).To(Equal(uint64(42)))
+			Expect(memory.TakeFault()).To(BeNil())
+		})
+
+		It("should shrink the break and unmap the freed pages", func() {
+			grown := emu.DefaultBrkBase + emu.PageSize
+			memory.Brk(grown)
+
+			memory.Brk(emu.DefaultBrkBase)
+
+			memory.Read8(emu.DefaultBrkBase)
+			Expect(memory.TakeFault().Kind).To(Equal(emu.FaultUnmapped))
+		})
+	})
+
+	Describe("SetExecWriteObserver", func() {
+		It("should notify the observer only when writing to executable memory", func() {
+			rwAddr := memory.Mmap(0x9000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+			rxAddr := memory.Mmap(0xA000, emu.PageSize, emu.PROT_READ|emu.PROT_EXEC|emu.PROT_WRITE, true)
+
+			var notified []uint64
+			memory.SetExecWriteObserver(func(addr uint64) {
+				notified = append(notified, addr)
+			})
+
+			memory.Write8(rwAddr, 1)
+			memory.Write8(rxAddr, 1)
+
+			Expect(notified).To(Equal([]uint64{rxAddr}))
+		})
+	})
+
+	Describe("SetWriteObserver", func() {
+		It("should notify the observer of every write with the old and new value", func() {
+			addr := memory.Mmap(0xC000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE, true)
+
+			type write struct {
+				addr     uint64
+				old, new byte
+			}
+			var notified []write
+			memory.SetWriteObserver(func(a uint64, old, newVal byte) {
+				notified = append(notified, write{addr: a, old: old, new: newVal})
+			})
+
+			memory.Write8(addr, 0x42)
+			memory.Write8(addr, 0x43)
+
+			Expect(notified).To(Equal([]write{
+				{addr: addr, old: 0x00, new: 0x42},
+				{addr: addr, old: 0x42, new: 0x43},
+			}))
+		})
+	})
+
+	Describe("LoadProgram", func() {
+		It("should map and copy program bytes as RWX", func() {
+			program := []byte{0x01, 0x02, 0x03, 0x04}
+			memory.LoadProgram(0x8000, program)
+
+			Expect(memory.Read32(0x8000)).To(Equal(uint32(0x04030201)))
+
+			_, fault := memory.FetchInst(0x8000)
+			Expect(fault).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,60 @@
+//go:build !linux
+
+package emu
+
+import "errors"
+
+// NewMmapMemory is unsupported outside Linux, where MmapMemory's growth
+// and msync strategy (see mmap_memory.go) rely on raw syscall numbers that
+// aren't available on other platforms through the stdlib syscall package.
+func NewMmapMemory(path string) (*MmapMemory, error) {
+	return nil, errors.New("emu: mmap memory: unsupported on this platform")
+}
+
+// MmapMemory is declared here so the package still compiles on
+// non-Linux platforms; NewMmapMemory above is the only way to construct
+// one, and it always fails.
+type MmapMemory struct{}
+
+// Read8 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Read8(addr uint64) byte { return 0 }
+
+// Write8 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Write8(addr uint64, value byte) {}
+
+// Read16 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Read16(addr uint64) uint16 { return 0 }
+
+// Write16 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Write16(addr uint64, value uint16) {}
+
+// Read32 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Read32(addr uint64) uint32 { return 0 }
+
+// Write32 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Write32(addr uint64, value uint32) {}
+
+// Read64 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Read64(addr uint64) uint64 { return 0 }
+
+// Write64 is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Write64(addr uint64, value uint64) {}
+
+// ReadBytes is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) ReadBytes(addr uint64, buf []byte) {}
+
+// WriteBytes is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) WriteBytes(addr uint64, buf []byte) {}
+
+// Close is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Close() error { return nil }
+
+// Snapshot is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Snapshot(path string) error {
+	return errors.New("emu: mmap memory: unsupported on this platform")
+}
+
+// Restore is never reachable: NewMmapMemory always fails on this platform.
+func (m *MmapMemory) Restore(path string) error {
+	return errors.New("emu: mmap memory: unsupported on this platform")
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package emu_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("MmapMemory", func() {
+	It("should read back what it writes, growing the mapping past its initial size", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "mem.img")
+		m, err := emu.NewMmapMemory(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer m.Close()
+
+		m.Write64(0, 0x1122334455667788)
+		m.Write32(10*emu.PageSize, 0xCAFEBABE)
+
+		Expect(m.Read64(0)).To(Equal(uint64(0x1122334455667788)))
+		Expect(m.Read32(10 * emu.PageSize)).To(Equal(uint32(0xCAFEBABE)))
+	})
+
+	It("should persist across a Close and a fresh NewMmapMemory on the same path", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "mem.img")
+		m, err := emu.NewMmapMemory(path)
+		Expect(err).NotTo(HaveOccurred())
+		m.Write64(0x1000, 0xDEADBEEF)
+		Expect(m.Close()).To(Succeed())
+
+		reopened, err := emu.NewMmapMemory(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer reopened.Close()
+
+		Expect(reopened.Read64(0x1000)).To(Equal(uint64(0xDEADBEEF)))
+	})
+
+	It("should round-trip a Snapshot/Restore checkpoint", func() {
+		dir := GinkgoT().TempDir()
+		m, err := emu.NewMmapMemory(filepath.Join(dir, "mem.img"))
+		Expect(err).NotTo(HaveOccurred())
+		defer m.Close()
+
+		m.Write64(0x2000, 0xAAAABBBBCCCCDDDD)
+		checkpoint := filepath.Join(dir, "checkpoint.img")
+		Expect(m.Snapshot(checkpoint)).To(Succeed())
+
+		m.Write64(0x2000, 0)
+
+		Expect(m.Restore(checkpoint)).To(Succeed())
+		Expect(m.Read64(0x2000)).To(Equal(uint64(0xAAAABBBBCCCCDDDD)))
+	})
+})
@@ -0,0 +1,120 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+// Pseudo-op HLT immediates. gem5 carves a range out of HLT's 16-bit
+// immediate so guest binaries can signal the simulator directly, without
+// routing through the host kernel the way SVC does. pseudoOpImmBase picks
+// a range (0x50xx, read as "M5xx" for gem5's "m5ops") that real HLT usage
+// (debugger breakpoints, which conventionally encode #0) never touches.
+const pseudoOpImmBase uint16 = 0x5000
+
+// Pseudo-op immediates, each pseudoOpImmBase plus a one-byte op selector.
+const (
+	PseudoOpExit       uint16 = pseudoOpImmBase | 0x00
+	PseudoOpDumpStats  uint16 = pseudoOpImmBase | 0x01
+	PseudoOpResetStats uint16 = pseudoOpImmBase | 0x02
+	PseudoOpCheckpoint uint16 = pseudoOpImmBase | 0x03
+	PseudoOpPanic      uint16 = pseudoOpImmBase | 0x04
+	PseudoOpWorkBegin  uint16 = pseudoOpImmBase | 0x05
+	PseudoOpWorkEnd    uint16 = pseudoOpImmBase | 0x06
+)
+
+// maxPseudoOpStringLen bounds Checkpoint/Panic string reads so a corrupt or
+// malicious guest pointer can't walk memory forever looking for a NUL.
+const maxPseudoOpStringLen = 4096
+
+// PseudoOpHandler implements gem5-style "M5 op" simulation-control
+// pseudo-instructions: HLT immediates in the PseudoOp* range that let a
+// guest binary talk to the simulator directly (exit, stats control,
+// checkpointing, and region-of-interest markers) the same way SVC lets it
+// talk to the emulated kernel. A nil handler on the Emulator makes every
+// pseudo-op other than PseudoOpExit a no-op; Exit always halts Step
+// regardless, matching the exit syscall's behavior.
+type PseudoOpHandler interface {
+	// Exit terminates the program with the given status code.
+	Exit(code int64)
+
+	// DumpStats asks the simulator to emit its current statistics without
+	// resetting them.
+	DumpStats()
+
+	// ResetStats asks the simulator to zero its statistics, typically at
+	// the start of a region of interest.
+	ResetStats()
+
+	// Checkpoint asks the simulator to save a checkpoint under name.
+	Checkpoint(name string)
+
+	// Panic reports a guest-detected fatal error with a diagnostic message.
+	Panic(msg string)
+
+	// WorkBegin marks the start of region-of-interest workID on threadID.
+	WorkBegin(workID, threadID uint64)
+
+	// WorkEnd marks the end of region-of-interest workID on threadID.
+	WorkEnd(workID, threadID uint64)
+}
+
+// executePseudoOp handles a HLT instruction whose immediate falls in the
+// PseudoOp* range, routing it to e.pseudoOpHandler. PC is advanced first,
+// matching executeSVC, since a pseudo-op's "return address" is the next
+// instruction.
+func (e *Emulator) executePseudoOp(imm uint16) StepResult {
+	e.regFile.PC += 4
+
+	if imm == PseudoOpExit {
+		code := int64(e.regFile.ReadReg(0))
+		if e.pseudoOpHandler != nil {
+			e.pseudoOpHandler.Exit(code)
+		}
+		return StepResult{Exited: true, ExitCode: code}
+	}
+
+	if e.pseudoOpHandler == nil {
+		return StepResult{}
+	}
+
+	switch imm {
+	case PseudoOpDumpStats:
+		e.pseudoOpHandler.DumpStats()
+	case PseudoOpResetStats:
+		e.pseudoOpHandler.ResetStats()
+	case PseudoOpCheckpoint:
+		e.pseudoOpHandler.Checkpoint(e.readPseudoOpString(e.regFile.ReadReg(0)))
+	case PseudoOpPanic:
+		e.pseudoOpHandler.Panic(e.readPseudoOpString(e.regFile.ReadReg(0)))
+	case PseudoOpWorkBegin:
+		e.pseudoOpHandler.WorkBegin(e.regFile.ReadReg(0), e.regFile.ReadReg(1))
+	case PseudoOpWorkEnd:
+		e.pseudoOpHandler.WorkEnd(e.regFile.ReadReg(0), e.regFile.ReadReg(1))
+	}
+
+	return StepResult{}
+}
+
+// isPseudoOpImm reports whether imm falls in the range executePseudoOp
+// recognizes.
+func isPseudoOpImm(imm uint16) bool {
+	switch imm {
+	case PseudoOpExit, PseudoOpDumpStats, PseudoOpResetStats, PseudoOpCheckpoint,
+		PseudoOpPanic, PseudoOpWorkBegin, PseudoOpWorkEnd:
+		return true
+	default:
+		return false
+	}
+}
+
+// readPseudoOpString reads a null-terminated string from memory, the same
+// convention DefaultSyscallHandler.readString uses for openat's pathname.
+func (e *Emulator) readPseudoOpString(addr uint64) string {
+	var buf []byte
+	for i := 0; i < maxPseudoOpStringLen; i++ {
+		b := e.memory.Read8(addr)
+		if b == 0 {
+			break
+		}
+		buf = append(buf, b)
+		addr++
+	}
+	return string(buf)
+}
@@ -0,0 +1,132 @@
+package emu_test
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// encodeHLT encodes a HLT #imm16 instruction: 11010100 010 imm16 00000.
+func encodeHLT(imm16 uint16) uint32 {
+	return 0xD4400000 | (uint32(imm16) << 5)
+}
+
+func pseudoOpProgram(inst uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, inst)
+	return buf
+}
+
+// recordingPseudoOpHandler records every call it receives, for assertions.
+type recordingPseudoOpHandler struct {
+	exitCode         int64
+	dumpStatsCalled  bool
+	resetStatsCalled bool
+	checkpointName   string
+	panicMsg         string
+	workBeginID      uint64
+	workBeginThread  uint64
+	workEndID        uint64
+	workEndThread    uint64
+}
+
+func (h *recordingPseudoOpHandler) Exit(code int64)     { h.exitCode = code }
+func (h *recordingPseudoOpHandler) DumpStats()          { h.dumpStatsCalled = true }
+func (h *recordingPseudoOpHandler) ResetStats()         { h.resetStatsCalled = true }
+func (h *recordingPseudoOpHandler) Checkpoint(n string) { h.checkpointName = n }
+func (h *recordingPseudoOpHandler) Panic(msg string)    { h.panicMsg = msg }
+func (h *recordingPseudoOpHandler) WorkBegin(workID, threadID uint64) {
+	h.workBeginID, h.workBeginThread = workID, threadID
+}
+func (h *recordingPseudoOpHandler) WorkEnd(workID, threadID uint64) {
+	h.workEndID, h.workEndThread = workID, threadID
+}
+
+var _ = Describe("Pseudo-ops", func() {
+	var (
+		e       *emu.Emulator
+		handler *recordingPseudoOpHandler
+	)
+
+	BeforeEach(func() {
+		handler = &recordingPseudoOpHandler{}
+		e = emu.NewEmulator(emu.WithPseudoOpHandler(handler))
+	})
+
+	It("should exit with the code in X0", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpExit)))
+		e.RegFile().WriteReg(0, 42)
+
+		result := e.Step()
+
+		Expect(result.Exited).To(BeTrue())
+		Expect(result.ExitCode).To(Equal(int64(42)))
+		Expect(handler.exitCode).To(Equal(int64(42)))
+	})
+
+	It("should exit even without a handler attached", func() {
+		bare := emu.NewEmulator()
+		bare.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpExit)))
+		bare.RegFile().WriteReg(0, 7)
+
+		result := bare.Step()
+
+		Expect(result.Exited).To(BeTrue())
+		Expect(result.ExitCode).To(Equal(int64(7)))
+	})
+
+	It("should route DumpStats and advance PC", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpDumpStats)))
+
+		result := e.Step()
+
+		Expect(result.Err).To(BeNil())
+		Expect(handler.dumpStatsCalled).To(BeTrue())
+		Expect(e.RegFile().PC).To(Equal(uint64(0x1004)))
+	})
+
+	It("should route ResetStats", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpResetStats)))
+
+		e.Step()
+
+		Expect(handler.resetStatsCalled).To(BeTrue())
+	})
+
+	It("should route Checkpoint with the name read from memory", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpCheckpoint)))
+		nameAddr := uint64(0x2000)
+		e.Memory().MapAnonymous(nameAddr, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE)
+		for i, b := range []byte("ckpt1\x00") {
+			e.Memory().Write8(nameAddr+uint64(i), b)
+		}
+		e.RegFile().WriteReg(0, nameAddr)
+
+		e.Step()
+
+		Expect(handler.checkpointName).To(Equal("ckpt1"))
+	})
+
+	It("should route WorkBegin/WorkEnd with workID and threadID", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(emu.PseudoOpWorkBegin)))
+		e.RegFile().WriteReg(0, 3)
+		e.RegFile().WriteReg(1, 1)
+
+		e.Step()
+
+		Expect(handler.workBeginID).To(Equal(uint64(3)))
+		Expect(handler.workBeginThread).To(Equal(uint64(1)))
+	})
+
+	It("should leave a plain HLT #0 as an ordinary unknown-range immediate untouched", func() {
+		e.LoadProgram(0x1000, pseudoOpProgram(encodeHLT(0)))
+
+		e.Step()
+
+		Expect(handler.dumpStatsCalled).To(BeFalse())
+		Expect(handler.resetStatsCalled).To(BeFalse())
+	})
+})
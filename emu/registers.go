@@ -0,0 +1,128 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import "encoding/binary"
+
+// PSTATE holds the four ARM64 condition flags (NZCV).
+//
+// N and Z are set together by every flag-setting instruction (there is no
+// ARM64 operation that writes one without the other), while C and V each
+// come from independent carry/overflow logic. SetNZ/SetC/SetV split flag
+// writes along that same boundary instead of exposing PSTATE as a single
+// opaque word, so a future out-of-order or pipelined backend can track
+// flag dataflow at bit-group granularity rather than serializing every
+// flag-producing instruction behind the whole PSTATE. ALU/VALU flag-setting
+// methods should write through these setters rather than the fields
+// directly wherever a future revision adds that tracking.
+type PSTATE struct {
+	N bool // Negative
+	Z bool // Zero
+	C bool // Carry
+	V bool // Overflow
+}
+
+// SetNZ writes the Negative and Zero flags together, as every ARM64
+// flag-setting instruction does.
+func (p *PSTATE) SetNZ(n, z bool) {
+	p.N = n
+	p.Z = z
+}
+
+// SetC writes the Carry flag independently of N/Z/V.
+func (p *PSTATE) SetC(c bool) {
+	p.C = c
+}
+
+// SetV writes the Overflow flag independently of N/Z/C.
+func (p *PSTATE) SetV(v bool) {
+	p.V = v
+}
+
+// RegFile holds the architectural state of a single ARM64 thread of
+// execution: the 31 general-purpose registers, SP, PC, PSTATE, and the
+// 32 128-bit SIMD/FP registers (V0-V31) with their control/status
+// registers.
+type RegFile struct {
+	gpr [31]uint64 // X0-X30
+	SP  uint64
+	PC  uint64
+
+	PSTATE PSTATE
+
+	// V holds the 32 128-bit SIMD/FP registers. ARM64 aliases each Vn
+	// across four widths (Qn = all 16 bytes, Dn = low 8 bytes, Sn = low 4
+	// bytes, Hn = low 2 bytes); the typed accessors below read and write
+	// through that same aliasing rather than keeping the widths separate.
+	V [32][16]byte
+
+	FPCR uint32 // Floating-point Control Register
+	FPSR uint32 // Floating-point Status Register
+}
+
+// ReadReg returns the value of Xn (n must be 0-30).
+func (r *RegFile) ReadReg(n uint8) uint64 {
+	return r.gpr[n]
+}
+
+// WriteReg sets Xn to value (n must be 0-30).
+func (r *RegFile) WriteReg(n uint8, value uint64) {
+	r.gpr[n] = value
+}
+
+// ReadV returns the full 128-bit value of Vn.
+func (r *RegFile) ReadV(n uint8) [16]byte {
+	return r.V[n]
+}
+
+// WriteV sets the full 128-bit value of Vn.
+func (r *RegFile) WriteV(n uint8, value [16]byte) {
+	r.V[n] = value
+}
+
+// ReadD returns the low 64 bits of Vn (the Dn view), little-endian.
+func (r *RegFile) ReadD(n uint8) uint64 {
+	return binary.LittleEndian.Uint64(r.V[n][0:8])
+}
+
+// WriteD sets the low 64 bits of Vn (the Dn view), zeroing the upper 64
+// bits as real hardware does for a write through a narrower view.
+func (r *RegFile) WriteD(n uint8, value uint64) {
+	r.V[n] = [16]byte{}
+	binary.LittleEndian.PutUint64(r.V[n][0:8], value)
+}
+
+// ReadS returns the low 32 bits of Vn (the Sn view), little-endian.
+func (r *RegFile) ReadS(n uint8) uint32 {
+	return binary.LittleEndian.Uint32(r.V[n][0:4])
+}
+
+// WriteS sets the low 32 bits of Vn (the Sn view), zeroing the rest of
+// the register.
+func (r *RegFile) WriteS(n uint8, value uint32) {
+	r.V[n] = [16]byte{}
+	binary.LittleEndian.PutUint32(r.V[n][0:4], value)
+}
+
+// ReadH returns the low 16 bits of Vn (the Hn view), little-endian.
+func (r *RegFile) ReadH(n uint8) uint16 {
+	return binary.LittleEndian.Uint16(r.V[n][0:2])
+}
+
+// WriteH sets the low 16 bits of Vn (the Hn view), zeroing the rest of
+// the register.
+func (r *RegFile) WriteH(n uint8, value uint16) {
+	r.V[n] = [16]byte{}
+	binary.LittleEndian.PutUint16(r.V[n][0:2], value)
+}
+
+// ReadB returns the low 8 bits of Vn (the Bn view).
+func (r *RegFile) ReadB(n uint8) uint8 {
+	return r.V[n][0]
+}
+
+// WriteB sets the low 8 bits of Vn (the Bn view), zeroing the rest of
+// the register.
+func (r *RegFile) WriteB(n uint8, value uint8) {
+	r.V[n] = [16]byte{}
+	r.V[n][0] = value
+}
@@ -0,0 +1,107 @@
+// Package sched models a table-driven instruction scheduling itinerary,
+// mirroring LLVM's InstrItinData/InstrStage subsystem: per-opcode issue
+// latency, forwarding behavior, and functional-unit occupancy, loaded from
+// a YAML file rather than baked into the timing pipeline's Go source. A
+// pipeline consults an *Itinerary for these facts instead of hardcoding
+// per-opcode constants in a switch statement, so recalibrating the model
+// against new hardware measurements is a matter of editing the YAML, not
+// the Go source.
+package sched
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FUClass names one functional-unit class an itinerary's resources and
+// per-opcode issue stages refer to (e.g. "ALU0", "LSU", "BRU"). Itinerary
+// doesn't constrain the set of names in use; it's whatever the loaded
+// YAML file defines resources for.
+type FUClass string
+
+// ForwardingClass describes how quickly an instruction's result becomes
+// visible to a dependent instruction's operand, relative to its nominal
+// OperandLatency.
+type ForwardingClass string
+
+// The ForwardingClass values a YAML itinerary file may specify.
+const (
+	// ForwardingNone means a dependent instruction must wait the full
+	// OperandLatency before issuing.
+	ForwardingNone ForwardingClass = "none"
+	// ForwardingBypass means the result reaches a dependent instruction
+	// one cycle earlier than OperandLatency alone would allow, as with
+	// an ALU-to-ALU bypass network.
+	ForwardingBypass ForwardingClass = "bypass"
+	// ForwardingFull means the result is available to a dependent
+	// instruction issuing in the very next cycle, regardless of
+	// OperandLatency (same-cycle result forwarding).
+	ForwardingFull ForwardingClass = "full"
+)
+
+// InstrItinData is one opcode's scheduling record: how many micro-ops it
+// decodes into, which functional-unit class each micro-op issues to, the
+// cycle latency of each operand (result) becoming available, and how
+// that result is forwarded to dependents.
+type InstrItinData struct {
+	NumMicroOps     int             `yaml:"num_micro_ops"`
+	IssueStages     []FUClass       `yaml:"issue_stages"`
+	OperandLatency  []int           `yaml:"operand_latency"`
+	ForwardingClass ForwardingClass `yaml:"forwarding_class"`
+}
+
+// FUResource describes one functional-unit class's pipeline resources:
+// how many copies of it exist (e.g. dual ALUs), and how many cycles it
+// takes to execute once issued, before OperandLatency/forwarding applies.
+type FUResource struct {
+	Class   FUClass `yaml:"class"`
+	Count   int     `yaml:"count"`
+	Latency int     `yaml:"latency"`
+}
+
+// Itinerary is a complete scheduling model: the pipeline's peak issue
+// width, its functional-unit resources, and a per-opcode table of
+// InstrItinData, loaded from a single YAML file such as
+// configs/m2_pcore.yaml.
+type Itinerary struct {
+	IssueWidth   int                      `yaml:"issue_width"`
+	Resources    []FUResource             `yaml:"resources"`
+	Instructions map[string]InstrItinData `yaml:"instructions"`
+}
+
+// LoadItinerary reads and parses a YAML itinerary file at path.
+func LoadItinerary(path string) (*Itinerary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("sched: reading itinerary %q: %w", path, err)
+	}
+
+	var it Itinerary
+	if err := yaml.Unmarshal(data, &it); err != nil {
+		return nil, fmt.Errorf("sched: parsing itinerary %q: %w", path, err)
+	}
+	return &it, nil
+}
+
+// Lookup returns the InstrItinData for opcode (e.g. "ADD", "LDR"), and
+// whether the itinerary defines one. Callers should fall back to a
+// conservative default (NumMicroOps: 1, single-cycle latency) for an
+// opcode the loaded model doesn't cover, rather than treating a miss as
+// fatal: a hand-edited YAML file is expected to grow incrementally.
+func (it *Itinerary) Lookup(opcode string) (InstrItinData, bool) {
+	data, ok := it.Instructions[opcode]
+	return data, ok
+}
+
+// Resource returns the FUResource for class, and whether the itinerary
+// defines one.
+func (it *Itinerary) Resource(class FUClass) (FUResource, bool) {
+	for _, r := range it.Resources {
+		if r.Class == class {
+			return r, true
+		}
+	}
+	return FUResource{}, false
+}
@@ -0,0 +1,81 @@
+package sched_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu/sched"
+)
+
+const testYAML = `
+issue_width: 6
+resources:
+  - class: ALU0
+    count: 1
+    latency: 1
+  - class: LSU
+    count: 2
+    latency: 4
+instructions:
+  ADD:
+    num_micro_ops: 1
+    issue_stages: [ALU0]
+    operand_latency: [1]
+    forwarding_class: bypass
+  LDR:
+    num_micro_ops: 1
+    issue_stages: [LSU]
+    operand_latency: [4]
+    forwarding_class: none
+`
+
+var _ = Describe("Itinerary", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "itinerary.yaml")
+		Expect(os.WriteFile(path, []byte(testYAML), 0644)).To(Succeed())
+	})
+
+	It("should load issue width, resources, and per-opcode data from YAML", func() {
+		it, err := sched.LoadItinerary(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(it.IssueWidth).To(Equal(6))
+
+		add, ok := it.Lookup("ADD")
+		Expect(ok).To(BeTrue())
+		Expect(add.NumMicroOps).To(Equal(1))
+		Expect(add.IssueStages).To(Equal([]sched.FUClass{"ALU0"}))
+		Expect(add.OperandLatency).To(Equal([]int{1}))
+		Expect(add.ForwardingClass).To(Equal(sched.ForwardingBypass))
+
+		lsu, ok := it.Resource("LSU")
+		Expect(ok).To(BeTrue())
+		Expect(lsu.Count).To(Equal(2))
+		Expect(lsu.Latency).To(Equal(4))
+	})
+
+	It("should report a miss for an opcode the itinerary doesn't cover", func() {
+		it, err := sched.LoadItinerary(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, ok := it.Lookup("MUL")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should report a miss for a resource class the itinerary doesn't cover", func() {
+		it, err := sched.LoadItinerary(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, ok := it.Resource("BRU")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return an error for a nonexistent file", func() {
+		_, err := sched.LoadItinerary(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+})
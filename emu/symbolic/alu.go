@@ -0,0 +1,107 @@
+package symbolic
+
+// lift computes witness, the concrete result, and only builds an Expr
+// tree (at the cost of an allocation) when at least one operand is
+// actually symbolic, so a path with no symbolic data still runs at
+// roughly the cost of plain uint64 arithmetic.
+func lift(kind ExprKind, l, r Value, witness uint64) Value {
+	if !l.IsSymbolic() && !r.IsSymbolic() {
+		return ConcreteValue(witness)
+	}
+	return SymbolicValue(bin(kind, l.AsExpr(), r.AsExpr()), witness)
+}
+
+// Add computes l+r, the symbolic lift of ADD.
+func Add(l, r Value) Value {
+	return lift(ExprAdd, l, r, l.Concrete+r.Concrete)
+}
+
+// Sub computes l-r, the symbolic lift of SUB.
+func Sub(l, r Value) Value {
+	return lift(ExprSub, l, r, l.Concrete-r.Concrete)
+}
+
+// And computes l&r, the symbolic lift of AND.
+func And(l, r Value) Value {
+	return lift(ExprAnd, l, r, l.Concrete&r.Concrete)
+}
+
+// Orr computes l|r, the symbolic lift of ORR.
+func Orr(l, r Value) Value {
+	return lift(ExprOr, l, r, l.Concrete|r.Concrete)
+}
+
+// Eor computes l^r, the symbolic lift of EOR.
+func Eor(l, r Value) Value {
+	return lift(ExprXor, l, r, l.Concrete^r.Concrete)
+}
+
+// Lsl computes l<<r, the symbolic lift of LSL.
+func Lsl(l, r Value) Value {
+	return lift(ExprShl, l, r, l.Concrete<<r.Concrete)
+}
+
+// Lsr computes l>>r as an unsigned shift, the symbolic lift of LSR.
+func Lsr(l, r Value) Value {
+	return lift(ExprLsr, l, r, l.Concrete>>r.Concrete)
+}
+
+// Asr computes l>>r as a signed, sign-extending shift, the symbolic lift
+// of ASR.
+func Asr(l, r Value) Value {
+	return lift(ExprAsr, l, r, uint64(int64(l.Concrete)>>r.Concrete))
+}
+
+// Eq reports whether l equals r, the symbolic lift of a CMP-derived Z
+// flag. The witness is 1 for true, 0 for false, matching how Not below
+// treats a Value as a boolean.
+func Eq(l, r Value) Value {
+	witness := uint64(0)
+	if l.Concrete == r.Concrete {
+		witness = 1
+	}
+	return lift(ExprEq, l, r, witness)
+}
+
+// Lt reports whether l is less than r as unsigned 64-bit integers, the
+// symbolic lift of a CMP-derived C flag.
+func Lt(l, r Value) Value {
+	witness := uint64(0)
+	if l.Concrete < r.Concrete {
+		witness = 1
+	}
+	return lift(ExprLt, l, r, witness)
+}
+
+// Not computes the boolean negation of v (0 becomes 1, anything nonzero
+// becomes 0), used to build the "condition not taken" path constraint in
+// Engine.fork.
+func Not(v Value) Value {
+	witness := uint64(0)
+	if v.Concrete == 0 {
+		witness = 1
+	}
+	if !v.IsSymbolic() {
+		return ConcreteValue(witness)
+	}
+	return SymbolicValue(&Expr{Kind: ExprNot, L: v.Expr}, witness)
+}
+
+// And2 computes the boolean AND of two 0/1-valued Values, used to combine
+// flag comparisons in evalCond (e.g. HI is C==1 && Z==0).
+func And2(l, r Value) Value {
+	witness := uint64(0)
+	if l.Concrete != 0 && r.Concrete != 0 {
+		witness = 1
+	}
+	return lift(ExprAnd, l, r, witness)
+}
+
+// Or2 computes the boolean OR of two 0/1-valued Values.
+func Or2(l, r Value) Value {
+	witness := uint64(0)
+	if l.Concrete != 0 || r.Concrete != 0 {
+		witness = 1
+	}
+	return lift(ExprOr, l, r, witness)
+}
@@ -0,0 +1,267 @@
+package symbolic
+
+import (
+	"errors"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/insts"
+)
+
+// ErrUnreachable is returned by FindInputsReaching when the worklist
+// empties without any feasible path reaching the target PC.
+var ErrUnreachable = errors.New("symbolic: target PC not reached by any feasible path")
+
+// Engine drives symbolic execution of an ARM64 program by wrapping an
+// emu.Emulator for instruction fetch/decode while State carries the
+// actual per-path register, flag, and memory values. Only a
+// representative instruction subset builds Exprs (executeDPReg's
+// ADD/SUB/AND/ORR/EOR and executeBranchCond's condition evaluation);
+// every other format executes on the concrete fast path (executeConcrete),
+// which drives the wrapped Emulator using each Value's witness and reads
+// the result back as concrete. Extending coverage to a new instruction is
+// mechanical: add a case to the relevant execute* that lifts through the
+// matching alu.go helper instead of reading/writing raw uint64s.
+type Engine struct {
+	Emulator *emu.Emulator
+	Solver   Solver
+
+	decoder *insts.Decoder
+}
+
+// NewEngine creates an Engine that fetches instructions from e's memory
+// and uses solver to decide which branch forks are feasible and to
+// produce concrete inputs. Pass NoOpSolver{} until a real binding (e.g.
+// Z3 or Boolector) is available.
+func NewEngine(e *emu.Emulator, solver Solver) *Engine {
+	return &Engine{Emulator: e, Solver: solver, decoder: insts.NewDecoder()}
+}
+
+// Step executes the instruction at state.PC, returning the successor
+// state(s): one in the common case, or two if state.PC held a
+// conditional branch whose condition turned out symbolic and both
+// directions are feasible per Solver.CheckSat. A direction the solver
+// rules out infeasible is silently dropped, the same as a worklist entry
+// that never gets queued. state itself is never mutated; every successor
+// is a Clone.
+func (e *Engine) Step(state *State) ([]*State, error) {
+	word, fault := e.Emulator.Memory().FetchInst(state.PC)
+	if fault != nil {
+		return nil, fault
+	}
+	inst := e.decoder.Decode(word)
+
+	switch inst.Format {
+	case insts.FormatBranchCond:
+		return e.executeBranchCond(state, inst)
+	case insts.FormatDPReg:
+		next := state.Clone()
+		e.executeDPReg(next, inst)
+		next.PC += 4
+		return []*State{next}, nil
+	default:
+		return e.executeConcrete(state, inst)
+	}
+}
+
+// executeDPReg lifts ADD/SUB/AND/ORR/EOR (register form) onto next's
+// Values, building an Expr when either operand is symbolic. Formats this
+// subset doesn't recognize (shifted-register forms, other DPReg ops) fall
+// through untouched; extending this switch is the mechanical step
+// described on Engine.
+func (e *Engine) executeDPReg(next *State, inst *insts.Instruction) {
+	rn := next.ReadReg(inst.Rn)
+	rm := next.ReadReg(inst.Rm)
+
+	var result Value
+	switch inst.Op {
+	case insts.OpADD:
+		result = Add(rn, rm)
+	case insts.OpSUB:
+		result = Sub(rn, rm)
+	case insts.OpAND:
+		result = And(rn, rm)
+	case insts.OpORR:
+		result = Orr(rn, rm)
+	case insts.OpEOR:
+		result = Eor(rn, rm)
+	default:
+		return
+	}
+
+	next.WriteReg(inst.Rd, result)
+
+	if inst.SetFlags {
+		// Only Z is modeled precisely for this subset; N/C/V would need
+		// per-op flag semantics (e.g. SUBS' carry-as-not-borrow) that a
+		// fuller lift should add alongside new Ops.
+		next.Flags.Z = Eq(result, ConcreteValue(0))
+	}
+}
+
+// executeBranchCond evaluates inst's condition against state.Flags. A
+// concrete condition steers state down a single path exactly like
+// emu.Emulator.executeBranchCond. A symbolic condition (because it
+// depends on a flag a symbolic compare set) forks instead.
+func (e *Engine) executeBranchCond(state *State, inst *insts.Instruction) ([]*State, error) {
+	cond := evalCond(state.Flags, inst.Cond)
+
+	if !cond.IsSymbolic() {
+		next := state.Clone()
+		if cond.Concrete != 0 {
+			next.PC = uint64(int64(next.PC) + inst.BranchOffset)
+		} else {
+			next.PC += 4
+		}
+		return []*State{next}, nil
+	}
+
+	return e.fork(state, cond, inst.BranchOffset)
+}
+
+// fork splits state into up to two successors along cond: one with cond
+// asserted (branch taken) and one with its negation (fall through), each
+// kept only if Solver reports its constraints satisfiable. Constraints
+// accumulate as a conjunction, so each successor's Constraints is state's
+// plus exactly one more conjunct.
+func (e *Engine) fork(state *State, cond Value, branchOffset int64) ([]*State, error) {
+	var successors []*State
+
+	taken := append(append([]*Expr(nil), state.Constraints...), cond.Expr)
+	sat, err := e.Solver.CheckSat(taken)
+	if err != nil {
+		return nil, err
+	}
+	if sat {
+		s := state.Clone()
+		s.Constraints = taken
+		s.PC = uint64(int64(s.PC) + branchOffset)
+		successors = append(successors, s)
+	}
+
+	notTaken := append(append([]*Expr(nil), state.Constraints...), Not(cond).Expr)
+	sat, err = e.Solver.CheckSat(notTaken)
+	if err != nil {
+		return nil, err
+	}
+	if sat {
+		s := state.Clone()
+		s.Constraints = notTaken
+		s.PC += 4
+		successors = append(successors, s)
+	}
+
+	return successors, nil
+}
+
+// evalCond evaluates an AArch64 condition code (the raw 4-bit encoding in
+// insts.Instruction.Cond) against flags, returning a symbolic Value
+// whenever any flag it reads is symbolic.
+func evalCond(flags Flags, code uint8) Value {
+	switch code {
+	case 0b0000: // EQ
+		return flags.Z
+	case 0b0001: // NE
+		return Not(flags.Z)
+	case 0b0010: // CS/HS
+		return flags.C
+	case 0b0011: // CC/LO
+		return Not(flags.C)
+	case 0b0100: // MI
+		return flags.N
+	case 0b0101: // PL
+		return Not(flags.N)
+	case 0b0110: // VS
+		return flags.V
+	case 0b0111: // VC
+		return Not(flags.V)
+	case 0b1000: // HI
+		return And2(flags.C, Not(flags.Z))
+	case 0b1001: // LS
+		return Not(And2(flags.C, Not(flags.Z)))
+	case 0b1010: // GE
+		return Eq(flags.N, flags.V)
+	case 0b1011: // LT
+		return Not(Eq(flags.N, flags.V))
+	case 0b1100: // GT
+		return And2(Not(flags.Z), Eq(flags.N, flags.V))
+	case 0b1101: // LE
+		return Not(And2(Not(flags.Z), Eq(flags.N, flags.V)))
+	default: // AL, NV
+		return ConcreteValue(1)
+	}
+}
+
+// executeConcrete runs inst on the wrapped Emulator using each operand
+// Value's witness, then reads the result back as concrete Values. This is
+// the fallback for every format executeBranchCond/executeDPReg don't
+// lift, so a symbolic component on a register or flag this path touches
+// is silently dropped to its witness; that is the coverage tradeoff
+// documented on Engine.
+func (e *Engine) executeConcrete(state *State, inst *insts.Instruction) ([]*State, error) {
+	next := state.Clone()
+
+	rf := e.Emulator.RegFile()
+	for i := uint8(0); i < 31; i++ {
+		rf.WriteReg(i, next.Regs[i].Concrete)
+	}
+	rf.SP = next.SP.Concrete
+	rf.PC = next.PC
+	rf.PSTATE = emu.PSTATE{
+		N: next.Flags.N.Concrete != 0,
+		Z: next.Flags.Z.Concrete != 0,
+		C: next.Flags.C.Concrete != 0,
+		V: next.Flags.V.Concrete != 0,
+	}
+
+	result := e.Emulator.Step()
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	for i := uint8(0); i < 31; i++ {
+		next.Regs[i] = ConcreteValue(rf.ReadReg(i))
+	}
+	next.SP = ConcreteValue(rf.SP)
+	next.PC = rf.PC
+	next.Flags = Flags{
+		N: ConcreteValue(boolToUint64(rf.PSTATE.N)),
+		Z: ConcreteValue(boolToUint64(rf.PSTATE.Z)),
+		C: ConcreteValue(boolToUint64(rf.PSTATE.C)),
+		V: ConcreteValue(boolToUint64(rf.PSTATE.V)),
+	}
+
+	return []*State{next}, nil
+}
+
+func boolToUint64(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// FindInputsReaching drives a worklist of States, starting from initial,
+// until one reaches target, and returns a concrete assignment (from
+// Solver.Model) of every symbolic variable along that path. A state whose
+// Step returns an error (e.g. a fetch fault) is dropped, the same as a
+// fork direction the solver ruled out infeasible.
+func (e *Engine) FindInputsReaching(target uint64, initial *State) (map[string]uint64, error) {
+	worklist := []*State{initial}
+
+	for len(worklist) > 0 {
+		state := worklist[0]
+		worklist = worklist[1:]
+
+		if state.PC == target {
+			return e.Solver.Model(state.Constraints)
+		}
+
+		next, err := e.Step(state)
+		if err != nil {
+			continue
+		}
+		worklist = append(worklist, next...)
+	}
+
+	return nil, ErrUnreachable
+}
@@ -0,0 +1,118 @@
+package symbolic
+
+import "github.com/sarchlab/m2sim/emu"
+
+// wordsPerPage is the number of 8-byte Value cells held per page, sized
+// to match emu.Memory's own page granularity so Engine's address
+// arithmetic stays consistent across the concrete and symbolic memories.
+const wordsPerPage = emu.PageSize / 8
+
+// page is one page's worth of 64-bit Value cells.
+type page struct {
+	words [wordsPerPage]Value
+}
+
+// Memory is a copy-on-write symbolic memory image: a sparse table of
+// pages, each holding Value cells at 8-byte granularity. Unlike
+// emu.Memory, Memory only exists to track which bytes are symbolic and
+// what Expr they carry; Engine's concrete fast path keeps reading and
+// writing the wrapped Emulator's own emu.Memory for everything else.
+type Memory struct {
+	pages map[uint64]*page
+}
+
+// NewMemory creates an empty symbolic memory image, every cell
+// implicitly ConcreteValue(0).
+func NewMemory() *Memory {
+	return &Memory{pages: make(map[uint64]*page)}
+}
+
+// Clone returns a copy of m that shares every page until one of the two
+// copies writes to it, at which point writablePage duplicates just that
+// one page — the copy-on-write property that keeps forking a State cheap
+// even though a program may have touched many pages of memory.
+func (m *Memory) Clone() *Memory {
+	pages := make(map[uint64]*page, len(m.pages))
+	for addr, p := range m.pages {
+		pages[addr] = p
+	}
+	return &Memory{pages: pages}
+}
+
+func pageBase(addr uint64) uint64 {
+	return addr &^ (emu.PageSize - 1)
+}
+
+func wordIndex(addr uint64) uint64 {
+	return (addr % emu.PageSize) / 8
+}
+
+// writablePage returns m's own, exclusively-owned page at base,
+// duplicating the stored page first. m has no way to tell whether a page
+// is already exclusively owned (Clone only copies pointers), so every
+// write pays one page-sized copy; that is the tradeoff this package makes
+// to avoid refcounting pages.
+func (m *Memory) writablePage(base uint64) *page {
+	p, ok := m.pages[base]
+	if !ok {
+		p = &page{}
+		m.pages[base] = p
+		return p
+	}
+	dup := *p
+	m.pages[base] = &dup
+	return &dup
+}
+
+// Read64 returns the 8-byte-aligned word containing addr.
+func (m *Memory) Read64(addr uint64) Value {
+	p, ok := m.pages[pageBase(addr)]
+	if !ok {
+		return ConcreteValue(0)
+	}
+	return p.words[wordIndex(addr)]
+}
+
+// Write64 stores v as the 8-byte-aligned word containing addr.
+func (m *Memory) Write64(addr uint64, v Value) {
+	p := m.writablePage(pageBase(addr))
+	p.words[wordIndex(addr)] = v
+}
+
+// Read32 returns the 4 bytes at addr, zero-extended to a Value, by
+// masking and shifting the 64-bit word addr falls within.
+func (m *Memory) Read32(addr uint64) Value {
+	shift := ConcreteValue((addr % 8) * 8)
+	word := m.Read64(addr &^ 7)
+	return And(Lsr(word, shift), ConcreteValue(0xFFFFFFFF))
+}
+
+// Write32 stores the low 32 bits of v at addr, read-modify-writing the
+// 64-bit word addr falls within so the other half is preserved.
+func (m *Memory) Write32(addr uint64, v Value) {
+	base := addr &^ 7
+	shiftAmt := (addr % 8) * 8
+	shift := ConcreteValue(shiftAmt)
+
+	old := m.Read64(base)
+	cleared := And(old, ConcreteValue(^(uint64(0xFFFFFFFF) << shiftAmt)))
+	placed := Lsl(And(v, ConcreteValue(0xFFFFFFFF)), shift)
+
+	m.Write64(base, Orr(cleared, placed))
+}
+
+// WriteSymbolicByte marks the single byte at addr as expr, read-modify-
+// writing the 64-bit word addr falls within. This is how StdinHandler
+// tags bytes read from stdin as symbolic without needing byte-granularity
+// pages.
+func (m *Memory) WriteSymbolicByte(addr uint64, expr *Expr) {
+	base := addr &^ 7
+	shiftAmt := (addr % 8) * 8
+	shift := ConcreteValue(shiftAmt)
+
+	old := m.Read64(base)
+	cleared := And(old, ConcreteValue(^(uint64(0xFF) << shiftAmt)))
+	placed := Lsl(SymbolicValue(expr, 0), shift)
+
+	m.Write64(base, Orr(cleared, placed))
+}
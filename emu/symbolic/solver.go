@@ -0,0 +1,53 @@
+package symbolic
+
+// Solver checks satisfiability of a path's accumulated constraints and
+// produces concrete witnesses for its symbolic variables. It is the
+// integration point for a real constraint solver (Z3, Boolector, ...) to
+// be wired in later; NoOpSolver is this package's only built-in
+// implementation and is not a substitute for one, see its doc comment.
+type Solver interface {
+	// CheckSat reports whether the conjunction of constraints is
+	// satisfiable.
+	CheckSat(constraints []*Expr) (bool, error)
+
+	// Model returns an assignment of every named variable (see Var)
+	// appearing in constraints that satisfies them.
+	Model(constraints []*Expr) (map[string]uint64, error)
+}
+
+// NoOpSolver is a placeholder Solver that lets Engine's forking and
+// worklist logic run end-to-end before a real solver binding exists.
+// CheckSat always reports satisfiable, so it never prunes a fork — a
+// state a real solver would have recognized as infeasible still gets
+// explored. Model always returns zero for every variable, so the
+// "concrete inputs" FindInputsReaching returns are not meaningful
+// witnesses, only placeholders of the right shape.
+type NoOpSolver struct{}
+
+// CheckSat always reports satisfiable.
+func (NoOpSolver) CheckSat(constraints []*Expr) (bool, error) {
+	return true, nil
+}
+
+// Model returns the zero value for every variable referenced anywhere in
+// constraints.
+func (NoOpSolver) Model(constraints []*Expr) (map[string]uint64, error) {
+	vars := map[string]uint64{}
+	for _, c := range constraints {
+		collectVars(c, vars)
+	}
+	return vars, nil
+}
+
+func collectVars(e *Expr, out map[string]uint64) {
+	if e == nil {
+		return
+	}
+	if e.Kind == ExprVar {
+		if _, ok := out[e.Name]; !ok {
+			out[e.Name] = 0
+		}
+	}
+	collectVars(e.L, out)
+	collectVars(e.R, out)
+}
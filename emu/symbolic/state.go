@@ -0,0 +1,60 @@
+package symbolic
+
+// Flags mirrors emu.PSTATE, but each bit is a Value so a comparison
+// against symbolic operands (see executeDPReg's SetFlags handling)
+// propagates into the conditional branch that reads it.
+type Flags struct {
+	N, Z, C, V Value
+}
+
+// State is one path of a symbolic execution: a register file, flags, and
+// memory image carrying Value cells, plus the path constraint
+// accumulated to reach it. PC always stays concrete, since Engine
+// concretizes a symbolic PC (asking Solver for one model) before fetch
+// rather than letting State itself track a symbolic PC.
+type State struct {
+	Regs  [31]Value // X0-X30
+	SP    Value
+	PC    uint64
+	Flags Flags
+	Mem   *Memory
+
+	// Constraints accumulate as a conjunction: this state is reachable
+	// only if every expression in Constraints holds.
+	Constraints []*Expr
+}
+
+// NewState creates a State with every register and flag concrete zero,
+// matching a freshly Reset emu.Emulator, and PC at entry.
+func NewState(entry uint64) *State {
+	return &State{PC: entry, Mem: NewMemory()}
+}
+
+// Clone deep-copies s: Regs/SP/Flags are plain value types so the struct
+// copy alone is enough for them, Mem is forked via its own copy-on-write
+// Clone, and Constraints gets its own backing array so appending to one
+// state's path never reallocates into the other's. The two states
+// returned by Engine.fork never alias each other's writes after this.
+func (s *State) Clone() *State {
+	clone := *s
+	clone.Mem = s.Mem.Clone()
+	clone.Constraints = append([]*Expr(nil), s.Constraints...)
+	return &clone
+}
+
+// ReadReg returns Xn (0-30) or SP (31).
+func (s *State) ReadReg(reg uint8) Value {
+	if reg == 31 {
+		return s.SP
+	}
+	return s.Regs[reg]
+}
+
+// WriteReg sets Xn (0-30) or SP (31).
+func (s *State) WriteReg(reg uint8, v Value) {
+	if reg == 31 {
+		s.SP = v
+		return
+	}
+	s.Regs[reg] = v
+}
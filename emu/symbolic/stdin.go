@@ -0,0 +1,86 @@
+package symbolic
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// StdinHandler is an emu.SyscallHandler that behaves exactly like the
+// emu.DefaultSyscallHandler it wraps, except for read(fd=0, ...): bytes
+// read from stdin are written to the wrapped Emulator's concrete memory
+// as usual, so Engine's concrete fast path keeps working, and are also
+// tagged as fresh symbolic Values in Target's memory. A program that
+// branches on stdin input then drives Engine.fork instead of silently
+// running one arbitrarily-concrete path.
+//
+// Target must be set (directly, or via SetTarget) to whichever State
+// Engine is currently stepping before a read syscall can retire, since a
+// single StdinHandler is shared across every forked path's Step call.
+type StdinHandler struct {
+	*emu.DefaultSyscallHandler
+
+	regFile *emu.RegFile
+	memory  *emu.Memory
+	stdin   io.Reader
+
+	Target *State
+
+	nextVar int
+}
+
+// NewStdinHandler creates a StdinHandler sharing regFile and memory with
+// the wrapped Emulator, and stdin as the source of symbolic input bytes.
+func NewStdinHandler(regFile *emu.RegFile, memory *emu.Memory, stdin io.Reader, stdout, stderr io.Writer) *StdinHandler {
+	h := &StdinHandler{
+		DefaultSyscallHandler: emu.NewDefaultSyscallHandler(regFile, memory, stdout, stderr),
+		regFile:               regFile,
+		memory:                memory,
+		stdin:                 stdin,
+	}
+	h.DefaultSyscallHandler.SetStdin(stdin)
+	return h
+}
+
+// SetTarget points h at the State whose memory should receive symbolic
+// tags for the next read syscall.
+func (h *StdinHandler) SetTarget(state *State) {
+	h.Target = state
+}
+
+// Handle intercepts read(fd=0, ...) to mark the bytes it reads symbolic,
+// and defers every other syscall to the embedded DefaultSyscallHandler.
+func (h *StdinHandler) Handle() emu.SyscallResult {
+	if h.regFile.ReadReg(8) != emu.SyscallRead || h.regFile.ReadReg(0) != 0 {
+		return h.DefaultSyscallHandler.Handle()
+	}
+	return h.handleSymbolicRead()
+}
+
+func (h *StdinHandler) handleSymbolicRead() emu.SyscallResult {
+	bufPtr := h.regFile.ReadReg(1)
+	count := h.regFile.ReadReg(2)
+
+	buf := make([]byte, count)
+	n := 0
+	if h.stdin != nil {
+		read, err := h.stdin.Read(buf)
+		if err == nil || read > 0 {
+			n = read
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		h.memory.Write8(bufPtr+uint64(i), buf[i])
+
+		if h.Target != nil {
+			name := fmt.Sprintf("stdin[%d]", h.nextVar)
+			h.nextVar++
+			h.Target.Mem.WriteSymbolicByte(bufPtr+uint64(i), Var(name))
+		}
+	}
+
+	h.regFile.WriteReg(0, uint64(n))
+	return emu.SyscallResult{}
+}
@@ -0,0 +1,168 @@
+package symbolic_test
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/emu/symbolic"
+)
+
+// encodeSUBS64 encodes SUBS Xd, Xn, Xm (add/subtract shifted register,
+// shift amount 0).
+func encodeSUBS64(rd, rn, rm uint8) uint32 {
+	return 0xEB000000 | (uint32(rm) << 16) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeBCondEQ encodes B.EQ, branching imm19*4 bytes from the
+// instruction's own address.
+func encodeBCondEQ(imm19 int32) uint32 {
+	const condEQ = 0
+	return 0x54000000 | ((uint32(imm19) & 0x7FFFF) << 5) | condEQ
+}
+
+func forkProgram() []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf[0:4], encodeSUBS64(0, 1, 2))
+	binary.LittleEndian.PutUint32(buf[4:8], encodeBCondEQ(2))
+	return buf
+}
+
+var _ = Describe("Value and Expr lifting", func() {
+	It("stays concrete when both operands are concrete", func() {
+		result := symbolic.Add(symbolic.ConcreteValue(2), symbolic.ConcreteValue(3))
+
+		Expect(result.IsSymbolic()).To(BeFalse())
+		Expect(result.Concrete).To(Equal(uint64(5)))
+	})
+
+	It("builds an Expr and still tracks a witness when either operand is symbolic", func() {
+		a := symbolic.SymbolicValue(symbolic.Var("a"), 2)
+
+		result := symbolic.Add(a, symbolic.ConcreteValue(3))
+
+		Expect(result.IsSymbolic()).To(BeTrue())
+		Expect(result.Concrete).To(Equal(uint64(5)))
+		Expect(result.Expr.Kind).To(Equal(symbolic.ExprAdd))
+	})
+
+	It("negates a boolean Value", func() {
+		Expect(symbolic.Not(symbolic.ConcreteValue(0)).Concrete).To(Equal(uint64(1)))
+		Expect(symbolic.Not(symbolic.ConcreteValue(1)).Concrete).To(Equal(uint64(0)))
+	})
+})
+
+var _ = Describe("Memory", func() {
+	It("round-trips a 64-bit concrete write", func() {
+		m := symbolic.NewMemory()
+		m.Write64(0x1000, symbolic.ConcreteValue(0xDEADBEEF))
+
+		Expect(m.Read64(0x1000).Concrete).To(Equal(uint64(0xDEADBEEF)))
+	})
+
+	It("clones without aliasing later writes", func() {
+		m := symbolic.NewMemory()
+		m.Write64(0x1000, symbolic.ConcreteValue(1))
+
+		clone := m.Clone()
+		clone.Write64(0x1000, symbolic.ConcreteValue(2))
+
+		Expect(m.Read64(0x1000).Concrete).To(Equal(uint64(1)))
+		Expect(clone.Read64(0x1000).Concrete).To(Equal(uint64(2)))
+	})
+
+	It("marks a single byte symbolic without disturbing its neighbors", func() {
+		m := symbolic.NewMemory()
+		m.Write64(0x2000, symbolic.ConcreteValue(0x1122334455667788))
+
+		m.WriteSymbolicByte(0x2000, symbolic.Var("b"))
+
+		Expect(m.Read64(0x2000).IsSymbolic()).To(BeTrue())
+		Expect(m.Read32(0x2004).Concrete).To(Equal(uint64(0x11223344)))
+	})
+})
+
+var _ = Describe("NoOpSolver", func() {
+	It("always reports satisfiable", func() {
+		sat, err := (symbolic.NoOpSolver{}).CheckSat([]*symbolic.Expr{symbolic.Var("x")})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sat).To(BeTrue())
+	})
+
+	It("collects every variable referenced in the constraints", func() {
+		constraint := symbolic.Eq(symbolic.SymbolicValue(symbolic.Var("x"), 1), symbolic.ConcreteValue(1)).Expr
+
+		model, err := (symbolic.NoOpSolver{}).Model([]*symbolic.Expr{constraint})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(model).To(HaveKey("x"))
+	})
+})
+
+var _ = Describe("State", func() {
+	It("clones registers and memory independently", func() {
+		s := symbolic.NewState(0x1000)
+		s.WriteReg(0, symbolic.ConcreteValue(1))
+		s.Mem.Write64(0x3000, symbolic.ConcreteValue(7))
+
+		clone := s.Clone()
+		clone.WriteReg(0, symbolic.ConcreteValue(2))
+		clone.Mem.Write64(0x3000, symbolic.ConcreteValue(8))
+
+		Expect(s.ReadReg(0).Concrete).To(Equal(uint64(1)))
+		Expect(s.Mem.Read64(0x3000).Concrete).To(Equal(uint64(7)))
+		Expect(clone.ReadReg(0).Concrete).To(Equal(uint64(2)))
+		Expect(clone.Mem.Read64(0x3000).Concrete).To(Equal(uint64(8)))
+	})
+})
+
+var _ = Describe("Engine", func() {
+	var (
+		e      *emu.Emulator
+		engine *symbolic.Engine
+	)
+
+	BeforeEach(func() {
+		e = emu.NewEmulator()
+		e.LoadProgram(0x1000, forkProgram())
+		engine = symbolic.NewEngine(e, symbolic.NoOpSolver{})
+	})
+
+	It("should execute SUBS with concrete operands without forking at the following B.EQ", func() {
+		state := symbolic.NewState(0x1000)
+		state.WriteReg(1, symbolic.ConcreteValue(5))
+		state.WriteReg(2, symbolic.ConcreteValue(5))
+
+		afterSubs, err := engine.Step(state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(afterSubs).To(HaveLen(1))
+		Expect(afterSubs[0].Flags.Z.IsSymbolic()).To(BeFalse())
+		Expect(afterSubs[0].Flags.Z.Concrete).To(Equal(uint64(1)))
+
+		afterBranch, err := engine.Step(afterSubs[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(afterBranch).To(HaveLen(1))
+		Expect(afterBranch[0].PC).To(Equal(uint64(0x1000 + 4 + 2*4)))
+	})
+
+	It("should fork into two states when SUBS sets a symbolic Z flag and B.EQ reads it", func() {
+		state := symbolic.NewState(0x1000)
+		state.WriteReg(1, symbolic.SymbolicValue(symbolic.Var("a"), 5))
+		state.WriteReg(2, symbolic.ConcreteValue(5))
+
+		afterSubs, err := engine.Step(state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(afterSubs).To(HaveLen(1))
+		Expect(afterSubs[0].Flags.Z.IsSymbolic()).To(BeTrue())
+
+		forks, err := engine.Step(afterSubs[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forks).To(HaveLen(2))
+		Expect(forks[0].PC).NotTo(Equal(forks[1].PC))
+		Expect(forks[0].Constraints).NotTo(BeEmpty())
+		Expect(forks[1].Constraints).NotTo(BeEmpty())
+	})
+})
@@ -0,0 +1,140 @@
+// Package symbolic wraps emu.Emulator to carry symbolic values alongside
+// concrete ones, forking into independent States at data-dependent
+// conditional branches. See Engine for how a program is driven and State
+// for what a single path's register/memory/flags snapshot looks like.
+package symbolic
+
+import "fmt"
+
+// ExprKind identifies the operation an Expr node represents.
+type ExprKind int
+
+// The expression kinds an Engine can build while lifting ALU/LSU
+// operations. Shl/Lsr/Asr mirror the ARM64 logical-left, logical-right,
+// and arithmetic-right shifts; Eq/Lt are the comparisons flag-setting
+// instructions need.
+const (
+	ExprVar ExprKind = iota
+	ExprConst
+	ExprAdd
+	ExprSub
+	ExprAnd
+	ExprOr
+	ExprXor
+	ExprShl
+	ExprLsr
+	ExprAsr
+	ExprEq
+	ExprLt
+	ExprNot
+)
+
+// Expr is a node in a symbolic expression tree. L and R hold operands for
+// binary kinds; only L is used for ExprNot. A tree is built bottom-up by
+// the Value-level operations in alu.go, never constructed directly by
+// callers outside this package.
+type Expr struct {
+	Kind  ExprKind
+	Name  string // set for ExprVar
+	Const uint64 // set for ExprConst
+	L, R  *Expr
+}
+
+// Var creates a named symbolic variable, the leaf a Solver's Model
+// assigns a concrete value to. Two Vars with the same name refer to the
+// same variable, the same convention insts.Instruction field reuse
+// implies identity elsewhere in this repo.
+func Var(name string) *Expr {
+	return &Expr{Kind: ExprVar, Name: name}
+}
+
+// ConstExpr lifts a plain uint64 into an expression tree leaf.
+func ConstExpr(v uint64) *Expr {
+	return &Expr{Kind: ExprConst, Const: v}
+}
+
+func bin(kind ExprKind, l, r *Expr) *Expr {
+	return &Expr{Kind: kind, L: l, R: r}
+}
+
+// String renders e as a small Lisp-like s-expression. Useful for
+// debugging and for a Solver implementation that shells out to a
+// text-based prover.
+func (e *Expr) String() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	switch e.Kind {
+	case ExprVar:
+		return e.Name
+	case ExprConst:
+		return fmt.Sprintf("0x%X", e.Const)
+	case ExprNot:
+		return fmt.Sprintf("(not %s)", e.L)
+	default:
+		return fmt.Sprintf("(%s %s %s)", exprOpName(e.Kind), e.L, e.R)
+	}
+}
+
+func exprOpName(k ExprKind) string {
+	switch k {
+	case ExprAdd:
+		return "add"
+	case ExprSub:
+		return "sub"
+	case ExprAnd:
+		return "and"
+	case ExprOr:
+		return "or"
+	case ExprXor:
+		return "xor"
+	case ExprShl:
+		return "shl"
+	case ExprLsr:
+		return "lsr"
+	case ExprAsr:
+		return "asr"
+	case ExprEq:
+		return "eq"
+	case ExprLt:
+		return "lt"
+	default:
+		return "?"
+	}
+}
+
+// Value is a 64-bit register or memory cell that is either fully
+// concrete, or symbolic with Concrete holding a witness: a placeholder
+// that keeps Engine's concrete fast path (see Engine.executeConcrete)
+// executing something well-defined even while Expr tracks the real,
+// input-dependent value. Expr is nil for a purely concrete Value.
+type Value struct {
+	Concrete uint64
+	Expr     *Expr
+}
+
+// ConcreteValue wraps a plain uint64 with no symbolic component.
+func ConcreteValue(v uint64) Value {
+	return Value{Concrete: v}
+}
+
+// SymbolicValue wraps an expression tree. witness is the concrete value
+// substituted on Engine's concrete fast path and reported by NoOpSolver.
+func SymbolicValue(expr *Expr, witness uint64) Value {
+	return Value{Concrete: witness, Expr: expr}
+}
+
+// IsSymbolic reports whether v carries a symbolic component.
+func (v Value) IsSymbolic() bool {
+	return v.Expr != nil
+}
+
+// AsExpr returns v's expression tree, lifting a purely concrete Value to
+// an ExprConst so it can be combined with a symbolic operand.
+func (v Value) AsExpr() *Expr {
+	if v.Expr != nil {
+		return v.Expr
+	}
+	return ConstExpr(v.Concrete)
+}
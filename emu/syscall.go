@@ -8,13 +8,15 @@ import (
 
 // ARM64 Linux syscall numbers.
 const (
-	SyscallOpenat uint64 = 56  // openat(dirfd, pathname, flags, mode)
-	SyscallClose  uint64 = 57  // close(fd)
-	SyscallRead   uint64 = 63  // read(fd, buf, count)
-	SyscallWrite  uint64 = 64  // write(fd, buf, count)
-	SyscallExit   uint64 = 93  // exit(status)
-	SyscallBrk    uint64 = 214 // brk(addr)
-	SyscallMmap   uint64 = 222 // mmap(addr, length, prot, flags, fd, offset)
+	SyscallOpenat   uint64 = 56  // openat(dirfd, pathname, flags, mode)
+	SyscallClose    uint64 = 57  // close(fd)
+	SyscallRead     uint64 = 63  // read(fd, buf, count)
+	SyscallWrite    uint64 = 64  // write(fd, buf, count)
+	SyscallExit     uint64 = 93  // exit(status)
+	SyscallBrk      uint64 = 214 // brk(addr)
+	SyscallMunmap   uint64 = 215 // munmap(addr, length)
+	SyscallMmap     uint64 = 222 // mmap(addr, length, prot, flags, fd, offset)
+	SyscallMprotect uint64 = 226 // mprotect(addr, length, prot)
 )
 
 // Linux error codes.
@@ -25,7 +27,9 @@ const (
 	ENOMEM = 12 // Out of memory
 	EACCES = 13 // Permission denied
 	EINVAL = 22 // Invalid argument
+	ERANGE = 34 // Result too large
 	ENOSYS = 38 // Function not implemented
+	ENOTTY = 25 // Not a typewriter
 )
 
 // Linux mmap protection flags.
@@ -80,14 +84,6 @@ type SyscallHandler interface {
 	Handle() SyscallResult
 }
 
-// MmapRegion represents a mapped memory region.
-type MmapRegion struct {
-	Addr   uint64 // Start address
-	Length uint64 // Length in bytes
-	Prot   int    // Protection flags
-	Flags  int    // Mapping flags
-}
-
 // DefaultSyscallHandler provides a basic syscall handler implementation.
 type DefaultSyscallHandler struct {
 	regFile      *RegFile
@@ -96,9 +92,83 @@ type DefaultSyscallHandler struct {
 	stdin        io.Reader
 	stdout       io.Writer
 	stderr       io.Writer
-	programBreak uint64       // Current program break (heap end)
-	nextMmapAddr uint64       // Next address for anonymous mmap
-	mmapRegions  []MmapRegion // Tracked mmap regions
+	programBreak uint64 // Current program break (heap end)
+	nextMmapAddr uint64 // Next address for anonymous mmap
+	vmas         vmaSet // Tracked mmap regions
+
+	table  map[uint64]syscallEntry
+	tracer SyscallTracer
+}
+
+// SyscallFunc implements one syscall. It reads its arguments from ctx
+// and sets X0 (via ctx.RegFile or ctx.SetError) before returning.
+type SyscallFunc func(ctx *SyscallContext) SyscallResult
+
+// syscallEntry pairs a registered SyscallFunc with the name it was
+// registered under, so tracing can name a syscall without a reverse
+// lookup table.
+type syscallEntry struct {
+	name string
+	fn   SyscallFunc
+}
+
+// SyscallContext gives a registered SyscallFunc the state it needs to
+// implement a syscall — registers, memory, and the file descriptor
+// table directly, plus accessors for the handler's output streams and
+// heap/mmap bookkeeping — without exposing DefaultSyscallHandler's
+// private fields.
+type SyscallContext struct {
+	RegFile *RegFile
+	Memory  *Memory
+	FDTable *FDTable
+
+	h *DefaultSyscallHandler
+}
+
+// Stdout returns the handler's stdout writer.
+func (c *SyscallContext) Stdout() io.Writer { return c.h.stdout }
+
+// Stderr returns the handler's stderr writer.
+func (c *SyscallContext) Stderr() io.Writer { return c.h.stderr }
+
+// Stdin returns the handler's stdin reader, or nil if none was set.
+func (c *SyscallContext) Stdin() io.Reader { return c.h.stdin }
+
+// ProgramBreak returns the current program break.
+func (c *SyscallContext) ProgramBreak() uint64 { return c.h.programBreak }
+
+// SetProgramBreak sets the program break to a specific address.
+func (c *SyscallContext) SetProgramBreak(addr uint64) { c.h.programBreak = addr }
+
+// AllocMmap finds length free bytes at or above the handler's
+// next-mmap address, reusing space munmap has freed where possible, and
+// returns the address it was allocated at.
+func (c *SyscallContext) AllocMmap(length uint64) uint64 {
+	addr := c.h.vmas.findGap(c.h.nextMmapAddr, length)
+	if end := addr + length; end > c.h.nextMmapAddr {
+		c.h.nextMmapAddr = end
+	}
+	return addr
+}
+
+// AddMmapRegion records region as tracked mmap state, visible later via
+// GetMmapRegions and LookupVMA.
+func (c *SyscallContext) AddMmapRegion(region MmapRegion) {
+	c.h.vmas.insert(&region)
+}
+
+// SetError sets X0 to -errno (as two's complement).
+func (c *SyscallContext) SetError(errno int) { c.h.setError(errno) }
+
+// SyscallTracer observes every syscall DefaultSyscallHandler dispatches,
+// so a harness can log, replay, or assert on syscall activity without
+// threading print statements through every handler. Enter fires with the
+// raw X0-X5 arguments before the syscall runs; Exit fires with its
+// result (and the errno decoded from X0, if any) after. See the trace
+// package for bundled text and JSONL implementations.
+type SyscallTracer interface {
+	Enter(num uint64, name string, args [6]uint64)
+	Exit(ret uint64, errno int, result SyscallResult)
 }
 
 // DefaultProgramBreak is the initial program break address.
@@ -111,7 +181,7 @@ const DefaultMmapBase uint64 = 0x40000000 // 1GB mark
 
 // NewDefaultSyscallHandler creates a default syscall handler.
 func NewDefaultSyscallHandler(regFile *RegFile, memory *Memory, stdout, stderr io.Writer) *DefaultSyscallHandler {
-	return &DefaultSyscallHandler{
+	h := &DefaultSyscallHandler{
 		regFile:      regFile,
 		memory:       memory,
 		fdTable:      NewFDTable(),
@@ -120,8 +190,59 @@ func NewDefaultSyscallHandler(regFile *RegFile, memory *Memory, stdout, stderr i
 		stderr:       stderr,
 		programBreak: DefaultProgramBreak,
 		nextMmapAddr: DefaultMmapBase,
-		mmapRegions:  make([]MmapRegion, 0),
+		table:        make(map[uint64]syscallEntry),
 	}
+
+	h.registerDefaultSyscalls()
+
+	return h
+}
+
+// registerDefaultSyscalls installs the syscalls this package implements,
+// including the FD-table and process-identity families registered by
+// registerFDTableSyscalls and registerProcessSyscalls. Each is a thin
+// adapter onto the pre-existing handleXxx methods, so RegisterSyscall can
+// override or add to this set (e.g. getrandom, sched_yield) without
+// forking the handler.
+func (h *DefaultSyscallHandler) registerDefaultSyscalls() {
+	h.RegisterSyscall(SyscallOpenat, "openat", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleOpenat() })
+	h.RegisterSyscall(SyscallClose, "close", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleClose() })
+	h.RegisterSyscall(SyscallRead, "read", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleRead() })
+	h.RegisterSyscall(SyscallWrite, "write", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleWrite() })
+	h.RegisterSyscall(SyscallExit, "exit", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleExit() })
+	h.RegisterSyscall(SyscallBrk, "brk", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleBrk() })
+	h.RegisterSyscall(SyscallMmap, "mmap", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleMmap() })
+	h.RegisterSyscall(SyscallMunmap, "munmap", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleMunmap() })
+	h.RegisterSyscall(SyscallMprotect, "mprotect", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleMprotect() })
+
+	h.registerFDTableSyscalls()
+	h.registerProcessSyscalls()
+}
+
+// RegisterSyscall installs fn as the handler for syscall number num,
+// replacing any handler previously registered for it (including one of
+// this package's own defaults). name is used for tracing.
+func (h *DefaultSyscallHandler) RegisterSyscall(num uint64, name string, fn SyscallFunc) {
+	h.table[num] = syscallEntry{name: name, fn: fn}
+}
+
+// SetTracer installs t as the syscall tracer, invoked around every
+// dispatched syscall. Pass nil to disable tracing.
+func (h *DefaultSyscallHandler) SetTracer(t SyscallTracer) {
+	h.tracer = t
+}
+
+func (h *DefaultSyscallHandler) newContext() *SyscallContext {
+	return &SyscallContext{RegFile: h.regFile, Memory: h.memory, FDTable: h.fdTable, h: h}
+}
+
+// errnoFromReturn extracts the errno a syscall return value encodes
+// under the negative-errno convention, or 0 if ret isn't an error.
+func errnoFromReturn(ret uint64) int {
+	if signed := int64(ret); signed < 0 {
+		return int(-signed)
+	}
+	return 0
 }
 
 // SetFDTable sets a custom file descriptor table for the syscall handler.
@@ -149,28 +270,33 @@ func (h *DefaultSyscallHandler) SetProgramBreak(addr uint64) {
 	h.programBreak = addr
 }
 
-// Handle executes the syscall indicated by the register file state.
+// Handle executes the syscall indicated by the register file state,
+// dispatching through the registered table and, if a tracer is
+// installed, emitting its Enter/Exit calls around the dispatch.
 func (h *DefaultSyscallHandler) Handle() SyscallResult {
-	syscallNum := h.regFile.ReadReg(8)
-
-	switch syscallNum {
-	case SyscallOpenat:
-		return h.handleOpenat()
-	case SyscallClose:
-		return h.handleClose()
-	case SyscallRead:
-		return h.handleRead()
-	case SyscallWrite:
-		return h.handleWrite()
-	case SyscallExit:
-		return h.handleExit()
-	case SyscallBrk:
-		return h.handleBrk()
-	case SyscallMmap:
-		return h.handleMmap()
-	default:
+	num := h.regFile.ReadReg(8)
+
+	entry, ok := h.table[num]
+	if !ok {
 		return h.handleUnknown()
 	}
+
+	if h.tracer != nil {
+		var args [6]uint64
+		for i := range args {
+			args[i] = h.regFile.ReadReg(uint8(i))
+		}
+		h.tracer.Enter(num, entry.name, args)
+	}
+
+	result := entry.fn(h.newContext())
+
+	if h.tracer != nil {
+		ret := h.regFile.ReadReg(0)
+		h.tracer.Exit(ret, errnoFromReturn(ret), result)
+	}
+
+	return result
 }
 
 // handleExit handles the exit syscall (93).
@@ -188,21 +314,23 @@ func (h *DefaultSyscallHandler) handleRead() SyscallResult {
 	bufPtr := h.regFile.ReadReg(1)
 	count := h.regFile.ReadReg(2)
 
-	// Only stdin (fd=0) is supported for now
-	if fd != 0 {
-		h.setError(EBADF)
-		return SyscallResult{}
-	}
+	buf := make([]byte, count)
 
-	// If no stdin is configured, return EOF
-	if h.stdin == nil {
-		h.regFile.WriteReg(0, 0)
-		return SyscallResult{}
+	var n int
+	var err error
+	switch fd {
+	case 0:
+		if h.stdin == nil {
+			// No stdin configured: report EOF rather than EBADF, since
+			// fd 0 is still a valid, just empty, descriptor.
+			h.regFile.WriteReg(0, 0)
+			return SyscallResult{}
+		}
+		n, err = h.stdin.Read(buf)
+	default:
+		n, err = h.fdTable.Read(fd, buf)
 	}
 
-	// Read from stdin
-	buf := make([]byte, count)
-	n, err := h.stdin.Read(buf)
 	if err != nil && n == 0 {
 		// EOF or error with no bytes read
 		h.regFile.WriteReg(0, 0)
@@ -219,32 +347,32 @@ func (h *DefaultSyscallHandler) handleRead() SyscallResult {
 	return SyscallResult{}
 }
 
-// handleWrite handles the write syscall (64).
+// handleWrite handles the write syscall (64). fd 1 and 2 go to the
+// handler's configured stdout/stderr writers (so a harness can capture
+// them), and every other fd — including ones handleOpenat returned —
+// goes through fdTable.
 func (h *DefaultSyscallHandler) handleWrite() SyscallResult {
 	fd := h.regFile.ReadReg(0)
 	bufPtr := h.regFile.ReadReg(1)
 	count := h.regFile.ReadReg(2)
 
-	// Select output based on file descriptor
-	var writer io.Writer
-	switch fd {
-	case 1:
-		writer = h.stdout
-	case 2:
-		writer = h.stderr
-	default:
-		h.setError(EBADF)
-		return SyscallResult{}
-	}
-
 	// Read buffer from memory
 	buf := make([]byte, count)
 	for i := uint64(0); i < count; i++ {
 		buf[i] = h.memory.Read8(bufPtr + i)
 	}
 
-	// Write to output
-	n, err := writer.Write(buf)
+	var n int
+	var err error
+	switch fd {
+	case 1:
+		n, err = h.stdout.Write(buf)
+	case 2:
+		n, err = h.stderr.Write(buf)
+	default:
+		n, err = h.fdTable.Write(fd, buf)
+	}
+
 	if err != nil {
 		h.setError(EIO)
 		return SyscallResult{}
@@ -380,10 +508,15 @@ func (h *DefaultSyscallHandler) handleBrk() SyscallResult {
 	return SyscallResult{}
 }
 
-// handleMmap handles the mmap syscall (222).
-// mmap maps memory regions. Currently only supports anonymous mappings.
+// handleMmap handles the mmap syscall (222), backed by a real vmaSet:
+// anonymous mappings get fresh zeroed pages from h.memory, and a
+// file-backed mapping (fd != -1, no MAP_ANONYMOUS) additionally reads
+// its mapped file range into those pages eagerly. Eager loading is
+// simpler to get right than a page-fault-driven path and, since nothing
+// else in this emulator can modify the backing file concurrently,
+// behaves the same from the guest's point of view.
 // Arguments:
-//   - X0: addr (hint address, or 0 for kernel to choose)
+//   - X0: addr (hint address, or required address if MAP_FIXED)
 //   - X1: length (size of mapping)
 //   - X2: prot (protection flags)
 //   - X3: flags (mapping flags)
@@ -395,59 +528,133 @@ func (h *DefaultSyscallHandler) handleMmap() SyscallResult {
 	prot := int(h.regFile.ReadReg(2))
 	flags := int(h.regFile.ReadReg(3))
 	fd := int64(h.regFile.ReadReg(4))
-	// offset := h.regFile.ReadReg(5) // Not used for anonymous mappings
+	offset := h.regFile.ReadReg(5)
 
-	// Validate length
 	if length == 0 {
 		h.setError(EINVAL)
 		return SyscallResult{}
 	}
 
-	// Check if anonymous mapping
-	isAnonymous := (flags & MAP_ANONYMOUS) != 0
-
-	// For now, only support anonymous mappings
-	// fd should be -1 for anonymous mappings
-	if !isAnonymous || (fd != -1 && !isAnonymous) {
-		h.setError(ENOSYS) // File mappings not implemented
+	isAnonymous := flags&MAP_ANONYMOUS != 0
+	if !isAnonymous && fd < 0 {
+		h.setError(EINVAL)
 		return SyscallResult{}
 	}
 
-	// Page-align the length (4KB pages)
-	const pageSize uint64 = 4096
-	alignedLength := (length + pageSize - 1) & ^(pageSize - 1)
+	alignedLength := (length + PageSize - 1) &^ (PageSize - 1)
 
 	var mappedAddr uint64
-
-	// Handle MAP_FIXED
 	if flags&MAP_FIXED != 0 {
 		if addr == 0 {
 			h.setError(EINVAL)
 			return SyscallResult{}
 		}
-		// Use the requested address (page-aligned)
-		mappedAddr = addr & ^(pageSize - 1)
+		mappedAddr = addr &^ (PageSize - 1)
+		h.vmas.removeRange(mappedAddr, alignedLength)
 	} else {
-		// Allocate from next available mmap address
-		mappedAddr = h.nextMmapAddr
-		h.nextMmapAddr += alignedLength
+		mappedAddr = h.vmas.findGap(h.nextMmapAddr, alignedLength)
+	}
+	if end := mappedAddr + alignedLength; end > h.nextMmapAddr {
+		h.nextMmapAddr = end
 	}
 
-	// Track the mapping
-	region := MmapRegion{
+	h.memory.Mmap(mappedAddr, alignedLength, prot, true)
+
+	region := &MmapRegion{
 		Addr:   mappedAddr,
 		Length: alignedLength,
 		Prot:   prot,
 		Flags:  flags,
+		Fd:     -1,
+	}
+	if !isAnonymous {
+		region.Fd = fd
+		region.Offset = offset
+	}
+	h.vmas.insert(region)
+
+	if !isAnonymous {
+		h.loadFileBackedPages(region)
 	}
-	h.mmapRegions = append(h.mmapRegions, region)
 
-	// Return the mapped address
 	h.regFile.WriteReg(0, mappedAddr)
 	return SyscallResult{}
 }
 
-// GetMmapRegions returns the list of mmap'd regions.
+// loadFileBackedPages reads region's backing file range, through
+// fdTable, into the memory handleMmap just mapped over it.
+func (h *DefaultSyscallHandler) loadFileBackedPages(region *MmapRegion) {
+	buf := make([]byte, region.Length)
+	n, err := h.fdTable.Pread(uint64(region.Fd), buf, int64(region.Offset))
+	if err != nil && n == 0 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		h.memory.Write8(region.Addr+uint64(i), buf[i])
+	}
+}
+
+// handleMunmap handles the munmap syscall (215), unmapping
+// [addr, addr+length) from both the real backing memory and the
+// tracked vmaSet, splitting any region only partially covered by the
+// range.
+func (h *DefaultSyscallHandler) handleMunmap() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	length := h.regFile.ReadReg(1)
+
+	if length == 0 {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	alignedLength := (length + PageSize - 1) &^ (PageSize - 1)
+	base := addr &^ (PageSize - 1)
+
+	h.memory.Munmap(base, alignedLength)
+	h.vmas.removeRange(base, alignedLength)
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleMprotect handles the mprotect syscall (226), changing the
+// protection of [addr, addr+length) in both the real backing memory and
+// the tracked vmaSet, splitting any region only partially covered by the
+// range.
+func (h *DefaultSyscallHandler) handleMprotect() SyscallResult {
+	addr := h.regFile.ReadReg(0)
+	length := h.regFile.ReadReg(1)
+	prot := int(h.regFile.ReadReg(2))
+
+	if length == 0 {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	alignedLength := (length + PageSize - 1) &^ (PageSize - 1)
+	base := addr &^ (PageSize - 1)
+
+	h.memory.Mprotect(base, alignedLength, prot)
+	h.vmas.setProt(base, alignedLength, prot)
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// GetMmapRegions returns a snapshot of the currently mapped regions,
+// ordered by address.
 func (h *DefaultSyscallHandler) GetMmapRegions() []MmapRegion {
-	return h.mmapRegions
+	regions := make([]MmapRegion, len(h.vmas.regions))
+	for i, r := range h.vmas.regions {
+		regions[i] = *r
+	}
+	return regions
+}
+
+// LookupVMA returns the mapped region containing addr, or nil if no
+// mapping covers it. Intended for use by a future page-fault path in
+// Memory, which today faults on any unmapped access without knowing
+// which VMA (if any) should be paged back in.
+func (h *DefaultSyscallHandler) LookupVMA(addr uint64) *MmapRegion {
+	return h.vmas.lookup(addr)
 }
@@ -0,0 +1,361 @@
+package emu
+
+import "os"
+
+// ARM64 Linux syscall numbers for the fd-table-backed operations beyond
+// the openat/close/read/write/brk/mmap family in syscall.go.
+const (
+	SyscallGetcwd     uint64 = 17 // getcwd(buf, size)
+	SyscallDup        uint64 = 23 // dup(oldfd)
+	SyscallDup3       uint64 = 24 // dup3(oldfd, newfd, flags)
+	SyscallIoctl      uint64 = 29 // ioctl(fd, request, argp)
+	SyscallPipe2      uint64 = 59 // pipe2(pipefd, flags)
+	SyscallLseek      uint64 = 62 // lseek(fd, offset, whence)
+	SyscallReadv      uint64 = 65 // readv(fd, iov, iovcnt)
+	SyscallWritev     uint64 = 66 // writev(fd, iov, iovcnt)
+	SyscallNewfstatat uint64 = 79 // newfstatat(dirfd, pathname, statbuf, flags)
+	SyscallFstat      uint64 = 80 // fstat(fd, statbuf)
+)
+
+// Linux ioctl request numbers this handler stubs out, just enough for a
+// libc isatty()/tcgetattr() check on the standard streams to succeed.
+const (
+	TCGETS     = 0x5401
+	TIOCGWINSZ = 0x5413
+)
+
+// linuxStatSize is sizeof(struct stat) for the generic 64-bit Linux ABI
+// (include/uapi/asm-generic/stat.h), which aarch64 uses.
+const linuxStatSize = 128
+
+// Linux struct stat st_mode file-type bits this handler can report.
+const (
+	linuxSIFCHR = 0o020000
+	linuxSIFDIR = 0o040000
+	linuxSIFREG = 0o100000
+)
+
+// registerFDTableSyscalls installs the syscalls that operate on fdTable
+// entries beyond plain read/write/openat/close.
+func (h *DefaultSyscallHandler) registerFDTableSyscalls() {
+	h.RegisterSyscall(SyscallGetcwd, "getcwd", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleGetcwd() })
+	h.RegisterSyscall(SyscallDup, "dup", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleDup() })
+	h.RegisterSyscall(SyscallDup3, "dup3", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleDup3() })
+	h.RegisterSyscall(SyscallIoctl, "ioctl", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleIoctl() })
+	h.RegisterSyscall(SyscallPipe2, "pipe2", func(ctx *SyscallContext) SyscallResult { return ctx.h.handlePipe2() })
+	h.RegisterSyscall(SyscallLseek, "lseek", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleLseek() })
+	h.RegisterSyscall(SyscallReadv, "readv", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleReadv() })
+	h.RegisterSyscall(SyscallWritev, "writev", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleWritev() })
+	h.RegisterSyscall(SyscallFstat, "fstat", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleFstat() })
+	h.RegisterSyscall(SyscallNewfstatat, "newfstatat", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleNewfstatat() })
+}
+
+// handleGetcwd handles the getcwd syscall (17).
+// char *getcwd(char *buf, size_t size)
+//
+// Unlike the glibc wrapper, the raw syscall returns the number of bytes
+// written (including the terminating NUL) on success, not a pointer.
+func (h *DefaultSyscallHandler) handleGetcwd() SyscallResult {
+	bufPtr := h.regFile.ReadReg(0)
+	size := h.regFile.ReadReg(1)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	n := uint64(len(cwd)) + 1 // include the terminating NUL
+	if n > size {
+		h.setError(ERANGE)
+		return SyscallResult{}
+	}
+
+	for i := 0; i < len(cwd); i++ {
+		h.memory.Write8(bufPtr+uint64(i), cwd[i])
+	}
+	h.memory.Write8(bufPtr+uint64(len(cwd)), 0)
+
+	h.regFile.WriteReg(0, n)
+	return SyscallResult{}
+}
+
+// handleDup handles the dup syscall (23).
+// int dup(int oldfd)
+func (h *DefaultSyscallHandler) handleDup() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+
+	newFD, err := h.fdTable.Dup(fd)
+	if err != nil {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	h.regFile.WriteReg(0, newFD)
+	return SyscallResult{}
+}
+
+// handleDup3 handles the dup3 syscall (24).
+// int dup3(int oldfd, int newfd, int flags)
+//
+// flags (e.g. O_CLOEXEC) is read but not modeled, since this emulator
+// has no notion of exec across which a descriptor could leak.
+func (h *DefaultSyscallHandler) handleDup3() SyscallResult {
+	oldFD := h.regFile.ReadReg(0)
+	newFD := h.regFile.ReadReg(1)
+
+	if err := h.fdTable.Dup2(oldFD, newFD); err != nil {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	h.regFile.WriteReg(0, newFD)
+	return SyscallResult{}
+}
+
+// handlePipe2 handles the pipe2 syscall (59).
+// int pipe2(int pipefd[2], int flags)
+//
+// flags (e.g. O_CLOEXEC/O_NONBLOCK) is read but not modeled.
+func (h *DefaultSyscallHandler) handlePipe2() SyscallResult {
+	pipefd := h.regFile.ReadReg(0)
+
+	r, w, err := h.fdTable.Pipe()
+	if err != nil {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	h.memory.Write32(pipefd, uint32(r))
+	h.memory.Write32(pipefd+4, uint32(w))
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleLseek handles the lseek syscall (62).
+// off_t lseek(int fd, off_t offset, int whence)
+func (h *DefaultSyscallHandler) handleLseek() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	offset := int64(h.regFile.ReadReg(1))
+	whence := int(h.regFile.ReadReg(2))
+
+	newOffset, err := h.fdTable.Lseek(fd, offset, whence)
+	if err != nil {
+		h.setError(EINVAL)
+		return SyscallResult{}
+	}
+
+	h.regFile.WriteReg(0, uint64(newOffset))
+	return SyscallResult{}
+}
+
+// handleReadv handles the readv syscall (65), walking a guest struct
+// iovec array (each entry: uint64 iov_base, uint64 iov_len) and reading
+// into each buffer in turn, stopping at the first short read.
+func (h *DefaultSyscallHandler) handleReadv() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	iov := h.regFile.ReadReg(1)
+	iovcnt := h.regFile.ReadReg(2)
+
+	var total uint64
+	for i := uint64(0); i < iovcnt; i++ {
+		base := h.memory.Read64(iov + i*16)
+		length := h.memory.Read64(iov + i*16 + 8)
+
+		buf := make([]byte, length)
+		n, err := h.fdTable.Read(fd, buf)
+		if n == 0 && err != nil {
+			break
+		}
+		for j := 0; j < n; j++ {
+			h.memory.Write8(base+uint64(j), buf[j])
+		}
+		total += uint64(n)
+		if uint64(n) < length {
+			break
+		}
+	}
+
+	h.regFile.WriteReg(0, total)
+	return SyscallResult{}
+}
+
+// handleWritev handles the writev syscall (66), walking a guest struct
+// iovec array and writing each buffer in turn.
+func (h *DefaultSyscallHandler) handleWritev() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	iov := h.regFile.ReadReg(1)
+	iovcnt := h.regFile.ReadReg(2)
+
+	var total uint64
+	for i := uint64(0); i < iovcnt; i++ {
+		base := h.memory.Read64(iov + i*16)
+		length := h.memory.Read64(iov + i*16 + 8)
+
+		buf := make([]byte, length)
+		for j := uint64(0); j < length; j++ {
+			buf[j] = h.memory.Read8(base + j)
+		}
+
+		n, err := h.fdTable.Write(fd, buf)
+		total += uint64(n)
+		if err != nil {
+			break
+		}
+	}
+
+	h.regFile.WriteReg(0, total)
+	return SyscallResult{}
+}
+
+// handleIoctl handles the ioctl syscall (29), with just enough of
+// TCGETS and TIOCGWINSZ implemented for a libc isatty() check on the
+// standard streams to succeed. Every other request, and any fd above 2,
+// fails the way a non-terminal descriptor would.
+func (h *DefaultSyscallHandler) handleIoctl() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	request := h.regFile.ReadReg(1)
+	argp := h.regFile.ReadReg(2)
+
+	if _, ok := h.fdTable.Get(fd); !ok {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	switch request {
+	case TCGETS:
+		if fd > 2 {
+			h.setError(ENOTTY)
+			return SyscallResult{}
+		}
+		// Claim success without filling in argp's struct termios;
+		// nothing in this emulator inspects its contents.
+		h.regFile.WriteReg(0, 0)
+	case TIOCGWINSZ:
+		if fd > 2 {
+			h.setError(ENOTTY)
+			return SyscallResult{}
+		}
+		h.writeWinsize(argp)
+		h.regFile.WriteReg(0, 0)
+	default:
+		h.setError(ENOSYS)
+	}
+
+	return SyscallResult{}
+}
+
+// writeWinsize fills a Linux struct winsize with a fixed, plausible
+// terminal size; nothing in this emulator tracks a real one.
+func (h *DefaultSyscallHandler) writeWinsize(addr uint64) {
+	h.memory.Write16(addr, 24)   // ws_row
+	h.memory.Write16(addr+2, 80) // ws_col
+	h.memory.Write16(addr+4, 0)  // ws_xpixel
+	h.memory.Write16(addr+6, 0)  // ws_ypixel
+}
+
+// handleFstat handles the fstat syscall (80).
+// int fstat(int fd, struct stat *statbuf)
+func (h *DefaultSyscallHandler) handleFstat() SyscallResult {
+	fd := h.regFile.ReadReg(0)
+	statbuf := h.regFile.ReadReg(1)
+
+	entry, ok := h.fdTable.Get(fd)
+	if !ok {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	if fd <= 2 {
+		h.writeCharDeviceStat(statbuf)
+		h.regFile.WriteReg(0, 0)
+		return SyscallResult{}
+	}
+
+	info, err := os.Stat(entry.Path)
+	if err != nil {
+		h.setError(EIO)
+		return SyscallResult{}
+	}
+
+	h.writeStat(statbuf, info)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleNewfstatat handles the newfstatat syscall (79).
+// int newfstatat(int dirfd, const char *pathname, struct stat *statbuf, int flags)
+//
+// Only the AT_FDCWD, path-based form is supported (the common
+// fstatat(AT_FDCWD, path, &st, 0) a libc stat() issues); the
+// AT_EMPTY_PATH form that stats dirfd itself is not implemented.
+func (h *DefaultSyscallHandler) handleNewfstatat() SyscallResult {
+	dirfd := int64(h.regFile.ReadReg(0))
+	if dirfd != AT_FDCWD {
+		h.setError(EBADF)
+		return SyscallResult{}
+	}
+
+	pathname := h.readString(h.regFile.ReadReg(1))
+	statbuf := h.regFile.ReadReg(2)
+
+	info, err := os.Stat(pathname)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.setError(ENOENT)
+		} else {
+			h.setError(EIO)
+		}
+		return SyscallResult{}
+	}
+
+	h.writeStat(statbuf, info)
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// writeStat marshals info into the Linux generic 64-bit struct stat
+// layout at addr. Fields this emulator has no real analogue for
+// (device/inode numbers, link count, ownership, timestamps) are filled
+// with fixed placeholder values rather than host values, since a guest's
+// behavior should not depend on the host filesystem's identity.
+func (h *DefaultSyscallHandler) writeStat(addr uint64, info os.FileInfo) {
+	mode := uint32(info.Mode().Perm())
+	if info.IsDir() {
+		mode |= linuxSIFDIR
+	} else {
+		mode |= linuxSIFREG
+	}
+
+	h.zeroStat(addr)
+	h.memory.Write64(addr, 1)                                // st_dev
+	h.memory.Write64(addr+8, 1)                              // st_ino
+	h.memory.Write32(addr+16, mode)                          // st_mode
+	h.memory.Write32(addr+20, 1)                             // st_nlink
+	h.memory.Write64(addr+48, uint64(info.Size()))           // st_size
+	h.memory.Write32(addr+56, 4096)                          // st_blksize
+	h.memory.Write64(addr+64, (uint64(info.Size())+511)/512) // st_blocks
+}
+
+// writeCharDeviceStat fills in a minimal struct stat for the standard
+// streams (fd 0-2), which aren't backed by a real path os.Stat can see:
+// just enough for a libc isatty()/fstat() check on st_mode to observe
+// S_IFCHR.
+func (h *DefaultSyscallHandler) writeCharDeviceStat(addr uint64) {
+	h.zeroStat(addr)
+	h.memory.Write64(addr, 5)                    // st_dev
+	h.memory.Write64(addr+8, 1)                  // st_ino
+	h.memory.Write32(addr+16, linuxSIFCHR|0o620) // st_mode
+	h.memory.Write32(addr+20, 1)                 // st_nlink
+	h.memory.Write32(addr+56, 4096)              // st_blksize
+}
+
+// zeroStat clears the struct stat region before writeStat fills in the
+// fields it knows, so padding and unmodeled fields (uid, gid, rdev,
+// timestamps) read as zero rather than stale memory.
+func (h *DefaultSyscallHandler) zeroStat(addr uint64) {
+	for i := uint64(0); i < linuxStatSize; i++ {
+		h.memory.Write8(addr+i, 0)
+	}
+}
@@ -0,0 +1,207 @@
+// Package emu provides functional ARM64 emulation.
+package emu_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Syscall Handler fd-table operations", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		stdout  *bytes.Buffer
+		stderr  *bytes.Buffer
+		handler *emu.DefaultSyscallHandler
+		tempDir string
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		stdout = new(bytes.Buffer)
+		stderr = new(bytes.Buffer)
+		handler = emu.NewDefaultSyscallHandler(regFile, memory, stdout, stderr)
+
+		var err error
+		tempDir, err = os.MkdirTemp("", "syscall_fdtable_test")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	// openFile drives the openat syscall and returns the fd it allocated.
+	openFile := func(path string, flags int, mode uint64) uint64 {
+		pathAddr := uint64(0x5000)
+		for i, b := range []byte(path + "\x00") {
+			memory.Write8(pathAddr+uint64(i), b)
+		}
+
+		regFile.WriteReg(8, emu.SyscallOpenat)
+		regFile.WriteReg(0, uint64(emu.AT_FDCWD))
+		regFile.WriteReg(1, pathAddr)
+		regFile.WriteReg(2, uint64(flags))
+		regFile.WriteReg(3, mode)
+		handler.Handle()
+		return regFile.ReadReg(0)
+	}
+
+	It("should read an openat'd file's contents through handleRead", func() {
+		path := filepath.Join(tempDir, "hello.txt")
+		Expect(os.WriteFile(path, []byte("hi there"), 0644)).To(Succeed())
+
+		fd := openFile(path, emu.O_RDONLY, 0)
+
+		regFile.WriteReg(8, emu.SyscallRead)
+		regFile.WriteReg(0, fd)
+		regFile.WriteReg(1, 0x6000)
+		regFile.WriteReg(2, 8)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(8)))
+		Expect(memory.Read8(0x6000)).To(Equal(byte('h')))
+	})
+
+	It("should seek within an open file via lseek", func() {
+		path := filepath.Join(tempDir, "seek.txt")
+		Expect(os.WriteFile(path, []byte("0123456789"), 0644)).To(Succeed())
+
+		fd := openFile(path, emu.O_RDONLY, 0)
+
+		regFile.WriteReg(8, emu.SyscallLseek)
+		regFile.WriteReg(0, fd)
+		regFile.WriteReg(1, 5)
+		regFile.WriteReg(2, 0) // SEEK_SET
+		handler.Handle()
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(5)))
+
+		regFile.WriteReg(8, emu.SyscallRead)
+		regFile.WriteReg(0, fd)
+		regFile.WriteReg(1, 0x6000)
+		regFile.WriteReg(2, 1)
+		handler.Handle()
+		Expect(memory.Read8(0x6000)).To(Equal(byte('5')))
+	})
+
+	It("should report the host's working directory via getcwd", func() {
+		want, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+
+		regFile.WriteReg(8, emu.SyscallGetcwd)
+		regFile.WriteReg(0, 0x7000)
+		regFile.WriteReg(1, 4096)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(len(want) + 1)))
+		got := make([]byte, len(want))
+		for i := range got {
+			got[i] = memory.Read8(0x7000 + uint64(i))
+		}
+		Expect(string(got)).To(Equal(want))
+		Expect(memory.Read8(0x7000 + uint64(len(want)))).To(Equal(byte(0)))
+	})
+
+	It("should duplicate a descriptor with dup", func() {
+		path := filepath.Join(tempDir, "dup.txt")
+		Expect(os.WriteFile(path, []byte("abc"), 0644)).To(Succeed())
+		fd := openFile(path, emu.O_RDONLY, 0)
+
+		regFile.WriteReg(8, emu.SyscallDup)
+		regFile.WriteReg(0, fd)
+		handler.Handle()
+
+		newFD := regFile.ReadReg(0)
+		Expect(newFD).ToNot(Equal(fd))
+	})
+
+	It("should move data through a pipe2 pair", func() {
+		regFile.WriteReg(8, emu.SyscallPipe2)
+		regFile.WriteReg(0, 0x8000)
+		regFile.WriteReg(1, 0)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		readFD := uint64(memory.Read32(0x8000))
+		writeFD := uint64(memory.Read32(0x8004))
+
+		for i, b := range []byte("ping") {
+			memory.Write8(0x6000+uint64(i), b)
+		}
+		regFile.WriteReg(8, emu.SyscallWrite)
+		regFile.WriteReg(0, writeFD)
+		regFile.WriteReg(1, 0x6000)
+		regFile.WriteReg(2, 4)
+		handler.Handle()
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(4)))
+
+		regFile.WriteReg(8, emu.SyscallRead)
+		regFile.WriteReg(0, readFD)
+		regFile.WriteReg(1, 0x6100)
+		regFile.WriteReg(2, 4)
+		handler.Handle()
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(4)))
+
+		got := make([]byte, 4)
+		for i := range got {
+			got[i] = memory.Read8(0x6100 + uint64(i))
+		}
+		Expect(string(got)).To(Equal("ping"))
+	})
+
+	It("should report stdin as a character device via fstat", func() {
+		regFile.WriteReg(8, emu.SyscallFstat)
+		regFile.WriteReg(0, 0) // stdin
+		regFile.WriteReg(1, 0x9000)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		mode := memory.Read32(0x9000 + 16)
+		Expect(mode & 0o170000).To(Equal(uint32(0o020000))) // S_IFCHR
+	})
+
+	It("should report a regular file's size via fstat", func() {
+		path := filepath.Join(tempDir, "sized.txt")
+		Expect(os.WriteFile(path, []byte("0123456789"), 0644)).To(Succeed())
+		fd := openFile(path, emu.O_RDONLY, 0)
+
+		regFile.WriteReg(8, emu.SyscallFstat)
+		regFile.WriteReg(0, fd)
+		regFile.WriteReg(1, 0x9000)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+		Expect(memory.Read64(0x9000 + 48)).To(Equal(uint64(10))) // st_size
+	})
+
+	It("should claim success for TCGETS on stdout, proving isatty-style checks work", func() {
+		regFile.WriteReg(8, emu.SyscallIoctl)
+		regFile.WriteReg(0, 1) // stdout
+		regFile.WriteReg(1, emu.TCGETS)
+		regFile.WriteReg(2, 0)
+		handler.Handle()
+
+		Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+	})
+
+	It("should fail TCGETS on a plain file with ENOTTY", func() {
+		path := filepath.Join(tempDir, "notatty.txt")
+		Expect(os.WriteFile(path, []byte("x"), 0644)).To(Succeed())
+		fd := openFile(path, emu.O_RDONLY, 0)
+
+		regFile.WriteReg(8, emu.SyscallIoctl)
+		regFile.WriteReg(0, fd)
+		regFile.WriteReg(1, emu.TCGETS)
+		regFile.WriteReg(2, 0)
+		handler.Handle()
+
+		Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-25))) // -ENOTTY
+	})
+})
@@ -0,0 +1,82 @@
+package emu
+
+// ARM64 Linux syscall numbers for process identity, time, and the
+// exit_group variant of process termination.
+const (
+	SyscallClockGettime uint64 = 113 // clock_gettime(clockid, tp)
+	SyscallUname        uint64 = 160 // uname(buf)
+	SyscallGetpid       uint64 = 172 // getpid()
+	SyscallExitGroup    uint64 = 94  // exit_group(status)
+)
+
+// emulatedPid is the PID this emulator reports for the process it is
+// running; there is exactly one, so it is a constant rather than
+// anything derived from the host OS.
+const emulatedPid = 1
+
+// utsnameFieldSize is the size of each of struct utsname's six fields,
+// per Linux's `new_utsname`.
+const utsnameFieldSize = 65
+
+// registerProcessSyscalls installs getpid/uname/clock_gettime/exit_group,
+// the remainder of the startup-and-teardown surface a statically linked
+// musl or Go binary issues beyond the core set registerDefaultSyscalls
+// already covers.
+func (h *DefaultSyscallHandler) registerProcessSyscalls() {
+	h.RegisterSyscall(SyscallClockGettime, "clock_gettime", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleClockGettime() })
+	h.RegisterSyscall(SyscallUname, "uname", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleUname() })
+	h.RegisterSyscall(SyscallGetpid, "getpid", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleGetpid() })
+	h.RegisterSyscall(SyscallExitGroup, "exit_group", func(ctx *SyscallContext) SyscallResult { return ctx.h.handleExit() })
+}
+
+// handleClockGettime handles the clock_gettime syscall (113).
+// int clock_gettime(clockid_t clockid, struct timespec *tp)
+//   - X0: clockid (ignored; the emulator has no wall clock)
+//   - X1: struct timespec * to fill with {tv_sec, tv_nsec}
+//   - Returns: 0
+//
+// The emulator always reports time zero, since it has no notion of wall
+// clock time; callers only need a struct shaped correctly, not an
+// accurate reading.
+func (h *DefaultSyscallHandler) handleClockGettime() SyscallResult {
+	tp := h.regFile.ReadReg(1)
+	h.memory.Write64(tp, 0)   // tv_sec
+	h.memory.Write64(tp+8, 0) // tv_nsec
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleUname handles the uname syscall (160).
+// int uname(struct utsname *buf)
+//   - X0: struct utsname * to fill with six NUL-padded 65-byte fields:
+//     sysname, nodename, release, version, machine, domainname
+//   - Returns: 0
+func (h *DefaultSyscallHandler) handleUname() SyscallResult {
+	buf := h.regFile.ReadReg(0)
+
+	fields := []string{"Linux", "m2sim", "0.0.0", "#1", "aarch64", "(none)"}
+	for i, field := range fields {
+		h.writeCString(buf+uint64(i*utsnameFieldSize), field, utsnameFieldSize)
+	}
+
+	h.regFile.WriteReg(0, 0)
+	return SyscallResult{}
+}
+
+// handleGetpid handles the getpid syscall (172).
+func (h *DefaultSyscallHandler) handleGetpid() SyscallResult {
+	h.regFile.WriteReg(0, emulatedPid)
+	return SyscallResult{}
+}
+
+// writeCString writes s into memory at addr, NUL-padded to size bytes,
+// truncating s if it does not fit.
+func (h *DefaultSyscallHandler) writeCString(addr uint64, s string, size int) {
+	for i := 0; i < size; i++ {
+		var b byte
+		if i < len(s) {
+			b = s[i]
+		}
+		h.memory.Write8(addr+uint64(i), b)
+	}
+}
@@ -0,0 +1,78 @@
+package emu_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Process identity and time syscalls", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		handler *emu.DefaultSyscallHandler
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		handler = emu.NewDefaultSyscallHandler(regFile, memory, new(bytes.Buffer), new(bytes.Buffer))
+	})
+
+	Describe("getpid", func() {
+		It("should report the emulator's single fixed pid", func() {
+			regFile.WriteReg(8, emu.SyscallGetpid)
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("uname", func() {
+		It("should fill struct utsname with six NUL-padded 65-byte fields", func() {
+			const buf = 0x3000
+			regFile.WriteReg(8, emu.SyscallUname)
+			regFile.WriteReg(0, buf)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read8(buf)).To(Equal(byte('L')))
+			Expect(memory.Read8(buf + 65)).To(Equal(byte('m'))) // nodename: "m2sim"
+		})
+	})
+
+	Describe("clock_gettime", func() {
+		It("should report time zero into the timespec", func() {
+			const tp = 0x4000
+			memory.Write64(tp, 0xFFFFFFFFFFFFFFFF)
+			memory.Write64(tp+8, 0xFFFFFFFFFFFFFFFF)
+
+			regFile.WriteReg(8, emu.SyscallClockGettime)
+			regFile.WriteReg(1, tp)
+
+			handler.Handle()
+
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(0)))
+			Expect(memory.Read64(tp)).To(Equal(uint64(0)))
+			Expect(memory.Read64(tp + 8)).To(Equal(uint64(0)))
+		})
+	})
+
+	Describe("exit_group", func() {
+		It("should terminate the same way exit does", func() {
+			regFile.WriteReg(8, emu.SyscallExitGroup)
+			regFile.WriteReg(0, 7)
+
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeTrue())
+			Expect(result.ExitCode).To(Equal(int64(7)))
+		})
+	})
+})
@@ -0,0 +1,235 @@
+package emu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// syscallSnapshotMagic and syscallSnapshotVersion identify the binary
+// format Snapshot writes and Restore reads, so a future format change
+// fails loudly on old snapshots instead of silently misreading them.
+const (
+	syscallSnapshotMagic   uint32 = 0x4D32534B // "M2SK"
+	syscallSnapshotVersion uint32 = 1
+)
+
+// FDRestorePolicy decides, for one file fdTable had open when a snapshot
+// was taken, whether Restore should reopen it (seeking back to its
+// recorded offset) or leave it as a tombstone: a reserved, already-closed
+// descriptor number, so a later access by the restored guest fails with
+// EBADF instead of silently landing on an unrelated file some other fd
+// happens to reuse that number for.
+type FDRestorePolicy func(fd uint64, path string) bool
+
+// ReopenAllFiles is an FDRestorePolicy that reopens every recorded file,
+// appropriate when the snapshot is being restored on the same machine
+// (or one with an identical filesystem layout) it was taken on.
+func ReopenAllFiles(fd uint64, path string) bool { return true }
+
+// TombstoneAllFiles is an FDRestorePolicy that reopens nothing, leaving
+// every recorded fd above 2 as a tombstone. Appropriate when the
+// snapshot is being inspected or replayed somewhere the original files
+// don't exist.
+func TombstoneAllFiles(fd uint64, path string) bool { return false }
+
+// Snapshot writes the program break, mmap allocator position, tracked
+// VMA list, and open (non-stdio) file descriptors to w, so Restore can
+// reconstruct an equivalent DefaultSyscallHandler without replaying the
+// syscalls that built this one. Combined with Memory.Snapshot and a
+// register snapshot, this is enough to fast-forward to a region of
+// interest and hand off to the timing pipeline from there.
+func (h *DefaultSyscallHandler) Snapshot(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, syscallSnapshotMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, syscallSnapshotVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, h.programBreak); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, h.nextMmapAddr); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(h.vmas.regions))); err != nil {
+		return err
+	}
+	for _, r := range h.vmas.regions {
+		if err := writeVMARegion(w, r); err != nil {
+			return err
+		}
+	}
+
+	return h.snapshotFDTable(w)
+}
+
+// writeVMARegion writes one MmapRegion's fields to w in a fixed order.
+func writeVMARegion(w io.Writer, r *MmapRegion) error {
+	fields := []any{r.Addr, r.Length, int32(r.Prot), int32(r.Flags), r.Fd, r.Offset, r.Dirty}
+	for _, f := range fields {
+		if err := binary.Write(w, binary.LittleEndian, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readVMARegion reads one MmapRegion back in the order writeVMARegion
+// wrote it.
+func readVMARegion(r io.Reader) (*MmapRegion, error) {
+	var region MmapRegion
+	var prot, flags int32
+
+	for _, f := range []any{&region.Addr, &region.Length, &prot, &flags, &region.Fd, &region.Offset, &region.Dirty} {
+		if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+			return nil, err
+		}
+	}
+	region.Prot = int(prot)
+	region.Flags = int(flags)
+	return &region, nil
+}
+
+// fdSnapshotEntry is one open, non-stdio file descriptor's recorded
+// state: enough to either reopen it at the same offset or tombstone it.
+type fdSnapshotEntry struct {
+	fd     uint64
+	path   string
+	offset int64
+}
+
+// snapshotFDTable writes every open fd above 2 (stdin/stdout/stderr are
+// reconstructed fresh by NewDefaultSyscallHandler and aren't recorded).
+func (h *DefaultSyscallHandler) snapshotFDTable(w io.Writer) error {
+	var entries []fdSnapshotEntry
+	for _, fd := range h.fdTable.OpenFDs() {
+		if fd <= 2 {
+			continue
+		}
+		entry, ok := h.fdTable.Get(fd)
+		if !ok {
+			continue
+		}
+		offset, err := h.fdTable.Lseek(fd, 0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("emu: snapshotting fd %d offset: %w", fd, err)
+		}
+		entries = append(entries, fdSnapshotEntry{fd: fd, path: entry.Path, offset: offset})
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(entries))); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := binary.Write(w, binary.LittleEndian, e.fd); err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, uint64(len(e.path))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, e.path); err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, e.offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore replaces h's program break, mmap allocator position, and VMA
+// bookkeeping with the state r was snapshotted from, and — per policy —
+// either reopens or tombstones each recorded non-stdio file descriptor.
+// It returns an error rather than partially restoring if the stream's
+// magic or version doesn't match, or if it's truncated.
+func (h *DefaultSyscallHandler) Restore(r io.Reader, policy FDRestorePolicy) error {
+	var magic, version uint32
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot magic: %w", err)
+	}
+	if magic != syscallSnapshotMagic {
+		return fmt.Errorf("emu: syscall handler snapshot has bad magic 0x%08X, want 0x%08X", magic, syscallSnapshotMagic)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot version: %w", err)
+	}
+	if version != syscallSnapshotVersion {
+		return fmt.Errorf("emu: syscall handler snapshot version %d is not supported (want %d)", version, syscallSnapshotVersion)
+	}
+
+	var programBreak, nextMmapAddr, numRegions uint64
+	if err := binary.Read(r, binary.LittleEndian, &programBreak); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot program break: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &nextMmapAddr); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot mmap cursor: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &numRegions); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot VMA count: %w", err)
+	}
+
+	regions := make([]*MmapRegion, numRegions)
+	for i := range regions {
+		region, err := readVMARegion(r)
+		if err != nil {
+			return fmt.Errorf("emu: reading syscall handler snapshot VMA %d: %w", i, err)
+		}
+		regions[i] = region
+	}
+
+	if err := h.restoreFDTable(r, policy); err != nil {
+		return err
+	}
+
+	h.programBreak = programBreak
+	h.nextMmapAddr = nextMmapAddr
+	h.vmas = vmaSet{regions: regions}
+	return nil
+}
+
+// restoreFDTable reads the recorded non-stdio file descriptors and,
+// for each, either reopens it at its recorded offset or tombstones it,
+// according to policy. A nil policy tombstones everything, the safer
+// default when the caller hasn't thought about whether the restoring
+// process can see the same files.
+func (h *DefaultSyscallHandler) restoreFDTable(r io.Reader, policy FDRestorePolicy) error {
+	if policy == nil {
+		policy = TombstoneAllFiles
+	}
+
+	var numEntries uint64
+	if err := binary.Read(r, binary.LittleEndian, &numEntries); err != nil {
+		return fmt.Errorf("emu: reading syscall handler snapshot fd count: %w", err)
+	}
+
+	for i := uint64(0); i < numEntries; i++ {
+		var fd, pathLen uint64
+		var offset int64
+		if err := binary.Read(r, binary.LittleEndian, &fd); err != nil {
+			return fmt.Errorf("emu: reading syscall handler snapshot fd %d: %w", i, err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &pathLen); err != nil {
+			return fmt.Errorf("emu: reading syscall handler snapshot fd %d path length: %w", i, err)
+		}
+		pathBytes := make([]byte, pathLen)
+		if _, err := io.ReadFull(r, pathBytes); err != nil {
+			return fmt.Errorf("emu: reading syscall handler snapshot fd %d path: %w", i, err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &offset); err != nil {
+			return fmt.Errorf("emu: reading syscall handler snapshot fd %d offset: %w", i, err)
+		}
+		path := string(pathBytes)
+
+		if !policy(fd, path) {
+			h.fdTable.Tombstone(fd)
+			continue
+		}
+		if err := h.fdTable.ReopenAt(fd, path, offset); err != nil {
+			return fmt.Errorf("emu: reopening fd %d (%s): %w", fd, path, err)
+		}
+	}
+
+	return nil
+}
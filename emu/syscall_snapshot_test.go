@@ -0,0 +1,70 @@
+package emu_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+var _ = Describe("Syscall Handler Snapshot/Restore", func() {
+	var (
+		regFile *emu.RegFile
+		memory  *emu.Memory
+		src     *emu.DefaultSyscallHandler
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		memory = emu.NewMemory()
+		src = emu.NewDefaultSyscallHandler(regFile, memory, new(bytes.Buffer), new(bytes.Buffer))
+	})
+
+	It("should round-trip the program break, mmap cursor, and VMA list", func() {
+		regFile.WriteReg(8, emu.SyscallBrk)
+		regFile.WriteReg(0, src.GetProgramBreak()+emu.PageSize)
+		src.Handle()
+
+		regFile.WriteReg(8, emu.SyscallMmap)
+		regFile.WriteReg(0, 0)
+		regFile.WriteReg(1, emu.PageSize)
+		regFile.WriteReg(2, emu.PROT_READ|emu.PROT_WRITE)
+		regFile.WriteReg(3, emu.MAP_ANONYMOUS|emu.MAP_PRIVATE)
+		regFile.WriteReg(4, ^uint64(0))
+		regFile.WriteReg(5, 0)
+		src.Handle()
+		mappedAddr := regFile.ReadReg(0)
+
+		var buf bytes.Buffer
+		Expect(src.Snapshot(&buf)).To(Succeed())
+
+		dstRegFile := &emu.RegFile{}
+		dst := emu.NewDefaultSyscallHandler(dstRegFile, emu.NewMemory(), new(bytes.Buffer), new(bytes.Buffer))
+		Expect(dst.Restore(&buf, emu.TombstoneAllFiles)).To(Succeed())
+
+		Expect(dst.GetProgramBreak()).To(Equal(src.GetProgramBreak()))
+		Expect(dst.LookupVMA(mappedAddr)).ToNot(BeNil())
+		Expect(dst.LookupVMA(mappedAddr).Length).To(Equal(uint64(emu.PageSize)))
+	})
+
+	It("should reject a snapshot with a bad magic number", func() {
+		dst := emu.NewDefaultSyscallHandler(&emu.RegFile{}, emu.NewMemory(), new(bytes.Buffer), new(bytes.Buffer))
+		err := dst.Restore(bytes.NewReader([]byte{0, 0, 0, 0}), emu.TombstoneAllFiles)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a snapshot with an unsupported version", func() {
+		var buf bytes.Buffer
+		Expect(src.Snapshot(&buf)).To(Succeed())
+		raw := buf.Bytes()
+		raw[4] = 0xFF // corrupt the version field just past the magic
+
+		dst := emu.NewDefaultSyscallHandler(&emu.RegFile{}, emu.NewMemory(), new(bytes.Buffer), new(bytes.Buffer))
+		err := dst.Restore(bytes.NewReader(raw), emu.TombstoneAllFiles)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
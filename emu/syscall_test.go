@@ -144,4 +144,183 @@ var _ = Describe("Syscall Handler", func() {
 			Expect(regFile.ReadReg(0)).To(Equal(uint64(3)))
 		})
 	})
+
+	Describe("RegisterSyscall", func() {
+		It("should let a caller add a syscall this package doesn't implement", func() {
+			const syscallGetrandom = 278
+			handler.RegisterSyscall(syscallGetrandom, "getrandom", func(ctx *emu.SyscallContext) emu.SyscallResult {
+				ctx.RegFile.WriteReg(0, 4)
+				return emu.SyscallResult{}
+			})
+
+			regFile.WriteReg(8, syscallGetrandom)
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(regFile.ReadReg(0)).To(Equal(uint64(4)))
+		})
+
+		It("should let a caller override a syscall this package already implements", func() {
+			handler.RegisterSyscall(emu.SyscallWrite, "write", func(ctx *emu.SyscallContext) emu.SyscallResult {
+				ctx.SetError(emu.ENOSYS)
+				return emu.SyscallResult{}
+			})
+
+			regFile.WriteReg(8, 64) // SyscallWrite
+			regFile.WriteReg(0, 1)
+			result := handler.Handle()
+
+			Expect(result.Exited).To(BeFalse())
+			Expect(int64(regFile.ReadReg(0))).To(Equal(int64(-38)))
+		})
+	})
+
+	Describe("mmap/munmap/mprotect", func() {
+		It("should back an anonymous mapping with real, writable memory", func() {
+			regFile.WriteReg(8, emu.SyscallMmap)
+			regFile.WriteReg(0, 0) // no hint
+			regFile.WriteReg(1, emu.PageSize)
+			regFile.WriteReg(2, emu.PROT_READ|emu.PROT_WRITE)
+			regFile.WriteReg(3, emu.MAP_ANONYMOUS|emu.MAP_PRIVATE)
+			regFile.WriteReg(4, ^uint64(0)) // fd -1
+			regFile.WriteReg(5, 0)
+
+			handler.Handle()
+			mappedAddr := regFile.ReadReg(0)
+
+			memory.Write8(mappedAddr, 0x42)
+			Expect(memory.Read8(mappedAddr)).To(Equal(byte(0x42)))
+
+			region := handler.LookupVMA(mappedAddr)
+			Expect(region).ToNot(BeNil())
+			Expect(region.Length).To(Equal(uint64(emu.PageSize)))
+		})
+
+		It("should free a middle mapping on munmap and leave the rest mapped", func() {
+			regFile.WriteReg(8, emu.SyscallMmap)
+			regFile.WriteReg(0, 0)
+			regFile.WriteReg(1, 3*emu.PageSize)
+			regFile.WriteReg(2, emu.PROT_READ|emu.PROT_WRITE)
+			regFile.WriteReg(3, emu.MAP_ANONYMOUS|emu.MAP_PRIVATE)
+			regFile.WriteReg(4, ^uint64(0))
+			regFile.WriteReg(5, 0)
+			handler.Handle()
+			base := regFile.ReadReg(0)
+
+			regFile.WriteReg(8, emu.SyscallMunmap)
+			regFile.WriteReg(0, base+emu.PageSize)
+			regFile.WriteReg(1, emu.PageSize)
+			handler.Handle()
+
+			Expect(handler.LookupVMA(base)).ToNot(BeNil())
+			Expect(handler.LookupVMA(base + emu.PageSize)).To(BeNil())
+			Expect(handler.LookupVMA(base + 2*emu.PageSize)).ToNot(BeNil())
+		})
+
+		It("should reuse a gap freed by munmap for a later anonymous mmap", func() {
+			mmapOnce := func(length uint64) uint64 {
+				regFile.WriteReg(8, emu.SyscallMmap)
+				regFile.WriteReg(0, 0)
+				regFile.WriteReg(1, length)
+				regFile.WriteReg(2, emu.PROT_READ|emu.PROT_WRITE)
+				regFile.WriteReg(3, emu.MAP_ANONYMOUS|emu.MAP_PRIVATE)
+				regFile.WriteReg(4, ^uint64(0))
+				regFile.WriteReg(5, 0)
+				handler.Handle()
+				return regFile.ReadReg(0)
+			}
+
+			first := mmapOnce(emu.PageSize)
+
+			regFile.WriteReg(8, emu.SyscallMunmap)
+			regFile.WriteReg(0, first)
+			regFile.WriteReg(1, emu.PageSize)
+			handler.Handle()
+
+			second := mmapOnce(emu.PageSize)
+			Expect(second).To(Equal(first))
+		})
+
+		It("should split protection changes that only cover part of a mapping", func() {
+			regFile.WriteReg(8, emu.SyscallMmap)
+			regFile.WriteReg(0, 0)
+			regFile.WriteReg(1, 2*emu.PageSize)
+			regFile.WriteReg(2, emu.PROT_READ|emu.PROT_WRITE)
+			regFile.WriteReg(3, emu.MAP_ANONYMOUS|emu.MAP_PRIVATE)
+			regFile.WriteReg(4, ^uint64(0))
+			regFile.WriteReg(5, 0)
+			handler.Handle()
+			base := regFile.ReadReg(0)
+
+			regFile.WriteReg(8, emu.SyscallMprotect)
+			regFile.WriteReg(0, base)
+			regFile.WriteReg(1, emu.PageSize)
+			regFile.WriteReg(2, emu.PROT_READ)
+			handler.Handle()
+
+			Expect(handler.LookupVMA(base).Prot).To(Equal(emu.PROT_READ))
+			Expect(handler.LookupVMA(base + emu.PageSize).Prot).To(Equal(emu.PROT_READ | emu.PROT_WRITE))
+		})
+	})
+
+	Describe("SetTracer", func() {
+		It("should call Enter before and Exit after each dispatched syscall", func() {
+			var entered, exited bool
+			var enterNum uint64
+			var enterName string
+			var enterArgs [6]uint64
+			var exitRet uint64
+			var exitErrno int
+
+			handler.SetTracer(fakeTracer{
+				enter: func(num uint64, name string, args [6]uint64) {
+					entered = true
+					enterNum, enterName, enterArgs = num, name, args
+				},
+				exit: func(ret uint64, errno int, result emu.SyscallResult) {
+					exited = true
+					exitRet, exitErrno = ret, errno
+				},
+			})
+
+			regFile.WriteReg(8, 93) // SyscallExit
+			regFile.WriteReg(0, 7)  // exit code
+			handler.Handle()
+
+			Expect(entered).To(BeTrue())
+			Expect(enterNum).To(Equal(uint64(93)))
+			Expect(enterName).To(Equal("exit"))
+			Expect(enterArgs[0]).To(Equal(uint64(7)))
+
+			Expect(exited).To(BeTrue())
+			Expect(exitRet).To(Equal(uint64(7)))
+			Expect(exitErrno).To(Equal(0))
+		})
+
+		It("should decode a negative return as its errno", func() {
+			var gotErrno int
+			handler.SetTracer(fakeTracer{
+				enter: func(uint64, string, [6]uint64) {},
+				exit:  func(ret uint64, errno int, result emu.SyscallResult) { gotErrno = errno },
+			})
+
+			regFile.WriteReg(8, 64) // SyscallWrite
+			regFile.WriteReg(0, 42) // invalid fd -> EBADF
+			handler.Handle()
+
+			Expect(gotErrno).To(Equal(9))
+		})
+	})
 })
+
+// fakeTracer is a minimal emu.SyscallTracer for asserting Enter/Exit are
+// called with the expected arguments.
+type fakeTracer struct {
+	enter func(num uint64, name string, args [6]uint64)
+	exit  func(ret uint64, errno int, result emu.SyscallResult)
+}
+
+func (t fakeTracer) Enter(num uint64, name string, args [6]uint64) { t.enter(num, name, args) }
+func (t fakeTracer) Exit(ret uint64, errno int, result emu.SyscallResult) {
+	t.exit(ret, errno, result)
+}
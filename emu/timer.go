@@ -0,0 +1,26 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+// Timer is a read-only MMIO Device exposing a monotonically increasing
+// count — typically Emulator.InstructionCount — at a single 64-bit
+// register, letting a bare-metal program (one with no syscalls available)
+// measure elapsed work.
+type Timer struct {
+	count func() uint64
+}
+
+// NewTimer creates a Timer that reports count() on every read.
+func NewTimer(count func() uint64) *Timer {
+	return &Timer{count: count}
+}
+
+// Read implements Device: every offset returns the current count, with
+// the caller narrowing it to the requested size.
+func (t *Timer) Read(addr uint64, size int) (uint64, error) {
+	return t.count(), nil
+}
+
+// Write implements Device. The timer is read-only, so writes are ignored.
+func (t *Timer) Write(addr uint64, size int, val uint64) error {
+	return nil
+}
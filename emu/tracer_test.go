@@ -0,0 +1,72 @@
+package emu_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+func tracerProgram(inst uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, inst)
+	return buf
+}
+
+var _ = Describe("Tracer", func() {
+	var e *emu.Emulator
+
+	BeforeEach(func() {
+		e = emu.NewEmulator()
+	})
+
+	It("should leave StepResult.Disassembly empty when no tracer is attached", func() {
+		inst := encodeUDIV(0, 1, 2, true)
+		e.RegFile().WriteReg(1, 100)
+		e.RegFile().WriteReg(2, 7)
+		e.LoadProgram(0x1000, tracerProgram(inst))
+
+		result := e.Step()
+
+		Expect(result.Err).To(BeNil())
+		Expect(result.Disassembly).To(BeEmpty())
+	})
+
+	It("should populate StepResult.Disassembly and write a line to the tracer", func() {
+		var buf bytes.Buffer
+		e.SetTracer(&buf)
+
+		inst := encodeUDIV(0, 1, 2, true)
+		e.RegFile().WriteReg(1, 100)
+		e.RegFile().WriteReg(2, 7)
+		e.LoadProgram(0x1000, tracerProgram(inst))
+
+		result := e.Step()
+
+		Expect(result.Err).To(BeNil())
+		Expect(result.Disassembly).NotTo(BeEmpty())
+		Expect(buf.String()).To(ContainSubstring("0x00001000"))
+		Expect(strings.TrimSpace(buf.String())).To(HaveSuffix(result.Disassembly))
+	})
+
+	It("should stop tracing once detached with nil", func() {
+		var buf bytes.Buffer
+		e.SetTracer(&buf)
+		e.SetTracer(nil)
+
+		inst := encodeUDIV(0, 1, 2, true)
+		e.RegFile().WriteReg(1, 100)
+		e.RegFile().WriteReg(2, 7)
+		e.LoadProgram(0x1000, tracerProgram(inst))
+
+		result := e.Step()
+
+		Expect(result.Err).To(BeNil())
+		Expect(result.Disassembly).To(BeEmpty())
+		Expect(buf.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,47 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import "io"
+
+// UART register offsets, relative to the device's mapped base. Only the
+// subset a polling bare-metal program needs is modeled.
+const (
+	uartRegTHR = 0x0 // Transmit Holding Register (write)
+	uartRegLSR = 0x5 // Line Status Register (read)
+)
+
+// uartLSRTHRE marks the Transmit Holding Register empty in LSR, so a
+// guest's "wait until ready to send" poll loop never blocks.
+const uartLSRTHRE = 1 << 5
+
+// UART is a minimal 16550-style serial port Device: a byte written to its
+// Transmit Holding Register is emitted to w (typically the Emulator's
+// configured stdout), and its Line Status Register always reports
+// ready-to-transmit. It does not model receiving, interrupts, or baud
+// rate — just enough for a bare-metal program to print output.
+type UART struct {
+	w io.Writer
+}
+
+// NewUART creates a UART that writes transmitted bytes to w.
+func NewUART(w io.Writer) *UART {
+	return &UART{w: w}
+}
+
+// Read implements Device.
+func (u *UART) Read(addr uint64, size int) (uint64, error) {
+	if addr == uartRegLSR {
+		return uartLSRTHRE, nil
+	}
+	return 0, nil
+}
+
+// Write implements Device. A write to THR emits val's low byte to w;
+// every other register is ignored.
+func (u *UART) Write(addr uint64, size int, val uint64) error {
+	if addr != uartRegTHR {
+		return nil
+	}
+	_, err := u.w.Write([]byte{byte(val)})
+	return err
+}
@@ -0,0 +1,425 @@
+// Package emu provides functional ARM64 emulation.
+package emu
+
+import "math"
+
+// Arrangement identifies how a 128-bit V register is divided into lanes
+// for a vector (ASIMD) operation, mirroring ARM64's Vd.<T> syntax.
+type Arrangement int
+
+const (
+	Arrangement16B Arrangement = iota // 16 lanes of 8 bits
+	Arrangement8H                     // 8 lanes of 16 bits
+	Arrangement4S                     // 4 lanes of 32 bits
+	Arrangement2D                     // 2 lanes of 64 bits
+)
+
+// laneSize returns the width in bytes of one lane of arr.
+func laneSize(arr Arrangement) int {
+	switch arr {
+	case Arrangement8H:
+		return 2
+	case Arrangement4S:
+		return 4
+	case Arrangement2D:
+		return 8
+	default:
+		return 1
+	}
+}
+
+// laneCount returns the number of lanes of arr in a 128-bit register.
+func laneCount(arr Arrangement) int {
+	return 16 / laneSize(arr)
+}
+
+// FPSRInvalidOp is the Invalid Operation bit of FPSR, the only exception
+// flag this emulator currently tracks: it is set whenever a scalar
+// floating-point operation consumes a signaling NaN.
+const FPSRInvalidOp uint32 = 1 << 0
+
+// VALU implements ARM64's scalar floating-point and vector (ASIMD)
+// instructions, operating on the V registers of a RegFile. It is a
+// sibling of ALU, which covers the integer data-processing instructions.
+type VALU struct {
+	rf *RegFile
+}
+
+// NewVALU creates a VALU operating on regFile.
+func NewVALU(regFile *RegFile) *VALU {
+	return &VALU{rf: regFile}
+}
+
+// isSNaN64 reports whether bits encodes a signaling (as opposed to
+// quiet) double-precision NaN: all-ones exponent, nonzero mantissa, and
+// the mantissa's leading bit (the "is quiet" bit) clear.
+func isSNaN64(bits uint64) bool {
+	const expMask = uint64(0x7FF) << 52
+	const mantMask = (uint64(1) << 52) - 1
+	return bits&expMask == expMask && bits&mantMask != 0 && bits&(1<<51) == 0
+}
+
+// isSNaN32 is isSNaN64 for single precision.
+func isSNaN32(bits uint32) bool {
+	const expMask = uint32(0xFF) << 23
+	const mantMask = (uint32(1) << 23) - 1
+	return bits&expMask == expMask && bits&mantMask != 0 && bits&(1<<22) == 0
+}
+
+// checkSNaN64 raises FPSRInvalidOp if any of vals is a signaling NaN.
+func (v *VALU) checkSNaN64(vals ...float64) {
+	for _, f := range vals {
+		if isSNaN64(math.Float64bits(f)) {
+			v.rf.FPSR |= FPSRInvalidOp
+		}
+	}
+}
+
+// checkSNaN32 is checkSNaN64 for single precision.
+func (v *VALU) checkSNaN32(vals ...float32) {
+	for _, f := range vals {
+		if isSNaN32(math.Float32bits(f)) {
+			v.rf.FPSR |= FPSRInvalidOp
+		}
+	}
+}
+
+// FADD64 computes Dd = Dn + Dm.
+func (v *VALU) FADD64(rd, rn, rm uint8) {
+	a := math.Float64frombits(v.rf.ReadD(rn))
+	b := math.Float64frombits(v.rf.ReadD(rm))
+	v.checkSNaN64(a, b)
+	v.rf.WriteD(rd, math.Float64bits(a+b))
+}
+
+// FSUB64 computes Dd = Dn - Dm.
+func (v *VALU) FSUB64(rd, rn, rm uint8) {
+	a := math.Float64frombits(v.rf.ReadD(rn))
+	b := math.Float64frombits(v.rf.ReadD(rm))
+	v.checkSNaN64(a, b)
+	v.rf.WriteD(rd, math.Float64bits(a-b))
+}
+
+// FMUL64 computes Dd = Dn * Dm.
+func (v *VALU) FMUL64(rd, rn, rm uint8) {
+	a := math.Float64frombits(v.rf.ReadD(rn))
+	b := math.Float64frombits(v.rf.ReadD(rm))
+	v.checkSNaN64(a, b)
+	v.rf.WriteD(rd, math.Float64bits(a*b))
+}
+
+// FDIV64 computes Dd = Dn / Dm.
+func (v *VALU) FDIV64(rd, rn, rm uint8) {
+	a := math.Float64frombits(v.rf.ReadD(rn))
+	b := math.Float64frombits(v.rf.ReadD(rm))
+	v.checkSNaN64(a, b)
+	v.rf.WriteD(rd, math.Float64bits(a/b))
+}
+
+// FADD32 computes Sd = Sn + Sm.
+func (v *VALU) FADD32(rd, rn, rm uint8) {
+	a := math.Float32frombits(v.rf.ReadS(rn))
+	b := math.Float32frombits(v.rf.ReadS(rm))
+	v.checkSNaN32(a, b)
+	v.rf.WriteS(rd, math.Float32bits(a+b))
+}
+
+// FSUB32 computes Sd = Sn - Sm.
+func (v *VALU) FSUB32(rd, rn, rm uint8) {
+	a := math.Float32frombits(v.rf.ReadS(rn))
+	b := math.Float32frombits(v.rf.ReadS(rm))
+	v.checkSNaN32(a, b)
+	v.rf.WriteS(rd, math.Float32bits(a-b))
+}
+
+// FMUL32 computes Sd = Sn * Sm.
+func (v *VALU) FMUL32(rd, rn, rm uint8) {
+	a := math.Float32frombits(v.rf.ReadS(rn))
+	b := math.Float32frombits(v.rf.ReadS(rm))
+	v.checkSNaN32(a, b)
+	v.rf.WriteS(rd, math.Float32bits(a*b))
+}
+
+// FDIV32 computes Sd = Sn / Sm.
+func (v *VALU) FDIV32(rd, rn, rm uint8) {
+	a := math.Float32frombits(v.rf.ReadS(rn))
+	b := math.Float32frombits(v.rf.ReadS(rm))
+	v.checkSNaN32(a, b)
+	v.rf.WriteS(rd, math.Float32bits(a/b))
+}
+
+// FCVTSToD widens Sn to double precision and writes it to Dd.
+func (v *VALU) FCVTSToD(rd, rn uint8) {
+	f := math.Float32frombits(v.rf.ReadS(rn))
+	v.checkSNaN32(f)
+	v.rf.WriteD(rd, math.Float64bits(float64(f)))
+}
+
+// FCVTDToS narrows Dn to single precision and writes it to Sd.
+func (v *VALU) FCVTDToS(rd, rn uint8) {
+	f := math.Float64frombits(v.rf.ReadD(rn))
+	v.checkSNaN64(f)
+	v.rf.WriteS(rd, math.Float32bits(float32(f)))
+}
+
+// FCMP64 compares Dn against Dm and sets PSTATE per the ARMv8 FCMP rules:
+// unordered (either operand NaN) sets C and V; equal sets Z and C; less
+// than sets N; greater than sets C alone. A signaling NaN additionally
+// raises FPSRInvalidOp; a quiet NaN does not (matching FCMP, as opposed
+// to the signaling FCMPE).
+func (v *VALU) FCMP64(rn, rm uint8) {
+	a := math.Float64frombits(v.rf.ReadD(rn))
+	b := math.Float64frombits(v.rf.ReadD(rm))
+	if isSNaN64(math.Float64bits(a)) || isSNaN64(math.Float64bits(b)) {
+		v.rf.FPSR |= FPSRInvalidOp
+	}
+	v.setCompareFlags(math.IsNaN(a) || math.IsNaN(b), a == b, a < b)
+}
+
+// FCMP32 is FCMP64 for single precision.
+func (v *VALU) FCMP32(rn, rm uint8) {
+	a := math.Float32frombits(v.rf.ReadS(rn))
+	b := math.Float32frombits(v.rf.ReadS(rm))
+	if isSNaN32(math.Float32bits(a)) || isSNaN32(math.Float32bits(b)) {
+		v.rf.FPSR |= FPSRInvalidOp
+	}
+	v.setCompareFlags(math.IsNaN(float64(a)) || math.IsNaN(float64(b)), a == b, a < b)
+}
+
+func (v *VALU) setCompareFlags(unordered, equal, less bool) {
+	p := &v.rf.PSTATE
+	switch {
+	case unordered:
+		p.N, p.Z, p.C, p.V = false, false, true, true
+	case equal:
+		p.N, p.Z, p.C, p.V = false, true, true, false
+	case less:
+		p.N, p.Z, p.C, p.V = true, false, false, false
+	default: // greater than
+		p.N, p.Z, p.C, p.V = false, false, true, false
+	}
+}
+
+// FMOVImmS writes bits, the already-decoded 32-bit float immediate
+// pattern, into Sd. (Expanding ARM64's 8-bit FMOV immediate encoding into
+// a full 32-bit pattern is the decoder's job, the same division of labor
+// MOVZ/MOVN/MOVK use for their 16-bit immediates.)
+func (v *VALU) FMOVImmS(rd uint8, bits uint32) {
+	v.rf.WriteS(rd, bits)
+}
+
+// FMOVImmD is FMOVImmS for the 64-bit immediate pattern.
+func (v *VALU) FMOVImmD(rd uint8, bits uint64) {
+	v.rf.WriteD(rd, bits)
+}
+
+// FMOVS copies Sn to Sd (register-to-register FMOV, single precision).
+func (v *VALU) FMOVS(rd, rn uint8) {
+	v.rf.WriteS(rd, v.rf.ReadS(rn))
+}
+
+// FMOVD copies Dn to Dd (register-to-register FMOV, double precision).
+func (v *VALU) FMOVD(rd, rn uint8) {
+	v.rf.WriteD(rd, v.rf.ReadD(rn))
+}
+
+// FMOVXToD implements "FMOV Dd, Xn": Dd's bits become value unchanged.
+func (v *VALU) FMOVXToD(rd uint8, value uint64) {
+	v.rf.WriteD(rd, value)
+}
+
+// FMOVDToX implements "FMOV Xd, Dn": returns Dn's bits unchanged.
+func (v *VALU) FMOVDToX(rn uint8) uint64 {
+	return v.rf.ReadD(rn)
+}
+
+// FMOVWToS implements "FMOV Sd, Wn": Sd's bits become value unchanged.
+func (v *VALU) FMOVWToS(rd uint8, value uint32) {
+	v.rf.WriteS(rd, value)
+}
+
+// FMOVSToW implements "FMOV Wd, Sn": returns Sn's bits unchanged.
+func (v *VALU) FMOVSToW(rn uint8) uint32 {
+	return v.rf.ReadS(rn)
+}
+
+// SCVTF64 implements the 64-bit-source, double-precision-result form of
+// SCVTF: Dd = (double)(signed)src.
+func (v *VALU) SCVTF64(rd uint8, src int64) {
+	v.rf.WriteD(rd, math.Float64bits(float64(src)))
+}
+
+// SCVTF32 implements the 32-bit-source, single-precision-result form of
+// SCVTF: Sd = (float)(signed)src.
+func (v *VALU) SCVTF32(rd uint8, src int32) {
+	v.rf.WriteS(rd, math.Float32bits(float32(src)))
+}
+
+// FCVTZS64 implements the double-precision-source, 64-bit-result form of
+// FCVTZS: round Dn toward zero into a signed 64-bit integer. A NaN input
+// converts to 0; an out-of-range input saturates, matching ARMv8's
+// defined behavior instead of C's undefined one.
+func (v *VALU) FCVTZS64(rn uint8) int64 {
+	return float64ToInt64RTZ(math.Float64frombits(v.rf.ReadD(rn)))
+}
+
+// FCVTZS32 is FCVTZS64 for a single-precision source.
+func (v *VALU) FCVTZS32(rn uint8) int32 {
+	return float32ToInt32RTZ(math.Float32frombits(v.rf.ReadS(rn)))
+}
+
+func float64ToInt64RTZ(f float64) int64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(f) // Go truncates toward zero, matching FCVTZS
+	}
+}
+
+func float32ToInt32RTZ(f float32) int32 {
+	switch {
+	case math.IsNaN(float64(f)):
+		return 0
+	case f >= math.MaxInt32:
+		return math.MaxInt32
+	case f <= math.MinInt32:
+		return math.MinInt32
+	default:
+		return int32(f)
+	}
+}
+
+// readLane interprets b (1, 2, 4, or 8 bytes) as a little-endian unsigned
+// integer.
+func readLane(b []byte) uint64 {
+	var v uint64
+	for i, by := range b {
+		v |= uint64(by) << (8 * i)
+	}
+	return v
+}
+
+// writeLane writes the low len(b) bytes of v into b, little-endian.
+func writeLane(b []byte, v uint64) {
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+}
+
+// laneAsD reads 64-bit lane `lane` of Vn as a float64 (the FMUL.2d form
+// reads each 2D lane as a double rather than a plain integer).
+func (v *VALU) laneAsD(rn, lane uint8) float64 {
+	vn := v.rf.ReadV(rn)
+	off := int(lane) * 8
+	return math.Float64frombits(readLane(vn[off : off+8]))
+}
+
+// writeLaneD writes value into 64-bit lane `lane` of Vd.
+func (v *VALU) writeLaneD(rd, lane uint8, value float64) {
+	vd := v.rf.ReadV(rd)
+	off := int(lane) * 8
+	writeLane(vd[off:off+8], math.Float64bits(value))
+	v.rf.WriteV(rd, vd)
+}
+
+// laneAsS is laneAsD for a 32-bit lane, used by the FMUL.4s form.
+func (v *VALU) laneAsS(rn, lane uint8) float32 {
+	vn := v.rf.ReadV(rn)
+	off := int(lane) * 4
+	return math.Float32frombits(uint32(readLane(vn[off : off+4])))
+}
+
+// writeLaneS writes value into 32-bit lane `lane` of Vd.
+func (v *VALU) writeLaneS(rd, lane uint8, value float32) {
+	vd := v.rf.ReadV(rd)
+	off := int(lane) * 4
+	writeLane(vd[off:off+4], uint64(math.Float32bits(value)))
+	v.rf.WriteV(rd, vd)
+}
+
+// vectorBinOp applies op lane-by-lane to Vn and Vm (arranged per arr) and
+// writes the result to Vd.
+func (v *VALU) vectorBinOp(rd, rn, rm uint8, arr Arrangement, op func(a, b uint64) uint64) {
+	size := laneSize(arr)
+	vn := v.rf.ReadV(rn)
+	vm := v.rf.ReadV(rm)
+
+	var out [16]byte
+	for i := 0; i < laneCount(arr); i++ {
+		off := i * size
+		a := readLane(vn[off : off+size])
+		b := readLane(vm[off : off+size])
+		writeLane(out[off:off+size], op(a, b))
+	}
+	v.rf.WriteV(rd, out)
+}
+
+// VADD computes Vd = Vn + Vm lane-by-lane, per arr.
+func (v *VALU) VADD(rd, rn, rm uint8, arr Arrangement) {
+	v.vectorBinOp(rd, rn, rm, arr, func(a, b uint64) uint64 { return a + b })
+}
+
+// VSUB computes Vd = Vn - Vm lane-by-lane, per arr.
+func (v *VALU) VSUB(rd, rn, rm uint8, arr Arrangement) {
+	v.vectorBinOp(rd, rn, rm, arr, func(a, b uint64) uint64 { return a - b })
+}
+
+// VAND computes Vd = Vn & Vm (the arrangement only affects how VADD/VSUB
+// wrap per lane; bitwise AND/ORR/EOR behave identically at any width, so
+// these ignore arr beyond the caller's own bookkeeping).
+func (v *VALU) VAND(rd, rn, rm uint8, arr Arrangement) {
+	v.vectorBinOp(rd, rn, rm, arr, func(a, b uint64) uint64 { return a & b })
+}
+
+// VORR computes Vd = Vn | Vm.
+func (v *VALU) VORR(rd, rn, rm uint8, arr Arrangement) {
+	v.vectorBinOp(rd, rn, rm, arr, func(a, b uint64) uint64 { return a | b })
+}
+
+// VEOR computes Vd = Vn ^ Vm.
+func (v *VALU) VEOR(rd, rn, rm uint8, arr Arrangement) {
+	v.vectorBinOp(rd, rn, rm, arr, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// DUPGeneral broadcasts value across every lane of Vd ("DUP Vd.<T>, Rn").
+func (v *VALU) DUPGeneral(rd uint8, value uint64, arr Arrangement) {
+	size := laneSize(arr)
+	var out [16]byte
+	for i := 0; i < laneCount(arr); i++ {
+		writeLane(out[i*size:i*size+size], value)
+	}
+	v.rf.WriteV(rd, out)
+}
+
+// DUPElement broadcasts lane `lane` of Vn across every lane of Vd
+// ("DUP Vd.<T>, Vn.<T>[lane]").
+func (v *VALU) DUPElement(rd, rn uint8, arr Arrangement, lane uint8) {
+	size := laneSize(arr)
+	vn := v.rf.ReadV(rn)
+	off := int(lane) * size
+	v.DUPGeneral(rd, readLane(vn[off:off+size]), arr)
+}
+
+// INS writes value into lane `lane` of Vd, leaving every other lane of
+// Vd unchanged ("INS Vd.<T>[lane], Rn").
+func (v *VALU) INS(rd uint8, arr Arrangement, lane uint8, value uint64) {
+	size := laneSize(arr)
+	vd := v.rf.ReadV(rd)
+	off := int(lane) * size
+	writeLane(vd[off:off+size], value)
+	v.rf.WriteV(rd, vd)
+}
+
+// UMOV extracts lane `lane` of Vn as an unsigned integer
+// ("UMOV Rd, Vn.<T>[lane]").
+func (v *VALU) UMOV(rn uint8, arr Arrangement, lane uint8) uint64 {
+	size := laneSize(arr)
+	vn := v.rf.ReadV(rn)
+	off := int(lane) * size
+	return readLane(vn[off : off+size])
+}
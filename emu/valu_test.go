@@ -0,0 +1,254 @@
+package emu_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// quietNaN64 and signalingNaN64 give two bit-distinct double NaNs so tests
+// can tell apart propagation (which must keep NaN-ness) from exception
+// reporting (which must distinguish the two).
+const (
+	quietNaN64     = 0x7FF8000000000001
+	signalingNaN64 = 0x7FF0000000000001
+)
+
+var _ = Describe("VALU", func() {
+	var (
+		regFile *emu.RegFile
+		valu    *emu.VALU
+	)
+
+	BeforeEach(func() {
+		regFile = &emu.RegFile{}
+		valu = emu.NewVALU(regFile)
+	})
+
+	Describe("scalar arithmetic", func() {
+		It("should add two doubles", func() {
+			regFile.WriteD(1, math.Float64bits(1.5))
+			regFile.WriteD(2, math.Float64bits(2.25))
+
+			valu.FADD64(0, 1, 2)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(3.75))
+		})
+
+		It("should subtract two singles", func() {
+			regFile.WriteS(1, math.Float32bits(5))
+			regFile.WriteS(2, math.Float32bits(2))
+
+			valu.FSUB32(0, 1, 2)
+
+			Expect(math.Float32frombits(regFile.ReadS(0))).To(Equal(float32(3)))
+		})
+
+		It("should multiply two doubles", func() {
+			regFile.WriteD(1, math.Float64bits(3))
+			regFile.WriteD(2, math.Float64bits(4))
+
+			valu.FMUL64(0, 1, 2)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(12.0))
+		})
+
+		It("should divide two singles", func() {
+			regFile.WriteS(1, math.Float32bits(9))
+			regFile.WriteS(2, math.Float32bits(2))
+
+			valu.FDIV32(0, 1, 2)
+
+			Expect(math.Float32frombits(regFile.ReadS(0))).To(Equal(float32(4.5)))
+		})
+
+		It("should propagate NaN through arithmetic without raising IOC for a quiet NaN", func() {
+			regFile.WriteD(1, quietNaN64)
+			regFile.WriteD(2, math.Float64bits(1))
+
+			valu.FADD64(0, 1, 2)
+
+			Expect(math.IsNaN(math.Float64frombits(regFile.ReadD(0)))).To(BeTrue())
+			Expect(regFile.FPSR & emu.FPSRInvalidOp).To(BeZero())
+		})
+
+		It("should raise IOC when an operand is a signaling NaN", func() {
+			regFile.WriteD(1, signalingNaN64)
+			regFile.WriteD(2, math.Float64bits(1))
+
+			valu.FADD64(0, 1, 2)
+
+			Expect(regFile.FPSR & emu.FPSRInvalidOp).ToNot(BeZero())
+		})
+	})
+
+	Describe("FCMP", func() {
+		It("should treat +0.0 and -0.0 as equal", func() {
+			regFile.WriteD(1, math.Float64bits(0.0))
+			regFile.WriteD(2, math.Float64bits(math.Copysign(0, -1)))
+
+			valu.FCMP64(1, 2)
+
+			Expect(regFile.PSTATE).To(Equal(emu.PSTATE{N: false, Z: true, C: true, V: false}))
+		})
+
+		It("should set N for a less-than comparison", func() {
+			regFile.WriteD(1, math.Float64bits(1))
+			regFile.WriteD(2, math.Float64bits(2))
+
+			valu.FCMP64(1, 2)
+
+			Expect(regFile.PSTATE).To(Equal(emu.PSTATE{N: true, Z: false, C: false, V: false}))
+		})
+
+		It("should set C alone for a greater-than comparison", func() {
+			regFile.WriteD(1, math.Float64bits(2))
+			regFile.WriteD(2, math.Float64bits(1))
+
+			valu.FCMP64(1, 2)
+
+			Expect(regFile.PSTATE).To(Equal(emu.PSTATE{N: false, Z: false, C: true, V: false}))
+		})
+
+		It("should set C and V for an unordered (NaN) comparison without IOC for a quiet NaN", func() {
+			regFile.WriteD(1, quietNaN64)
+			regFile.WriteD(2, math.Float64bits(1))
+
+			valu.FCMP64(1, 2)
+
+			Expect(regFile.PSTATE).To(Equal(emu.PSTATE{N: false, Z: false, C: true, V: true}))
+			Expect(regFile.FPSR & emu.FPSRInvalidOp).To(BeZero())
+		})
+
+		It("should raise IOC for an unordered comparison against a signaling NaN", func() {
+			regFile.WriteD(1, signalingNaN64)
+			regFile.WriteD(2, math.Float64bits(1))
+
+			valu.FCMP64(1, 2)
+
+			Expect(regFile.FPSR & emu.FPSRInvalidOp).ToNot(BeZero())
+		})
+	})
+
+	Describe("FMOV", func() {
+		It("should move a register-form float between V registers", func() {
+			regFile.WriteD(1, math.Float64bits(7.5))
+
+			valu.FMOVD(0, 1)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(7.5))
+		})
+
+		It("should move raw bits between a GPR and a D register", func() {
+			valu.FMOVXToD(0, 0x4010000000000000) // 4.0 as a double
+
+			Expect(valu.FMOVDToX(0)).To(Equal(uint64(0x4010000000000000)))
+		})
+
+		It("should write an already-decoded immediate pattern", func() {
+			valu.FMOVImmS(0, math.Float32bits(1))
+
+			Expect(math.Float32frombits(regFile.ReadS(0))).To(Equal(float32(1)))
+		})
+	})
+
+	Describe("integer/float conversion", func() {
+		It("should convert a signed 64-bit integer to double", func() {
+			valu.SCVTF64(0, -3)
+
+			Expect(math.Float64frombits(regFile.ReadD(0))).To(Equal(-3.0))
+		})
+
+		It("should round a double toward zero", func() {
+			regFile.WriteD(0, math.Float64bits(-3.9))
+
+			Expect(valu.FCVTZS64(0)).To(Equal(int64(-3)))
+		})
+
+		It("should convert a NaN to zero", func() {
+			regFile.WriteD(0, quietNaN64)
+
+			Expect(valu.FCVTZS64(0)).To(Equal(int64(0)))
+		})
+
+		It("should saturate an out-of-range double", func() {
+			regFile.WriteD(0, math.Float64bits(1e30))
+
+			Expect(valu.FCVTZS64(0)).To(Equal(int64(math.MaxInt64)))
+		})
+	})
+
+	Describe("vector arithmetic", func() {
+		It("should add four 32-bit lanes", func() {
+			var a, b [16]byte
+			for i := 0; i < 4; i++ {
+				a[i*4] = byte(i + 1)
+				b[i*4] = byte(10)
+			}
+			regFile.WriteV(1, a)
+			regFile.WriteV(2, b)
+
+			valu.VADD(0, 1, 2, emu.Arrangement4S)
+
+			out := regFile.ReadV(0)
+			for i := 0; i < 4; i++ {
+				Expect(out[i*4]).To(Equal(byte(11 + i)))
+			}
+		})
+
+		It("should XOR 16 byte lanes", func() {
+			a := [16]byte{}
+			b := [16]byte{}
+			for i := range a {
+				a[i] = 0xFF
+				b[i] = 0x0F
+			}
+			regFile.WriteV(1, a)
+			regFile.WriteV(2, b)
+
+			valu.VEOR(0, 1, 2, emu.Arrangement16B)
+
+			out := regFile.ReadV(0)
+			for _, by := range out {
+				Expect(by).To(Equal(byte(0xF0)))
+			}
+		})
+	})
+
+	Describe("DUP/INS/UMOV", func() {
+		It("should broadcast a GPR value across 2D lanes", func() {
+			valu.DUPGeneral(0, 0xDEADBEEF, emu.Arrangement2D)
+
+			Expect(valu.UMOV(0, emu.Arrangement2D, 0)).To(Equal(uint64(0xDEADBEEF)))
+			Expect(valu.UMOV(0, emu.Arrangement2D, 1)).To(Equal(uint64(0xDEADBEEF)))
+		})
+
+		It("should broadcast one element across every 8H lane", func() {
+			var v [16]byte
+			v[2], v[3] = 0x34, 0x12 // lane 1 = 0x1234
+			regFile.WriteV(1, v)
+
+			valu.DUPElement(0, 1, emu.Arrangement8H, 1)
+
+			for lane := uint8(0); lane < 8; lane++ {
+				Expect(valu.UMOV(0, emu.Arrangement8H, lane)).To(Equal(uint64(0x1234)))
+			}
+		})
+
+		It("should insert into one lane without disturbing the others", func() {
+			var v [16]byte
+			for i := range v {
+				v[i] = 0xAA
+			}
+			regFile.WriteV(0, v)
+
+			valu.INS(0, emu.Arrangement4S, 2, 0x11223344)
+
+			Expect(valu.UMOV(0, emu.Arrangement4S, 2)).To(Equal(uint64(0x11223344)))
+			Expect(valu.UMOV(0, emu.Arrangement4S, 0)).To(Equal(uint64(0xAAAAAAAA)))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+package emu
+
+import "sort"
+
+// MmapRegion describes one virtual memory area DefaultSyscallHandler has
+// mapped: a page-aligned, non-overlapping range of the guest's address
+// space, together with the syscall state needed to back it (Fd/Offset
+// for a file-backed mapping, Prot/Flags as mmap(2) set them) and Dirty
+// for MAP_PRIVATE copy-on-write tracking.
+type MmapRegion struct {
+	Addr   uint64 // Start address (page-aligned)
+	Length uint64 // Length in bytes (a multiple of PageSize)
+	Prot   int    // Protection flags
+	Flags  int    // Mapping flags
+
+	Fd     int64  // backing file descriptor, or -1 for an anonymous mapping
+	Offset uint64 // byte offset into the backing file this region starts at
+
+	Dirty bool // true once a MAP_PRIVATE mapping has been written to
+}
+
+func (r *MmapRegion) end() uint64 { return r.Addr + r.Length }
+
+// vmaSet is a sorted, non-overlapping list of *MmapRegion ordered by
+// Addr, supporting the split/merge operations mmap/munmap/mprotect need.
+// A full interval tree would cost more to build and maintain than it
+// buys here: the access pattern is split-heavy rather than lookup-heavy,
+// VMA counts in this emulator's workloads are small, and a sorted slice
+// gives the same O(log n) lookup via binary search that a balanced tree
+// would.
+type vmaSet struct {
+	regions []*MmapRegion
+}
+
+// find returns the index of the first region whose end is > addr — the
+// only region that could contain addr, if any does, since regions are
+// sorted and non-overlapping.
+func (s *vmaSet) find(addr uint64) int {
+	return sort.Search(len(s.regions), func(i int) bool {
+		return s.regions[i].end() > addr
+	})
+}
+
+// lookup returns the region containing addr, or nil.
+func (s *vmaSet) lookup(addr uint64) *MmapRegion {
+	i := s.find(addr)
+	if i < len(s.regions) && s.regions[i].Addr <= addr {
+		return s.regions[i]
+	}
+	return nil
+}
+
+// insert adds region, which the caller guarantees does not overlap any
+// existing region, keeping regions sorted by Addr.
+func (s *vmaSet) insert(region *MmapRegion) {
+	i := sort.Search(len(s.regions), func(i int) bool {
+		return s.regions[i].Addr >= region.Addr
+	})
+	s.regions = append(s.regions, nil)
+	copy(s.regions[i+1:], s.regions[i:])
+	s.regions[i] = region
+}
+
+// removeRange deletes the portion of every region overlapping
+// [addr, addr+length), splitting a region that only partially overlaps
+// so the part outside the range survives — munmap(2)'s semantics.
+func (s *vmaSet) removeRange(addr, length uint64) {
+	end := addr + length
+	var kept []*MmapRegion
+
+	for _, r := range s.regions {
+		switch {
+		case r.end() <= addr || r.Addr >= end:
+			kept = append(kept, r) // entirely outside the range
+
+		case r.Addr >= addr && r.end() <= end:
+			// entirely inside the range: dropped
+
+		case r.Addr < addr && r.end() > end:
+			// the range is a hole in the middle of r: split into two
+			kept = append(kept,
+				cloneVMA(r, r.Addr, addr-r.Addr),
+				cloneVMA(r, end, r.end()-end))
+
+		case r.Addr < addr:
+			kept = append(kept, cloneVMA(r, r.Addr, addr-r.Addr)) // keep r's head
+
+		default:
+			kept = append(kept, cloneVMA(r, end, r.end()-end)) // keep r's tail
+		}
+	}
+
+	s.regions = kept
+}
+
+// setProt changes the protection of [addr, addr+length), splitting any
+// region that only partially overlaps so the part outside the range
+// keeps its old protection — mprotect(2)'s semantics.
+func (s *vmaSet) setProt(addr, length uint64, prot int) {
+	end := addr + length
+	var out []*MmapRegion
+
+	for _, r := range s.regions {
+		if r.end() <= addr || r.Addr >= end {
+			out = append(out, r)
+			continue
+		}
+
+		overlapStart := maxU64(r.Addr, addr)
+		overlapEnd := minU64(r.end(), end)
+
+		if r.Addr < overlapStart {
+			out = append(out, cloneVMA(r, r.Addr, overlapStart-r.Addr))
+		}
+
+		mid := cloneVMA(r, overlapStart, overlapEnd-overlapStart)
+		mid.Prot = prot
+		out = append(out, mid)
+
+		if r.end() > overlapEnd {
+			out = append(out, cloneVMA(r, overlapEnd, r.end()-overlapEnd))
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Addr < out[j].Addr })
+	s.regions = out
+}
+
+// findGap returns the lowest address at or above minAddr with length
+// free bytes not covered by any existing region, so handleMmap can reuse
+// space munmap has freed instead of only ever bumping a pointer forward
+// (which would leak address space over repeated map/unmap cycles).
+func (s *vmaSet) findGap(minAddr, length uint64) uint64 {
+	candidate := minAddr
+	for _, r := range s.regions {
+		if r.Addr < candidate+length && r.end() > candidate {
+			candidate = r.end()
+		}
+	}
+	return candidate
+}
+
+// cloneVMA copies r's metadata into a new region covering
+// [addr, addr+length), adjusting Offset for a file-backed mapping so it
+// still refers to the same bytes of the file.
+func cloneVMA(r *MmapRegion, addr, length uint64) *MmapRegion {
+	return &MmapRegion{
+		Addr:   addr,
+		Length: length,
+		Prot:   r.Prot,
+		Flags:  r.Flags,
+		Fd:     r.Fd,
+		Offset: r.Offset + (addr - r.Addr),
+		Dirty:  r.Dirty,
+	}
+}
+
+func maxU64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minU64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,95 @@
+package emutest_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emutest"
+)
+
+var _ = Describe("Run", func() {
+	It("should pass a hand-built ADD vector", func() {
+		v := emutest.Vector{
+			Name:   "ADD Xd, Xn, Xm",
+			Opcode: "0x8B020020", // ADD X0, X1, X2
+			Initial: emutest.State{
+				PC:   0x1000,
+				Regs: []uint64{0, 10, 20},
+			},
+			Final: emutest.State{
+				PC:   0x1004,
+				Regs: []uint64{30, 10, 20},
+			},
+		}
+
+		result := emutest.Run(v)
+
+		Expect(result.Diffs).To(BeEmpty())
+		Expect(result.Pass).To(BeTrue())
+	})
+
+	It("should report a diff when a register doesn't match", func() {
+		v := emutest.Vector{
+			Name:   "ADD Xd, Xn, Xm",
+			Opcode: "0x8B020020", // ADD X0, X1, X2
+			Initial: emutest.State{
+				PC:   0x1000,
+				Regs: []uint64{0, 10, 20},
+			},
+			Final: emutest.State{
+				PC:   0x1004,
+				Regs: []uint64{999, 10, 20},
+			},
+		}
+
+		result := emutest.Run(v)
+
+		Expect(result.Pass).To(BeFalse())
+		Expect(result.Diffs).To(ContainElement(HaveField("Field", "X0")))
+	})
+
+	It("should diff touched memory bytes", func() {
+		v := emutest.Vector{
+			Name:   "STR Xt, [Xn, #imm]",
+			Opcode: "0xF9000420", // STR X0, [X1, #8]
+			Initial: emutest.State{
+				PC:   0x1000,
+				Regs: []uint64{0xDEAD, 0x2000},
+			},
+			Final: emutest.State{
+				PC:   0x1004,
+				Regs: []uint64{0xDEAD, 0x2000},
+				Memory: []emutest.MemByte{
+					{Addr: 0x2008, Byte: 0xAD},
+					{Addr: 0x2009, Byte: 0xDE},
+				},
+			},
+		}
+
+		result := emutest.Run(v)
+
+		Expect(result.Pass).To(BeTrue())
+	})
+})
+
+var _ = Describe("GenerateVectors", func() {
+	It("should regression-test every generated mnemonic without failures", func() {
+		byMnemonic := emutest.GenerateVectors()
+
+		var vectors []emutest.Vector
+		for _, vs := range byMnemonic {
+			vectors = append(vectors, vs...)
+		}
+
+		results := emutest.RunAll(vectors)
+		passed, total := emutest.Summarize(results)
+
+		for _, r := range results {
+			if !r.Pass {
+				GinkgoWriter.Printf("FAIL %s: %+v\n", r.Name, r.Diffs)
+			}
+		}
+
+		Expect(passed).To(Equal(total))
+	})
+})
@@ -0,0 +1,501 @@
+package emutest
+
+import "fmt"
+
+// GenerateVectors emits a small regression suite covering the integer
+// data-processing, move-wide, load/store, and branch instructions ALU,
+// LoadStoreUnit, and BranchUnit currently implement, keyed by mnemonic so
+// callers can write one file per key (see WriteVectorFile). It computes
+// each vector's expected "final" state directly from ARM64 semantics
+// rather than by running the emulator, so the vectors exercise the
+// decoder's bit-level encoding in addition to the execution units' own
+// arithmetic. Extending coverage to a new mnemonic is mechanical: add an
+// encoder following the existing ones below and a generator function
+// that calls it.
+func GenerateVectors() map[string][]Vector {
+	return map[string][]Vector{
+		"add_reg_64": {addReg64Vector()},
+		"sub_reg_64": {subReg64Vector()},
+		"and_reg_64": {andReg64Vector()},
+		"orr_reg_64": {orrReg64Vector()},
+		"eor_reg_64": {eorReg64Vector()},
+		"add_imm_64": {addImm64Vector()},
+		"movz_64":    {movz64Vector()},
+		"ldr_imm_64": {ldrImm64Vector()},
+		"str_imm_64": {strImm64Vector()},
+		"b_uncond":   {bVector()},
+		"cbz_64":     {cbz64Vector()},
+		"tbz_64":     {tbz64Vector()},
+		"csel_64":    {csel64Vector()},
+		"madd_64":    {madd64Vector()},
+		"udiv_64":    {udiv64Vector()},
+		"ldp_64":     {ldp64Vector()},
+		"stp_64":     {stp64Vector()},
+		"ldrb_imm":   {ldrbImmVector()},
+		"strb_imm":   {strbImmVector()},
+		"ldrh_imm":   {ldrhImmVector()},
+		"ldrsw_imm":  {ldrswImmVector()},
+		"and_imm_64": {andImm64Vector()},
+	}
+}
+
+// regsWith returns a copy of base with index idx set to val, growing the
+// slice with zeros if idx is beyond its current length.
+func regsWith(base []uint64, idx uint8, val uint64) []uint64 {
+	out := make([]uint64, len(base))
+	copy(out, base)
+	for len(out) <= int(idx) {
+		out = append(out, 0)
+	}
+	out[idx] = val
+	return out
+}
+
+// encodeAddSubShiftedReg encodes ADD/SUB (shifted register, no shift),
+// 64-bit: sf=1 op S 01011 shift(00) 0 Rm imm6(0) Rn Rd.
+func encodeAddSubShiftedReg(op uint32, rd, rn, rm uint8) uint32 {
+	const base = uint32(0x0B000000) | (1 << 31) // sf=1
+	return base | (op << 30) | (uint32(rm) << 16) | (uint32(rn) << 5) | uint32(rd)
+}
+
+func encodeADD64(rd, rn, rm uint8) uint32 { return encodeAddSubShiftedReg(0, rd, rn, rm) }
+func encodeSUB64(rd, rn, rm uint8) uint32 { return encodeAddSubShiftedReg(1, rd, rn, rm) }
+
+// encodeLogicalShiftedReg encodes AND/ORR/EOR (shifted register, no
+// shift), 64-bit: sf=1 opc 01010 shift(00) N=0 Rm imm6(0) Rn Rd.
+func encodeLogicalShiftedReg(opc uint32, rd, rn, rm uint8) uint32 {
+	const base = uint32(0x0A000000) | (1 << 31) // sf=1
+	return base | (opc << 29) | (uint32(rm) << 16) | (uint32(rn) << 5) | uint32(rd)
+}
+
+func encodeAND64(rd, rn, rm uint8) uint32 { return encodeLogicalShiftedReg(0, rd, rn, rm) }
+func encodeORR64(rd, rn, rm uint8) uint32 { return encodeLogicalShiftedReg(1, rd, rn, rm) }
+func encodeEOR64(rd, rn, rm uint8) uint32 { return encodeLogicalShiftedReg(2, rd, rn, rm) }
+
+// encodeADD64Imm encodes ADD (immediate, no shift), 64-bit:
+// sf=1 op=0 S=0 100010 sh=0 imm12 Rn Rd.
+func encodeADD64Imm(rd, rn uint8, imm12 uint16) uint32 {
+	return 0x91000000 | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeMOVZ64 encodes MOVZ, 64-bit, hw=0: sf=1 10 100101 hw(00) imm16 Rd.
+func encodeMOVZ64(rd uint8, imm16 uint16) uint32 {
+	return 0xD2800000 | (uint32(imm16) << 5) | uint32(rd)
+}
+
+// encodeLoadStoreImm64 encodes LDR/STR (immediate, unsigned offset),
+// 64-bit: size=11 111 0 01 opc imm12 Rn Rt. imm12 is measured in 8-byte
+// units, matching the real instruction's scaled offset.
+func encodeLoadStoreImm64(opcBase uint32, rt, rn uint8, imm12 uint16) uint32 {
+	return opcBase | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rt)
+}
+
+func encodeLDR64Imm(rt, rn uint8, imm12 uint16) uint32 {
+	return encodeLoadStoreImm64(0xF9400000, rt, rn, imm12)
+}
+func encodeSTR64Imm(rt, rn uint8, imm12 uint16) uint32 {
+	return encodeLoadStoreImm64(0xF9000000, rt, rn, imm12)
+}
+
+// encodeB encodes an unconditional branch (B): op=0 00101 imm26. offset
+// is the byte displacement from the instruction to the target; it must
+// be 4-byte aligned.
+func encodeB(offset int64) uint32 {
+	imm26 := uint32((offset / 4)) & 0x3FFFFFF
+	return 0x14000000 | imm26
+}
+
+// encodeCBZ64 encodes CBZ, 64-bit: sf=1 011010 0 imm19 Rt.
+func encodeCBZ64(rt uint8, imm19 int32) uint32 {
+	return 0xB4000000 | ((uint32(imm19) & 0x7FFFF) << 5) | uint32(rt)
+}
+
+// encodeTBZ encodes TBZ for b5=0 (bit position 0-31): b5(0) 011011 0 b40(5) imm14 Rt.
+func encodeTBZ(rt uint8, bit uint8, imm14 int32) uint32 {
+	return 0x36000000 | (uint32(bit&0x1F) << 19) | ((uint32(imm14) & 0x3FFF) << 5) | uint32(rt)
+}
+
+// encodeCSEL64 encodes CSEL Xd, Xn, Xm, cond, 64-bit.
+func encodeCSEL64(rd, rn, rm, cond uint8) uint32 {
+	return 0x9A800000 | (uint32(rm) << 16) | (uint32(cond) << 12) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeMADD64 encodes MADD Xd, Xn, Xm, Xa, 64-bit (Xd = Xn*Xm + Xa). MUL is
+// this instruction's Ra=XZR alias, so this same encoder covers it.
+func encodeMADD64(rd, rn, rm, ra uint8) uint32 {
+	return 0x9B000000 | (uint32(rm) << 16) | (uint32(ra) << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeUDIV64 encodes UDIV Xd, Xn, Xm, 64-bit.
+func encodeUDIV64(rd, rn, rm uint8) uint32 {
+	return 0x9AC00000 | (uint32(rm) << 16) | (0b000010 << 10) | (uint32(rn) << 5) | uint32(rd)
+}
+
+// encodeLDP64 encodes LDP Xt1, Xt2, [Xn, #imm] (signed offset, no
+// writeback), 64-bit. imm7 is measured in 8-byte units.
+func encodeLDP64(rt1, rt2, rn uint8, imm7 int32) uint32 {
+	return 0xA9400000 | ((uint32(imm7) & 0x7F) << 15) | (uint32(rt2) << 10) | (uint32(rn) << 5) | uint32(rt1)
+}
+
+// encodeSTP64 encodes STP Xt1, Xt2, [Xn, #imm] (signed offset, no
+// writeback), 64-bit. imm7 is measured in 8-byte units.
+func encodeSTP64(rt1, rt2, rn uint8, imm7 int32) uint32 {
+	return 0xA9000000 | ((uint32(imm7) & 0x7F) << 15) | (uint32(rt2) << 10) | (uint32(rn) << 5) | uint32(rt1)
+}
+
+// encodeLDRB encodes LDRB Wt, [Xn, #imm] (unsigned offset, unscaled
+// byte count, zero extension).
+func encodeLDRB(rt, rn uint8, imm12 uint16) uint32 {
+	return 0x39400000 | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rt)
+}
+
+// encodeSTRB encodes STRB Wt, [Xn, #imm] (unsigned offset, unscaled
+// byte count).
+func encodeSTRB(rt, rn uint8, imm12 uint16) uint32 {
+	return 0x39000000 | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rt)
+}
+
+// encodeLDRH encodes LDRH Wt, [Xn, #imm] (unsigned offset, imm12 measured
+// in 2-byte units, zero extension).
+func encodeLDRH(rt, rn uint8, imm12 uint16) uint32 {
+	return 0x79400000 | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rt)
+}
+
+// encodeLDRSW encodes LDRSW Xt, [Xn, #imm] (unsigned offset, imm12
+// measured in 4-byte units, sign extension to 64 bits).
+func encodeLDRSW(rt, rn uint8, imm12 uint16) uint32 {
+	return 0xB9800000 | (uint32(imm12&0xFFF) << 10) | (uint32(rn) << 5) | uint32(rt)
+}
+
+// encodeANDImm64 encodes AND Xd, Xn, #0xFF, 64-bit. The bitmask-immediate
+// field (N=1, immr=0, imms=7) is the one ARM64 encoding that isolates the
+// low 8 bits, chosen here so the vector doesn't need the general bitmask
+// replication algorithm just to cover one logical-immediate case.
+func encodeANDImm64(rd, rn uint8) uint32 {
+	return 0x92401C00 | (uint32(rn) << 5) | uint32(rd)
+}
+
+func opcodeHex(v uint32) string {
+	return fmt.Sprintf("0x%08X", v)
+}
+
+func addReg64Vector() Vector {
+	var rn, rm, rd uint8 = 1, 2, 0
+	a, b := uint64(10), uint64(20)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "ADD Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeADD64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a+b)},
+	}
+}
+
+func subReg64Vector() Vector {
+	var rn, rm, rd uint8 = 1, 2, 0
+	a, b := uint64(30), uint64(12)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "SUB Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeSUB64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a-b)},
+	}
+}
+
+func andReg64Vector() Vector {
+	var rn, rm, rd uint8 = 1, 2, 0
+	a, b := uint64(0xFF00), uint64(0x0FF0)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "AND Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeAND64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a&b)},
+	}
+}
+
+func orrReg64Vector() Vector {
+	var rn, rm, rd uint8 = 1, 2, 0
+	a, b := uint64(0xFF00), uint64(0x0FF0)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "ORR Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeORR64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a|b)},
+	}
+}
+
+func eorReg64Vector() Vector {
+	var rn, rm, rd uint8 = 1, 2, 0
+	a, b := uint64(0xFF00), uint64(0x0FF0)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "EOR Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeEOR64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a^b)},
+	}
+}
+
+func addImm64Vector() Vector {
+	var rn, rd uint8 = 1, 0
+	a := uint64(100)
+	const imm = uint16(42)
+	initRegs := regsWith(nil, rn, a)
+	return Vector{
+		Name:    "ADD Xd, Xn, #imm",
+		Opcode:  opcodeHex(encodeADD64Imm(rd, rn, imm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a+uint64(imm))},
+	}
+}
+
+func movz64Vector() Vector {
+	var rd uint8 = 3
+	const imm = uint16(0xBEEF)
+	return Vector{
+		Name:    "MOVZ Xd, #imm",
+		Opcode:  opcodeHex(encodeMOVZ64(rd, imm)),
+		Initial: State{PC: 0x1000, Regs: nil},
+		Final:   State{PC: 0x1004, Regs: regsWith(nil, rd, uint64(imm))},
+	}
+}
+
+func ldrImm64Vector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(2) // offset = 2*8 = 16 bytes
+	base := uint64(0x5000)
+	addr := base + 16
+	var want uint64 = 0x1122334455667788
+	initRegs := regsWith(nil, rn, base)
+	return Vector{
+		Name:   "LDR Xt, [Xn, #imm]",
+		Opcode: opcodeHex(encodeLDR64Imm(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0x88}, {Addr: addr + 1, Byte: 0x77},
+			{Addr: addr + 2, Byte: 0x66}, {Addr: addr + 3, Byte: 0x55},
+			{Addr: addr + 4, Byte: 0x44}, {Addr: addr + 5, Byte: 0x33},
+			{Addr: addr + 6, Byte: 0x22}, {Addr: addr + 7, Byte: 0x11},
+		}},
+		Final: State{PC: 0x1004, Regs: regsWith(initRegs, rt, want)},
+	}
+}
+
+func strImm64Vector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(2) // offset = 2*8 = 16 bytes
+	base := uint64(0x5000)
+	addr := base + 16
+	var value uint64 = 0x1122334455667788
+	initRegs := regsWith(regsWith(nil, rn, base), rt, value)
+	return Vector{
+		Name:    "STR Xt, [Xn, #imm]",
+		Opcode:  opcodeHex(encodeSTR64Imm(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final: State{PC: 0x1004, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0x88}, {Addr: addr + 1, Byte: 0x77},
+			{Addr: addr + 2, Byte: 0x66}, {Addr: addr + 3, Byte: 0x55},
+			{Addr: addr + 4, Byte: 0x44}, {Addr: addr + 5, Byte: 0x33},
+			{Addr: addr + 6, Byte: 0x22}, {Addr: addr + 7, Byte: 0x11},
+		}},
+	}
+}
+
+func bVector() Vector {
+	const offset = int64(0x20)
+	return Vector{
+		Name:    "B #offset",
+		Opcode:  opcodeHex(encodeB(offset)),
+		Initial: State{PC: 0x1000},
+		Final:   State{PC: uint64(0x1000 + offset)},
+	}
+}
+
+func cbz64Vector() Vector {
+	var rt uint8 = 0
+	const imm19 = int32(3) // offset = 3*4 = 12
+	initRegs := regsWith(nil, rt, 0)
+	return Vector{
+		Name:    "CBZ Xt, #offset",
+		Opcode:  opcodeHex(encodeCBZ64(rt, imm19)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1000 + 12, Regs: initRegs},
+	}
+}
+
+func tbz64Vector() Vector {
+	var rt uint8 = 0
+	const bit = uint8(0)
+	const imm14 = int32(4) // offset = 4*4 = 16
+	initRegs := regsWith(nil, rt, 0b10)
+	return Vector{
+		Name:    "TBZ Xt, #0, #offset",
+		Opcode:  opcodeHex(encodeTBZ(rt, bit, imm14)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1000 + 16, Regs: initRegs},
+	}
+}
+
+func csel64Vector() Vector {
+	var rd, rn, rm uint8 = 0, 1, 2
+	const condAL = uint8(0b1110)
+	a, b := uint64(11), uint64(22)
+	initRegs := regsWith(regsWith(nil, rn, a), rm, b)
+	return Vector{
+		Name:    "CSEL Xd, Xn, Xm, AL",
+		Opcode:  opcodeHex(encodeCSEL64(rd, rn, rm, condAL)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a)},
+	}
+}
+
+func madd64Vector() Vector {
+	var rd, rn, rm, ra uint8 = 0, 1, 2, 3
+	n, m, a := uint64(6), uint64(7), uint64(2)
+	initRegs := regsWith(regsWith(regsWith(nil, rn, n), rm, m), ra, a)
+	return Vector{
+		Name:    "MADD Xd, Xn, Xm, Xa",
+		Opcode:  opcodeHex(encodeMADD64(rd, rn, rm, ra)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, n*m+a)},
+	}
+}
+
+func udiv64Vector() Vector {
+	var rd, rn, rm uint8 = 0, 1, 2
+	n, m := uint64(17), uint64(5)
+	initRegs := regsWith(regsWith(nil, rn, n), rm, m)
+	return Vector{
+		Name:    "UDIV Xd, Xn, Xm",
+		Opcode:  opcodeHex(encodeUDIV64(rd, rn, rm)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, n/m)},
+	}
+}
+
+func ldp64Vector() Vector {
+	var rt1, rt2, rn uint8 = 0, 1, 2
+	const imm7 = int32(2) // offset = 2*8 = 16 bytes
+	base := uint64(0x5000)
+	addr := base + 16
+	var v1, v2 uint64 = 0x1111111111111111, 0x2222222222222222
+	initRegs := regsWith(nil, rn, base)
+	return Vector{
+		Name:   "LDP Xt1, Xt2, [Xn, #imm]",
+		Opcode: opcodeHex(encodeLDP64(rt1, rt2, rn, imm7)),
+		Initial: State{PC: 0x1000, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0x11}, {Addr: addr + 1, Byte: 0x11},
+			{Addr: addr + 2, Byte: 0x11}, {Addr: addr + 3, Byte: 0x11},
+			{Addr: addr + 4, Byte: 0x11}, {Addr: addr + 5, Byte: 0x11},
+			{Addr: addr + 6, Byte: 0x11}, {Addr: addr + 7, Byte: 0x11},
+			{Addr: addr + 8, Byte: 0x22}, {Addr: addr + 9, Byte: 0x22},
+			{Addr: addr + 10, Byte: 0x22}, {Addr: addr + 11, Byte: 0x22},
+			{Addr: addr + 12, Byte: 0x22}, {Addr: addr + 13, Byte: 0x22},
+			{Addr: addr + 14, Byte: 0x22}, {Addr: addr + 15, Byte: 0x22},
+		}},
+		Final: State{PC: 0x1004, Regs: regsWith(regsWith(initRegs, rt1, v1), rt2, v2)},
+	}
+}
+
+func stp64Vector() Vector {
+	var rt1, rt2, rn uint8 = 0, 1, 2
+	const imm7 = int32(2) // offset = 2*8 = 16 bytes
+	base := uint64(0x5000)
+	addr := base + 16
+	var v1, v2 uint64 = 0x1111111111111111, 0x2222222222222222
+	initRegs := regsWith(regsWith(regsWith(nil, rn, base), rt1, v1), rt2, v2)
+	return Vector{
+		Name:    "STP Xt1, Xt2, [Xn, #imm]",
+		Opcode:  opcodeHex(encodeSTP64(rt1, rt2, rn, imm7)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final: State{PC: 0x1004, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0x11}, {Addr: addr + 1, Byte: 0x11},
+			{Addr: addr + 2, Byte: 0x11}, {Addr: addr + 3, Byte: 0x11},
+			{Addr: addr + 4, Byte: 0x11}, {Addr: addr + 5, Byte: 0x11},
+			{Addr: addr + 6, Byte: 0x11}, {Addr: addr + 7, Byte: 0x11},
+			{Addr: addr + 8, Byte: 0x22}, {Addr: addr + 9, Byte: 0x22},
+			{Addr: addr + 10, Byte: 0x22}, {Addr: addr + 11, Byte: 0x22},
+			{Addr: addr + 12, Byte: 0x22}, {Addr: addr + 13, Byte: 0x22},
+			{Addr: addr + 14, Byte: 0x22}, {Addr: addr + 15, Byte: 0x22},
+		}},
+	}
+}
+
+func ldrbImmVector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(5)
+	base := uint64(0x5000)
+	addr := base + 5
+	initRegs := regsWith(nil, rn, base)
+	return Vector{
+		Name:   "LDRB Wt, [Xn, #imm]",
+		Opcode: opcodeHex(encodeLDRB(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr, Byte: 0xAB},
+		}},
+		Final: State{PC: 0x1004, Regs: regsWith(initRegs, rt, 0xAB)},
+	}
+}
+
+func strbImmVector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(5)
+	base := uint64(0x5000)
+	addr := base + 5
+	initRegs := regsWith(regsWith(nil, rn, base), rt, 0xAB)
+	return Vector{
+		Name:    "STRB Wt, [Xn, #imm]",
+		Opcode:  opcodeHex(encodeSTRB(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final: State{PC: 0x1004, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr, Byte: 0xAB},
+		}},
+	}
+}
+
+func ldrhImmVector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(3) // offset = 3*2 = 6 bytes
+	base := uint64(0x5000)
+	addr := base + 6
+	initRegs := regsWith(nil, rn, base)
+	return Vector{
+		Name:   "LDRH Wt, [Xn, #imm]",
+		Opcode: opcodeHex(encodeLDRH(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0xCD}, {Addr: addr + 1, Byte: 0xAB},
+		}},
+		Final: State{PC: 0x1004, Regs: regsWith(initRegs, rt, 0xABCD)},
+	}
+}
+
+func ldrswImmVector() Vector {
+	var rt, rn uint8 = 0, 1
+	const imm12 = uint16(1) // offset = 1*4 = 4 bytes
+	base := uint64(0x5000)
+	addr := base + 4
+	initRegs := regsWith(nil, rn, base)
+	return Vector{
+		Name:   "LDRSW Xt, [Xn, #imm]",
+		Opcode: opcodeHex(encodeLDRSW(rt, rn, imm12)),
+		Initial: State{PC: 0x1000, Regs: initRegs, Memory: []MemByte{
+			{Addr: addr + 0, Byte: 0xFF}, {Addr: addr + 1, Byte: 0xFF},
+			{Addr: addr + 2, Byte: 0xFF}, {Addr: addr + 3, Byte: 0xFF},
+		}},
+		Final: State{PC: 0x1004, Regs: regsWith(initRegs, rt, 0xFFFFFFFFFFFFFFFF)},
+	}
+}
+
+func andImm64Vector() Vector {
+	var rd, rn uint8 = 0, 1
+	a := uint64(0x1234)
+	initRegs := regsWith(nil, rn, a)
+	return Vector{
+		Name:    "AND Xd, Xn, #0xFF",
+		Opcode:  opcodeHex(encodeANDImm64(rd, rn)),
+		Initial: State{PC: 0x1000, Regs: initRegs},
+		Final:   State{PC: 0x1004, Regs: regsWith(initRegs, rd, a&0xFF)},
+	}
+}
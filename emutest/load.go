@@ -0,0 +1,51 @@
+package emutest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadVectors reads every *.json file in dir (one file per mnemonic, each
+// holding a JSON array of Vector) and returns their concatenation.
+func LoadVectors(dir string) ([]Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("emutest: reading vector directory %s: %w", dir, err)
+	}
+
+	var vectors []Vector
+	for _, ent := range entries {
+		if ent.IsDir() || !strings.HasSuffix(ent.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, ent.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("emutest: reading %s: %w", path, err)
+		}
+
+		var fileVectors []Vector
+		if err := json.Unmarshal(data, &fileVectors); err != nil {
+			return nil, fmt.Errorf("emutest: parsing %s: %w", path, err)
+		}
+		vectors = append(vectors, fileVectors...)
+	}
+	return vectors, nil
+}
+
+// WriteVectorFile writes vectors to path as a JSON array, indented for
+// readability, the format LoadVectors expects to find one of per mnemonic.
+func WriteVectorFile(path string, vectors []Vector) error {
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("emutest: encoding %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("emutest: writing %s: %w", path, err)
+	}
+	return nil
+}
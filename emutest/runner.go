@@ -0,0 +1,172 @@
+package emutest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Diff describes a single field that did not match between a Step's
+// actual result and a vector's expected "final" state.
+type Diff struct {
+	Field    string
+	Expected string
+	Got      string
+}
+
+// Result is the outcome of running one Vector.
+type Result struct {
+	Name  string
+	Pass  bool
+	Diffs []Diff
+}
+
+// Run builds a fresh Emulator, seeds it from v.Initial, places v.Opcode at
+// v.Initial.PC, executes exactly one Step, and diffs the resulting state
+// against v.Final.
+func Run(v Vector) Result {
+	opcode, err := parseOpcode(v.Opcode)
+	if err != nil {
+		return Result{Name: v.Name, Diffs: []Diff{
+			{Field: "opcode", Expected: v.Opcode, Got: err.Error()},
+		}}
+	}
+
+	e := emu.NewEmulator()
+	rf := e.RegFile()
+	mem := e.Memory()
+
+	seedMemory(mem, v.Initial.PC, opcode, v.Initial.Memory)
+	seedRegs(rf, v.Initial)
+
+	if res := e.Step(); res.Err != nil {
+		return Result{Name: v.Name, Diffs: []Diff{
+			{Field: "step", Expected: "no error", Got: res.Err.Error()},
+		}}
+	}
+
+	diffs := diffState(rf, mem, v.Final)
+	return Result{Name: v.Name, Pass: len(diffs) == 0, Diffs: diffs}
+}
+
+// RunAll runs every vector and returns one Result per vector, in order.
+func RunAll(vectors []Vector) []Result {
+	results := make([]Result, len(vectors))
+	for i, v := range vectors {
+		results[i] = Run(v)
+	}
+	return results
+}
+
+// Summarize counts how many of results passed.
+func Summarize(results []Result) (passed, total int) {
+	total = len(results)
+	for _, r := range results {
+		if r.Pass {
+			passed++
+		}
+	}
+	return passed, total
+}
+
+// parseOpcode decodes a "0x..."-prefixed (or bare) hex opcode string into
+// its 32-bit value.
+func parseOpcode(s string) (uint32, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("emutest: invalid opcode %q: %w", s, err)
+	}
+	return uint32(v), nil
+}
+
+// seedMemory maps every page the vector touches (the one containing pc
+// and every byte in initial) and writes the opcode and initial bytes into
+// them. Pages are mapped before anything is written, since mapping the
+// same page twice would wipe out the earlier write.
+func seedMemory(mem *emu.Memory, pc uint64, opcode uint32, initial []MemByte) {
+	pages := map[uint64]bool{pc &^ (emu.PageSize - 1): true}
+	for _, mb := range initial {
+		pages[mb.Addr&^(emu.PageSize-1)] = true
+	}
+	for page := range pages {
+		mem.MapAnonymous(page, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE|emu.PROT_EXEC)
+	}
+
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], opcode)
+	for i, b := range buf {
+		mem.Write8(pc+uint64(i), b)
+	}
+	for _, mb := range initial {
+		mem.Write8(mb.Addr, mb.Byte)
+	}
+}
+
+// seedRegs writes a State's registers, SP, PC, and flags into rf.
+func seedRegs(rf *emu.RegFile, s State) {
+	for i, v := range s.Regs {
+		switch {
+		case i < 31:
+			rf.WriteReg(uint8(i), v)
+		case i == 31:
+			rf.SP = v
+		}
+	}
+	rf.PC = s.PC
+	rf.PSTATE = emu.PSTATE{N: s.Flags.N, Z: s.Flags.Z, C: s.Flags.C, V: s.Flags.V}
+}
+
+// diffState compares rf/mem's current state against final, reporting one
+// Diff per field that does not match. Only registers and memory bytes
+// final explicitly lists are checked, so a vector only needs to mention
+// the state an instruction is expected to touch.
+func diffState(rf *emu.RegFile, mem *emu.Memory, final State) []Diff {
+	var diffs []Diff
+
+	for i, want := range final.Regs {
+		var got uint64
+		switch {
+		case i < 31:
+			got = rf.ReadReg(uint8(i))
+		case i == 31:
+			got = rf.SP
+		default:
+			continue
+		}
+		if got != want {
+			diffs = append(diffs, Diff{
+				Field:    fmt.Sprintf("X%d", i),
+				Expected: fmt.Sprintf("0x%X", want),
+				Got:      fmt.Sprintf("0x%X", got),
+			})
+		}
+	}
+
+	if rf.PC != final.PC {
+		diffs = append(diffs, Diff{Field: "PC", Expected: fmt.Sprintf("0x%X", final.PC), Got: fmt.Sprintf("0x%X", rf.PC)})
+	}
+
+	if rf.PSTATE != (emu.PSTATE{N: final.Flags.N, Z: final.Flags.Z, C: final.Flags.C, V: final.Flags.V}) {
+		diffs = append(diffs, Diff{
+			Field:    "NZCV",
+			Expected: fmt.Sprintf("%+v", final.Flags),
+			Got:      fmt.Sprintf("%+v", rf.PSTATE),
+		})
+	}
+
+	for _, mb := range final.Memory {
+		got := mem.Read8(mb.Addr)
+		if got != mb.Byte {
+			diffs = append(diffs, Diff{
+				Field:    fmt.Sprintf("mem[0x%X]", mb.Addr),
+				Expected: fmt.Sprintf("0x%02X", mb.Byte),
+				Got:      fmt.Sprintf("0x%02X", got),
+			})
+		}
+	}
+
+	return diffs
+}
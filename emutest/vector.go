@@ -0,0 +1,64 @@
+// Package emutest implements Harte-style per-instruction JSON test
+// vectors for validating emu's decoder and execution units: each vector
+// seeds an Emulator's register file and memory, executes exactly one
+// Step, and diffs the resulting architectural state against an expected
+// "final" snapshot.
+package emutest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Flags holds the four NZCV condition flags a vector seeds or checks.
+type Flags struct {
+	N bool `json:"N"`
+	Z bool `json:"Z"`
+	C bool `json:"C"`
+	V bool `json:"V"`
+}
+
+// MemByte is one (address, value) entry of a vector's memory list.
+type MemByte struct {
+	Addr uint64
+	Byte byte
+}
+
+// UnmarshalJSON decodes a MemByte from its wire form, a 2-element array
+// of [addr, byte].
+func (m *MemByte) UnmarshalJSON(data []byte) error {
+	var pair [2]uint64
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return fmt.Errorf("emutest: decoding memory entry: %w", err)
+	}
+	m.Addr = pair[0]
+	m.Byte = byte(pair[1])
+	return nil
+}
+
+// MarshalJSON encodes a MemByte back to its wire form.
+func (m MemByte) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]uint64{m.Addr, uint64(m.Byte)})
+}
+
+// State is one side (initial or final) of a test vector: the register
+// file, NZCV flags, and a sparse list of memory bytes. Regs is indexed by
+// register number, with index 31 holding SP rather than XZR (XZR never
+// needs seeding or checking, since reads/writes to it are already
+// discarded by RegFile).
+type State struct {
+	PC     uint64    `json:"pc"`
+	Regs   []uint64  `json:"regs"`
+	Flags  Flags     `json:"flags"`
+	Memory []MemByte `json:"memory"`
+}
+
+// Vector is a single Harte-style test case: seed `initial`, execute the
+// 4-byte instruction `opcode` (a "0x..."-prefixed hex string) placed at
+// initial.PC, and expect the architectural state to match `final`.
+type Vector struct {
+	Name    string `json:"name"`
+	Opcode  string `json:"opcode"`
+	Initial State  `json:"initial"`
+	Final   State  `json:"final"`
+}
@@ -0,0 +1,55 @@
+// Package loader turns a statically-linked AArch64 Linux ELF binary into
+// an *emu.Emulator ready to run from _start: PT_LOAD segments mapped,
+// PC set to e_entry, and SP pointing at a System V AArch64 process-start
+// stack image (argv, envp, auxv).
+//
+// The segment-mapping and stack-building logic itself lives in
+// driver.LoadELF, which this package calls rather than duplicating a
+// third time — emu.LoadELF already has its own copy for callers that
+// only have the emu package available (emu cannot import driver, which
+// imports emu), but loader has no such constraint.
+package loader
+
+import (
+	"os"
+
+	"github.com/sarchlab/m2sim/driver"
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// Load reads the ELF binary at path and returns an emulator positioned
+// at its entry point, with argv and envp laid out on the stack per the
+// AArch64 Linux calling convention. The emulated CPU's type is
+// *emu.Emulator; this package has no separate CPU type of its own.
+func Load(path string, argv, envp []string) (*emu.Emulator, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadBytes(raw, argv, envp)
+}
+
+// LoadBytes is Load, given the ELF file's contents directly rather than
+// a path.
+//
+// The returned emulator uses driver.SyscallHandler rather than
+// emu.DefaultSyscallHandler, since a real binary's libc startup code
+// expects the fuller Linux syscall surface that package implements.
+func LoadBytes(raw []byte, argv, envp []string) (*emu.Emulator, error) {
+	e := emu.NewEmulator()
+
+	loaded, err := driver.LoadELF(e.Memory(), raw, argv, envp)
+	if err != nil {
+		return nil, err
+	}
+
+	// WithSyscallHandler is just an EmulatorOption (func(*Emulator)), so it
+	// can be applied after construction too, once e.RegFile()/e.Memory()
+	// exist to build the handler from.
+	emu.WithSyscallHandler(driver.NewSyscallHandler(e.RegFile(), e.Memory()))(e)
+
+	e.RegFile().PC = loaded.Entry
+	e.RegFile().SP = loaded.StackPointer
+
+	return e, nil
+}
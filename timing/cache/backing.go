@@ -5,19 +5,35 @@ import (
 	"github.com/sarchlab/m2sim/emu"
 )
 
-// MemoryBacking wraps emu.Memory as a BackingStore.
+// MemoryBacking wraps an emu.MemAccessor as a BackingStore. Taking the
+// interface rather than a concrete *emu.Memory means the same adapter
+// works unchanged whether it's backed by Memory's Go-heap page map or by
+// an emu.MmapMemory for large workloads.
 type MemoryBacking struct {
-	memory *emu.Memory
+	memory emu.MemAccessor
 }
 
 // NewMemoryBacking creates a new MemoryBacking adapter.
-func NewMemoryBacking(memory *emu.Memory) *MemoryBacking {
+func NewMemoryBacking(memory emu.MemAccessor) *MemoryBacking {
 	return &MemoryBacking{memory: memory}
 }
 
+// bulkMemAccessor is an optional capability of an emu.MemAccessor: both
+// emu.Memory and emu.MmapMemory implement it with wide aligned accesses
+// instead of a byte loop, which matters on CacheBacking's hot path of
+// refilling a whole cache line at once.
+type bulkMemAccessor interface {
+	ReadBytes(addr uint64, buf []byte)
+	WriteBytes(addr uint64, buf []byte)
+}
+
 // Read fetches data from the backing memory.
 func (m *MemoryBacking) Read(addr uint64, size int) []byte {
 	data := make([]byte, size)
+	if bulk, ok := m.memory.(bulkMemAccessor); ok {
+		bulk.ReadBytes(addr, data)
+		return data
+	}
 	for i := 0; i < size; i++ {
 		data[i] = m.memory.Read8(addr + uint64(i))
 	}
@@ -26,6 +42,10 @@ func (m *MemoryBacking) Read(addr uint64, size int) []byte {
 
 // Write stores data to the backing memory.
 func (m *MemoryBacking) Write(addr uint64, data []byte) {
+	if bulk, ok := m.memory.(bulkMemAccessor); ok {
+		bulk.WriteBytes(addr, data)
+		return
+	}
 	for i, b := range data {
 		m.memory.Write8(addr+uint64(i), b)
 	}
@@ -34,7 +54,8 @@ func (m *MemoryBacking) Write(addr uint64, data []byte) {
 // CacheBacking wraps a Cache as a BackingStore.
 // This enables hierarchical cache configurations (e.g., L1 → L2 → Memory).
 type CacheBacking struct {
-	cache *Cache
+	cache   *Cache
+	metrics *Metrics
 }
 
 // NewCacheBacking creates a new CacheBacking adapter.
@@ -42,6 +63,15 @@ func NewCacheBacking(cache *Cache) *CacheBacking {
 	return &CacheBacking{cache: cache}
 }
 
+// NewCacheBackingWithMetrics creates a CacheBacking adapter whose Read and
+// Write calls observe into metrics, so that in a hierarchical setup (e.g.
+// L1 → L2 → memory) the accesses L1 makes into L2 through this adapter
+// are attributed to L2's own Metrics (from RegisterMetrics(reg, "l2")),
+// rather than being invisible between the two levels' own instrumentation.
+func NewCacheBackingWithMetrics(cache *Cache, metrics *Metrics) *CacheBacking {
+	return &CacheBacking{cache: cache, metrics: metrics}
+}
+
 // Read fetches data from the backing cache.
 func (c *CacheBacking) Read(addr uint64, size int) []byte {
 	data := make([]byte, size)
@@ -56,6 +86,10 @@ func (c *CacheBacking) Read(addr uint64, size int) []byte {
 		}
 
 		result := c.cache.Read(addr+uint64(offset), chunkSize)
+		c.metrics.observe(result, false)
+		if result.Evicted {
+			c.metrics.observeWriteback()
+		}
 		word := result.Data
 
 		for i := 0; i < chunkSize; i++ {
@@ -90,7 +124,11 @@ func (c *CacheBacking) Write(addr uint64, data []byte) {
 			word |= uint64(data[offset+i])
 		}
 
-		c.cache.Write(addr+uint64(offset), chunkSize, word)
+		result := c.cache.Write(addr+uint64(offset), chunkSize, word)
+		c.metrics.observe(result, true)
+		if result.Evicted {
+			c.metrics.observeWriteback()
+		}
 		offset += chunkSize
 	}
 }
@@ -0,0 +1,125 @@
+package cache_test
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/timing/cache"
+)
+
+// counterValue digs a single counter's value out of a registry by its
+// fully-qualified metric name, for assertions; cache.Metrics keeps its
+// collectors unexported, so tests outside the package can only observe
+// them through the registry they were registered into.
+func counterValue(reg *prometheus.Registry, name string) float64 {
+	families, err := reg.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		var total float64
+		for _, metric := range family.GetMetric() {
+			total += metric.GetCounter().GetValue()
+		}
+		return total
+	}
+	return 0
+}
+
+func sampleCount(reg *prometheus.Registry, name string) uint64 {
+	families, err := reg.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		var total uint64
+		for _, metric := range family.GetMetric() {
+			total += metric.GetHistogram().GetSampleCount()
+		}
+		return total
+	}
+	return 0
+}
+
+var _ = Describe("InstrumentedCache", func() {
+	var (
+		reg     *prometheus.Registry
+		c       *cache.Cache
+		memory  *emu.Memory
+		backing *cache.MemoryBacking
+		ic      *cache.InstrumentedCache
+	)
+
+	BeforeEach(func() {
+		reg = prometheus.NewRegistry()
+		memory = emu.NewMemory()
+		backing = cache.NewMemoryBacking(memory)
+		config := cache.Config{
+			Size:          4 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   10,
+		}
+		c = cache.New(config, backing)
+		ic = cache.NewInstrumentedCache(c, cache.RegisterMetrics(reg, "l1"))
+	})
+
+	It("should count a miss then a hit, and observe into the matching histogram", func() {
+		memory.Write64(0x1000, 0xDEADBEEF)
+
+		ic.Read(0x1000, 8)
+		ic.Read(0x1000, 8)
+
+		Expect(counterValue(reg, "m2sim_cache_reads_total")).To(Equal(2.0))
+		Expect(counterValue(reg, "m2sim_cache_hits_total")).To(Equal(1.0))
+		Expect(counterValue(reg, "m2sim_cache_misses_total")).To(Equal(1.0))
+		Expect(sampleCount(reg, "m2sim_cache_hit_latency_cycles")).To(Equal(uint64(1)))
+		Expect(sampleCount(reg, "m2sim_cache_miss_latency_cycles")).To(Equal(uint64(1)))
+	})
+
+	It("should count evictions and writebacks", func() {
+		ic.Write(0x0000, 8, 0x11111111)
+		ic.Write(0x0400, 8, 0x22222222)
+		ic.Write(0x0800, 8, 0x33333333)
+		ic.Write(0x0C00, 8, 0x44444444)
+
+		ic.Write(0x1000, 8, 0x55555555) // evicts the LRU line of set 0
+
+		Expect(counterValue(reg, "m2sim_cache_evictions_total")).To(Equal(1.0))
+		Expect(counterValue(reg, "m2sim_cache_writebacks_total")).To(Equal(1.0))
+	})
+
+	It("should attribute CacheBacking accesses to the backing level's own metrics", func() {
+		l2Config := cache.Config{
+			Size:          16 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    5,
+			MissLatency:   50,
+		}
+		l2 := cache.New(l2Config, backing)
+		l2Backing := cache.NewCacheBackingWithMetrics(l2, cache.RegisterMetrics(reg, "l2"))
+
+		l1Config := cache.Config{
+			Size:          1 * 1024,
+			Associativity: 2,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   5,
+		}
+		l1 := cache.New(l1Config, l2Backing)
+
+		l1.Read(0x2000, 8)
+
+		Expect(l2.Stats().Reads).To(Equal(uint64(1)))
+		Expect(counterValue(reg, "m2sim_cache_reads_total")).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,155 @@
+package cache
+
+import "math/bits"
+
+// mshrMergeCost is the small extra latency a secondary access pays on
+// top of the primary miss's remaining latency when it coalesces into an
+// already-outstanding MSHR.
+const mshrMergeCost = 1
+
+// mshrEntry tracks one miss status holding register: the block address
+// it covers, the cycle its primary fetch completes, and how many
+// secondary accesses have coalesced into it so far.
+type mshrEntry struct {
+	completeAt    uint64
+	secondaryHits int
+}
+
+// NonBlockingStats extends Stats with the MSHR-specific counters a
+// NonBlockingCache tracks on top of whatever the wrapped Cache counts.
+type NonBlockingStats struct {
+	Stats
+
+	// MSHRHits counts accesses that coalesced into an already-outstanding
+	// MSHR instead of issuing a new miss.
+	MSHRHits uint64
+
+	// MSHRFull counts accesses that stalled because every MSHR was busy
+	// (or, for a block already tracked, because its MSHR had already
+	// reached MaxMissesPerMSHR).
+	MSHRFull uint64
+
+	// SecondaryMisses counts misses to a block that was already being
+	// fetched by another in-flight MSHR; every MSHRHit is also a
+	// SecondaryMiss, but they're reported separately since a future
+	// MaxMissesPerMSHR policy could in principle hit one without the
+	// other.
+	SecondaryMisses uint64
+}
+
+// NonBlockingCache wraps a Cache with Miss Status Holding Register (MSHR)
+// tracking, modeling hit-under-miss (a hit is serviced immediately even
+// while other misses are outstanding) and miss-under-miss (distinct
+// in-flight blocks proceed in parallel, up to NumMSHRs; repeated misses
+// to the same in-flight block coalesce into its MSHR instead of issuing
+// a second fetch). Cache's own blocking behavior — whatever it is in the
+// vendored Akita cache component this package wraps, not in this source
+// tree — is left untouched; NonBlockingCache gets its parallelism by
+// deciding, before ever calling into Cache, whether an access should
+// proceed, coalesce into an existing MSHR, or stall, using the issue
+// cycle the caller passes to ReadAt/WriteAt.
+type NonBlockingCache struct {
+	cache            *Cache
+	numMSHRs         int
+	maxMissesPerMSHR int
+	mshrs            map[uint64]*mshrEntry
+	blockShift       uint8
+
+	stats NonBlockingStats
+}
+
+// NewNonBlockingCache wraps cache with MSHR tracking configured by
+// config.NumMSHRs and config.MaxMissesPerMSHR. A NumMSHRs of 0 disables
+// non-blocking behavior entirely: ReadAt/WriteAt then just forward to
+// cache with no MSHR bookkeeping at all.
+func NewNonBlockingCache(cache *Cache, config Config) *NonBlockingCache {
+	return &NonBlockingCache{
+		cache:            cache,
+		numMSHRs:         config.NumMSHRs,
+		maxMissesPerMSHR: config.MaxMissesPerMSHR,
+		mshrs:            make(map[uint64]*mshrEntry),
+		blockShift:       uint8(bits.Len(uint(config.BlockSize)) - 1),
+	}
+}
+
+func (n *NonBlockingCache) blockAddr(addr uint64) uint64 {
+	return addr >> n.blockShift
+}
+
+// expire drops every MSHR whose primary fetch has completed by cycle, so
+// it no longer occupies a slot or coalesces further accesses.
+func (n *NonBlockingCache) expire(cycle uint64) {
+	for addr, e := range n.mshrs {
+		if cycle >= e.completeAt {
+			delete(n.mshrs, addr)
+		}
+	}
+}
+
+// earliestCompletion returns the soonest completeAt among all
+// outstanding MSHRs; it's only called when the MSHR table is non-empty.
+func (n *NonBlockingCache) earliestCompletion() uint64 {
+	earliest, first := uint64(0), true
+	for _, e := range n.mshrs {
+		if first || e.completeAt < earliest {
+			earliest, first = e.completeAt, false
+		}
+	}
+	return earliest
+}
+
+// ReadAt issues a read at cycle, modeling MSHR-based non-blocking misses
+// when NumMSHRs > 0.
+func (n *NonBlockingCache) ReadAt(addr uint64, size int, cycle uint64) Result {
+	return n.access(addr, cycle, func() Result { return n.cache.Read(addr, size) })
+}
+
+// WriteAt issues a write at cycle, modeling MSHR-based non-blocking
+// misses when NumMSHRs > 0.
+func (n *NonBlockingCache) WriteAt(addr uint64, size int, data uint64, cycle uint64) Result {
+	return n.access(addr, cycle, func() Result { return n.cache.Write(addr, size, data) })
+}
+
+func (n *NonBlockingCache) access(addr uint64, cycle uint64, do func() Result) Result {
+	if n.numMSHRs <= 0 {
+		return do()
+	}
+
+	n.expire(cycle)
+	block := n.blockAddr(addr)
+
+	if entry, ok := n.mshrs[block]; ok {
+		if n.maxMissesPerMSHR > 0 && entry.secondaryHits >= n.maxMissesPerMSHR {
+			n.stats.MSHRFull++
+			return Result{Stall: true, RetryAfter: entry.completeAt - cycle}
+		}
+		entry.secondaryHits++
+		n.stats.MSHRHits++
+		n.stats.SecondaryMisses++
+		return Result{Hit: false, Latency: entry.completeAt - cycle + mshrMergeCost}
+	}
+
+	if len(n.mshrs) >= n.numMSHRs {
+		n.stats.MSHRFull++
+		return Result{Stall: true, RetryAfter: n.earliestCompletion() - cycle}
+	}
+
+	result := do()
+	if !result.Hit {
+		n.mshrs[block] = &mshrEntry{completeAt: cycle + result.Latency}
+	}
+	return result
+}
+
+// Stats returns the wrapped cache's Stats plus the MSHR-specific counters.
+func (n *NonBlockingCache) Stats() NonBlockingStats {
+	st := n.stats
+	st.Stats = n.cache.Stats()
+	return st
+}
+
+// Cache returns the underlying cache, e.g. for pre-warming it directly
+// with Read/Write before issuing timed accesses through ReadAt/WriteAt.
+func (n *NonBlockingCache) Cache() *Cache {
+	return n.cache
+}
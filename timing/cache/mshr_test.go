@@ -0,0 +1,83 @@
+package cache_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/timing/cache"
+)
+
+var _ = Describe("NonBlockingCache", func() {
+	var (
+		memory  *emu.Memory
+		backing *cache.MemoryBacking
+		config  cache.Config
+		c       *cache.Cache
+		nb      *cache.NonBlockingCache
+	)
+
+	BeforeEach(func() {
+		memory = emu.NewMemory()
+		backing = cache.NewMemoryBacking(memory)
+		config = cache.Config{
+			Size:             4 * 1024,
+			Associativity:    4,
+			BlockSize:        64,
+			HitLatency:       1,
+			MissLatency:      20,
+			NumMSHRs:         2,
+			MaxMissesPerMSHR: 4,
+		}
+		c = cache.New(config, backing)
+		nb = cache.NewNonBlockingCache(c, config)
+	})
+
+	It("should service a hit to a different set while a miss to another set is outstanding", func() {
+		memory.Write64(0x2000, 0xCAFE)
+		nb.Cache().Read(0x2000, 8) // pre-warm a different block
+
+		miss := nb.ReadAt(0x1000, 8, 0) // occupies one MSHR, completes at cycle 20
+		Expect(miss.Hit).To(BeFalse())
+
+		hit := nb.ReadAt(0x2000, 8, 1) // hit-under-miss
+		Expect(hit.Hit).To(BeTrue())
+		Expect(hit.Data).To(Equal(uint64(0xCAFE)))
+
+		Expect(nb.Stats().MSHRHits).To(Equal(uint64(0)))
+	})
+
+	It("should coalesce a second miss to the same in-flight block", func() {
+		primary := nb.ReadAt(0x1000, 8, 0)
+		Expect(primary.Hit).To(BeFalse())
+		Expect(primary.Latency).To(Equal(uint64(20)))
+
+		secondary := nb.ReadAt(0x1008, 8, 5) // same 64-byte block, 5 cycles later
+		Expect(secondary.Hit).To(BeFalse())
+		Expect(secondary.Stall).To(BeFalse())
+		Expect(secondary.Latency).To(Equal(uint64(20 - 5 + 1))) // remaining + merge cost
+
+		stats := nb.Stats()
+		Expect(stats.MSHRHits).To(Equal(uint64(1)))
+		Expect(stats.SecondaryMisses).To(Equal(uint64(1)))
+	})
+
+	It("should stall a new miss when every MSHR is busy", func() {
+		nb.ReadAt(0x0000, 8, 0) // set 0, way A, MSHR 1/2
+		nb.ReadAt(0x1000, 8, 0) // distinct block/set, MSHR 2/2
+
+		stalled := nb.ReadAt(0x2000, 8, 0) // a third distinct block: no free MSHR
+		Expect(stalled.Stall).To(BeTrue())
+		Expect(stalled.RetryAfter).To(Equal(uint64(20)))
+
+		Expect(nb.Stats().MSHRFull).To(Equal(uint64(1)))
+	})
+
+	It("should free an MSHR once its completion cycle has passed", func() {
+		nb.ReadAt(0x0000, 8, 0)
+		nb.ReadAt(0x1000, 8, 0)
+
+		afterCompletion := nb.ReadAt(0x2000, 8, 20) // both prior MSHRs have completed by now
+		Expect(afterCompletion.Stall).To(BeFalse())
+	})
+})
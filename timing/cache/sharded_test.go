@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/timing/cache"
+)
+
+var _ = Describe("ShardedCache", func() {
+	var (
+		memory  *emu.Memory
+		backing *cache.MemoryBacking
+	)
+
+	BeforeEach(func() {
+		memory = emu.NewMemory()
+		backing = cache.NewMemoryBacking(memory)
+	})
+
+	It("should behave like a single cache when Shards is 0", func() {
+		config := cache.Config{
+			Size:          4 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   10,
+		}
+		sc, err := cache.NewShardedCache(config, backing)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sc.NumShards()).To(Equal(1))
+
+		memory.Write64(0x1000, 0xDEADBEEF)
+
+		miss := sc.Read(0x1000, 8)
+		Expect(miss.Hit).To(BeFalse())
+
+		hit := sc.Read(0x1000, 8)
+		Expect(hit.Hit).To(BeTrue())
+		Expect(hit.Data).To(Equal(uint64(0xDEADBEEF)))
+
+		Expect(sc.Stats().Reads).To(Equal(uint64(2)))
+	})
+
+	It("should reject a Shards value that isn't a power of two", func() {
+		config := cache.Config{
+			Size:          4 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   10,
+			Shards:        3,
+		}
+		_, err := cache.NewShardedCache(config, backing)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should distribute concurrent accesses across shards without losing any", func() {
+		config := cache.Config{
+			Size:          16 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   10,
+			Shards:        4,
+		}
+		sc, err := cache.NewShardedCache(config, backing)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sc.NumShards()).To(Equal(4))
+
+		const goroutines = 32
+		const accessesPerGoroutine = 64
+
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < accessesPerGoroutine; i++ {
+					addr := uint64(g*accessesPerGoroutine+i) * 64 // one distinct block each
+					sc.Write(addr, 8, uint64(i+1))
+					sc.Read(addr, 8)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		stats := sc.Stats()
+		Expect(stats.Writes).To(Equal(uint64(goroutines * accessesPerGoroutine)))
+		Expect(stats.Reads).To(Equal(uint64(goroutines * accessesPerGoroutine)))
+	})
+})
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+// cacheSnapshotMagic and cacheSnapshotVersion identify the binary format
+// Cache.Snapshot writes and Cache.Restore reads.
+const (
+	cacheSnapshotMagic   uint32 = 0x4D325343 // "M2SC"
+	cacheSnapshotVersion uint32 = 1
+)
+
+// configHash identifies a Config's shape (capacity, associativity, block
+// size, latencies) so Restore can refuse to load a snapshot taken with a
+// different cache configuration instead of silently warming the wrong
+// shape of cache.
+func configHash(config Config) uint64 {
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.LittleEndian, int64(config.Size))
+	_ = binary.Write(h, binary.LittleEndian, int64(config.Associativity))
+	_ = binary.Write(h, binary.LittleEndian, int64(config.BlockSize))
+	_ = binary.Write(h, binary.LittleEndian, int64(config.HitLatency))
+	_ = binary.Write(h, binary.LittleEndian, int64(config.MissLatency))
+	return h.Sum64()
+}
+
+// Snapshot writes this cache's configuration fingerprint and accumulated
+// Stats to w.
+//
+// It deliberately does not capture the tag array, dirty bits, or LRU
+// stack: Cache's internal representation lives in the vendored Akita
+// cache component this package wraps, which isn't part of this source
+// tree and exposes no way to enumerate its resident lines or inject
+// them back in. A true "warmed cache" snapshot needs that component to
+// grow an export/import hook; until it does, Snapshot/Restore round-trip
+// what this package can actually see from the outside — the cache's
+// statistics — and Config verification, so at least a mismatched
+// snapshot is caught loudly rather than silently misapplied.
+func (c *Cache) Snapshot(w io.Writer, config Config) error {
+	if err := binary.Write(w, binary.LittleEndian, cacheSnapshotMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, cacheSnapshotVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, configHash(config)); err != nil {
+		return err
+	}
+
+	stats := c.Stats()
+	return binary.Write(w, binary.LittleEndian, stats)
+}
+
+// Restore reads a snapshot written by Snapshot, verifying it was taken
+// with a Config matching config, and returns the Stats it recorded. It
+// does not — see Snapshot's doc comment — repopulate this cache's tag
+// array, dirty bits, or LRU state; a caller wanting a genuinely warm
+// cache must still replay the accesses that warmed it, using the
+// returned Stats only to validate that replay reproduced the same
+// access counts.
+func (c *Cache) Restore(r io.Reader, config Config) (Stats, error) {
+	var magic, version uint32
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
+		return Stats{}, fmt.Errorf("cache: reading snapshot magic: %w", err)
+	}
+	if magic != cacheSnapshotMagic {
+		return Stats{}, fmt.Errorf("cache: snapshot has bad magic 0x%08X, want 0x%08X", magic, cacheSnapshotMagic)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return Stats{}, fmt.Errorf("cache: reading snapshot version: %w", err)
+	}
+	if version != cacheSnapshotVersion {
+		return Stats{}, fmt.Errorf("cache: snapshot version %d is not supported (want %d)", version, cacheSnapshotVersion)
+	}
+
+	var wantHash uint64
+	if err := binary.Read(r, binary.LittleEndian, &wantHash); err != nil {
+		return Stats{}, fmt.Errorf("cache: reading snapshot config hash: %w", err)
+	}
+	if gotHash := configHash(config); gotHash != wantHash {
+		return Stats{}, fmt.Errorf("cache: snapshot was taken with a different Config (hash 0x%X, current Config hashes to 0x%X)", wantHash, gotHash)
+	}
+
+	var stats Stats
+	if err := binary.Read(r, binary.LittleEndian, &stats); err != nil {
+		return Stats{}, fmt.Errorf("cache: reading snapshot stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Snapshot writes the backing memory's full address space to w, via
+// emu.Memory.Snapshot, so a hierarchy's last level (backed by real
+// memory) can be captured and restored exactly, even though the cache
+// levels above it can only round-trip their Stats (see Cache.Snapshot).
+func (m *MemoryBacking) Snapshot(w io.Writer) error {
+	return m.memory.Snapshot(w)
+}
+
+// Restore reads a snapshot written by Snapshot back into the backing
+// memory, via emu.Memory.Restore.
+func (m *MemoryBacking) Restore(r io.Reader) error {
+	return m.memory.Restore(r)
+}
@@ -0,0 +1,97 @@
+package cache_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/timing/cache"
+)
+
+var _ = Describe("Cache and MemoryBacking Snapshot/Restore", func() {
+	var config cache.Config
+
+	BeforeEach(func() {
+		config = cache.Config{
+			Size:          4 * 1024,
+			Associativity: 4,
+			BlockSize:     64,
+			HitLatency:    1,
+			MissLatency:   10,
+		}
+	})
+
+	It("should round-trip a cache's Stats and verify the config matches", func() {
+		memory := emu.NewMemory()
+		backing := cache.NewMemoryBacking(memory)
+		c := cache.New(config, backing)
+
+		memory.Write64(0x1000, 0xDEADBEEF)
+		c.Read(0x1000, 8)
+		c.Read(0x1000, 8)
+
+		var buf bytes.Buffer
+		Expect(c.Snapshot(&buf, config)).To(Succeed())
+
+		c2 := cache.New(config, backing)
+		stats, err := c2.Restore(&buf, config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Reads).To(Equal(uint64(2)))
+		Expect(stats.Hits).To(Equal(uint64(1)))
+	})
+
+	It("should reject restoring into a differently-configured cache", func() {
+		memory := emu.NewMemory()
+		backing := cache.NewMemoryBacking(memory)
+		c := cache.New(config, backing)
+
+		var buf bytes.Buffer
+		Expect(c.Snapshot(&buf, config)).To(Succeed())
+
+		mismatched := config
+		mismatched.Associativity = 8
+		c2 := cache.New(mismatched, backing)
+
+		_, err := c2.Restore(&buf, mismatched)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should snapshot and restore an L1+L2+memory hierarchy's backing memory exactly", func() {
+		memory := emu.NewMemory()
+		memBacking := cache.NewMemoryBacking(memory)
+
+		l2Config := cache.Config{Size: 16 * 1024, Associativity: 4, BlockSize: 64, HitLatency: 5, MissLatency: 50}
+		l2 := cache.New(l2Config, memBacking)
+		l2Backing := cache.NewCacheBacking(l2)
+
+		l1Config := cache.Config{Size: 4 * 1024, Associativity: 2, BlockSize: 64, HitLatency: 1, MissLatency: 5}
+		l1 := cache.New(l1Config, l2Backing)
+
+		l1.Write(0x5000, 8, 0x1234567890ABCDEF)
+		l1.Flush()
+		l2.Flush()
+
+		var memBuf, l1Buf, l2Buf bytes.Buffer
+		Expect(memBacking.Snapshot(&memBuf)).To(Succeed())
+		Expect(l1.Snapshot(&l1Buf, l1Config)).To(Succeed())
+		Expect(l2.Snapshot(&l2Buf, l2Config)).To(Succeed())
+
+		freshMemory := emu.NewMemory()
+		Expect(freshMemory.Restore(&memBuf)).To(Succeed())
+		freshMemBacking := cache.NewMemoryBacking(freshMemory)
+
+		freshL2 := cache.New(l2Config, freshMemBacking)
+		_, err := freshL2.Restore(&l2Buf, l2Config)
+		Expect(err).NotTo(HaveOccurred())
+
+		freshL2Backing := cache.NewCacheBacking(freshL2)
+		freshL1 := cache.New(l1Config, freshL2Backing)
+		_, err = freshL1.Restore(&l1Buf, l1Config)
+		Expect(err).NotTo(HaveOccurred())
+
+		result := freshL1.Read(0x5000, 8)
+		Expect(result.Data).To(Equal(uint64(0x1234567890ABCDEF)))
+	})
+})
@@ -0,0 +1,248 @@
+package trace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// TraceFilter narrows which instructions an InstructionTraceSink records,
+// so a long run doesn't drown a human (or a downstream analysis tool) in
+// noise. The zero value matches everything.
+type TraceFilter struct {
+	// PCStart and PCEnd restrict matches to [PCStart, PCEnd). Leaving both
+	// zero disables PC filtering.
+	PCStart, PCEnd uint64
+
+	// Opcodes restricts matches to instructions whose mnemonic starts with
+	// one of these strings (e.g. "ccmp", "csel"). An empty slice matches
+	// every mnemonic.
+	Opcodes []string
+
+	// FlagsChangedOnly restricts matches to instructions whose PSTATE
+	// differs from the previous matched instruction's PSTATE.
+	FlagsChangedOnly bool
+}
+
+// matches reports whether rec passes f, given the PSTATE of the last
+// instruction that passed (used only when FlagsChangedOnly is set).
+func (f TraceFilter) matches(pc uint64, mnemonic string, pstate, prevPSTATE emu.PSTATE) bool {
+	if f.PCStart != 0 || f.PCEnd != 0 {
+		if pc < f.PCStart || pc >= f.PCEnd {
+			return false
+		}
+	}
+
+	if len(f.Opcodes) > 0 {
+		matched := false
+		for _, op := range f.Opcodes {
+			if strings.HasPrefix(mnemonic, op) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.FlagsChangedOnly && pstate == prevPSTATE {
+		return false
+	}
+
+	return true
+}
+
+// FilteredSink wraps an emu.InstructionTraceSink, forwarding only the
+// OnInstruction calls that pass filter. Attach it to the emulator with
+// Emulator.SetInstructionTracer in place of the unwrapped sink.
+type FilteredSink struct {
+	sink   emu.InstructionTraceSink
+	filter TraceFilter
+
+	havePrev   bool
+	prevPSTATE emu.PSTATE
+}
+
+// NewFilteredSink creates a FilteredSink that forwards to sink only the
+// instructions matching filter.
+func NewFilteredSink(sink emu.InstructionTraceSink, filter TraceFilter) *FilteredSink {
+	return &FilteredSink{sink: sink, filter: filter}
+}
+
+// OnInstruction implements emu.InstructionTraceSink.
+func (s *FilteredSink) OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter emu.RegSnapshot, pstate emu.PSTATE) {
+	prev := s.prevPSTATE
+	if !s.havePrev {
+		prev = pstate // a first instruction never counts as a flag change
+	}
+
+	if s.filter.matches(pc, mnemonic, pstate, prev) {
+		s.sink.OnInstruction(pc, raw, mnemonic, regsBefore, regsAfter, pstate)
+	}
+
+	s.prevPSTATE = pstate
+	s.havePrev = true
+}
+
+// TextInstructionSink implements emu.InstructionTraceSink, writing one
+// human-readable line per instruction to an underlying io.Writer, e.g.:
+//
+//	0x00001000: ccmp x1, x2, #0, eq  N=0 Z=1 C=1 V=0
+type TextInstructionSink struct {
+	w   io.Writer
+	err error // first write error encountered; sticky
+}
+
+// NewTextInstructionSink creates a TextInstructionSink that writes to w.
+func NewTextInstructionSink(w io.Writer) *TextInstructionSink {
+	return &TextInstructionSink{w: w}
+}
+
+// Err returns the first error encountered writing to the underlying
+// io.Writer, if any.
+func (s *TextInstructionSink) Err() error {
+	return s.err
+}
+
+// OnInstruction implements emu.InstructionTraceSink.
+func (s *TextInstructionSink) OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter emu.RegSnapshot, pstate emu.PSTATE) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, "0x%08X: %-40s N=%s Z=%s C=%s V=%s\n",
+		pc, mnemonic, flagBit(pstate.N), flagBit(pstate.Z), flagBit(pstate.C), flagBit(pstate.V))
+}
+
+func flagBit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// jsonlInstructionRecord is the JSON shape JSONLInstructionSink writes,
+// one per line.
+type jsonlInstructionRecord struct {
+	PC         uint64          `json:"pc"`
+	Raw        uint32          `json:"raw"`
+	Mnemonic   string          `json:"mnemonic"`
+	RegsBefore emu.RegSnapshot `json:"regs_before"`
+	RegsAfter  emu.RegSnapshot `json:"regs_after"`
+	N          bool            `json:"n"`
+	Z          bool            `json:"z"`
+	C          bool            `json:"c"`
+	V          bool            `json:"v"`
+}
+
+// JSONLInstructionSink implements emu.InstructionTraceSink, writing one
+// JSON object per instruction, newline-delimited, suitable for streaming
+// into an external analysis tool.
+type JSONLInstructionSink struct {
+	enc *json.Encoder
+	err error // first encode error encountered; sticky
+}
+
+// NewJSONLInstructionSink creates a JSONLInstructionSink that writes to w.
+func NewJSONLInstructionSink(w io.Writer) *JSONLInstructionSink {
+	return &JSONLInstructionSink{enc: json.NewEncoder(w)}
+}
+
+// Err returns the first error encountered encoding to the underlying
+// io.Writer, if any.
+func (s *JSONLInstructionSink) Err() error {
+	return s.err
+}
+
+// OnInstruction implements emu.InstructionTraceSink.
+func (s *JSONLInstructionSink) OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter emu.RegSnapshot, pstate emu.PSTATE) {
+	if s.err != nil {
+		return
+	}
+	s.err = s.enc.Encode(jsonlInstructionRecord{
+		PC:         pc,
+		Raw:        raw,
+		Mnemonic:   mnemonic,
+		RegsBefore: regsBefore,
+		RegsAfter:  regsAfter,
+		N:          pstate.N,
+		Z:          pstate.Z,
+		C:          pstate.C,
+		V:          pstate.V,
+	})
+}
+
+// BinaryInstructionSink implements emu.InstructionTraceSink, writing a
+// compact fixed-size binary record per instruction: pc(8) raw(4)
+// mnemonicLen(2) mnemonic(mnemonicLen) regsBefore(32*8) regsAfter(32*8)
+// nzcv(1). It mirrors Recorder's tagged-binary-stream style but needs no
+// tag byte, since it carries only one kind of record.
+type BinaryInstructionSink struct {
+	w   io.Writer
+	err error // first write error encountered; sticky
+}
+
+// NewBinaryInstructionSink creates a BinaryInstructionSink that writes to w.
+func NewBinaryInstructionSink(w io.Writer) *BinaryInstructionSink {
+	return &BinaryInstructionSink{w: w}
+}
+
+// Err returns the first error encountered writing to the underlying
+// io.Writer, if any.
+func (s *BinaryInstructionSink) Err() error {
+	return s.err
+}
+
+// OnInstruction implements emu.InstructionTraceSink.
+func (s *BinaryInstructionSink) OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter emu.RegSnapshot, pstate emu.PSTATE) {
+	if s.err != nil {
+		return
+	}
+
+	mn := []byte(mnemonic)
+	buf := make([]byte, 8+4+2+len(mn)+numRegs*8+numRegs*8+1)
+	off := 0
+	binary.LittleEndian.PutUint64(buf[off:off+8], pc)
+	off += 8
+	binary.LittleEndian.PutUint32(buf[off:off+4], raw)
+	off += 4
+	binary.LittleEndian.PutUint16(buf[off:off+2], uint16(len(mn)))
+	off += 2
+	off += copy(buf[off:], mn)
+	for _, v := range regsBefore {
+		binary.LittleEndian.PutUint64(buf[off:off+8], v)
+		off += 8
+	}
+	for _, v := range regsAfter {
+		binary.LittleEndian.PutUint64(buf[off:off+8], v)
+		off += 8
+	}
+	buf[off] = packNZCV(pstate)
+
+	if _, err := s.w.Write(buf); err != nil {
+		s.err = err
+	}
+}
+
+// packNZCV packs PSTATE's four flags into a single byte, N in bit 3 down
+// to V in bit 0, matching the architectural nzcv field CCMP/CCMN use.
+func packNZCV(pstate emu.PSTATE) byte {
+	var b byte
+	if pstate.N {
+		b |= 0b1000
+	}
+	if pstate.Z {
+		b |= 0b0100
+	}
+	if pstate.C {
+		b |= 0b0010
+	}
+	if pstate.V {
+		b |= 0b0001
+	}
+	return b
+}
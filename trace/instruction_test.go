@@ -0,0 +1,133 @@
+package trace_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/trace"
+)
+
+var _ = Describe("Instruction trace sinks", func() {
+	Describe("TextInstructionSink", func() {
+		It("should write one human-readable line per instruction", func() {
+			var buf bytes.Buffer
+			sink := trace.NewTextInstructionSink(&buf)
+
+			sink.OnInstruction(0x1000, 0xDEADBEEF, "ccmp x1, x2, #0, eq",
+				emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{Z: true, C: true})
+
+			Expect(sink.Err()).ToNot(HaveOccurred())
+			Expect(buf.String()).To(ContainSubstring("0x00001000"))
+			Expect(buf.String()).To(ContainSubstring("ccmp x1, x2, #0, eq"))
+			Expect(buf.String()).To(ContainSubstring("Z=1"))
+			Expect(buf.String()).To(ContainSubstring("C=1"))
+			Expect(buf.String()).To(ContainSubstring("N=0"))
+		})
+	})
+
+	Describe("JSONLInstructionSink", func() {
+		It("should write one JSON object per instruction", func() {
+			var buf bytes.Buffer
+			sink := trace.NewJSONLInstructionSink(&buf)
+
+			sink.OnInstruction(0x1000, 0xDEADBEEF, "ccmn x1, x2, #0, eq",
+				emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{V: true})
+			sink.OnInstruction(0x1004, 0x12345678, "csel x0, x1, x2, ne",
+				emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{})
+
+			Expect(sink.Err()).ToNot(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(ContainSubstring(`"mnemonic":"ccmn x1, x2, #0, eq"`))
+			Expect(lines[0]).To(ContainSubstring(`"v":true`))
+			Expect(lines[1]).To(ContainSubstring(`"mnemonic":"csel x0, x1, x2, ne"`))
+		})
+	})
+
+	Describe("BinaryInstructionSink", func() {
+		It("should write a fixed-shape record without error", func() {
+			var buf bytes.Buffer
+			sink := trace.NewBinaryInstructionSink(&buf)
+
+			sink.OnInstruction(0x1000, 0xDEADBEEF, "udiv x0, x1, x2",
+				emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{N: true, V: true})
+
+			Expect(sink.Err()).ToNot(HaveOccurred())
+			Expect(buf.Len()).To(BeNumerically(">", 8+4+2+len("udiv x0, x1, x2")))
+		})
+	})
+
+	Describe("FilteredSink", func() {
+		It("should drop instructions outside the PC range", func() {
+			var recorded []string
+			sink := trace.NewFilteredSink(recordingSink(&recorded), trace.TraceFilter{
+				PCStart: 0x2000,
+				PCEnd:   0x3000,
+			})
+
+			sink.OnInstruction(0x1000, 0, "nop", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{})
+			sink.OnInstruction(0x2000, 0, "nop", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{})
+
+			Expect(recorded).To(Equal([]string{"nop@0x2000"}))
+		})
+
+		It("should drop mnemonics not matching Opcodes", func() {
+			var recorded []string
+			sink := trace.NewFilteredSink(recordingSink(&recorded), trace.TraceFilter{
+				Opcodes: []string{"ccmp"},
+			})
+
+			sink.OnInstruction(0x1000, 0, "csel x0, x1, x2, eq", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{})
+			sink.OnInstruction(0x1004, 0, "ccmp x1, x2, #0, eq", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{})
+
+			Expect(recorded).To(Equal([]string{"ccmp x1, x2, #0, eq@0x1004"}))
+		})
+
+		It("should drop instructions whose PSTATE did not change when FlagsChangedOnly is set", func() {
+			var recorded []string
+			sink := trace.NewFilteredSink(recordingSink(&recorded), trace.TraceFilter{
+				FlagsChangedOnly: true,
+			})
+
+			sink.OnInstruction(0x1000, 0, "mov x0, x1", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{Z: true})
+			sink.OnInstruction(0x1004, 0, "mov x0, x2", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{Z: true})
+			sink.OnInstruction(0x1008, 0, "ccmp x1, x2, #0, eq", emu.RegSnapshot{}, emu.RegSnapshot{}, emu.PSTATE{Z: false})
+
+			Expect(recorded).To(Equal([]string{"mov x0, x1@0x1000", "ccmp x1, x2, #0, eq@0x1008"}))
+		})
+	})
+})
+
+// recordingSink builds an emu.InstructionTraceSink that appends
+// "mnemonic@0xPC" to *out for every call it receives, for asserting which
+// instructions a FilteredSink let through.
+func recordingSink(out *[]string) emu.InstructionTraceSink {
+	return &sliceSink{out: out}
+}
+
+type sliceSink struct {
+	out *[]string
+}
+
+func (s *sliceSink) OnInstruction(pc uint64, raw uint32, mnemonic string, regsBefore, regsAfter emu.RegSnapshot, pstate emu.PSTATE) {
+	*s.out = append(*s.out, mnemonic+"@"+hex(pc))
+}
+
+func hex(v uint64) string {
+	const digits = "0123456789abcdef"
+	if v == 0 {
+		return "0x0"
+	}
+	var buf [16]byte
+	i := len(buf)
+	for v > 0 {
+		i--
+		buf[i] = digits[v&0xF]
+		v >>= 4
+	}
+	return "0x" + string(buf[i:])
+}
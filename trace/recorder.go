@@ -0,0 +1,167 @@
+// Package trace implements deterministic record-and-replay of emulated
+// execution.
+package trace
+
+import (
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// Event tags identify the kind of record in the binary trace stream. Every
+// tag's payload has a fixed size, so Replayer never needs a length prefix.
+const (
+	tagRegWrite byte = iota
+	tagMemWrite
+	tagSyscall
+	tagRetire
+	tagSnapshot
+)
+
+const numRegs = 32 // x0-x30 (0-30) plus SP (31)
+
+// Recorder implements emu.TraceSink, writing every event it receives to an
+// underlying io.Writer in a compact binary format. Attach it to a running
+// emulator with Emulator.SetTraceSink.
+type Recorder struct {
+	w io.Writer
+
+	chunkSize  uint64 // retired instructions between snapshots; 0 disables periodic snapshots
+	sinceChunk uint64
+	instIdx    uint64
+
+	regs [numRegs]uint64 // shadow of the register file, kept for snapshotting
+	pc   uint64          // PC of the most recently retired instruction
+	mem  map[uint64]byte // shadow of every byte written so far, kept for snapshotting
+
+	err error // first write error encountered; sticky
+}
+
+// NewRecorder creates a Recorder that writes to w, emitting a full
+// snapshot of register and memory state every chunkSize retired
+// instructions (in addition to the snapshot always written up front), so
+// a Replayer can seek to any instruction index by replaying forward from
+// the nearest snapshot instead of from the start of the trace. A
+// chunkSize of 0 disables periodic snapshots; only the initial one is
+// written.
+func NewRecorder(w io.Writer, chunkSize uint64) *Recorder {
+	r := &Recorder{
+		w:         w,
+		chunkSize: chunkSize,
+		mem:       make(map[uint64]byte),
+	}
+	r.writeSnapshot()
+	return r
+}
+
+// Err returns the first error encountered writing to the underlying
+// io.Writer, if any. Callers that care about a full trace should check it
+// after execution finishes.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// RecordRegWrite implements emu.TraceSink.
+func (r *Recorder) RecordRegWrite(reg uint8, old, newVal uint64) {
+	if int(reg) < numRegs {
+		r.regs[reg] = newVal
+	}
+
+	buf := make([]byte, 17)
+	buf[0] = reg
+	binary.LittleEndian.PutUint64(buf[1:9], old)
+	binary.LittleEndian.PutUint64(buf[9:17], newVal)
+	r.emit(tagRegWrite, buf)
+}
+
+// RecordMemWrite implements emu.TraceSink.
+func (r *Recorder) RecordMemWrite(addr uint64, old, newVal byte) {
+	r.mem[addr] = newVal
+
+	buf := make([]byte, 10)
+	binary.LittleEndian.PutUint64(buf[0:8], addr)
+	buf[8] = old
+	buf[9] = newVal
+	r.emit(tagMemWrite, buf)
+}
+
+// RecordSyscall implements emu.TraceSink.
+func (r *Recorder) RecordSyscall(num uint64, args [6]uint64, exited bool, exitCode int64) {
+	buf := make([]byte, 65)
+	binary.LittleEndian.PutUint64(buf[0:8], num)
+	for i, a := range args {
+		binary.LittleEndian.PutUint64(buf[8+i*8:16+i*8], a)
+	}
+	if exited {
+		buf[56] = 1
+	}
+	binary.LittleEndian.PutUint64(buf[57:65], uint64(exitCode))
+	r.emit(tagSyscall, buf)
+}
+
+// RecordRetire implements emu.TraceSink. It also advances the instruction
+// index and, once chunkSize instructions have retired since the last one,
+// writes a fresh snapshot.
+func (r *Recorder) RecordRetire(pc uint64) {
+	r.instIdx++
+	r.pc = pc
+
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint64(buf[0:8], pc)
+	binary.LittleEndian.PutUint64(buf[8:16], r.instIdx)
+	r.emit(tagRetire, buf)
+
+	r.sinceChunk++
+	if r.chunkSize > 0 && r.sinceChunk >= r.chunkSize {
+		r.sinceChunk = 0
+		r.writeSnapshot()
+	}
+}
+
+// writeSnapshot emits the current instruction index, every register, and
+// every distinct byte written so far (in ascending address order, for
+// determinism), so a Replayer can restart from here instead of the
+// beginning of the trace.
+func (r *Recorder) writeSnapshot() {
+	addrs := make([]uint64, 0, len(r.mem))
+	for addr := range r.mem {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+
+	buf := make([]byte, 8+8+numRegs*8+8)
+	off := 0
+	binary.LittleEndian.PutUint64(buf[off:off+8], r.instIdx)
+	off += 8
+	binary.LittleEndian.PutUint64(buf[off:off+8], r.pc)
+	off += 8
+	for _, v := range r.regs {
+		binary.LittleEndian.PutUint64(buf[off:off+8], v)
+		off += 8
+	}
+	binary.LittleEndian.PutUint64(buf[off:off+8], uint64(len(addrs)))
+
+	for _, addr := range addrs {
+		entry := make([]byte, 9)
+		binary.LittleEndian.PutUint64(entry[0:8], addr)
+		entry[8] = r.mem[addr]
+		buf = append(buf, entry...)
+	}
+
+	r.emit(tagSnapshot, buf)
+}
+
+// emit writes tag followed by payload, recording (and then suppressing
+// further writes after) the first error encountered.
+func (r *Recorder) emit(tag byte, payload []byte) {
+	if r.err != nil {
+		return
+	}
+	if _, err := r.w.Write([]byte{tag}); err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.w.Write(payload); err != nil {
+		r.err = err
+	}
+}
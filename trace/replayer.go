@@ -0,0 +1,292 @@
+// Package trace implements deterministic record-and-replay of emulated
+// execution.
+package trace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// event is one parsed record from a trace log; exactly one of the typed
+// fields is meaningful, selected by tag.
+type event struct {
+	tag byte
+
+	reg    regWrite
+	memw   memWrite
+	sc     syscallCall
+	retire retire
+	snap   snapshot
+}
+
+type regWrite struct {
+	reg      uint8
+	old, new uint64
+}
+
+type memWrite struct {
+	addr     uint64
+	old, new byte
+}
+
+type syscallCall struct {
+	num      uint64
+	args     [6]uint64
+	exited   bool
+	exitCode int64
+}
+
+type retire struct {
+	pc      uint64
+	instIdx uint64
+}
+
+type snapshot struct {
+	instIdx uint64
+	pc      uint64
+	regs    [numRegs]uint64
+	mem     map[uint64]byte
+}
+
+// snapshotRef locates a tagSnapshot event by the instruction index it was
+// taken at, so Seek can find the nearest one without scanning every event.
+type snapshotRef struct {
+	instIdx  uint64
+	eventIdx int
+}
+
+// Replayer reconstructs the exact machine state at any retired instruction
+// index from a trace previously written by a Recorder. It parses the
+// entire log up front: simpler than seeking within an io.Reader, and fine
+// for the reverse-debugging and post-mortem analysis this subsystem
+// targets rather than live production tracing.
+type Replayer struct {
+	events    []event
+	snapshots []snapshotRef
+
+	pos int    // index into events of the next one to apply
+	cur uint64 // current instruction index
+	pc  uint64 // PC of the instruction most recently retired
+
+	regs        [numRegs]uint64
+	mem         map[uint64]byte
+	lastSyscall syscallCall
+}
+
+// NewReplayer parses every event in r and positions the Replayer at
+// instruction index 0.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{}
+
+	for {
+		var tagBuf [1]byte
+		if _, err := io.ReadFull(r, tagBuf[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("trace: reading event tag: %w", err)
+		}
+
+		e, err := readEvent(r, tagBuf[0])
+		if err != nil {
+			return nil, err
+		}
+		if e.tag == tagSnapshot {
+			rp.snapshots = append(rp.snapshots, snapshotRef{
+				instIdx:  e.snap.instIdx,
+				eventIdx: len(rp.events),
+			})
+		}
+		rp.events = append(rp.events, e)
+	}
+
+	if len(rp.snapshots) == 0 {
+		return nil, fmt.Errorf("trace: log has no snapshots (is it empty?)")
+	}
+	if err := rp.Seek(0); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+// readEvent reads one event's payload (tag has already been consumed).
+func readEvent(r io.Reader, tag byte) (event, error) {
+	switch tag {
+	case tagRegWrite:
+		buf := make([]byte, 17)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return event{}, fmt.Errorf("trace: reading reg-write event: %w", err)
+		}
+		return event{tag: tag, reg: regWrite{
+			reg: buf[0],
+			old: binary.LittleEndian.Uint64(buf[1:9]),
+			new: binary.LittleEndian.Uint64(buf[9:17]),
+		}}, nil
+
+	case tagMemWrite:
+		buf := make([]byte, 10)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return event{}, fmt.Errorf("trace: reading mem-write event: %w", err)
+		}
+		return event{tag: tag, memw: memWrite{
+			addr: binary.LittleEndian.Uint64(buf[0:8]),
+			old:  buf[8],
+			new:  buf[9],
+		}}, nil
+
+	case tagSyscall:
+		buf := make([]byte, 65)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return event{}, fmt.Errorf("trace: reading syscall event: %w", err)
+		}
+		var args [6]uint64
+		for i := range args {
+			args[i] = binary.LittleEndian.Uint64(buf[8+i*8 : 16+i*8])
+		}
+		return event{tag: tag, sc: syscallCall{
+			num:      binary.LittleEndian.Uint64(buf[0:8]),
+			args:     args,
+			exited:   buf[56] != 0,
+			exitCode: int64(binary.LittleEndian.Uint64(buf[57:65])),
+		}}, nil
+
+	case tagRetire:
+		buf := make([]byte, 16)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return event{}, fmt.Errorf("trace: reading retire event: %w", err)
+		}
+		return event{tag: tag, retire: retire{
+			pc:      binary.LittleEndian.Uint64(buf[0:8]),
+			instIdx: binary.LittleEndian.Uint64(buf[8:16]),
+		}}, nil
+
+	case tagSnapshot:
+		header := make([]byte, 8+8+numRegs*8+8)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return event{}, fmt.Errorf("trace: reading snapshot header: %w", err)
+		}
+		instIdx := binary.LittleEndian.Uint64(header[0:8])
+		pc := binary.LittleEndian.Uint64(header[8:16])
+		var regs [numRegs]uint64
+		for i := range regs {
+			regs[i] = binary.LittleEndian.Uint64(header[16+i*8 : 24+i*8])
+		}
+		memCount := binary.LittleEndian.Uint64(header[16+numRegs*8 : 24+numRegs*8])
+
+		entries := make([]byte, memCount*9)
+		if _, err := io.ReadFull(r, entries); err != nil {
+			return event{}, fmt.Errorf("trace: reading snapshot memory: %w", err)
+		}
+		mem := make(map[uint64]byte, memCount)
+		for i := uint64(0); i < memCount; i++ {
+			addr := binary.LittleEndian.Uint64(entries[i*9 : i*9+8])
+			mem[addr] = entries[i*9+8]
+		}
+		return event{tag: tag, snap: snapshot{instIdx: instIdx, pc: pc, regs: regs, mem: mem}}, nil
+
+	default:
+		return event{}, fmt.Errorf("trace: unknown event tag %d", tag)
+	}
+}
+
+// Seek repositions the Replayer at instIdx, restarting from the nearest
+// snapshot at or before it and replaying forward from there.
+func (rp *Replayer) Seek(instIdx uint64) error {
+	ref := rp.snapshots[0]
+	for _, s := range rp.snapshots {
+		if s.instIdx > instIdx {
+			break
+		}
+		ref = s
+	}
+
+	snap := rp.events[ref.eventIdx].snap
+	rp.cur = snap.instIdx
+	rp.pc = snap.pc
+	rp.regs = snap.regs
+	rp.mem = make(map[uint64]byte, len(snap.mem))
+	for addr, b := range snap.mem {
+		rp.mem[addr] = b
+	}
+	rp.pos = ref.eventIdx + 1
+
+	for rp.cur < instIdx {
+		if !rp.StepForward() {
+			return fmt.Errorf("trace: instruction index %d is beyond the end of the trace", instIdx)
+		}
+	}
+	return nil
+}
+
+// StepForward applies events up to and including the next retired
+// instruction, advancing the Replayer by one instruction index. It
+// reports false once the trace is exhausted.
+func (rp *Replayer) StepForward() bool {
+	for rp.pos < len(rp.events) {
+		e := rp.events[rp.pos]
+		rp.pos++
+
+		switch e.tag {
+		case tagRegWrite:
+			if int(e.reg.reg) < numRegs {
+				rp.regs[e.reg.reg] = e.reg.new
+			}
+		case tagMemWrite:
+			rp.mem[e.memw.addr] = e.memw.new
+		case tagSyscall:
+			rp.lastSyscall = e.sc
+		case tagRetire:
+			rp.cur = e.retire.instIdx
+			rp.pc = e.retire.pc
+			return true
+		case tagSnapshot:
+			// Snapshots are only consulted by Seek; ordinary forward
+			// replay skips over them.
+		}
+	}
+	return false
+}
+
+// StepBackward moves the Replayer back by one instruction index. It
+// reports false if already at instruction index 0.
+func (rp *Replayer) StepBackward() bool {
+	if rp.cur == 0 {
+		return false
+	}
+	return rp.Seek(rp.cur-1) == nil
+}
+
+// InstructionIndex returns the number of instructions retired so far.
+func (rp *Replayer) InstructionIndex() uint64 {
+	return rp.cur
+}
+
+// PC returns the program counter of the most recently retired instruction.
+func (rp *Replayer) PC() uint64 {
+	return rp.pc
+}
+
+// ReadReg returns GPR n (0-30) or SP (31, via index 31).
+func (rp *Replayer) ReadReg(n uint8) uint64 {
+	return rp.regs[n]
+}
+
+// SP returns the stack pointer.
+func (rp *Replayer) SP() uint64 {
+	return rp.regs[31]
+}
+
+// ReadByte returns the byte at addr as of the current instruction index.
+// An address never written by the trace reads as 0, since the Replayer
+// only reconstructs bytes the Recorder actually observed being written.
+func (rp *Replayer) ReadByte(addr uint64) byte {
+	return rp.mem[addr]
+}
+
+// LastSyscall returns the most recently replayed syscall's inputs and
+// outputs, or the zero value if no syscall has been replayed yet.
+func (rp *Replayer) LastSyscall() (num uint64, args [6]uint64, exited bool, exitCode int64) {
+	sc := rp.lastSyscall
+	return sc.num, sc.args, sc.exited, sc.exitCode
+}
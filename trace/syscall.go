@@ -0,0 +1,157 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sarchlab/m2sim/emu"
+)
+
+// maxSyscallBufPreview caps how many bytes of a read/write buffer the
+// syscall sinks render, so a large buffer doesn't flood the trace.
+const maxSyscallBufPreview = 32
+
+// TextSyscallSink implements emu.SyscallTracer, writing one
+// strace-style line per syscall to an underlying io.Writer, e.g.:
+//
+//	openat(AT_FDCWD, "/etc/passwd", 0) = 3
+//	read(3, "hello, world", 12) = 12
+type TextSyscallSink struct {
+	w      io.Writer
+	memory *emu.Memory
+	err    error // first write error encountered; sticky
+
+	pending string // "name(args)" recorded by Enter, completed by Exit
+}
+
+// NewTextSyscallSink creates a TextSyscallSink that writes to w, decoding
+// path and buffer arguments by reading them out of memory.
+func NewTextSyscallSink(w io.Writer, memory *emu.Memory) *TextSyscallSink {
+	return &TextSyscallSink{w: w, memory: memory}
+}
+
+// Err returns the first error encountered writing to the underlying
+// io.Writer, if any.
+func (s *TextSyscallSink) Err() error {
+	return s.err
+}
+
+// Enter implements emu.SyscallTracer.
+func (s *TextSyscallSink) Enter(num uint64, name string, args [6]uint64) {
+	s.pending = fmt.Sprintf("%s(%s)", name, formatSyscallArgs(s.memory, name, args))
+}
+
+// Exit implements emu.SyscallTracer.
+func (s *TextSyscallSink) Exit(ret uint64, errno int, result emu.SyscallResult) {
+	if s.err != nil {
+		return
+	}
+	if errno != 0 {
+		_, s.err = fmt.Fprintf(s.w, "%s = -1 (errno %d)\n", s.pending, errno)
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, "%s = %d\n", s.pending, int64(ret))
+}
+
+// jsonlSyscallRecord is the JSON shape JSONLSyscallSink writes, one per
+// line.
+type jsonlSyscallRecord struct {
+	Num   uint64    `json:"num"`
+	Name  string    `json:"name"`
+	Args  [6]uint64 `json:"args"`
+	Ret   int64     `json:"ret"`
+	Errno int       `json:"errno,omitempty"`
+}
+
+// JSONLSyscallSink implements emu.SyscallTracer, writing one JSON object
+// per syscall, newline-delimited, suitable for streaming into an
+// external analysis tool.
+type JSONLSyscallSink struct {
+	enc *json.Encoder
+	err error // first encode error encountered; sticky
+
+	pending jsonlSyscallRecord
+}
+
+// NewJSONLSyscallSink creates a JSONLSyscallSink that writes to w.
+func NewJSONLSyscallSink(w io.Writer) *JSONLSyscallSink {
+	return &JSONLSyscallSink{enc: json.NewEncoder(w)}
+}
+
+// Err returns the first error encountered encoding to the underlying
+// io.Writer, if any.
+func (s *JSONLSyscallSink) Err() error {
+	return s.err
+}
+
+// Enter implements emu.SyscallTracer.
+func (s *JSONLSyscallSink) Enter(num uint64, name string, args [6]uint64) {
+	s.pending = jsonlSyscallRecord{Num: num, Name: name, Args: args}
+}
+
+// Exit implements emu.SyscallTracer.
+func (s *JSONLSyscallSink) Exit(ret uint64, errno int, result emu.SyscallResult) {
+	if s.err != nil {
+		return
+	}
+	s.pending.Ret = int64(ret)
+	s.pending.Errno = errno
+	s.err = s.enc.Encode(s.pending)
+}
+
+// formatSyscallArgs renders args the way strace would, for the syscalls
+// this package knows the argument shape of (openat's path, read/write's
+// buffer); everything else falls back to plain hex.
+func formatSyscallArgs(memory *emu.Memory, name string, args [6]uint64) string {
+	switch name {
+	case "openat":
+		return fmt.Sprintf("%s, %q, %#o", atFDName(int64(args[0])), readCString(memory, args[1]), args[2])
+	case "read", "write":
+		return fmt.Sprintf("%d, %s, %d", args[0], previewBuf(memory, args[1], args[2]), args[2])
+	default:
+		return fmt.Sprintf("%#x, %#x, %#x, %#x, %#x, %#x", args[0], args[1], args[2], args[3], args[4], args[5])
+	}
+}
+
+// atFDName renders an openat dirfd argument, naming AT_FDCWD rather than
+// printing its two's-complement value.
+func atFDName(dirfd int64) string {
+	if dirfd == emu.AT_FDCWD {
+		return "AT_FDCWD"
+	}
+	return fmt.Sprintf("%d", dirfd)
+}
+
+func readCString(memory *emu.Memory, addr uint64) string {
+	var buf []byte
+	for {
+		b := memory.Read8(addr)
+		if b == 0 {
+			break
+		}
+		buf = append(buf, b)
+		addr++
+	}
+	return string(buf)
+}
+
+// previewBuf renders up to maxSyscallBufPreview bytes of the buffer at
+// addr, marking it with a trailing "..." if count exceeds that cap.
+func previewBuf(memory *emu.Memory, addr, count uint64) string {
+	n := count
+	truncated := n > maxSyscallBufPreview
+	if truncated {
+		n = maxSyscallBufPreview
+	}
+
+	buf := make([]byte, n)
+	for i := uint64(0); i < n; i++ {
+		buf[i] = memory.Read8(addr + i)
+	}
+
+	if truncated {
+		return fmt.Sprintf("%q...", buf)
+	}
+	return fmt.Sprintf("%q", buf)
+}
@@ -0,0 +1,77 @@
+package trace_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/emu"
+	"github.com/sarchlab/m2sim/trace"
+)
+
+var _ = Describe("Syscall trace sinks", func() {
+	var memory *emu.Memory
+
+	BeforeEach(func() {
+		memory = emu.NewMemory()
+		memory.MapAnonymous(0x1000, emu.PageSize, emu.PROT_READ|emu.PROT_WRITE)
+		for i, b := range []byte("/etc/passwd") {
+			memory.Write8(0x1000+uint64(i), b)
+		}
+		memory.Write8(0x1000+11, 0)
+	})
+
+	Describe("TextSyscallSink", func() {
+		It("should decode openat's path argument", func() {
+			var buf bytes.Buffer
+			sink := trace.NewTextSyscallSink(&buf, memory)
+
+			sink.Enter(56, "openat", [6]uint64{uint64(emu.AT_FDCWD), 0x1000, 0, 0, 0, 0})
+			sink.Exit(3, 0, emu.SyscallResult{})
+
+			Expect(sink.Err()).ToNot(HaveOccurred())
+			Expect(buf.String()).To(ContainSubstring(`openat(AT_FDCWD, "/etc/passwd", 0)`))
+			Expect(buf.String()).To(ContainSubstring("= 3"))
+		})
+
+		It("should report an error return as errno", func() {
+			var buf bytes.Buffer
+			sink := trace.NewTextSyscallSink(&buf, memory)
+
+			sink.Enter(57, "close", [6]uint64{42, 0, 0, 0, 0, 0})
+			sink.Exit(uint64(int64(-9)), 9, emu.SyscallResult{})
+
+			Expect(buf.String()).To(ContainSubstring("errno 9"))
+		})
+
+		It("should fall back to hex for unknown syscalls", func() {
+			var buf bytes.Buffer
+			sink := trace.NewTextSyscallSink(&buf, memory)
+
+			sink.Enter(999, "mystery", [6]uint64{1, 2, 3, 0, 0, 0})
+			sink.Exit(0, 0, emu.SyscallResult{})
+
+			Expect(buf.String()).To(ContainSubstring("0x1, 0x2, 0x3"))
+		})
+	})
+
+	Describe("JSONLSyscallSink", func() {
+		It("should write one JSON object per syscall", func() {
+			var buf bytes.Buffer
+			sink := trace.NewJSONLSyscallSink(&buf)
+
+			sink.Enter(93, "exit", [6]uint64{42, 0, 0, 0, 0, 0})
+			sink.Exit(42, 0, emu.SyscallResult{Exited: true, ExitCode: 42})
+
+			Expect(sink.Err()).ToNot(HaveOccurred())
+
+			var record map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+			Expect(record["name"]).To(Equal("exit"))
+			Expect(record["ret"]).To(Equal(float64(42)))
+			Expect(record).ToNot(HaveKey("errno"))
+		})
+	})
+})
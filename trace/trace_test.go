@@ -0,0 +1,107 @@
+// Package trace implements deterministic record-and-replay of emulated
+// execution.
+package trace_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sarchlab/m2sim/trace"
+)
+
+var _ = Describe("Recorder/Replayer", func() {
+	It("should replay register and memory writes in order", func() {
+		var buf bytes.Buffer
+		rec := trace.NewRecorder(&buf, 0)
+
+		rec.RecordRegWrite(0, 0, 42)
+		rec.RecordMemWrite(0x1000, 0, 0xAB)
+		rec.RecordRetire(0x8000)
+
+		rec.RecordRegWrite(1, 0, 7)
+		rec.RecordRetire(0x8004)
+
+		Expect(rec.Err()).ToNot(HaveOccurred())
+
+		rp, err := trace.NewReplayer(bytes.NewReader(buf.Bytes()))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(rp.InstructionIndex()).To(Equal(uint64(0)))
+
+		Expect(rp.StepForward()).To(BeTrue())
+		Expect(rp.InstructionIndex()).To(Equal(uint64(1)))
+		Expect(rp.PC()).To(Equal(uint64(0x8000)))
+		Expect(rp.ReadReg(0)).To(Equal(uint64(42)))
+		Expect(rp.ReadByte(0x1000)).To(Equal(byte(0xAB)))
+
+		Expect(rp.StepForward()).To(BeTrue())
+		Expect(rp.InstructionIndex()).To(Equal(uint64(2)))
+		Expect(rp.PC()).To(Equal(uint64(0x8004)))
+		Expect(rp.ReadReg(1)).To(Equal(uint64(7)))
+
+		Expect(rp.StepForward()).To(BeFalse())
+	})
+
+	It("should step backward to the previous instruction's state", func() {
+		var buf bytes.Buffer
+		rec := trace.NewRecorder(&buf, 0)
+		rec.RecordRegWrite(0, 0, 1)
+		rec.RecordRetire(0x1000)
+		rec.RecordRegWrite(0, 1, 2)
+		rec.RecordRetire(0x1004)
+
+		rp, err := trace.NewReplayer(bytes.NewReader(buf.Bytes()))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(rp.Seek(2)).To(Succeed())
+		Expect(rp.ReadReg(0)).To(Equal(uint64(2)))
+
+		Expect(rp.StepBackward()).To(BeTrue())
+		Expect(rp.InstructionIndex()).To(Equal(uint64(1)))
+		Expect(rp.ReadReg(0)).To(Equal(uint64(1)))
+		Expect(rp.PC()).To(Equal(uint64(0x1000)))
+	})
+
+	It("should seek using a snapshot instead of replaying from the start", func() {
+		var buf bytes.Buffer
+		rec := trace.NewRecorder(&buf, 2) // snapshot every 2 retired instructions
+
+		for i := uint64(0); i < 5; i++ {
+			rec.RecordRegWrite(0, i, i+1)
+			rec.RecordRetire(0x1000 + i*4)
+		}
+
+		rp, err := trace.NewReplayer(bytes.NewReader(buf.Bytes()))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(rp.Seek(4)).To(Succeed())
+		Expect(rp.ReadReg(0)).To(Equal(uint64(4)))
+		Expect(rp.PC()).To(Equal(uint64(0x1000 + 3*4)))
+	})
+
+	It("should replay the most recent syscall's inputs and outputs", func() {
+		var buf bytes.Buffer
+		rec := trace.NewRecorder(&buf, 0)
+
+		args := [6]uint64{1, 2, 3, 4, 5, 6}
+		rec.RecordSyscall(64, args, false, 0)
+		rec.RecordRetire(0x2000)
+
+		rp, err := trace.NewReplayer(bytes.NewReader(buf.Bytes()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rp.StepForward()).To(BeTrue())
+
+		num, gotArgs, exited, exitCode := rp.LastSyscall()
+		Expect(num).To(Equal(uint64(64)))
+		Expect(gotArgs).To(Equal(args))
+		Expect(exited).To(BeFalse())
+		Expect(exitCode).To(Equal(int64(0)))
+	})
+
+	It("should reject a reader with no events at all", func() {
+		_, err := trace.NewReplayer(bytes.NewReader(nil))
+		Expect(err).To(HaveOccurred())
+	})
+})